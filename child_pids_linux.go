@@ -0,0 +1,77 @@
+// +build linux
+
+package jasper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// childPIDs returns the PIDs of all descendants of the process with the given
+// PID by walking /proc. Processes that exit while /proc is being read are
+// ignored.
+func childPIDs(pid int) ([]int, error) {
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading /proc")
+	}
+
+	children := map[int][]int{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		childPID, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		ppid, err := parentPID(childPID)
+		if err != nil {
+			continue
+		}
+		children[ppid] = append(children[ppid], childPID)
+	}
+
+	pids := []int{}
+	queue := children[pid]
+	for len(queue) != 0 {
+		next := queue[0]
+		queue = queue[1:]
+		pids = append(pids, next)
+		queue = append(queue, children[next]...)
+	}
+	sort.Ints(pids)
+
+	return pids, nil
+}
+
+// parentPID returns the parent PID of the process from /proc/<pid>/stat.
+func parentPID(pid int) (int, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem reading stat for process %d", pid)
+	}
+
+	// The command name is in parentheses and may itself contain spaces or
+	// parentheses, so the remaining fields start after the last ')'.
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return 0, errors.Errorf("malformed stat for process %d", pid)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 2 {
+		return 0, errors.Errorf("malformed stat for process %d", pid)
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed parent PID for process %d", pid)
+	}
+
+	return ppid, nil
+}
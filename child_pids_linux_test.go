@@ -0,0 +1,73 @@
+// +build linux
+
+package jasper
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestProcessChildPIDs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("ReportsDescendants", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sh", "-c", "sleep 100 & sleep 100 & wait"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+
+				var pids []int
+				assert.Eventually(t, func() bool {
+					pids, err = proc.ChildPIDs(ctx)
+					return err == nil && len(pids) == 2
+				}, 5*time.Second, 10*time.Millisecond, "child PIDs: %v, error: %v", pids, err)
+
+				for _, pid := range pids {
+					ppid, err := parentPID(pid)
+					require.NoError(t, err)
+					assert.Equal(t, proc.Info(ctx).PID, ppid)
+					assert.NoError(t, syscall.Kill(pid, syscall.SIGKILL))
+				}
+				_, _ = proc.Wait(ctx)
+			})
+			t.Run("FailsForCompletedProcess", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"true"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				pids, err := proc.ChildPIDs(ctx)
+				assert.Error(t, err)
+				assert.Empty(t, pids)
+			})
+		})
+	}
+	t.Run("FailsForUnstartedProcess", func(t *testing.T) {
+		proc, err := NewProcess(ctx, &options.Create{
+			Args:       []string{"true"},
+			DeferStart: true,
+		})
+		require.NoError(t, err)
+
+		pids, err := proc.ChildPIDs(ctx)
+		assert.Error(t, err)
+		assert.Empty(t, pids)
+	})
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package jasper
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+func childPIDs(pid int) ([]int, error) {
+	return nil, errors.Errorf("getting child PIDs is not supported on %s", runtime.GOOS)
+}
@@ -445,7 +445,7 @@ func (in *LoggingCacheCreateInput) Validate() error {
 // that has been cached in the remote service.
 type CachedLoggerResponse struct {
 	OutcomeResponse `json:"outcome"`
-	Logger          options.CachedLogger `json:"logger"`
+	Logger          *options.CachedLogger `json:"logger"`
 }
 
 // ExtractCachedLoggerResponse unmarshals the input bytes into a
@@ -54,7 +54,7 @@ func loggingCacheCreate() cli.Command {
 				if err != nil {
 					return &CachedLoggerResponse{OutcomeResponse: *makeOutcomeResponse(err)}
 				}
-				return &CachedLoggerResponse{Logger: *logger, OutcomeResponse: *makeOutcomeResponse(nil)}
+				return &CachedLoggerResponse{Logger: logger, OutcomeResponse: *makeOutcomeResponse(nil)}
 			})
 		},
 	}
@@ -76,7 +76,7 @@ func loggingCacheGet() cli.Command {
 				if logger == nil {
 					return &CachedLoggerResponse{OutcomeResponse: *makeOutcomeResponse(errors.Errorf("logger with id '%s' not found", input.ID))}
 				}
-				return &CachedLoggerResponse{Logger: *logger, OutcomeResponse: *makeOutcomeResponse(nil)}
+				return &CachedLoggerResponse{Logger: logger, OutcomeResponse: *makeOutcomeResponse(nil)}
 			})
 		},
 	}
@@ -187,7 +187,7 @@ func validLoggingCacheOptions(t *testing.T) options.Output {
 
 // createCachedLoggerFromCLI creates a cached logger on a remote service using
 // the CLI.
-func createCachedLoggerFromCLI(t *testing.T, c *cli.Context, id string) options.CachedLogger {
+func createCachedLoggerFromCLI(t *testing.T, c *cli.Context, id string) *options.CachedLogger {
 	input, err := json.Marshal(LoggingCacheCreateInput{
 		ID:     id,
 		Output: validLoggingCacheOptions(t),
@@ -196,6 +196,7 @@ func createCachedLoggerFromCLI(t *testing.T, c *cli.Context, id string) options.
 	resp := &CachedLoggerResponse{}
 	require.NoError(t, execCLICommandInputOutput(t, c, loggingCacheCreate(), input, resp))
 	require.True(t, resp.Successful())
+	require.NotNil(t, resp.Logger)
 	require.Equal(t, id, resp.Logger.ID)
 
 	return resp.Logger
@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 	"github.com/tychoish/jasper/remote"
@@ -120,6 +124,10 @@ func (c *sshClient) Register(ctx context.Context, proc jasper.Process) error {
 	return errors.New("cannot register existing processes on remote manager")
 }
 
+func (c *sshClient) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	return nil, errors.New("cannot register external processes on remote process managers")
+}
+
 func (c *sshClient) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
 	output, err := c.runManagerCommand(ctx, ListCommand, &FilterInput{Filter: f})
 	if err != nil {
@@ -239,6 +247,11 @@ func (c *sshClient) GetLogStream(ctx context.Context, id string, count int) (jas
 	return resp.LogStream, nil
 }
 
+// StreamLogs is not supported by the SSH client.
+func (c *sshClient) StreamLogs(_ context.Context, _ string) (<-chan []byte, error) {
+	return nil, errors.New("streaming logs is not supported by the SSH client")
+}
+
 func (c *sshClient) SignalEvent(ctx context.Context, name string) error {
 	output, err := c.runRemoteCommand(ctx, SignalEventCommand, &EventInput{Name: name})
 	if err != nil {
@@ -257,6 +270,42 @@ func (c *sshClient) LoggingCache(ctx context.Context) jasper.LoggingCache {
 	return c.manager.LoggingCache(ctx)
 }
 
+func (c *sshClient) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return jasper.SignalGroup(ctx, c, tag, sig)
+}
+
+func (c *sshClient) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return jasper.WaitForTag(ctx, c, tag)
+}
+
+func (c *sshClient) SetTagLimit(ctx context.Context, tag string, max int) error {
+	return errors.New("cannot set tag limits on remote process managers")
+}
+
+func (c *sshClient) Stats(ctx context.Context) jasper.ManagerStats {
+	stats, err := jasper.CollectManagerStats(ctx, c)
+	grip.Debug(message.WrapError(err, "problem collecting manager stats"))
+	return stats
+}
+
+// Subscribe is not supported by the SSH client, so the returned channel is
+// closed.
+func (c *sshClient) Subscribe(_ context.Context) <-chan jasper.ProcessEvent {
+	events := make(chan jasper.ProcessEvent)
+	close(events)
+	return events
+}
+
+// Snapshot is not supported by the SSH client.
+func (c *sshClient) Snapshot(_ context.Context) ([]byte, error) {
+	return nil, errors.New("snapshots are not supported by the SSH client")
+}
+
+// PruneLoggers is not supported by the SSH client.
+func (c *sshClient) PruneLoggers(_ context.Context, _ time.Duration) (int, error) {
+	return 0, errors.New("pruning loggers is not supported by the SSH client")
+}
+
 func (c *sshClient) SendMessages(ctx context.Context, opts options.LoggingPayload) error {
 	output, err := c.runRemoteCommand(ctx, SendMessagesCommand, opts)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
+	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -39,7 +40,7 @@ func (lc *sshLoggingCache) Create(id string, opts *options.Output) (*options.Cac
 		return nil, errors.WithStack(err)
 	}
 
-	return &resp.Logger, nil
+	return resp.Logger, nil
 }
 
 func (lc *sshLoggingCache) Put(id string, cl *options.CachedLogger) error {
@@ -59,7 +60,13 @@ func (lc *sshLoggingCache) Get(id string) *options.CachedLogger {
 		return nil
 	}
 
-	return &resp.Logger
+	return resp.Logger
+}
+
+func (lc *sshLoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return jasper.SendToMany(lc, ids, lp, func(_ *options.CachedLogger, lp *options.LoggingPayload) error {
+		return lc.client.SendMessages(lc.ctx, *lp)
+	})
 }
 
 func (lc *sshLoggingCache) Remove(id string) {
@@ -94,6 +101,11 @@ func (lc *sshLoggingCache) Clear(ctx context.Context) error {
 	return err
 }
 
+// Drain is not supported by the SSH client.
+func (lc *sshLoggingCache) Drain(_ context.Context) error {
+	return errors.New("draining the logging cache is not supported by the SSH client")
+}
+
 func (lc *sshLoggingCache) Prune(ts time.Time) {
 	output, err := lc.runCommand(lc.ctx, LoggingCachePruneCommand, LoggingCachePruneInput{LastAccessed: ts})
 	if err != nil {
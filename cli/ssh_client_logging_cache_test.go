@@ -22,7 +22,7 @@ func TestSSHLoggingCache(t *testing.T) {
 			inputChecker := &LoggingCacheCreateInput{}
 			resp := &CachedLoggerResponse{
 				OutcomeResponse: *makeOutcomeResponse(nil),
-				Logger: options.CachedLogger{
+				Logger: &options.CachedLogger{
 					ID:       "id",
 					Manager:  "manager_id",
 					Accessed: time.Now(),
@@ -57,7 +57,7 @@ func TestSSHLoggingCache(t *testing.T) {
 		"GetPassesWithValidResponse": func(ctx context.Context, t *testing.T, lc *sshLoggingCache, client *sshClient, baseManager *mock.Manager) {
 			resp := &CachedLoggerResponse{
 				OutcomeResponse: *makeOutcomeResponse(nil),
-				Logger: options.CachedLogger{
+				Logger: &options.CachedLogger{
 					ID:      "id",
 					Manager: "manager_id",
 				},
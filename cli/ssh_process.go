@@ -2,7 +2,10 @@ package cli
 
 import (
 	"context"
+	"regexp"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/jasper"
@@ -17,6 +20,8 @@ type clientFunc func(ctx context.Context, subcommand []string, input interface{}
 type sshProcess struct {
 	runClientCommand clientFunc
 	info             jasper.ProcessInfo
+	done             <-chan struct{}
+	doneOnce         sync.Once
 }
 
 // newSSHProcess creates a new process that runs using a Jasper CLI over SSH.
@@ -55,6 +60,12 @@ func (p *sshProcess) Info(ctx context.Context) jasper.ProcessInfo {
 	return p.info
 }
 
+// InfoWith returns the same information as Info, since the optional fields
+// are not supported for remote processes.
+func (p *sshProcess) InfoWith(ctx context.Context, _ jasper.InfoOptions) jasper.ProcessInfo {
+	return p.Info(ctx)
+}
+
 func (p *sshProcess) Running(ctx context.Context) bool {
 	if p.info.Complete {
 		return false
@@ -124,6 +135,36 @@ func (p *sshProcess) Wait(ctx context.Context) (int, error) {
 	return resp.ExitCode, nil
 }
 
+// Done waits for the process in the background to detect when it completes.
+func (p *sshProcess) Done() <-chan struct{} {
+	p.doneOnce.Do(func() { p.done = jasper.WaitDone(p) })
+	return p.done
+}
+
+func (p *sshProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(jasper.ProcessInfo)) error {
+	return jasper.WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *sshProcess) WaitResult(ctx context.Context) (jasper.WaitResult, error) {
+	return jasper.WaitForResult(ctx, p)
+}
+
+func (p *sshProcess) Start(_ context.Context) error {
+	return errors.New("cannot start remote processes")
+}
+
+func (p *sshProcess) ResolvedCommand() ([]string, []string, string) {
+	return nil, nil, ""
+}
+
+func (p *sshProcess) ChildPIDs(_ context.Context) ([]int, error) {
+	return nil, errors.New("cannot get child PIDs of remote processes")
+}
+
+func (p *sshProcess) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	return errors.New("cannot watch the output of remote processes")
+}
+
 func (p *sshProcess) Respawn(ctx context.Context) (jasper.Process, error) {
 	output, err := p.runCommand(ctx, RespawnCommand, &IDInput{ID: p.info.ID})
 	if err != nil {
@@ -142,6 +183,18 @@ func (p *sshProcess) RegisterTrigger(ctx context.Context, t jasper.ProcessTrigge
 	return errors.New("cannot register triggers on remote processes")
 }
 
+func (p *sshProcess) RegisterTriggerWithContext(ctx context.Context, t jasper.ProcessTriggerWithContext) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *sshProcess) RegisterTriggerWithPhase(ctx context.Context, phase jasper.TriggerPhase, t jasper.ProcessTrigger) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *sshProcess) RegisterNamedTrigger(ctx context.Context, name string, t jasper.ProcessTrigger) error {
+	return errors.New("cannot register named triggers on remote processes")
+}
+
 func (p *sshProcess) RegisterSignalTrigger(ctx context.Context, t jasper.SignalTrigger) error {
 	return errors.New("cannot register signal triggers on remote processes")
 }
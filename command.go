@@ -24,10 +24,24 @@ import (
 type Command struct {
 	opts     options.Command
 	procs    []Process
+	results  []CommandResult
 	runFunc  func(options.Command) error
 	makeProc ProcessConstructor
 }
 
+// CommandResult describes the outcome of a single sub-command executed by a
+// Command.
+type CommandResult struct {
+	// Args are the arguments of the sub-command.
+	Args []string
+	// ExitCode is the exit code of the sub-command, or -1 if the
+	// sub-command could not be started or was run in the background.
+	ExitCode int
+	// Err is the error, if any, encountered when running the
+	// sub-command.
+	Err error
+}
+
 func (c *Command) sudoCmd() []string {
 	sudoCmd := []string{}
 	if c.opts.Sudo {
@@ -78,6 +92,15 @@ func (c *Command) GetProcIDs() []string {
 	return ids
 }
 
+// Results returns the outcome of each sub-command that the Command has
+// attempted to execute. Sub-commands that were not executed, such as those
+// after a failed sub-command when ContinueOnError is not set, have no result.
+// For commands run with RunParallel, the results are not guaranteed to be in
+// the same order as the sub-commands.
+func (c *Command) Results() []CommandResult {
+	return c.results
+}
+
 // ApplyFromOpts uses the options.Create to configure the Command. All existing
 // options will be overwritten. Use of this function is discouraged unless all
 // desired options are populated in the given opts.
@@ -575,12 +598,14 @@ func (c *Command) RunParallel(ctx context.Context) error {
 		splitCmd.opts.Process = *optsCopy
 		splitCmd.opts.Commands = [][]string{cmd}
 		splitCmd.procs = []Process{}
+		splitCmd.results = nil
 		parallelCmds[idx] = splitCmd
 	}
 
 	type cmdResult struct {
-		procs []Process
-		err   error
+		procs   []Process
+		results []CommandResult
+		err     error
 	}
 	cmdResults := make(chan cmdResult, len(c.opts.Commands))
 	for _, parallelCmd := range parallelCmds {
@@ -593,7 +618,7 @@ func (c *Command) RunParallel(ctx context.Context) error {
 			}()
 			err := innerCmd.Run(ctx)
 			select {
-			case cmdResults <- cmdResult{procs: innerCmd.procs, err: err}:
+			case cmdResults <- cmdResult{procs: innerCmd.procs, results: innerCmd.results, err: err}:
 			case <-ctx.Done():
 			}
 		}(parallelCmd)
@@ -607,6 +632,7 @@ func (c *Command) RunParallel(ctx context.Context) error {
 				catcher.Add(cmdRes.err)
 			}
 			c.procs = append(c.procs, cmdRes.procs...)
+			c.results = append(c.results, cmdRes.results...)
 		case <-ctx.Done():
 			c.procs = []Process{}
 			catcher.Add(c.Close())
@@ -837,10 +863,13 @@ func (c *Command) exec(ctx context.Context, opts *options.Create, idx int) error
 	writeOutput := getMsgOutput(opts.Output)
 	proc, err := c.makeProc(ctx, opts)
 	if err != nil {
-		return errors.Wrap(err, "problem starting command")
+		err = errors.Wrap(err, "problem starting command")
+		c.results = append(c.results, CommandResult{Args: opts.Args, ExitCode: -1, Err: err})
+		return err
 	}
 	c.procs = append(c.procs, proc)
 
+	result := CommandResult{Args: opts.Args, ExitCode: -1}
 	if !c.opts.RunBackground {
 		waitCatcher := grip.NewBasicCatcher()
 		for _, proc := range c.procs {
@@ -850,7 +879,10 @@ func (c *Command) exec(ctx context.Context, opts *options.Create, idx int) error
 		err = waitCatcher.Resolve()
 		msg["err"] = err
 		grip.Log(c.opts.Priority, writeOutput(msg))
+
+		result.ExitCode, result.Err = proc.Wait(ctx)
 	}
+	c.results = append(c.results, result)
 
 	return errors.WithStack(err)
 }
@@ -364,6 +364,36 @@ func TestCommandImplementation(t *testing.T) {
 							assert.NoError(t, cmd.Extend(subCmds).ContinueOnError(true).IgnoreError(true).Run(ctx))
 							assert.Len(t, cmd.GetProcIDs(), len(subCmds))
 						},
+						"ResultsReflectEachSubCommand": func(ctx context.Context, t *testing.T, cmd Command) {
+							subCmds := [][]string{
+								{echo, arg1},
+								{"false"},
+								{echo, arg2},
+							}
+							assert.Error(t, runFunc(cmd.Extend(subCmds).ContinueOnError(true), ctx))
+
+							results := cmd.Results()
+							require.Len(t, results, len(subCmds))
+							if runFuncType == "NonParallel" {
+								for idx, result := range results {
+									assert.Equal(t, subCmds[idx], result.Args)
+								}
+							}
+
+							for _, result := range results {
+								if result.Args[0] == "false" {
+									assert.NotZero(t, result.ExitCode)
+									assert.Error(t, result.Err)
+									continue
+								}
+								assert.Zero(t, result.ExitCode)
+								assert.NoError(t, result.Err)
+							}
+						},
+						"ResultsAreEmptyBeforeRun": func(ctx context.Context, t *testing.T, cmd Command) {
+							cmd.Append("true")
+							assert.Empty(t, cmd.Results())
+						},
 						"ApplyFromOptsUpdatesCmdCorrectly": func(ctx context.Context, t *testing.T, cmd Command) {
 							opts := &options.Create{
 								WorkingDirectory: cwd,
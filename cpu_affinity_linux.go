@@ -0,0 +1,33 @@
+// +build linux
+
+package jasper
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// setCPUAffinity restricts the process with the given PID to run on the given
+// CPUs using sched_setaffinity(2).
+func setCPUAffinity(pid int, cpus []int) error {
+	maxCPU := 0
+	for _, cpu := range cpus {
+		if cpu > maxCPU {
+			maxCPU = cpu
+		}
+	}
+
+	mask := make([]uint64, maxCPU/64+1)
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << uint(cpu%64)
+	}
+
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, uintptr(pid), uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errors.Wrapf(errno, "problem setting CPU affinity of process %d", pid)
+	}
+
+	return nil
+}
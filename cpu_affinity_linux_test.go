@@ -0,0 +1,44 @@
+// +build linux
+
+package jasper
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestProcessCPUAffinity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			proc, err := NewProcess(ctx, &options.Create{
+				Args:           []string{"sleep", "5"},
+				Implementation: impl,
+				CPUAffinity:    []int{0},
+			})
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			}()
+
+			status, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/status", proc.Info(ctx).PID))
+			require.NoError(t, err)
+			assert.Contains(t, strings.Split(string(status), "\n"), "Cpus_allowed_list:\t0")
+		})
+	}
+}
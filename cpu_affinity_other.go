@@ -0,0 +1,13 @@
+// +build !linux
+
+package jasper
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+func setCPUAffinity(pid int, cpus []int) error {
+	return errors.Errorf("CPU affinity is not supported on %s", runtime.GOOS)
+}
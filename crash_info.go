@@ -0,0 +1,141 @@
+package jasper
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/tychoish/jasper/internal/executor"
+	"github.com/tychoish/jasper/options"
+)
+
+// CrashInfo describes a process that crashed, which is recorded in its info
+// if its options set CaptureCrashInfo.
+type CrashInfo struct {
+	// Signal is the signal that terminated the process.
+	Signal syscall.Signal `json:"signal" bson:"signal"`
+	// CoreDumped indicates that the process dumped core, which depends on
+	// the core dump size limit of the process. It is only known for
+	// local processes.
+	CoreDumped bool `json:"core_dumped,omitempty" bson:"core_dumped,omitempty"`
+	// CorePath is the path of the core dump, if the process dumped core
+	// and the path can be determined from the system's core dump
+	// pattern. It is only determined for local processes on Linux, and
+	// is not set if core dumps are piped to a program, such as
+	// systemd-coredump.
+	CorePath string `json:"core_path,omitempty" bson:"core_path,omitempty"`
+}
+
+// crashSignals are the signals whose default action is to terminate the
+// process and dump core, which indicate that the process crashed.
+var crashSignals = map[syscall.Signal]struct{}{
+	syscall.SIGQUIT: {},
+	syscall.SIGILL:  {},
+	syscall.SIGTRAP: {},
+	syscall.SIGABRT: {},
+	syscall.SIGBUS:  {},
+	syscall.SIGFPE:  {},
+	syscall.SIGSEGV: {},
+}
+
+// newCrashInfo returns information about the crash of the completed process,
+// or nil if the options do not capture crash information or the process did
+// not crash.
+func newCrashInfo(opts *options.Create, exec executor.Executor) *CrashInfo {
+	if !opts.CaptureCrashInfo {
+		return nil
+	}
+
+	sig, signaled := exec.SignalInfo()
+	if !signaled {
+		return nil
+	}
+	if _, ok := crashSignals[sig]; !ok {
+		return nil
+	}
+
+	info := &CrashInfo{
+		Signal:     sig,
+		CoreDumped: exec.CoreDumped(),
+	}
+	if info.CoreDumped && opts.Remote == nil && opts.Docker == nil {
+		info.CorePath = corePath(exec.PID(), exec.Args(), opts.WorkingDirectory, sig)
+	}
+
+	return info
+}
+
+// corePatternValues are the values that the specifiers in a core dump pattern
+// are expanded to.
+type corePatternValues struct {
+	pid      int
+	command  string
+	signal   syscall.Signal
+	hostname string
+	uid      int
+	gid      int
+}
+
+// maxCommandNameLength is the maximum length of the name of a command, which
+// the command name in core dump patterns is truncated to.
+const maxCommandNameLength = 15
+
+// expandCorePattern returns the path of the core dump described by the core
+// dump pattern (see core(5)), which is relative to the working directory of
+// the process if it is not absolute. If usesPID is set and the pattern does
+// not include the PID, the PID is appended to the path. It returns the empty
+// string if the core dump is piped to a program or the pattern contains
+// specifiers whose values are not known.
+func expandCorePattern(pattern string, usesPID bool, dir string, values corePatternValues) string {
+	if pattern == "" || strings.HasPrefix(pattern, "|") {
+		return ""
+	}
+
+	command := filepath.Base(values.command)
+	if len(command) > maxCommandNameLength {
+		command = command[:maxCommandNameLength]
+	}
+
+	var path strings.Builder
+	hasPID := false
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			path.WriteByte(pattern[i])
+			continue
+		}
+
+		i++
+		if i == len(pattern) {
+			break
+		}
+		switch pattern[i] {
+		case '%':
+			path.WriteByte('%')
+		case 'p', 'P', 'i', 'I':
+			hasPID = true
+			path.WriteString(strconv.Itoa(values.pid))
+		case 'e':
+			path.WriteString(command)
+		case 's':
+			path.WriteString(strconv.Itoa(int(values.signal)))
+		case 'h':
+			path.WriteString(values.hostname)
+		case 'u':
+			path.WriteString(strconv.Itoa(values.uid))
+		case 'g':
+			path.WriteString(strconv.Itoa(values.gid))
+		default:
+			return ""
+		}
+	}
+
+	if usesPID && !hasPID {
+		path.WriteString("." + strconv.Itoa(values.pid))
+	}
+
+	if !filepath.IsAbs(path.String()) {
+		return filepath.Join(dir, path.String())
+	}
+	return path.String()
+}
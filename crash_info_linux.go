@@ -0,0 +1,30 @@
+// +build linux
+
+package jasper
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// corePath returns the path of the core dump of the process according to the
+// system's core dump pattern, or the empty string if it cannot be determined.
+func corePath(pid int, args []string, dir string, sig syscall.Signal) string {
+	pattern, err := ioutil.ReadFile("/proc/sys/kernel/core_pattern")
+	if err != nil || len(args) == 0 {
+		return ""
+	}
+	usesPID, _ := ioutil.ReadFile("/proc/sys/kernel/core_uses_pid")
+	hostname, _ := os.Hostname()
+
+	return expandCorePattern(strings.TrimSpace(string(pattern)), strings.TrimSpace(string(usesPID)) == "1", dir, corePatternValues{
+		pid:      pid,
+		command:  args[0],
+		signal:   sig,
+		hostname: hostname,
+		uid:      os.Getuid(),
+		gid:      os.Getgid(),
+	})
+}
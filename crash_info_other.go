@@ -0,0 +1,9 @@
+// +build !linux
+
+package jasper
+
+import "syscall"
+
+func corePath(pid int, args []string, dir string, sig syscall.Signal) string {
+	return ""
+}
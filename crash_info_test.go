@@ -0,0 +1,132 @@
+package jasper
+
+import (
+	"context"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestExpandCorePattern(t *testing.T) {
+	values := corePatternValues{
+		pid:      1234,
+		command:  "/usr/local/bin/a-very-long-command-name",
+		signal:   syscall.SIGSEGV,
+		hostname: "host",
+		uid:      1000,
+		gid:      100,
+	}
+	for testName, testCase := range map[string]struct {
+		pattern  string
+		usesPID  bool
+		expected string
+	}{
+		"Default": {
+			pattern:  "core",
+			expected: "/work/core",
+		},
+		"DefaultWithPID": {
+			pattern:  "core",
+			usesPID:  true,
+			expected: "/work/core.1234",
+		},
+		"AbsolutePathWithSpecifiers": {
+			pattern:  "/var/crash/core.%e.%p.%s.%h.%u.%g",
+			expected: "/var/crash/core.a-very-long-com.1234.11.host.1000.100",
+		},
+		"PIDIsNotAppendedTwice": {
+			pattern:  "/tmp/core-%p",
+			usesPID:  true,
+			expected: "/tmp/core-1234",
+		},
+		"LiteralPercent": {
+			pattern:  "/tmp/100%%-%P",
+			expected: "/tmp/100%-1234",
+		},
+		"Piped": {
+			pattern: "|/usr/lib/systemd/systemd-coredump %P %u %g %s %t %c %h",
+		},
+		"UnknownSpecifier": {
+			pattern: "/tmp/core.%t",
+		},
+		"Empty": {},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, expandCorePattern(testCase.pattern, testCase.usesPID, "/work", values))
+		})
+	}
+}
+
+func TestProcessCrashInfo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			for testName, testCase := range map[string]struct {
+				command string
+				capture bool
+				crashed bool
+				signal  syscall.Signal
+			}{
+				"RecordsSegmentationFault": {
+					command: "kill -SEGV $$",
+					capture: true,
+					crashed: true,
+					signal:  syscall.SIGSEGV,
+				},
+				"RecordsAbort": {
+					command: "kill -ABRT $$",
+					capture: true,
+					crashed: true,
+					signal:  syscall.SIGABRT,
+				},
+				"IgnoresNonCrashSignals": {
+					command: "kill -TERM $$",
+					capture: true,
+				},
+				"IgnoresExitWithoutSignal": {
+					command: "exit 1",
+					capture: true,
+				},
+				"IgnoresCrashWhenNotCapturing": {
+					command: "kill -SEGV $$",
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+					defer cancel()
+
+					proc, err := NewProcess(ctx, &options.Create{
+						Args:             []string{"sh", "-c", testCase.command},
+						CaptureCrashInfo: testCase.capture,
+						Implementation:   impl,
+					})
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					require.Error(t, err)
+
+					crash := proc.Info(ctx).CrashInfo
+					if !testCase.crashed {
+						assert.Nil(t, crash)
+						return
+					}
+					require.NotNil(t, crash)
+					assert.Equal(t, testCase.signal, crash.Signal)
+					if !crash.CoreDumped {
+						assert.Empty(t, crash.CorePath)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package jasper
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// dependentProcess is implemented by local processes so that managers can
+// defer starting them until the processes that they depend on complete.
+type dependentProcess interface {
+	Process
+	setDependencies([]DependencyInfo)
+	failBeforeStart(error)
+}
+
+// startAfterDependencies starts the process, which must have been created
+// with DeferStart, once all of its dependencies have completed successfully.
+// If any dependency fails or the context is canceled first, the process
+// completes unsuccessfully without being started. If start is false, the
+// process is left for the caller to start.
+func startAfterDependencies(ctx context.Context, proc dependentProcess, deps []Process, start bool) {
+	if err := waitForDependencies(ctx, proc, deps); err != nil {
+		proc.failBeforeStart(errors.Wrap(err, "problem waiting for process dependencies"))
+		return
+	}
+	if !start {
+		return
+	}
+
+	if err := proc.Start(ctx); err != nil {
+		proc.failBeforeStart(errors.Wrap(err, "problem starting process after its dependencies completed"))
+	}
+}
+
+// waitForDependencies blocks until all of the given processes have
+// completed successfully, recording the state of each dependency in the
+// dependent process's info as it completes. The dependencies must already be
+// recorded as pending. It returns an error as soon as any dependency fails or
+// the context is canceled, without waiting for the remaining dependencies to
+// finish.
+func waitForDependencies(ctx context.Context, proc dependentProcess, deps []Process) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	state := pendingDependencies(deps)
+
+	type result struct {
+		idx  int
+		info DependencyInfo
+	}
+
+	results := make(chan result, len(deps))
+	for idx, dep := range deps {
+		go func(idx int, dep Process) {
+			// Waiting on the dependency would run its context
+			// triggers with this context, so wait for it to
+			// complete instead.
+			select {
+			case <-ctx.Done():
+				return
+			case <-dep.Done():
+			}
+
+			info := dep.Info(ctx)
+			results <- result{
+				idx: idx,
+				info: DependencyInfo{
+					ID:         dep.ID(),
+					ExitCode:   info.ExitCode,
+					Complete:   true,
+					Successful: info.Successful,
+					EndAt:      info.EndAt,
+				},
+			}
+		}(idx, dep)
+	}
+
+	for range deps {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "canceled while waiting for dependencies")
+		case res := <-results:
+			state[res.idx] = res.info
+			proc.setDependencies(copyDependencies(state))
+			if !res.info.Successful {
+				return errors.Errorf("dependency '%s' did not complete successfully", res.info.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// pendingDependencies returns the state of the given dependencies before any
+// of them have completed.
+func pendingDependencies(deps []Process) []DependencyInfo {
+	out := make([]DependencyInfo, len(deps))
+	for idx, dep := range deps {
+		out[idx] = DependencyInfo{ID: dep.ID()}
+	}
+	return out
+}
+
+func copyDependencies(deps []DependencyInfo) []DependencyInfo {
+	out := make([]DependencyInfo, len(deps))
+	_ = copy(out, deps)
+	return out
+}
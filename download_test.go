@@ -100,7 +100,7 @@ func TestProcessDownloadJobs(t *testing.T) {
 	job, err := NewDownloadJob(fmt.Sprintf("%s/%s", baseURL, fileName), downloadDir, true)
 	require.NoError(t, err)
 
-	q := queue.NewLocalLimitedSize(2, 1048)
+	q := queue.NewLocalLimitedSize(&queue.FixedSizeQueueOptions{Workers: 2, Capacity: 1048})
 	require.NoError(t, q.Start(ctx))
 	require.NoError(t, q.Put(ctx, job))
 
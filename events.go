@@ -0,0 +1,98 @@
+package jasper
+
+import (
+	"context"
+	"sync"
+	"syscall"
+)
+
+// ProcessEventType describes a change in the lifecycle of a managed process.
+type ProcessEventType string
+
+const (
+	// ProcessEventCreated is emitted when a manager creates a process.
+	ProcessEventCreated ProcessEventType = "created"
+	// ProcessEventCompleted is emitted when a managed process exits.
+	ProcessEventCompleted ProcessEventType = "completed"
+	// ProcessEventSignaled is emitted when a managed process is sent a
+	// signal.
+	ProcessEventSignaled ProcessEventType = "signaled"
+)
+
+// ProcessEventBufferSize is the number of events buffered for each
+// subscriber. Events are dropped for subscribers whose buffer is full.
+const ProcessEventBufferSize = 128
+
+// ProcessEvent describes a change in the lifecycle of a managed process. Info
+// reflects the state of the process when the event occurred. Signal is only
+// set for ProcessEventSignaled events.
+type ProcessEvent struct {
+	Type   ProcessEventType `json:"type" bson:"type" yaml:"type"`
+	Info   ProcessInfo      `json:"info" bson:"info" yaml:"info"`
+	Signal syscall.Signal   `json:"signal,omitempty" bson:"signal,omitempty" yaml:"signal,omitempty"`
+}
+
+// processEventPublisher sends process events to subscribers. The zero value
+// is ready to use and it is safe for concurrent use.
+type processEventPublisher struct {
+	subscribers map[chan ProcessEvent]struct{}
+	mu          sync.Mutex
+}
+
+// subscribe returns a channel that receives published events until the
+// context is done, at which point the channel is closed.
+func (p *processEventPublisher) subscribe(ctx context.Context) <-chan ProcessEvent {
+	events := make(chan ProcessEvent, ProcessEventBufferSize)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.subscribers == nil {
+		p.subscribers = map[chan ProcessEvent]struct{}{}
+	}
+	p.subscribers[events] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		delete(p.subscribers, events)
+		close(events)
+	}()
+
+	return events
+}
+
+// publish sends the event to every subscriber without blocking, so the event
+// is dropped for subscribers that are not keeping up.
+func (p *processEventPublisher) publish(event ProcessEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for events := range p.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// watchProcess publishes events for the lifecycle of the managed process.
+// Events are published on a best-effort basis, since the process may
+// already have completed.
+func (p *processEventPublisher) watchProcess(ctx context.Context, proc Process) {
+	p.publish(ProcessEvent{Type: ProcessEventCreated, Info: proc.Info(ctx)})
+
+	_ = proc.RegisterSignalTrigger(ctx, func(info ProcessInfo, sig syscall.Signal) bool {
+		p.publish(ProcessEvent{Type: ProcessEventSignaled, Info: info, Signal: sig})
+		return false
+	})
+	err := proc.RegisterTrigger(ctx, func(info ProcessInfo) {
+		p.publish(ProcessEvent{Type: ProcessEventCompleted, Info: info})
+	})
+	if err != nil && proc.Complete(ctx) {
+		p.publish(ProcessEvent{Type: ProcessEventCompleted, Info: proc.Info(ctx)})
+	}
+}
@@ -0,0 +1,78 @@
+package jasper
+
+import (
+	"context"
+	"time"
+
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/jasper/options"
+)
+
+// healthRecorder is implemented by local processes so that managers can
+// record the outcome of their health checks.
+type healthRecorder interface {
+	setHealth(healthy bool, failures int)
+}
+
+func recordHealth(proc Process, healthy bool, failures int) {
+	if rec, ok := proc.(healthRecorder); ok {
+		rec.setHealth(healthy, failures)
+	}
+}
+
+// runHealthCheck probes the process using its health check every interval,
+// once it has started and is ready, until the process completes or the context
+// is done, recording the outcome in the process's info. The process is healthy
+// once a probe succeeds, and is unhealthy once the failure threshold of
+// consecutive probes fail, at which point it is replaced using the restart
+// function if the health check restarts processes.
+func runHealthCheck(ctx context.Context, proc Process, opts *options.Create, restart func(*options.Create) (Process, error)) {
+	hc := opts.HealthCheck
+	ticker := time.NewTicker(hc.GetInterval())
+	defer ticker.Stop()
+
+	var healthy bool
+	var failures int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info := proc.Info(ctx)
+		if info.Complete {
+			return
+		}
+		if info.NotStarted || !info.IsRunning {
+			continue
+		}
+
+		if err := hc.Probe(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			grip.Debug(message.WrapError(err, message.Fields{
+				"message":  "health check failed",
+				"process":  proc.ID(),
+				"failures": failures,
+			}))
+		} else {
+			failures = 0
+			healthy = true
+		}
+
+		unhealthy := failures >= hc.GetFailureThreshold()
+		if unhealthy {
+			healthy = false
+		}
+		recordHealth(proc, healthy, failures)
+
+		if unhealthy && hc.RestartOnFailure {
+			replaceProcess(ctx, proc, opts, restart, "health check failed")
+			return
+		}
+	}
+}
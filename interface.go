@@ -2,6 +2,8 @@ package jasper
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"syscall"
 	"time"
 
@@ -34,14 +36,74 @@ type Manager interface {
 	CreateCommand(context.Context) *Command
 	Register(context.Context, Process) error
 
+	// RegisterExternal registers a running process that was not
+	// started by jasper, so that it can be signaled and waited on.
+	// The options describe the process but are not used to start
+	// it. Since the process is not a child of the manager, its exit
+	// is detected by polling until the context is done, and its exit
+	// code is unknown. It returns an error if there is no running
+	// process with the PID. Remote managers do not support
+	// registering external processes.
+	RegisterExternal(ctx context.Context, pid int, opts *options.Create) (Process, error)
+
 	List(context.Context, options.Filter) ([]Process, error)
 	Group(context.Context, string) ([]Process, error)
 	Get(context.Context, string) (Process, error)
 	Clear(context.Context)
 	Close(context.Context) error
 
+	// SignalGroup sends the signal to every running process with
+	// the given tag. It attempts to signal all of the processes,
+	// even if signaling some of them fails, and returns an
+	// aggregate of the errors.
+	SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error
+
+	// WaitForTag waits for every process with the given tag to
+	// complete and returns the error from waiting on each process
+	// keyed by its ID. If no process has the tag, it returns an
+	// empty map immediately. The error is only non-nil if the
+	// processes could not be found or the context is done before
+	// they complete.
+	WaitForTag(ctx context.Context, tag string) (map[string]error, error)
+
+	// SetTagLimit limits the number of processes with the given tag
+	// that the manager runs concurrently. Once the limit is reached,
+	// CreateProcess blocks when creating a process with the tag
+	// until one of the running processes with the tag completes or
+	// the context is done. Each tag's limit is independent, so a
+	// process with several limited tags waits for a slot for each
+	// of them. A limit of zero removes the limit. Tags added to a
+	// process after it is created, and processes that the manager
+	// restarts, are not subject to the limit.
+	SetTagLimit(ctx context.Context, tag string, max int) error
+
 	LoggingCache(context.Context) LoggingCache
 	WriteFile(ctx context.Context, opts options.WriteFile) error
+
+	// Stats reports the number of processes tracked by the manager
+	// in each state. If the context is canceled or there is another
+	// error, the stats may be incomplete.
+	Stats(context.Context) ManagerStats
+
+	// Subscribe returns a channel that receives events for the
+	// lifecycle of the processes that the manager creates until the
+	// context is done, at which point the channel is closed. Events
+	// are dropped if the subscriber does not keep up with them.
+	// Managers that do not support subscriptions return a closed
+	// channel.
+	Subscribe(context.Context) <-chan ProcessEvent
+
+	// Snapshot serializes the state of the manager's local
+	// processes so that they can be reattached to after a restart
+	// using RestoreManager. Managers that do not support
+	// snapshots return an error.
+	Snapshot(context.Context) ([]byte, error)
+
+	// PruneLoggers closes and removes the loggers in the logging
+	// cache that were last accessed longer ago than olderThan,
+	// except for loggers of processes that are still running. It
+	// returns the number of loggers pruned.
+	PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error)
 }
 
 // Process objects reflect ways of starting and managing
@@ -60,6 +122,11 @@ type Process interface {
 	// is another error, an empty struct may be returned.
 	Info(context.Context) ProcessInfo
 
+	// InfoWith is the same as Info, but allows callers to request
+	// additional, potentially expensive, information about the
+	// process.
+	InfoWith(context.Context, InfoOptions) ProcessInfo
+
 	// Running provides a quick predicate for checking to see if a
 	// process is running.
 	Running(context.Context) bool
@@ -67,9 +134,31 @@ type Process interface {
 	// process has finished.
 	Complete(context.Context) bool
 
+	// Start starts a process that was created with
+	// (options.Create).DeferStart set. It returns an error if the
+	// process has already been started.
+	Start(context.Context) error
+
+	// ResolvedCommand returns the arguments, environment and working
+	// directory that the process was started with, after they were
+	// resolved from its options. The values of environment variables
+	// listed in (options.Create).RedactedEnvironment are replaced with
+	// RedactedEnvValue. Remote processes do not report their resolved
+	// command, so all of the returned values are empty.
+	ResolvedCommand() (args []string, env []string, dir string)
+
+	// ChildPIDs returns the PIDs of all of the process's running
+	// descendants, not only its direct children. It returns an
+	// error if the process is not running, and is only supported
+	// for local processes on Linux.
+	ChildPIDs(context.Context) ([]int, error)
+
 	// Signal sends the specified signals to the underlying
 	// process. Its error response reflects the outcome of sending
-	// the signal, not the state of the process signaled.
+	// the signal, not the state of the process signaled. It
+	// returns ErrProcessComplete if the process has already
+	// terminated and ErrProcessNotStarted if it has not started,
+	// so it is safe to call while racing with the process's exit.
 	Signal(context.Context, syscall.Signal) error
 
 	// Wait blocks until the process exits or the context is
@@ -83,6 +172,36 @@ type Process interface {
 	// and instead is returned as -1.
 	Wait(context.Context) (int, error)
 
+	// Done returns a channel that is closed once the process
+	// completes, in the same manner as Wait returning, so that
+	// callers can select on the process's completion. It is safe to
+	// call before and after the process completes, and always
+	// returns the same channel. Use ProcessContext to derive a
+	// context that is canceled once the process completes.
+	Done() <-chan struct{}
+
+	// WaitResult is the same as Wait, but returns the exit code,
+	// terminating signal, run duration and end reason of the
+	// process together. The result is empty if the process did not
+	// complete, such as when the context is canceled.
+	WaitResult(context.Context) (WaitResult, error)
+
+	// WaitWithProgress waits for the process in the same manner
+	// as Wait, but calls the callback with the process's current
+	// info every interval until the process completes. The
+	// callback is not called after the process completes. It
+	// returns the error that Wait would return.
+	WaitWithProgress(ctx context.Context, interval time.Duration, cb func(ProcessInfo)) error
+
+	// WaitForOutput blocks until the process writes a line to
+	// standard output or error that matches the pattern, which is
+	// useful for checking that a process is ready. Recent lines
+	// written before WaitForOutput is called are also matched. It
+	// returns ErrOutputNotMatched if the process exits without
+	// writing a matching line. The process must be created with
+	// (options.Output).WatchLines set.
+	WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error
+
 	// Respawn respawns a near-identical version of the process on
 	// which it is called. It will spawn a new process with the same
 	// options and return the new, "respawned" process.
@@ -107,12 +226,38 @@ type Process interface {
 	// complete.
 	RegisterTrigger(context.Context, ProcessTrigger) error
 
+	// RegisterTriggerWithContext associates a trigger with a
+	// process, erroring when the process is complete. Unlike
+	// triggers registered with RegisterTrigger, the trigger runs
+	// with the context passed to the first call to Wait that
+	// returns after the process completes, before that call
+	// returns.
+	RegisterTriggerWithContext(context.Context, ProcessTriggerWithContext) error
+
+	// RegisterTriggerWithPhase associates a trigger with a process
+	// in the same manner as RegisterTrigger, but allows the caller
+	// to choose whether the trigger runs before Wait returns
+	// (TriggerPhasePre) or asynchronously afterwards
+	// (TriggerPhasePost).
+	RegisterTriggerWithPhase(context.Context, TriggerPhase, ProcessTrigger) error
+
+	// RegisterNamedTrigger associates a trigger with a process
+	// under the given name, in the same manner as
+	// RegisterTrigger. Because triggers cannot be compared,
+	// the name identifies the trigger, and implementations
+	// should reject a trigger whose name has already been
+	// registered.
+	RegisterNamedTrigger(context.Context, string, ProcessTrigger) error
+
 	// Tag adds a tag to a process. Implementations should avoid
 	// allowing duplicate tags to exist.
 	Tag(string)
-	// GetTags should return all tags for a process.
+	// GetTags should return all tags for a process in the order in
+	// which they were first added, so that the order is stable across
+	// calls.
 	GetTags() []string
-	// ResetTags should clear all existing tags.
+	// ResetTags should clear all existing tags. Tags that are added
+	// afterwards are ordered as if they had never been added before.
 	ResetTags()
 }
 
@@ -135,4 +280,133 @@ type ProcessInfo struct {
 	Options    options.Create `json:"options" bson:"options"`
 	StartAt    time.Time      `json:"start_at" bson:"start_at"`
 	EndAt      time.Time      `json:"end_at" bson:"end_at"`
+	// NotStarted indicates that the process was created with
+	// DeferStart and has not yet been started with Start.
+	NotStarted bool `json:"not_started,omitempty" bson:"not_started,omitempty"`
+	// OutputTail contains the most recent lines of output, when
+	// requested using InfoWith.
+	OutputTail []string `json:"output_tail,omitempty" bson:"output_tail,omitempty"`
+	// Dependencies reports the state of the processes specified in
+	// Options.DependsOn. While the process is waiting to start, it
+	// shows which dependencies are still pending.
+	Dependencies []DependencyInfo `json:"dependencies,omitempty" bson:"dependencies,omitempty"`
+	// SignalHistory contains the most recent signals sent to the
+	// process, oldest first, up to SignalHistoryLimit events.
+	SignalHistory []SignalHistoryEvent `json:"signal_history,omitempty" bson:"signal_history,omitempty"`
+	// EndReason describes why the process stopped running. It is empty
+	// while the process is running and for processes whose end reason
+	// cannot be determined, such as restored processes.
+	EndReason EndReason `json:"end_reason,omitempty" bson:"end_reason,omitempty"`
+	// Signal is the signal that terminated the process, if it was
+	// terminated by a signal. In that case, ExitCode is also set to
+	// the signal's number.
+	Signal syscall.Signal `json:"signal,omitempty" bson:"signal,omitempty"`
+	// CrashInfo describes how the process crashed, if it crashed and
+	// its options set CaptureCrashInfo.
+	CrashInfo *CrashInfo `json:"crash_info,omitempty" bson:"crash_info,omitempty"`
+	// Healthy reports whether the process is passing the health check
+	// in its options, which its manager runs. It is false until a
+	// probe first succeeds, and once the process has failed its health
+	// check's failure threshold of consecutive probes.
+	// HealthCheckFailures is the current number of consecutive failed
+	// probes.
+	Healthy             bool `json:"healthy,omitempty" bson:"healthy,omitempty"`
+	HealthCheckFailures int  `json:"health_check_failures,omitempty" bson:"health_check_failures,omitempty"`
+	// OutputChecksum contains the checksums of the process' output
+	// and error once it completes, if the output options specify a
+	// checksum algorithm.
+	OutputChecksum *options.OutputChecksum `json:"output_checksum,omitempty" bson:"output_checksum,omitempty"`
+	// OutputTruncated indicates that the process was killed because
+	// it timed out, so its output may be incomplete. The output that
+	// it wrote before timing out is still captured.
+	OutputTruncated bool `json:"output_truncated,omitempty" bson:"output_truncated,omitempty"`
+	// OutputBytes, OutputLines, ErrorBytes and ErrorLines count the
+	// bytes and lines that the process has written to standard output
+	// and error. They are updated while the process is running. A
+	// final line that does not end in a newline is counted as a line.
+	OutputBytes int64 `json:"output_bytes" bson:"output_bytes"`
+	OutputLines int   `json:"output_lines" bson:"output_lines"`
+	ErrorBytes  int64 `json:"error_bytes" bson:"error_bytes"`
+	ErrorLines  int   `json:"error_lines" bson:"error_lines"`
+}
+
+// EndReason describes why a process stopped running.
+type EndReason string
+
+const (
+	// EndReasonExited indicates that the process exited on its own.
+	EndReasonExited EndReason = "exited"
+	// EndReasonSignaled indicates that the process was terminated by a
+	// signal.
+	EndReasonSignaled EndReason = "signaled"
+	// EndReasonTimedOut indicates that the process was killed because
+	// it ran longer than the timeout in its options.
+	EndReasonTimedOut EndReason = "timed-out"
+	// EndReasonAborted indicates that the process was terminated
+	// because the manager that created it was closed.
+	EndReasonAborted EndReason = "aborted"
+	// EndReasonContextCanceled indicates that the process was killed
+	// because the context used to create it was canceled.
+	EndReasonContextCanceled EndReason = "context-canceled"
+	// EndReasonNotReady indicates that the process was killed because
+	// its readiness probe did not succeed before the probe's timeout.
+	EndReasonNotReady EndReason = "not-ready"
+	// EndReasonDependencyFailed indicates that the process was never
+	// started because one of the processes that it depends on did not
+	// complete successfully.
+	EndReasonDependencyFailed EndReason = "dependency-failed"
+)
+
+// WaitResult reports the outcome of a completed process, as returned by
+// Process.WaitResult.
+type WaitResult struct {
+	ExitCode int `json:"exit_code" bson:"exit_code"`
+	// Signal is the signal that terminated the process, if any.
+	Signal    syscall.Signal `json:"signal,omitempty" bson:"signal,omitempty"`
+	Duration  time.Duration  `json:"duration" bson:"duration"`
+	EndReason EndReason      `json:"end_reason,omitempty" bson:"end_reason,omitempty"`
+}
+
+// SignalHistoryLimit is the maximum number of events retained in
+// ProcessInfo.SignalHistory.
+const SignalHistoryLimit = 32
+
+// SignalHistoryEvent records a signal sent to a process.
+type SignalHistoryEvent struct {
+	Signal syscall.Signal `json:"signal" bson:"signal"`
+	Time   time.Time      `json:"time" bson:"time"`
+}
+
+// InfoOptions configures the information returned by Process.InfoWith.
+type InfoOptions struct {
+	// IncludeOutputTail is the maximum number of lines of recent
+	// output to include in ProcessInfo.OutputTail. The output is
+	// read from the in-memory logger attached to the process'
+	// output, if there is one. This is not supported for remote
+	// processes.
+	IncludeOutputTail int `json:"include_output_tail,omitempty" bson:"include_output_tail,omitempty"`
+}
+
+// ProcessError is returned by Wait when a process that exited with a zero
+// exit code is nonetheless classified as having failed, such as when it
+// writes to standard error and its output options treat that as an error.
+type ProcessError struct {
+	ID       string
+	ExitCode int
+	Reason   string
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("process '%s' failed with exit code %d: %s", e.ID, e.ExitCode, e.Reason)
+}
+
+// DependencyInfo reports on the state of a process that another process
+// depends on. Until Complete is set, the dependent process is waiting for the
+// dependency and the other fields are unset.
+type DependencyInfo struct {
+	ID         string    `json:"id" bson:"id"`
+	ExitCode   int       `json:"exit_code" bson:"exit_code"`
+	Complete   bool      `json:"complete" bson:"complete"`
+	Successful bool      `json:"successful" bson:"successful"`
+	EndAt      time.Time `json:"end_at" bson:"end_at"`
 }
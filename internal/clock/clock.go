@@ -0,0 +1,154 @@
+// Package clock provides an abstraction over the passage of time so that
+// time-dependent behavior, such as process timeouts, can be tested
+// deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(time.Duration) <-chan time.Time
+	// NewTimer creates a new Timer that will send the current time on its
+	// channel after at least the duration has elapsed.
+	NewTimer(time.Duration) Timer
+}
+
+// Timer is an abstraction over time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer
+	// fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer
+	// has already fired or been stopped.
+	Stop() bool
+	// Reset changes the timer to fire after the duration has elapsed. It
+	// returns true if the timer had been active.
+	Reset(time.Duration) bool
+}
+
+// New returns a Clock backed by the time package.
+func New() Clock { return realClock{} }
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{Timer: time.NewTimer(d)} }
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+// MockClock is a Clock whose time only changes when it is explicitly
+// advanced. It is safe for concurrent use.
+type MockClock struct {
+	now    time.Time
+	timers []*mockTimer
+	mu     sync.Mutex
+}
+
+// NewMockClock returns a MockClock whose current time is start.
+func NewMockClock(start time.Time) *MockClock {
+	return &MockClock{now: start}
+}
+
+// Now returns the mock clock's current time.
+func (c *MockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel that receives the mock clock's time once the clock
+// has been advanced by at least the duration.
+func (c *MockClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a Timer that fires once the mock clock has been advanced
+// by at least the duration.
+func (c *MockClock) NewTimer(d time.Duration) Timer {
+	t := &mockTimer{
+		clock: c,
+		ch:    make(chan time.Time, 1),
+	}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the mock clock's time forward by the duration, firing any
+// timers that expire.
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	active := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			active = append(active, t)
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	c.timers = active
+}
+
+// removeTimer stops tracking the timer. It returns true if the timer was
+// being tracked. The caller must hold the clock's lock.
+func (c *MockClock) removeTimer(t *mockTimer) bool {
+	for idx, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:idx], c.timers[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type mockTimer struct {
+	clock    *MockClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *mockTimer) C() <-chan time.Time { return t.ch }
+
+func (t *mockTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.removeTimer(t)
+}
+
+func (t *mockTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.removeTimer(t)
+	t.deadline = t.clock.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- t.clock.now:
+		default:
+		}
+		return active
+	}
+	t.clock.timers = append(t.clock.timers, t)
+
+	return active
+}
@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockClock(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	for testName, testCase := range map[string]func(t *testing.T, c *MockClock){
+		"NowReturnsStartTime": func(t *testing.T, c *MockClock) {
+			assert.Equal(t, start, c.Now())
+		},
+		"AdvanceMovesTimeForward": func(t *testing.T, c *MockClock) {
+			c.Advance(time.Minute)
+			assert.Equal(t, start.Add(time.Minute), c.Now())
+		},
+		"TimerFiresOnlyAfterAdvancingPastDeadline": func(t *testing.T, c *MockClock) {
+			timer := c.NewTimer(time.Minute)
+
+			c.Advance(30 * time.Second)
+			select {
+			case <-timer.C():
+				assert.Fail(t, "timer fired before deadline")
+			default:
+			}
+
+			c.Advance(30 * time.Second)
+			select {
+			case now := <-timer.C():
+				assert.Equal(t, start.Add(time.Minute), now)
+			default:
+				assert.Fail(t, "timer did not fire after deadline")
+			}
+		},
+		"AfterFiresAfterAdvancing": func(t *testing.T, c *MockClock) {
+			ch := c.After(time.Second)
+			c.Advance(time.Second)
+			select {
+			case <-ch:
+			default:
+				assert.Fail(t, "channel did not receive after deadline")
+			}
+		},
+		"StoppedTimerDoesNotFire": func(t *testing.T, c *MockClock) {
+			timer := c.NewTimer(time.Minute)
+			assert.True(t, timer.Stop())
+			assert.False(t, timer.Stop())
+
+			c.Advance(time.Hour)
+			select {
+			case <-timer.C():
+				assert.Fail(t, "stopped timer fired")
+			default:
+			}
+		},
+		"ResetExtendsDeadline": func(t *testing.T, c *MockClock) {
+			timer := c.NewTimer(time.Minute)
+			c.Advance(30 * time.Second)
+			assert.True(t, timer.Reset(time.Minute))
+
+			c.Advance(30 * time.Second)
+			select {
+			case <-timer.C():
+				assert.Fail(t, "timer fired before reset deadline")
+			default:
+			}
+
+			c.Advance(30 * time.Second)
+			select {
+			case <-timer.C():
+			default:
+				assert.Fail(t, "timer did not fire after reset deadline")
+			}
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			testCase(t, NewMockClock(start))
+		})
+	}
+}
@@ -366,6 +366,12 @@ func (e *docker) SignalInfo() (sig syscall.Signal, signaled bool) {
 	return e.signal, e.signal != -1
 }
 
+// CoreDumped always returns false because it is not known whether the process
+// in the container dumped core.
+func (e *docker) CoreDumped() bool {
+	return false
+}
+
 // Close cleans up the container associated with this process executor and
 // closes the connection to the Docker daemon.
 func (e *docker) Close() error {
@@ -48,6 +48,10 @@ type Executor interface {
 	Success() bool
 	// SignalInfo returns information about signals the process has received.
 	SignalInfo() (sig syscall.Signal, signaled bool)
+	// CoreDumped returns whether the process dumped core when it was
+	// terminated by a signal. Callers must call Wait before checking
+	// whether the process dumped core.
+	CoreDumped() bool
 	// Close cleans up the executor's resources. Users should not assume the
 	// information from the Executor will be accurate after it has been closed.
 	Close() error
@@ -3,7 +3,9 @@ package executor
 import (
 	"context"
 	"io"
+	"os"
 	"os/exec"
+	"sync"
 	"syscall"
 
 	"github.com/pkg/errors"
@@ -12,6 +14,12 @@ import (
 // local runs processes on a local machine via exec.
 type local struct {
 	cmd *exec.Cmd
+	// state is the state of the process once it has exited. The state is
+	// only read from the command when Wait returns, so that the process
+	// state can be inspected safely while another goroutine is waiting on
+	// the process.
+	state *os.ProcessState
+	mu    sync.RWMutex
 }
 
 // NewLocal returns an Executor that creates processes locally.
@@ -25,10 +33,19 @@ func NewLocal(ctx context.Context, args []string) Executor {
 	return &local{cmd: cmd}
 }
 
+// NewLocalWithAttributes is the same as NewLocal, but starts the process with
+// the given OS-specific attributes.
+func NewLocalWithAttributes(ctx context.Context, args []string, attr *syscall.SysProcAttr) Executor {
+	e := NewLocal(ctx, args).(*local)
+	e.cmd.SysProcAttr = attr
+	return e
+}
+
 // MakeLocal wraps an existing local process.
 func MakeLocal(cmd *exec.Cmd) Executor {
 	return &local{
-		cmd: cmd,
+		cmd:   cmd,
+		state: cmd.ProcessState,
 	}
 }
 
@@ -87,7 +104,13 @@ func (e *local) Start() error {
 
 // Wait returns the result for waiting for the process to finish.
 func (e *local) Wait() error {
-	return e.cmd.Wait()
+	err := e.cmd.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = e.cmd.ProcessState
+
+	return err
 }
 
 // Signal sends a signal to the process.
@@ -109,30 +132,51 @@ func (e *local) PID() int {
 // ExitCode returns the exit code of the process, or -1 if the process is not
 // finished.
 func (e *local) ExitCode() int {
-	if e.cmd.ProcessState == nil {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.state == nil {
 		return -1
 	}
-	status := e.cmd.ProcessState.Sys().(syscall.WaitStatus)
+	status := e.state.Sys().(syscall.WaitStatus)
 	return status.ExitStatus()
 }
 
 // Success returns whether or not the process ran successfully.
 func (e *local) Success() bool {
-	if e.cmd.ProcessState == nil {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.state == nil {
 		return false
 	}
-	return e.cmd.ProcessState.Success()
+	return e.state.Success()
 }
 
 // SignalInfo returns information about the signals the process has received.
 func (e *local) SignalInfo() (sig syscall.Signal, signaled bool) {
-	if e.cmd.ProcessState == nil {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.state == nil {
 		return syscall.Signal(-1), false
 	}
-	status := e.cmd.ProcessState.Sys().(syscall.WaitStatus)
+	status := e.state.Sys().(syscall.WaitStatus)
 	return status.Signal(), status.Signaled()
 }
 
+// CoreDumped returns whether the process dumped core.
+func (e *local) CoreDumped() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.state == nil {
+		return false
+	}
+	status := e.state.Sys().(syscall.WaitStatus)
+	return status.CoreDump()
+}
+
 // Close is a no-op.
 func (e *local) Close() error {
 	return nil
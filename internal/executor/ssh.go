@@ -150,6 +150,12 @@ func (e *ssh) SignalInfo() (sig syscall.Signal, signaled bool) {
 	return sshToSyscallSignal(sshSig), sshSig != ""
 }
 
+// CoreDumped always returns false because it is not known whether the remote
+// process dumped core.
+func (e *ssh) CoreDumped() bool {
+	return false
+}
+
 // Close closes the SSH connection resources.
 func (e *ssh) Close() error {
 	catcher := grip.NewBasicCatcher()
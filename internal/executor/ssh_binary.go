@@ -155,6 +155,12 @@ func (e *execSSHBinary) SignalInfo() (sig syscall.Signal, signaled bool) {
 	return status.Signal(), status.Signaled()
 }
 
+// CoreDumped always returns false because it is not known whether the remote
+// process dumped core.
+func (e *execSSHBinary) CoreDumped() bool {
+	return false
+}
+
 // Close is a no-op.
 func (e *execSSHBinary) Close() error {
 	return nil
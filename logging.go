@@ -2,6 +2,8 @@ package jasper
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,10 +24,77 @@ type LoggingCache interface {
 	CloseAndRemove(ctx context.Context, id string) error
 	// Clear closes and removes any remaining loggers in the logging cache.
 	Clear(ctx context.Context) error
+	// Drain flushes, closes and removes all loggers in the logging cache,
+	// waiting until their buffered messages have been sent or the context
+	// is done.
+	Drain(ctx context.Context) error
 	// Prune removes all loggers that were last accessed before the given
 	// timestamp.
 	Prune(lastAccessed time.Time)
 	Len() int
+	// SendToMany sends the payload to each of the loggers with the given
+	// IDs, ignoring the payload's LoggerID. The payload is validated
+	// before it is sent to any logger. If any of the loggers do not exist
+	// or fail to send the payload, it still attempts to send to every
+	// other logger and returns a *SendToManyError.
+	SendToMany(ids []string, lp *options.LoggingPayload) error
+}
+
+// SendToManyError is returned by LoggingCache.SendToMany when the payload
+// could not be sent to all of the loggers.
+type SendToManyError struct {
+	// Missing contains the IDs of the loggers that do not exist.
+	Missing []string
+	// Failed maps the IDs of the loggers that exist to the error sending
+	// the payload to them.
+	Failed map[string]error
+}
+
+func (e *SendToManyError) Error() string {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(len(e.Missing) != 0, "loggers do not exist: %s", strings.Join(e.Missing, ", "))
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		catcher.Wrapf(e.Failed[id], "problem sending to logger '%s'", id)
+	}
+	return catcher.Resolve().Error()
+}
+
+// SendToMany is a helper for implementing LoggingCache.SendToMany. After
+// validating the payload, it uses the cache's Get method to check that each
+// logger exists and calls sendTo with the logger and a copy of the payload
+// whose LoggerID is the ID of the logger.
+func SendToMany(cache LoggingCache, ids []string, lp *options.LoggingPayload, sendTo func(*options.CachedLogger, *options.LoggingPayload) error) error {
+	if lp == nil {
+		return errors.New("cannot send nil logging payload")
+	}
+	if err := lp.Validate(); err != nil {
+		return errors.Wrap(err, "invalid logging payload")
+	}
+
+	sendErr := &SendToManyError{Failed: map[string]error{}}
+	for _, id := range ids {
+		logger := cache.Get(id)
+		if logger == nil {
+			sendErr.Missing = append(sendErr.Missing, id)
+			continue
+		}
+
+		loggerPayload := *lp
+		loggerPayload.LoggerID = id
+		if err := sendTo(logger, &loggerPayload); err != nil {
+			sendErr.Failed[id] = err
+		}
+	}
+
+	if len(sendErr.Missing) == 0 && len(sendErr.Failed) == 0 {
+		return nil
+	}
+	return sendErr
 }
 
 // NewLoggingCache produces a thread-safe implementation of a logging
@@ -73,6 +142,27 @@ func (c *loggingCacheImpl) Prune(ts time.Time) {
 	}
 }
 
+// closeAndPrune closes and removes the loggers that were last accessed before
+// the given timestamp, except for those with the given IDs, and returns the
+// number of loggers removed.
+func (c *loggingCacheImpl) closeAndPrune(ts time.Time, keep map[string]struct{}) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	catcher := grip.NewBasicCatcher()
+	var pruned int
+	for id, logger := range c.cache {
+		if _, ok := keep[id]; ok || !logger.Accessed.Before(ts) {
+			continue
+		}
+		catcher.Wrapf(logger.Close(), "problem closing logger with id %s", id)
+		delete(c.cache, id)
+		pruned++
+	}
+
+	return pruned, catcher.Resolve()
+}
+
 func (c *loggingCacheImpl) Get(id string) *options.CachedLogger {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -101,11 +191,21 @@ func (c *loggingCacheImpl) Put(id string, logger *options.CachedLogger) error {
 
 	logger.Accessed = time.Now()
 
+	if logger.Fallback != nil {
+		logger.SetFallback(logger.Fallback)
+	}
+
 	c.cache[id] = logger
 
 	return nil
 }
 
+func (c *loggingCacheImpl) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return SendToMany(c, ids, lp, func(logger *options.CachedLogger, lp *options.LoggingPayload) error {
+		return logger.Send(lp)
+	})
+}
+
 func (c *loggingCacheImpl) Remove(id string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -139,3 +239,30 @@ func (c *loggingCacheImpl) Clear(_ context.Context) error {
 
 	return errors.Wrap(catcher.Resolve(), "problem clearing logger cache")
 }
+
+func (c *loggingCacheImpl) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	loggers := c.cache
+	c.cache = map[string]*options.CachedLogger{}
+	c.mu.Unlock()
+
+	// Senders may block while sending their buffered messages, so the
+	// loggers are drained in the background in case the context is done
+	// first.
+	drained := make(chan error, 1)
+	go func() {
+		catcher := grip.NewBasicCatcher()
+		for id, logger := range loggers {
+			catcher.Wrapf(logger.Flush(ctx), "problem flushing logger with id %s", id)
+			catcher.Wrapf(logger.Close(), "problem closing logger with id %s", id)
+		}
+		drained <- catcher.Resolve()
+	}()
+
+	select {
+	case err := <-drained:
+		return errors.Wrap(err, "problem draining logger cache")
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "logger cache did not finish draining")
+	}
+}
@@ -5,11 +5,27 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
 	"github.com/tychoish/jasper/options"
 )
 
+// blockingFlushSender is a sender whose Flush blocks until unblock is
+// closed, regardless of the context.
+type blockingFlushSender struct {
+	*options.MockSender
+	unblock chan struct{}
+}
+
+func (s *blockingFlushSender) Flush(_ context.Context) error {
+	<-s.unblock
+	return nil
+}
+
 func TestLogging(t *testing.T) {
 	for _, test := range []struct {
 		Name string
@@ -41,6 +57,21 @@ func TestLogging(t *testing.T) {
 				assert.Equal(t, "id", lg.ID)
 			},
 		},
+		{
+			Name: "PutInstallsFallback",
+			Case: func(t *testing.T, cache LoggingCache) {
+				primary := options.NewMockSender("primary")
+				primary.FailSend = true
+				fallback := send.MakeInternalLogger()
+				require.NoError(t, cache.Put("id", &options.CachedLogger{ID: "id", Output: primary, Fallback: fallback}))
+
+				lg := cache.Get("id")
+				require.NotNil(t, lg)
+				require.NoError(t, lg.Send(&options.LoggingPayload{Data: "hello", Priority: level.Info}))
+				assert.EqualValues(t, 1, lg.Failovers())
+				assert.Equal(t, 1, fallback.Len())
+			},
+		},
 		{
 			Name: "PutDuplicate",
 			Case: func(t *testing.T, cache LoggingCache) {
@@ -139,6 +170,124 @@ func TestLogging(t *testing.T) {
 				assert.Nil(t, cache.Get("id1"))
 			},
 		},
+		{
+			Name: "Drain",
+			Case: func(t *testing.T, cache LoggingCache) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				sender, err := send.NewInMemorySender("output", send.LevelInfo{Default: level.Info, Threshold: level.Info}, 10)
+				require.NoError(t, err)
+				buffered := send.NewBufferedSender(sender, time.Hour, 100)
+				require.NoError(t, cache.Put("id", &options.CachedLogger{Output: buffered}))
+
+				buffered.Send(message.ConvertToComposer(level.Info, "foo"))
+				buffered.Send(message.ConvertToComposer(level.Info, "bar"))
+				assert.Empty(t, sender.(*send.InMemorySender).Get())
+
+				require.NoError(t, cache.Drain(ctx))
+				assert.Equal(t, 0, cache.Len())
+				msgs, err := sender.(*send.InMemorySender).GetString()
+				require.NoError(t, err)
+				require.Len(t, msgs, 1)
+				assert.Contains(t, msgs[0], "foo")
+				assert.Contains(t, msgs[0], "bar")
+			},
+		},
+		{
+			Name: "DrainStopsAtContextDeadline",
+			Case: func(t *testing.T, cache LoggingCache) {
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+
+				sender := &blockingFlushSender{
+					MockSender: options.NewMockSender("output"),
+					unblock:    make(chan struct{}),
+				}
+				defer close(sender.unblock)
+				require.NoError(t, cache.Put("id", &options.CachedLogger{Output: sender}))
+
+				err := cache.Drain(ctx)
+				require.Error(t, err)
+				assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+				assert.Equal(t, 0, cache.Len())
+			},
+		},
+		{
+			Name: "SendToMany",
+			Case: func(t *testing.T, cache LoggingCache) {
+				senders := map[string]*send.InMemorySender{}
+				for _, id := range []string{"id0", "id1"} {
+					sender, err := send.NewInMemorySender(id, send.LevelInfo{Default: level.Info, Threshold: level.Info}, 10)
+					require.NoError(t, err)
+					senders[id] = sender.(*send.InMemorySender)
+					require.NoError(t, cache.Put(id, &options.CachedLogger{ID: id, Output: sender}))
+				}
+
+				err := cache.SendToMany([]string{"id0", "missing", "id1"}, &options.LoggingPayload{
+					LoggerID: "ignored",
+					Data:     "foo",
+					Priority: level.Info,
+				})
+				require.Error(t, err)
+				sendErr, ok := err.(*SendToManyError)
+				require.True(t, ok)
+				assert.Equal(t, []string{"missing"}, sendErr.Missing)
+				assert.Empty(t, sendErr.Failed)
+				assert.Contains(t, err.Error(), "missing")
+
+				for id, sender := range senders {
+					msgs, err := sender.GetString()
+					require.NoError(t, err)
+					require.Len(t, msgs, 1, id)
+					assert.Contains(t, msgs[0], "foo")
+				}
+			},
+		},
+		{
+			Name: "SendToManyReportsSendFailures",
+			Case: func(t *testing.T, cache LoggingCache) {
+				sender, err := send.NewInMemorySender("id0", send.LevelInfo{Default: level.Info, Threshold: level.Info}, 10)
+				require.NoError(t, err)
+				require.NoError(t, cache.Put("id0", &options.CachedLogger{ID: "id0", Output: sender}))
+				require.NoError(t, cache.Put("id1", &options.CachedLogger{ID: "id1"}))
+
+				err = cache.SendToMany([]string{"id0", "id1", "missing"}, &options.LoggingPayload{
+					Data:     "foo",
+					Priority: level.Info,
+				})
+				require.Error(t, err)
+				sendErr, ok := err.(*SendToManyError)
+				require.True(t, ok)
+				assert.Equal(t, []string{"missing"}, sendErr.Missing)
+				require.Len(t, sendErr.Failed, 1)
+				assert.Error(t, sendErr.Failed["id1"])
+				assert.Len(t, sender.(*send.InMemorySender).Get(), 1)
+			},
+		},
+		{
+			Name: "SendToManyValidatesPayload",
+			Case: func(t *testing.T, cache LoggingCache) {
+				sender, err := send.NewInMemorySender("id0", send.LevelInfo{Default: level.Info, Threshold: level.Info}, 10)
+				require.NoError(t, err)
+				require.NoError(t, cache.Put("id0", &options.CachedLogger{ID: "id0", Output: sender}))
+
+				err = cache.SendToMany([]string{"id0", "missing"}, &options.LoggingPayload{})
+				require.Error(t, err)
+				_, ok := err.(*SendToManyError)
+				assert.False(t, ok)
+				assert.Error(t, cache.SendToMany([]string{"id0"}, nil))
+				assert.Empty(t, sender.(*send.InMemorySender).Get())
+			},
+		},
+		{
+			Name: "SendToManyAllExist",
+			Case: func(t *testing.T, cache LoggingCache) {
+				require.NoError(t, cache.Put("id0", &options.CachedLogger{ID: "id0", Output: options.NewMockSender("id0")}))
+				assert.NoError(t, cache.SendToMany([]string{"id0"}, &options.LoggingPayload{Data: "foo", Priority: level.Info}))
+				assert.NoError(t, cache.SendToMany(nil, &options.LoggingPayload{Data: "foo", Priority: level.Info}))
+			},
+		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			require.NotPanics(t, func() {
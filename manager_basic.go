@@ -2,6 +2,7 @@ package jasper
 
 import (
 	"context"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,19 @@ type basicProcessManager struct {
 	useSSHLibrary bool
 	tracker       ProcessTracker
 	loggers       LoggingCache
+	closed        bool
+	events        processEventPublisher
+	tagLimits     *tagLimiter
+	// restartHook, if set, is used instead of restartProcess to restart
+	// processes that should always restart. This allows managers that
+	// wrap this one to restart processes safely.
+	restartHook func(context.Context, *options.Create, *restartHistory) (Process, error)
+	// watchCtx is canceled by stopWatching when the manager closes, which
+	// stops the goroutines that watch the manager's processes, such as
+	// to restart them when their watched paths change or to run their
+	// health checks.
+	watchCtx     context.Context
+	stopWatching context.CancelFunc
 }
 
 // newBasicProcessManager returns a manager which is not thread safe for
@@ -32,6 +46,7 @@ func newBasicProcessManager(procs map[string]Process, trackProcs bool, useSSHLib
 		id:            uuid.New().String(),
 		useSSHLibrary: useSSHLibrary,
 		loggers:       NewLoggingCache(),
+		tagLimits:     newTagLimiter(),
 	}
 	if trackProcs {
 		tracker, err := NewProcessTracker(m.id)
@@ -48,16 +63,73 @@ func (m *basicProcessManager) ID() string {
 }
 
 func (m *basicProcessManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	// Create the process from a copy of the options so that callers can
+	// reuse the same options to create multiple processes. The closers
+	// registered on the original options still run when the process
+	// completes.
+	optsCopy := opts.Copy()
+	optsCopy.RegisterCloser(opts.Close)
+
+	release, err := m.tagLimits.acquire(ctx, optsCopy.Tags)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	proc, err := m.createProcess(ctx, optsCopy, nil)
+	if err != nil {
+		release()
+		return nil, errors.WithStack(err)
+	}
+	releaseOnExit(ctx, proc, release)
+
+	return proc, nil
+}
+
+func (m *basicProcessManager) createProcess(ctx context.Context, opts *options.Create, restarts *restartHistory) (Process, error) {
 	opts.AddEnvVar(ManagerEnvironID, m.id)
 
 	if opts.Remote != nil && m.useSSHLibrary {
 		opts.Remote.UseSSHLibrary = true
 	}
 
+	// Restarted processes replace the process with their ID, so only new
+	// processes can conflict with existing ones.
+	if _, ok := m.procs[opts.ID]; ok && opts.ID != "" && restarts == nil {
+		return nil, errors.Errorf("process with ID '%s' already exists", opts.ID)
+	}
+
+	deps := make([]Process, 0, len(opts.DependsOn))
+	for _, id := range opts.DependsOn {
+		dep, ok := m.procs[id]
+		if !ok {
+			return nil, errors.Errorf("dependency '%s' does not exist", id)
+		}
+		deps = append(deps, dep)
+	}
+
+	// Processes with dependencies are created without being started,
+	// and are started once their dependencies complete.
+	startAfterDeps := !opts.DeferStart
+	if len(deps) != 0 {
+		opts.DeferStart = true
+	}
+
 	proc, err := NewProcess(ctx, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "problem constructing process")
 	}
+	if len(deps) != 0 {
+		dependent, ok := proc.(dependentProcess)
+		if !ok {
+			return nil, errors.Errorf("process '%s' does not support dependencies", proc.ID())
+		}
+		dependent.setDependencies(pendingDependencies(deps))
+		depCtx, cancel := m.watchContext(ctx)
+		go func() {
+			defer cancel()
+			startAfterDependencies(depCtx, dependent, deps, startAfterDeps)
+		}()
+	}
 
 	grip.Warning(message.WrapError(m.loggers.Put(proc.ID(), &options.CachedLogger{
 		ID:      proc.ID(),
@@ -75,6 +147,34 @@ func (m *basicProcessManager) CreateProcess(ctx context.Context, opts *options.C
 	// as a closer to CreateOptions.
 	_ = proc.RegisterTrigger(ctx, makeDefaultTrigger(ctx, m, opts, proc.ID()))
 
+	restartOnUnhealthy := opts.HealthCheck != nil && opts.HealthCheck.RestartOnFailure
+	var restart func(context.Context, *options.Create) (Process, error)
+	if opts.RestartAlways || len(opts.WatchPaths) != 0 || restartOnUnhealthy {
+		if restarts == nil {
+			restarts = newRestartHistory(opts)
+		}
+		restart = func(ctx context.Context, opts *options.Create) (Process, error) {
+			if m.restartHook != nil {
+				return m.restartHook(ctx, opts, restarts)
+			}
+			return m.restartProcess(ctx, opts, restarts)
+		}
+
+		if opts.RestartAlways {
+			_ = proc.RegisterTrigger(ctx, makeRestartTrigger(ctx, opts, proc.ID(), restarts, restart))
+		}
+		if len(opts.WatchPaths) != 0 {
+			m.watchPaths(ctx, proc, opts, restart)
+		}
+	}
+	if opts.HealthCheck != nil {
+		healthRestart := restart
+		if restartOnUnhealthy && opts.RestartBackoff != nil {
+			healthRestart = restarts.withBackoff(proc, restart)
+		}
+		m.checkHealth(ctx, proc, opts, healthRestart)
+	}
+
 	if m.tracker != nil {
 		// The process may have terminated already, so don't return on error.
 		if err := m.tracker.Add(proc.Info(ctx)); err != nil {
@@ -83,10 +183,77 @@ func (m *basicProcessManager) CreateProcess(ctx context.Context, opts *options.C
 	}
 
 	m.procs[proc.ID()] = proc
+	m.events.watchProcess(ctx, proc)
 
 	return proc, nil
 }
 
+// watchPaths restarts the process using the restart function when the paths
+// that it watches change, until the process completes or the manager is
+// closed.
+func (m *basicProcessManager) watchPaths(ctx context.Context, proc Process, opts *options.Create, restart func(context.Context, *options.Create) (Process, error)) {
+	// Record the initial state of the paths before returning so that
+	// changes made after the process is created are always detected.
+	states := statPaths(opts.WatchPaths)
+	watchCtx, cancel := m.watchContext(ctx)
+	go func() {
+		defer cancel()
+		// The restarted process must not be bound to the watcher's
+		// context, which is canceled once the watcher returns.
+		watchForRestart(watchCtx, proc, opts, states, func(opts *options.Create) (Process, error) {
+			return restart(ctx, opts)
+		})
+	}()
+}
+
+// checkHealth runs the process's health check until the process completes or
+// the manager is closed. If the health check restarts the process, the
+// restart function is used to replace it.
+func (m *basicProcessManager) checkHealth(ctx context.Context, proc Process, opts *options.Create, restart func(context.Context, *options.Create) (Process, error)) {
+	watchCtx, cancel := m.watchContext(ctx)
+	go func() {
+		defer cancel()
+		runHealthCheck(watchCtx, proc, opts, func(opts *options.Create) (Process, error) {
+			return restart(ctx, opts)
+		})
+	}()
+}
+
+// watchContext returns a context for a goroutine that watches one of the
+// manager's processes, which is canceled when either the given context is
+// done or the manager closes.
+func (m *basicProcessManager) watchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.stopWatching == nil {
+		m.watchCtx, m.stopWatching = context.WithCancel(context.Background())
+	}
+
+	managerCtx := m.watchCtx
+	watchCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-managerCtx.Done():
+			cancel()
+		case <-watchCtx.Done():
+		}
+	}()
+
+	return watchCtx, cancel
+}
+
+// restartProcess creates a process to replace one that should always
+// restart, unless the manager is closed or the process has exceeded its
+// restart limit.
+func (m *basicProcessManager) restartProcess(ctx context.Context, opts *options.Create, restarts *restartHistory) (Process, error) {
+	if m.closed {
+		return nil, errors.New("cannot restart process after manager is closed")
+	}
+	if !restarts.record(time.Now()) {
+		return nil, errors.Errorf("process exceeded restart limit of %d restarts in %s", restarts.limit, restarts.interval)
+	}
+
+	return m.createProcess(ctx, opts, restarts)
+}
+
 func (m *basicProcessManager) LoggingCache(_ context.Context) LoggingCache { return m.loggers }
 
 func (m *basicProcessManager) CreateCommand(ctx context.Context) *Command {
@@ -123,6 +290,19 @@ func (m *basicProcessManager) Register(ctx context.Context, proc Process) error
 	return nil
 }
 
+func (m *basicProcessManager) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (Process, error) {
+	proc, err := newExternalProcess(ctx, pid, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem importing external process")
+	}
+
+	if err = m.Register(ctx, proc); err != nil {
+		return nil, errors.Wrap(err, "problem registering external process")
+	}
+
+	return proc, nil
+}
+
 func (m *basicProcessManager) List(ctx context.Context, f options.Filter) ([]Process, error) {
 	out := []Process{}
 
@@ -166,7 +346,7 @@ func (m *basicProcessManager) List(ctx context.Context, f options.Filter) ([]Pro
 func (m *basicProcessManager) Get(ctx context.Context, id string) (Process, error) {
 	proc, ok := m.procs[id]
 	if !ok {
-		return nil, errors.Errorf("process '%s' does not exist", id)
+		return nil, errors.Wrapf(ErrProcessNotFound, "process '%s'", id)
 	}
 
 	return proc, nil
@@ -182,6 +362,11 @@ func (m *basicProcessManager) Clear(ctx context.Context) {
 }
 
 func (m *basicProcessManager) Close(ctx context.Context) error {
+	m.closed = true
+	if m.stopWatching != nil {
+		m.stopWatching()
+	}
+
 	if len(m.procs) == 0 {
 		return nil
 	}
@@ -190,6 +375,12 @@ func (m *basicProcessManager) Close(ctx context.Context) error {
 		return errors.WithStack(err)
 	}
 
+	for _, proc := range procs {
+		if abortable, ok := proc.(abortableProcess); ok {
+			abortable.markAborted()
+		}
+	}
+
 	termCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -229,6 +420,64 @@ func (m *basicProcessManager) Group(ctx context.Context, name string) ([]Process
 	return out, nil
 }
 
+func (m *basicProcessManager) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return SignalGroup(ctx, m, tag, sig)
+}
+
+func (m *basicProcessManager) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return WaitForTag(ctx, m, tag)
+}
+
+func (m *basicProcessManager) SetTagLimit(_ context.Context, tag string, max int) error {
+	if m.tagLimits == nil {
+		m.tagLimits = newTagLimiter()
+	}
+	return m.tagLimits.setLimit(tag, max)
+}
+
+func (m *basicProcessManager) Stats(ctx context.Context) ManagerStats {
+	procs := make([]Process, 0, len(m.procs))
+	for _, proc := range m.procs {
+		procs = append(procs, proc)
+	}
+
+	return NewManagerStats(ctx, procs)
+}
+
+func (m *basicProcessManager) Subscribe(ctx context.Context) <-chan ProcessEvent {
+	return m.events.subscribe(ctx)
+}
+
+func (m *basicProcessManager) Snapshot(ctx context.Context) ([]byte, error) {
+	procs := make([]Process, 0, len(m.procs))
+	for _, proc := range m.procs {
+		procs = append(procs, proc)
+	}
+
+	return snapshotProcesses(ctx, m.id, procs)
+}
+
+func (m *basicProcessManager) PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, errors.New("cannot prune loggers with a negative age")
+	}
+
+	cache, ok := m.loggers.(*loggingCacheImpl)
+	if !ok {
+		return 0, errors.Errorf("cannot prune loggers in logging cache of type %T", m.loggers)
+	}
+
+	running := map[string]struct{}{}
+	for id, proc := range m.procs {
+		if proc.Running(ctx) {
+			running[id] = struct{}{}
+		}
+	}
+
+	pruned, err := cache.closeAndPrune(time.Now().Add(-olderThan), running)
+	return pruned, errors.Wrap(err, "problem pruning loggers")
+}
+
 func (m *basicProcessManager) WriteFile(ctx context.Context, opts options.WriteFile) error {
 	if err := opts.Validate(); err != nil {
 		return errors.Wrap(err, "invalid write options")
@@ -0,0 +1,55 @@
+package jasper
+
+import (
+	"context"
+
+	"github.com/tychoish/jasper/options"
+)
+
+type capturingManager struct {
+	Manager
+	output options.Output
+}
+
+// NewCapturingManager returns a manager that wraps an existing manager, but
+// merges the given default output options into the options of every process
+// that it creates, so that, for example, every process can have an in-memory
+// logger from which its output can be read. Output configured for a process
+// takes precedence: the default loggers are only used if the process has no
+// loggers, and the default output and error writers are only used if the
+// process has none.
+func NewCapturingManager(m Manager, output options.Output) Manager {
+	return &capturingManager{
+		Manager: m,
+		output:  *output.Copy(),
+	}
+}
+
+func (m *capturingManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	optsCopy := opts.Copy()
+	optsCopy.RegisterCloser(opts.Close)
+	m.mergeOutput(&optsCopy.Output)
+	return m.Manager.CreateProcess(ctx, optsCopy)
+}
+
+func (m *capturingManager) CreateCommand(ctx context.Context) *Command {
+	return m.Manager.CreateCommand(ctx).ProcConstructor(m.CreateProcess)
+}
+
+// mergeOutput fills in the output options that are not set with the
+// manager's defaults. Each process gets its own copy of the default loggers,
+// since loggers are closed when the process that uses them exits.
+func (m *capturingManager) mergeOutput(output *options.Output) {
+	if len(output.Loggers) == 0 && len(m.output.Loggers) != 0 {
+		output.Loggers = make([]*options.LoggerConfig, 0, len(m.output.Loggers))
+		for _, logger := range m.output.Loggers {
+			output.Loggers = append(output.Loggers, logger.Copy())
+		}
+	}
+	if output.Output == nil {
+		output.Output = m.output.Output
+	}
+	if output.Error == nil {
+		output.Error = m.output.Error
+	}
+}
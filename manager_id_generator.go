@@ -0,0 +1,45 @@
+package jasper
+
+import (
+	"context"
+
+	"github.com/tychoish/jasper/options"
+)
+
+// IDGenerator returns the ID for a new process. IDs must be unique within
+// the manager that creates the process.
+type IDGenerator func() string
+
+type idGeneratorMgr struct {
+	Manager
+	generate IDGenerator
+}
+
+// NewIDGeneratorManager wraps an existing manager so that processes are
+// created with IDs from the given generator unless their options already
+// specify an ID. If the generator is nil, the manager is returned
+// unchanged, so processes have randomly generated IDs.
+func NewIDGeneratorManager(m Manager, generate IDGenerator) Manager {
+	if generate == nil {
+		return m
+	}
+	return &idGeneratorMgr{
+		Manager:  m,
+		generate: generate,
+	}
+}
+
+func (m *idGeneratorMgr) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	if opts.ID != "" {
+		return m.Manager.CreateProcess(ctx, opts)
+	}
+
+	optsCopy := opts.Copy()
+	optsCopy.RegisterCloser(opts.Close)
+	optsCopy.ID = m.generate()
+	return m.Manager.CreateProcess(ctx, optsCopy)
+}
+
+func (m *idGeneratorMgr) CreateCommand(ctx context.Context) *Command {
+	return m.Manager.CreateCommand(ctx).ProcConstructor(m.CreateProcess)
+}
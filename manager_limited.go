@@ -0,0 +1,57 @@
+package jasper
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/jasper/options"
+)
+
+type limitedManager struct {
+	Manager
+	slots chan struct{}
+}
+
+// NewLimitedManager returns a manager that wraps an existing manager, but
+// limits the number of processes that it creates which can run
+// concurrently. When the limit is reached, CreateProcess blocks until one of
+// the running processes completes or the context is done. Commands created
+// by the manager are subject to the same limit, unless they are run by a
+// remote manager. If maxConcurrent is not positive, the manager is returned
+// unchanged.
+func NewLimitedManager(m Manager, maxConcurrent int) Manager {
+	if maxConcurrent <= 0 {
+		return m
+	}
+
+	return &limitedManager{
+		Manager: m,
+		slots:   make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (m *limitedManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	select {
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "context done while waiting for a process slot")
+	case m.slots <- struct{}{}:
+	}
+
+	once := &sync.Once{}
+	release := func() { once.Do(func() { <-m.slots }) }
+
+	proc, err := m.Manager.CreateProcess(ctx, opts)
+	if err != nil {
+		release()
+		return nil, errors.WithStack(err)
+	}
+
+	releaseOnExit(ctx, proc, release)
+
+	return proc, nil
+}
+
+func (m *limitedManager) CreateCommand(ctx context.Context) *Command {
+	return m.Manager.CreateCommand(ctx).ProcConstructor(m.CreateProcess)
+}
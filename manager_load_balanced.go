@@ -0,0 +1,439 @@
+package jasper
+
+import (
+	"context"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/jasper/options"
+)
+
+// LoadBalancingStrategy determines which backend of a load-balanced manager
+// creates each process.
+type LoadBalancingStrategy string
+
+const (
+	// LoadBalanceRoundRobin creates processes on each backend in turn.
+	LoadBalanceRoundRobin LoadBalancingStrategy = "round-robin"
+	// LoadBalanceLeastRunning creates each process on the backend that
+	// is running the fewest processes according to its Stats, counting
+	// the processes that the backend is still creating. Ties are broken
+	// in favor of the backend that was specified first.
+	LoadBalanceLeastRunning LoadBalancingStrategy = "least-running"
+)
+
+// Validate checks that the load balancing strategy is recognized.
+func (s LoadBalancingStrategy) Validate() error {
+	switch s {
+	case LoadBalanceRoundRobin, LoadBalanceLeastRunning:
+		return nil
+	default:
+		return errors.Errorf("unrecognized load balancing strategy '%s'", s)
+	}
+}
+
+type loadBalancedManager struct {
+	id       string
+	backends []Manager
+	strategy LoadBalancingStrategy
+	mu       sync.Mutex
+	next     int
+	// inFlight is the number of processes that each backend has been
+	// picked to run, but that are not yet reflected in its Stats.
+	inFlight []int
+}
+
+// NewLoadBalancedManager returns a manager that distributes the processes
+// that it creates across the backends according to the strategy, such as
+// remote managers that each run processes on a different host. The backends
+// must be thread-safe.
+//
+// Processes that are registered are also assigned to a backend according to
+// the strategy. Queries, such as List and Get, and operations on groups of
+// processes are performed on every backend and their results are combined.
+// Tag limits and files are set on every backend, so each backend enforces
+// tag limits independently. Snapshots are not supported.
+func NewLoadBalancedManager(backends []Manager, strategy LoadBalancingStrategy) (Manager, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("must specify at least one backend")
+	}
+	for idx, backend := range backends {
+		if backend == nil {
+			return nil, errors.Errorf("backend %d cannot be nil", idx)
+		}
+	}
+	if err := strategy.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &loadBalancedManager{
+		id:       uuid.New().String(),
+		backends: append([]Manager{}, backends...),
+		strategy: strategy,
+		inFlight: make([]int, len(backends)),
+	}, nil
+}
+
+// pick returns the backend that should run the next process, along with a
+// function that must be called once the backend has created the process.
+// Backends are picked by the number of processes that they are running plus
+// the number that they have been picked to run but have not yet created, so
+// that concurrent calls do not all pick the same backend.
+func (m *loadBalancedManager) pick(ctx context.Context) (Manager, func()) {
+	if m.strategy != LoadBalanceLeastRunning {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		picked := m.backends[m.next]
+		m.next = (m.next + 1) % len(m.backends)
+		return picked, func() {}
+	}
+
+	// Getting the stats may require a call to a remote backend, so it is
+	// done without holding the lock.
+	running := make([]int, len(m.backends))
+	for idx, backend := range m.backends {
+		running[idx] = backend.Stats(ctx).Running
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	picked := 0
+	for idx := range m.backends {
+		if running[idx]+m.inFlight[idx] < running[picked]+m.inFlight[picked] {
+			picked = idx
+		}
+	}
+	m.inFlight[picked]++
+
+	return m.backends[picked], func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.inFlight[picked]--
+	}
+}
+
+func (m *loadBalancedManager) ID() string {
+	return m.id
+}
+
+func (m *loadBalancedManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	backend, done := m.pick(ctx)
+	defer done()
+
+	proc, err := backend.CreateProcess(ctx, opts)
+	return proc, errors.WithStack(err)
+}
+
+func (m *loadBalancedManager) CreateCommand(ctx context.Context) *Command {
+	return NewCommand().ProcConstructor(m.CreateProcess)
+}
+
+func (m *loadBalancedManager) Register(ctx context.Context, proc Process) error {
+	backend, done := m.pick(ctx)
+	defer done()
+
+	return errors.WithStack(backend.Register(ctx, proc))
+}
+
+func (m *loadBalancedManager) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (Process, error) {
+	backend, done := m.pick(ctx)
+	defer done()
+
+	proc, err := backend.RegisterExternal(ctx, pid, opts)
+	return proc, errors.WithStack(err)
+}
+
+func (m *loadBalancedManager) List(ctx context.Context, f options.Filter) ([]Process, error) {
+	out := []Process{}
+	for idx, backend := range m.backends {
+		procs, err := backend.List(ctx, f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem listing processes on backend %d", idx)
+		}
+		out = append(out, procs...)
+	}
+
+	return out, nil
+}
+
+func (m *loadBalancedManager) Group(ctx context.Context, tag string) ([]Process, error) {
+	out := []Process{}
+	for idx, backend := range m.backends {
+		procs, err := backend.Group(ctx, tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem getting group on backend %d", idx)
+		}
+		out = append(out, procs...)
+	}
+
+	return out, nil
+}
+
+func (m *loadBalancedManager) Get(ctx context.Context, id string) (Process, error) {
+	var lastErr error
+	for idx, backend := range m.backends {
+		proc, err := backend.Get(ctx, id)
+		if err == nil {
+			return proc, nil
+		}
+		if errors.Cause(err) != ErrProcessNotFound {
+			lastErr = errors.Wrapf(err, "problem getting process '%s' on backend %d", id, idx)
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+
+	return nil, errors.Wrapf(ErrProcessNotFound, "process '%s' on any backend", id)
+}
+
+func (m *loadBalancedManager) Clear(ctx context.Context) {
+	for _, backend := range m.backends {
+		backend.Clear(ctx)
+	}
+}
+
+func (m *loadBalancedManager) Close(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	for idx, backend := range m.backends {
+		catcher.Wrapf(backend.Close(ctx), "problem closing backend %d", idx)
+	}
+
+	return catcher.Resolve()
+}
+
+func (m *loadBalancedManager) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	catcher := grip.NewBasicCatcher()
+	for idx, backend := range m.backends {
+		catcher.Wrapf(backend.SignalGroup(ctx, tag, sig), "problem signaling group on backend %d", idx)
+	}
+
+	return catcher.Resolve()
+}
+
+func (m *loadBalancedManager) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	results := map[string]error{}
+	for idx, backend := range m.backends {
+		backendResults, err := backend.WaitForTag(ctx, tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem waiting for tag on backend %d", idx)
+		}
+		for id, err := range backendResults {
+			results[id] = err
+		}
+	}
+
+	return results, nil
+}
+
+func (m *loadBalancedManager) SetTagLimit(ctx context.Context, tag string, max int) error {
+	catcher := grip.NewBasicCatcher()
+	for idx, backend := range m.backends {
+		catcher.Wrapf(backend.SetTagLimit(ctx, tag, max), "problem setting tag limit on backend %d", idx)
+	}
+
+	return catcher.Resolve()
+}
+
+// LoggingCache returns a logging cache that finds loggers in the logging
+// caches of every backend. New loggers are added to the logging cache of the
+// first backend that has one.
+func (m *loadBalancedManager) LoggingCache(ctx context.Context) LoggingCache {
+	caches := []LoggingCache{}
+	for _, backend := range m.backends {
+		if cache := backend.LoggingCache(ctx); cache != nil {
+			caches = append(caches, cache)
+		}
+	}
+	if len(caches) == 0 {
+		return nil
+	}
+
+	return loadBalancedLoggingCache(caches)
+}
+
+func (m *loadBalancedManager) WriteFile(ctx context.Context, opts options.WriteFile) error {
+	catcher := grip.NewBasicCatcher()
+	for idx, backend := range m.backends {
+		catcher.Wrapf(backend.WriteFile(ctx, opts), "problem writing file on backend %d", idx)
+	}
+
+	return catcher.Resolve()
+}
+
+func (m *loadBalancedManager) Stats(ctx context.Context) ManagerStats {
+	stats := ManagerStats{}
+	for _, backend := range m.backends {
+		backendStats := backend.Stats(ctx)
+		stats.Created += backendStats.Created
+		stats.Running += backendStats.Running
+		stats.Completed += backendStats.Completed
+		stats.Failed += backendStats.Failed
+	}
+
+	return stats
+}
+
+func (m *loadBalancedManager) Subscribe(ctx context.Context) <-chan ProcessEvent {
+	events := make(chan ProcessEvent, ProcessEventBufferSize)
+	wg := &sync.WaitGroup{}
+	for _, backend := range m.backends {
+		wg.Add(1)
+		go func(backendEvents <-chan ProcessEvent) {
+			defer wg.Done()
+			for event := range backendEvents {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+				}
+			}
+		}(backend.Subscribe(ctx))
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events
+}
+
+func (m *loadBalancedManager) Snapshot(ctx context.Context) ([]byte, error) {
+	return nil, errors.New("snapshots are not supported by the load-balanced manager")
+}
+
+func (m *loadBalancedManager) PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error) {
+	catcher := grip.NewBasicCatcher()
+	pruned := 0
+	for idx, backend := range m.backends {
+		n, err := backend.PruneLoggers(ctx, olderThan)
+		catcher.Wrapf(err, "problem pruning loggers on backend %d", idx)
+		pruned += n
+	}
+
+	return pruned, catcher.Resolve()
+}
+
+// loadBalancedLoggingCache combines the logging caches of the backends of a
+// load-balanced manager.
+type loadBalancedLoggingCache []LoggingCache
+
+func (c loadBalancedLoggingCache) Create(id string, opts *options.Output) (*options.CachedLogger, error) {
+	return c[0].Create(id, opts)
+}
+
+func (c loadBalancedLoggingCache) Put(id string, logger *options.CachedLogger) error {
+	return c[0].Put(id, logger)
+}
+
+func (c loadBalancedLoggingCache) Get(id string) *options.CachedLogger {
+	for _, cache := range c {
+		if logger := cache.Get(id); logger != nil {
+			return logger
+		}
+	}
+	return nil
+}
+
+func (c loadBalancedLoggingCache) Remove(id string) {
+	for _, cache := range c {
+		cache.Remove(id)
+	}
+}
+
+func (c loadBalancedLoggingCache) CloseAndRemove(ctx context.Context, id string) error {
+	for _, cache := range c {
+		if cache.Get(id) != nil {
+			return cache.CloseAndRemove(ctx, id)
+		}
+	}
+	return nil
+}
+
+// SendToMany sends the payload to each logger through the logging cache of
+// the backend that has it.
+func (c loadBalancedLoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	if lp == nil {
+		return errors.New("cannot send nil logging payload")
+	}
+	if err := lp.Validate(); err != nil {
+		return errors.Wrap(err, "invalid logging payload")
+	}
+
+	sendErr := &SendToManyError{Failed: map[string]error{}}
+	cacheIDs := make([][]string, len(c))
+	for _, id := range ids {
+		found := false
+		for idx, cache := range c {
+			if cache.Get(id) != nil {
+				cacheIDs[idx] = append(cacheIDs[idx], id)
+				found = true
+				break
+			}
+		}
+		if !found {
+			sendErr.Missing = append(sendErr.Missing, id)
+		}
+	}
+
+	for idx, cache := range c {
+		if len(cacheIDs[idx]) == 0 {
+			continue
+		}
+		err := cache.SendToMany(cacheIDs[idx], lp)
+		if err == nil {
+			continue
+		}
+		if cacheErr, ok := err.(*SendToManyError); ok {
+			sendErr.Missing = append(sendErr.Missing, cacheErr.Missing...)
+			for id, err := range cacheErr.Failed {
+				sendErr.Failed[id] = err
+			}
+			continue
+		}
+		for _, id := range cacheIDs[idx] {
+			sendErr.Failed[id] = err
+		}
+	}
+
+	if len(sendErr.Missing) == 0 && len(sendErr.Failed) == 0 {
+		return nil
+	}
+	return sendErr
+}
+
+func (c loadBalancedLoggingCache) Clear(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	for _, cache := range c {
+		catcher.Add(cache.Clear(ctx))
+	}
+	return catcher.Resolve()
+}
+
+func (c loadBalancedLoggingCache) Drain(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	for _, cache := range c {
+		catcher.Add(cache.Drain(ctx))
+	}
+	return catcher.Resolve()
+}
+
+func (c loadBalancedLoggingCache) Prune(lastAccessed time.Time) {
+	for _, cache := range c {
+		cache.Prune(lastAccessed)
+	}
+}
+
+func (c loadBalancedLoggingCache) Len() int {
+	total := 0
+	for _, cache := range c {
+		total += cache.Len()
+	}
+	return total
+}
@@ -0,0 +1,209 @@
+package jasper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/jasper"
+	"github.com/tychoish/jasper/mock"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestLoadBalancedManager(t *testing.T) {
+	// makeBackend returns a mock manager whose processes are running and
+	// have IDs that identify the backend that created them.
+	makeBackend := func(name string) *mock.Manager {
+		backend := &mock.Manager{ManagerID: name}
+		backend.Create = func(opts *options.Create) mock.Process {
+			return mock.Process{ProcInfo: jasper.ProcessInfo{
+				ID:        name + "-" + opts.ID,
+				IsRunning: true,
+				Options:   *opts,
+			}}
+		}
+		return backend
+	}
+	createProcesses := func(ctx context.Context, t *testing.T, m jasper.Manager, n int) []string {
+		var ids []string
+		for i := 0; i < n; i++ {
+			proc, err := m.CreateProcess(ctx, &options.Create{ID: string(rune('a' + i)), Args: []string{"true"}})
+			require.NoError(t, err)
+			ids = append(ids, proc.ID())
+		}
+		return ids
+	}
+
+	t.Run("Constructor", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			backends []jasper.Manager
+			strategy jasper.LoadBalancingStrategy
+		}{
+			"FailsWithoutBackends": {
+				strategy: jasper.LoadBalanceRoundRobin,
+			},
+			"FailsWithNilBackend": {
+				backends: []jasper.Manager{makeBackend("first"), nil},
+				strategy: jasper.LoadBalanceRoundRobin,
+			},
+			"FailsWithUnrecognizedStrategy": {
+				backends: []jasper.Manager{makeBackend("first")},
+				strategy: "foo",
+			},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				m, err := jasper.NewLoadBalancedManager(testCase.backends, testCase.strategy)
+				assert.Error(t, err)
+				assert.Nil(t, m)
+			})
+		}
+	})
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, first, second *mock.Manager){
+		"RoundRobinAlternatesBackends": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+
+			ids := createProcesses(ctx, t, m, 5)
+			assert.Equal(t, []string{"first-a", "second-b", "first-c", "second-d", "first-e"}, ids)
+			assert.Len(t, first.Procs, 3)
+			assert.Len(t, second.Procs, 2)
+		},
+		"LeastRunningPicksBackendWithFewestRunningProcesses": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			first.Procs = []jasper.Process{
+				&mock.Process{ProcInfo: jasper.ProcessInfo{ID: "running-0", IsRunning: true}},
+				&mock.Process{ProcInfo: jasper.ProcessInfo{ID: "running-1", IsRunning: true}},
+				&mock.Process{ProcInfo: jasper.ProcessInfo{ID: "completed", Complete: true}},
+			}
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceLeastRunning)
+			require.NoError(t, err)
+
+			ids := createProcesses(ctx, t, m, 4)
+			assert.Equal(t, []string{"second-a", "second-b", "first-c", "second-d"}, ids)
+		},
+		"ListAndGetCombineBackends": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+			ids := createProcesses(ctx, t, m, 4)
+
+			procs, err := m.List(ctx, options.All)
+			require.NoError(t, err)
+			var listed []string
+			for _, proc := range procs {
+				listed = append(listed, proc.ID())
+			}
+			assert.ElementsMatch(t, ids, listed)
+
+			for _, id := range ids {
+				proc, err := m.Get(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, id, proc.ID())
+			}
+			_, err = m.Get(ctx, "nonexistent")
+			assert.Error(t, err)
+
+			assert.Equal(t, jasper.ManagerStats{Created: 4, Running: 4}, m.Stats(ctx))
+		},
+		"GetReturnsBackendError": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+
+			_, err = m.Get(ctx, "nonexistent")
+			require.Error(t, err)
+			assert.Equal(t, jasper.ErrProcessNotFound, errors.Cause(err))
+
+			second.FailGet = true
+			_, err = m.Get(ctx, "nonexistent")
+			require.Error(t, err)
+			assert.NotEqual(t, jasper.ErrProcessNotFound, errors.Cause(err))
+		},
+		"LeastRunningSpreadsConcurrentCreates": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			release := make(chan struct{})
+			backends := []*slowCreateManager{
+				{Manager: first, started: make(chan struct{}), release: release},
+				{Manager: second, started: make(chan struct{}), release: release},
+			}
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{backends[0], backends[1]}, jasper.LoadBalanceLeastRunning)
+			require.NoError(t, err)
+
+			created := make(chan error, 2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					_, err := m.CreateProcess(ctx, &options.Create{Args: []string{"true"}})
+					created <- err
+				}()
+			}
+			for _, backend := range backends {
+				select {
+				case <-backend.started:
+				case <-ctx.Done():
+					require.FailNow(t, "backend was not picked")
+				}
+			}
+			close(release)
+			for i := 0; i < 2; i++ {
+				assert.NoError(t, <-created)
+			}
+		},
+		"ListFailsIfAnyBackendFails": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+			second.FailList = true
+
+			_, err = m.List(ctx, options.All)
+			assert.Error(t, err)
+		},
+		"TagLimitsAreSetOnEveryBackend": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+
+			require.NoError(t, m.SetTagLimit(ctx, "tag", 2))
+			assert.Equal(t, map[string]int{"tag": 2}, first.TagLimits)
+			assert.Equal(t, map[string]int{"tag": 2}, second.TagLimits)
+		},
+		"SendToManyUsesBackendWithLogger": func(ctx context.Context, t *testing.T, first, second *mock.Manager) {
+			firstCache := &mock.LoggingCache{Cache: map[string]*options.CachedLogger{}}
+			secondCache := &mock.LoggingCache{Cache: map[string]*options.CachedLogger{}}
+			first.LoggingCacheVal = firstCache
+			second.LoggingCacheVal = secondCache
+			m, err := jasper.NewLoadBalancedManager([]jasper.Manager{first, second}, jasper.LoadBalanceRoundRobin)
+			require.NoError(t, err)
+
+			require.NoError(t, firstCache.Put("first", &options.CachedLogger{ID: "first", Output: options.NewMockSender("first")}))
+			require.NoError(t, secondCache.Put("second", &options.CachedLogger{ID: "second"}))
+
+			err = m.LoggingCache(ctx).SendToMany([]string{"first", "second", "missing"}, &options.LoggingPayload{Data: "foo", Priority: level.Info})
+			require.Error(t, err)
+			sendErr, ok := err.(*jasper.SendToManyError)
+			require.True(t, ok)
+			assert.Equal(t, []string{"missing"}, sendErr.Missing)
+			require.Len(t, sendErr.Failed, 1)
+			assert.Error(t, sendErr.Failed["second"])
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			testCase(ctx, t, makeBackend("first"), makeBackend("second"))
+		})
+	}
+}
+
+// slowCreateManager is a manager whose CreateProcess closes started and then
+// blocks until release is closed.
+type slowCreateManager struct {
+	*mock.Manager
+	started chan struct{}
+	release <-chan struct{}
+}
+
+func (m *slowCreateManager) CreateProcess(ctx context.Context, opts *options.Create) (jasper.Process, error) {
+	close(m.started)
+	<-m.release
+	return m.Manager.CreateProcess(ctx, opts)
+}
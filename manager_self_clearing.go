@@ -88,3 +88,16 @@ func (m *selfClearingProcessManager) Register(ctx context.Context, proc Process)
 
 	return errors.WithStack(m.basicProcessManager.Register(ctx, proc))
 }
+
+func (m *selfClearingProcessManager) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (Process, error) {
+	if err := m.checkProcCapacity(ctx); err != nil {
+		return nil, err
+	}
+
+	proc, err := m.basicProcessManager.RegisterExternal(ctx, pid, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return proc, nil
+}
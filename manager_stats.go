@@ -0,0 +1,108 @@
+package jasper
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+	"github.com/tychoish/jasper/options"
+)
+
+// ManagerStats reports the number of processes tracked by a manager in
+// each state. Completed includes both successful and failed
+// processes.
+type ManagerStats struct {
+	// Created is the number of processes that the manager currently
+	// tracks, which is the same as the number of processes listed by
+	// the options.All filter. Processes removed by Clear are not
+	// counted, so stats from several managers can be added together.
+	Created   int `json:"created" bson:"created" yaml:"created"`
+	Running   int `json:"running" bson:"running" yaml:"running"`
+	Completed int `json:"completed" bson:"completed" yaml:"completed"`
+	Failed    int `json:"failed" bson:"failed" yaml:"failed"`
+}
+
+// NewManagerStats computes statistics for the given processes, each of
+// which is counted as created.
+func NewManagerStats(ctx context.Context, procs []Process) ManagerStats {
+	stats := ManagerStats{Created: len(procs)}
+	for _, proc := range procs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		cctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		info := proc.Info(cctx)
+		cancel()
+
+		if info.IsRunning {
+			stats.Running++
+		}
+		if info.Complete {
+			stats.Completed++
+			if !info.Successful {
+				stats.Failed++
+			}
+		}
+	}
+
+	return stats
+}
+
+// CollectManagerStats computes statistics for a manager using its List
+// method, which avoids querying every process individually. This is
+// useful for managers that proxy to remote services.
+func CollectManagerStats(ctx context.Context, m Manager) (ManagerStats, error) {
+	stats := ManagerStats{}
+	for filter, count := range map[options.Filter]*int{
+		options.All:        &stats.Created,
+		options.Running:    &stats.Running,
+		options.Terminated: &stats.Completed,
+		options.Failed:     &stats.Failed,
+	} {
+		procs, err := m.List(ctx, filter)
+		if err != nil {
+			return ManagerStats{}, errors.Wrapf(err, "problem listing '%s' processes", filter)
+		}
+		*count = len(procs)
+	}
+
+	return stats, nil
+}
+
+// Message returns a loggable representation of the stats.
+func (s ManagerStats) Message() message.Composer {
+	return message.NewFields(level.Info, message.Fields{
+		"message":   "jasper manager stats",
+		"created":   s.Created,
+		"running":   s.Running,
+		"completed": s.Completed,
+		"failed":    s.Failed,
+	})
+}
+
+// EmitManagerStats sends the manager's stats to the sender at the given
+// interval, blocking until the context is canceled. Callers will
+// typically run this function in its own goroutine.
+func EmitManagerStats(ctx context.Context, m Manager, sender send.Sender, interval time.Duration) {
+	if interval <= 0 {
+		grip.Warningf("cannot emit manager stats with non-positive interval '%s'", interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sender.Send(m.Stats(ctx).Message())
+		}
+	}
+}
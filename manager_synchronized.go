@@ -3,6 +3,8 @@ package jasper
 import (
 	"context"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/jasper/options"
@@ -10,7 +12,7 @@ import (
 
 // MakeSynchronizedManager wraps the given manager in a thread-safe Manager.
 func MakeSynchronizedManager(manager Manager) Manager {
-	return &synchronizedProcessManager{manager: manager}
+	return newSynchronizedProcessManager(manager)
 }
 
 // NewSynchronizedManager is a constructor for a thread-safe basic Manager.
@@ -20,7 +22,7 @@ func NewSynchronizedManager(trackProcs bool) (Manager, error) {
 		return nil, err
 	}
 
-	return &synchronizedProcessManager{manager: basicManager}, nil
+	return newSynchronizedProcessManager(basicManager), nil
 }
 
 // NewSSHLibrarySynchronizedManager is the same as NewSynchronizedManager but
@@ -30,12 +32,33 @@ func NewSSHLibrarySynchronizedManager(trackProcs bool) (Manager, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "problem constructing underlying manager")
 	}
-	return &synchronizedProcessManager{manager: basicManager}, nil
+	return newSynchronizedProcessManager(basicManager), nil
 }
 
 type synchronizedProcessManager struct {
 	mu      sync.RWMutex
 	manager Manager
+	// tagLimits are enforced by this manager rather than the wrapped
+	// manager so that waiting for a slot does not hold the lock.
+	tagLimits *tagLimiter
+}
+
+func newSynchronizedProcessManager(manager Manager) *synchronizedProcessManager {
+	m := &synchronizedProcessManager{
+		manager:   manager,
+		tagLimits: newTagLimiter(),
+	}
+	if bpm, ok := manager.(*basicProcessManager); ok {
+		// Processes that always restart are restarted asynchronously,
+		// so the restart must acquire the lock.
+		bpm.restartHook = func(ctx context.Context, opts *options.Create, restarts *restartHistory) (Process, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			return bpm.restartProcess(ctx, opts, restarts)
+		}
+	}
+	return m
 }
 
 func (m *synchronizedProcessManager) ID() string {
@@ -43,13 +66,20 @@ func (m *synchronizedProcessManager) ID() string {
 }
 
 func (m *synchronizedProcessManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	release, err := m.tagLimits.acquire(ctx, opts.Tags)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	proc, err := m.manager.CreateProcess(ctx, opts)
 	if err != nil {
+		release()
 		return nil, errors.WithStack(err)
 	}
+	releaseOnExit(ctx, proc, release)
 
 	return &synchronizedProcess{proc: proc}, nil
 }
@@ -68,6 +98,18 @@ func (m *synchronizedProcessManager) Register(ctx context.Context, proc Process)
 	return errors.WithStack(m.manager.Register(ctx, proc))
 }
 
+func (m *synchronizedProcessManager) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (Process, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	proc, err := m.manager.RegisterExternal(ctx, pid, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &synchronizedProcess{proc: proc}, nil
+}
+
 func (m *synchronizedProcessManager) List(ctx context.Context, f options.Filter) ([]Process, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -130,3 +172,47 @@ func (m *synchronizedProcessManager) WriteFile(ctx context.Context, opts options
 
 	return m.manager.WriteFile(ctx, opts)
 }
+
+// SignalGroup signals the processes through Group so that each process is
+// signaled while holding its own lock.
+func (m *synchronizedProcessManager) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return SignalGroup(ctx, m, tag, sig)
+}
+
+// WaitForTag waits on the processes found through Group so that the manager
+// is not locked while waiting.
+func (m *synchronizedProcessManager) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return WaitForTag(ctx, m, tag)
+}
+
+func (m *synchronizedProcessManager) SetTagLimit(_ context.Context, tag string, max int) error {
+	return m.tagLimits.setLimit(tag, max)
+}
+
+func (m *synchronizedProcessManager) Stats(ctx context.Context) ManagerStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.manager.Stats(ctx)
+}
+
+func (m *synchronizedProcessManager) Subscribe(ctx context.Context) <-chan ProcessEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.manager.Subscribe(ctx)
+}
+
+func (m *synchronizedProcessManager) PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.manager.PruneLoggers(ctx, olderThan)
+}
+
+func (m *synchronizedProcessManager) Snapshot(ctx context.Context) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.manager.Snapshot(ctx)
+}
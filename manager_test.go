@@ -1,15 +1,32 @@
 package jasper
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
 	"github.com/tychoish/jasper/options"
 	"github.com/tychoish/jasper/testutil"
+	"github.com/tychoish/jasper/util"
 )
 
 var echoSubCmd = []string{"echo", "foo"}
@@ -92,6 +109,21 @@ func TestManagerInterface(t *testing.T) {
 					info := proc.Info(ctx)
 					assert.True(t, info.IsRunning || info.Complete)
 				},
+				"CreateProcessWithReusedOptions": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					opts := testutil.TrueCreateOpts()
+					mod(opts)
+					opts.Tags = []string{"reused"}
+
+					first, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					second, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					assert.NotEqual(t, first.ID(), second.ID())
+
+					assert.NotContains(t, opts.Environment, ManagerEnvironID)
+					assert.Equal(t, []string{"reused"}, opts.Tags)
+					assert.Equal(t, []string{"reused"}, second.GetTags())
+				},
 				"CreateProcessFails": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
 					opts := &options.Create{}
 					mod(opts)
@@ -99,6 +131,111 @@ func TestManagerInterface(t *testing.T) {
 					require.Error(t, err)
 					assert.Nil(t, proc)
 				},
+				"CreateProcessFailsWithMissingDependency": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					opts := testutil.TrueCreateOpts()
+					opts.DependsOn = []string{"does-not-exist"}
+					mod(opts)
+					proc, err := manager.CreateProcess(ctx, opts)
+					assert.Error(t, err)
+					assert.Nil(t, proc)
+				},
+				"DependentProcessStartsAfterDependencySucceeds": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					depOpts := testutil.SleepCreateOpts(1)
+					mod(depOpts)
+					dep, err := manager.CreateProcess(ctx, depOpts)
+					require.NoError(t, err)
+
+					opts := testutil.TrueCreateOpts()
+					mod(opts)
+					opts.Implementation = options.ProcessImplementationBasic
+					opts.DependsOn = []string{dep.ID()}
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+
+					info := proc.Info(ctx)
+					assert.True(t, info.NotStarted, "process should not start before its dependency completes")
+					require.Len(t, info.Dependencies, 1)
+					assert.Equal(t, dep.ID(), info.Dependencies[0].ID)
+					assert.False(t, info.Dependencies[0].Complete)
+
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+
+					depInfo := dep.Info(ctx)
+					require.True(t, depInfo.Complete)
+					assert.True(t, depInfo.Successful)
+
+					info = proc.Info(ctx)
+					assert.False(t, info.NotStarted)
+					assert.False(t, info.StartAt.Before(depInfo.EndAt))
+					require.Len(t, info.Dependencies, 1)
+					assert.True(t, info.Dependencies[0].Complete)
+					assert.True(t, info.Dependencies[0].Successful)
+				},
+				"DependentProcessDoesNotStartWhenDependencyFails": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					depOpts := testutil.FalseCreateOpts()
+					mod(depOpts)
+					dep, err := manager.CreateProcess(ctx, depOpts)
+					require.NoError(t, err)
+
+					opts := testutil.TrueCreateOpts()
+					mod(opts)
+					opts.Implementation = options.ProcessImplementationBasic
+					opts.DependsOn = []string{dep.ID()}
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+
+					info := proc.Info(ctx)
+					assert.True(t, info.Complete)
+					assert.True(t, info.NotStarted)
+					assert.False(t, info.Successful)
+					assert.Zero(t, info.PID)
+					assert.Equal(t, EndReasonDependencyFailed, info.EndReason)
+					require.Len(t, info.Dependencies, 1)
+					assert.True(t, info.Dependencies[0].Complete)
+					assert.False(t, info.Dependencies[0].Successful)
+				},
+				"StatsAreEmptyByDefault": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					assert.Zero(t, manager.Stats(ctx))
+				},
+				"StatsReflectProcessStates": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					for _, opts := range []*options.Create{testutil.TrueCreateOpts(), testutil.FalseCreateOpts()} {
+						mod(opts)
+						proc, err := manager.CreateProcess(ctx, opts)
+						require.NoError(t, err)
+						_, _ = proc.Wait(ctx)
+					}
+
+					opts := testutil.SleepCreateOpts(10)
+					mod(opts)
+					_, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+
+					assert.Equal(t, ManagerStats{
+						Created:   3,
+						Running:   1,
+						Completed: 2,
+						Failed:    1,
+					}, manager.Stats(ctx))
+				},
+				"StatsDoNotCountClearedProcesses": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					opts := testutil.TrueCreateOpts()
+					mod(opts)
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+
+					manager.Clear(ctx)
+
+					procs, err := manager.List(ctx, options.All)
+					require.NoError(t, err)
+					assert.Len(t, procs, 0)
+					assert.Zero(t, manager.Stats(ctx))
+				},
 				"ListAllOperations": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
 					opts := testutil.TrueCreateOpts()
 					mod(opts)
@@ -219,6 +356,80 @@ func TestManagerInterface(t *testing.T) {
 					require.Len(t, procs, 1)
 					assert.Equal(t, procs[0].ID(), proc.ID())
 				},
+				"SignalGroupSignalsAllTaggedProcesses": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					opts := testutil.SleepCreateOpts(100)
+					opts.Tags = []string{"sleepers"}
+					mod(opts)
+
+					procs, err := createProcs(ctx, opts, manager, 3)
+					require.NoError(t, err)
+
+					untaggedOpts := testutil.SleepCreateOpts(100)
+					mod(untaggedOpts)
+					untagged, err := manager.CreateProcess(ctx, untaggedOpts)
+					require.NoError(t, err)
+
+					require.NoError(t, manager.SignalGroup(ctx, "sleepers", syscall.SIGKILL))
+
+					for _, proc := range procs {
+						_, err = proc.Wait(ctx)
+						assert.Error(t, err)
+						assert.True(t, proc.Complete(ctx))
+					}
+					assert.True(t, untagged.Running(ctx))
+					assert.NoError(t, untagged.Signal(ctx, syscall.SIGKILL))
+				},
+				"WaitForTagReturnsResultsForTaggedProcesses": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					trueOpts := testutil.TrueCreateOpts()
+					trueOpts.Tags = []string{"tagged"}
+					mod(trueOpts)
+					succeeding, err := manager.CreateProcess(ctx, trueOpts)
+					require.NoError(t, err)
+
+					falseOpts := testutil.FalseCreateOpts()
+					falseOpts.Tags = []string{"tagged"}
+					mod(falseOpts)
+					failing, err := manager.CreateProcess(ctx, falseOpts)
+					require.NoError(t, err)
+
+					untaggedOpts := testutil.SleepCreateOpts(100)
+					mod(untaggedOpts)
+					untagged, err := manager.CreateProcess(ctx, untaggedOpts)
+					require.NoError(t, err)
+
+					results, err := manager.WaitForTag(ctx, "tagged")
+					require.NoError(t, err)
+					require.Len(t, results, 2)
+					assert.NoError(t, results[succeeding.ID()])
+					assert.Error(t, results[failing.ID()])
+					assert.True(t, succeeding.Complete(ctx))
+					assert.True(t, failing.Complete(ctx))
+					assert.True(t, untagged.Running(ctx))
+					assert.NoError(t, untagged.Signal(ctx, syscall.SIGKILL))
+				},
+				"WaitForTagWithNoMatchingProcessesReturnsEmpty": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					results, err := manager.WaitForTag(ctx, "nonexistent")
+					require.NoError(t, err)
+					assert.Empty(t, results)
+				},
+				"WaitForTagErrorsWithCanceledContext": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					opts := testutil.SleepCreateOpts(100)
+					opts.Tags = []string{"sleepers"}
+					mod(opts)
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					defer func() {
+						assert.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+					}()
+
+					waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+					defer waitCancel()
+					_, err = manager.WaitForTag(waitCtx, "sleepers")
+					assert.Error(t, err)
+				},
+				"SignalGroupWithNoMatchingProcessesNoops": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
+					assert.NoError(t, manager.SignalGroup(ctx, "nonexistent", syscall.SIGKILL))
+				},
 				"CloseEmptyManagerNoops": func(ctx context.Context, t *testing.T, manager Manager, mod testutil.OptsModify) {
 					assert.NoError(t, manager.Close(ctx))
 				},
@@ -600,3 +811,1587 @@ func TestTrackedManager(t *testing.T) {
 		})
 	}
 }
+
+func TestEmitManagerStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+	defer cancel()
+
+	manager, err := NewSynchronizedManager(false)
+	require.NoError(t, err)
+	proc, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+	require.NoError(t, err)
+	_, err = proc.Wait(ctx)
+	require.NoError(t, err)
+
+	sender, err := send.NewInMemorySender("stats", send.LevelInfo{Default: level.Info, Threshold: level.Info}, 10)
+	require.NoError(t, err)
+
+	ectx, ecancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer ecancel()
+	EmitManagerStats(ectx, manager, sender, 10*time.Millisecond)
+
+	msgs := sender.(*send.InMemorySender).Get()
+	require.NotEmpty(t, msgs)
+	fields, ok := msgs[0].Raw().(message.Fields)
+	require.True(t, ok)
+	assert.Equal(t, 1, fields["created"])
+	assert.Equal(t, 1, fields["completed"])
+	assert.Equal(t, 0, fields["failed"])
+}
+
+func TestManagerRestartAlways(t *testing.T) {
+	waitForProcs := func(ctx context.Context, t *testing.T, manager Manager, num int) []Process {
+		for {
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			if len(procs) >= num {
+				return procs
+			}
+			select {
+			case <-ctx.Done():
+				require.FailNow(t, "timed out waiting for process restarts", "expected %d processes, found %d", num, len(procs))
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	startTimes := func(ctx context.Context, procs []Process) []time.Time {
+		starts := make([]time.Time, 0, len(procs))
+		for _, proc := range procs {
+			starts = append(starts, proc.Info(ctx).StartAt)
+		}
+		sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+		return starts
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+		"RestartsAfterCleanExitWithDelay": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.TrueCreateOpts()
+			opts.RestartAlways = true
+			opts.RestartDelay = 100 * time.Millisecond
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			starts := startTimes(ctx, waitForProcs(ctx, t, manager, 3))
+			for i := 1; i < len(starts); i++ {
+				assert.True(t, starts[i].Sub(starts[i-1]) >= opts.RestartDelay)
+			}
+		},
+		"RestartsAfterFailure": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.FalseCreateOpts()
+			opts.RestartAlways = true
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			waitForProcs(ctx, t, manager, 2)
+		},
+		"RestartedProcessesKeepCustomID": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.TrueCreateOpts()
+			opts.ID = "custom"
+			opts.RestartAlways = true
+			opts.RestartLimit = 1
+			opts.RestartLimitInterval = time.Hour
+			first, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			for {
+				proc, err := manager.Get(ctx, "custom")
+				require.NoError(t, err)
+				if proc != first {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					require.FailNow(t, "timed out waiting for process restart")
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 1)
+		},
+		"BackoffIncreasesDelayBetweenRapidFailures": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.FalseCreateOpts()
+			opts.RestartAlways = true
+			opts.RestartLimit = 4
+			opts.RestartLimitInterval = time.Hour
+			opts.RestartBackoff = &options.RestartBackoff{
+				Strategy:   options.RestartBackoffExponential,
+				Delay:      100 * time.Millisecond,
+				MaxDelay:   300 * time.Millisecond,
+				Multiplier: 2,
+			}
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			starts := startTimes(ctx, waitForProcs(ctx, t, manager, 5))
+			for i, delay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+				assert.True(t, starts[i+1].Sub(starts[i]) >= delay, "restart %d should wait at least %s, but waited %s", i, delay, starts[i+1].Sub(starts[i]))
+			}
+		},
+		"BackoffResetsAfterLongLivedRun": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := &options.Create{Args: []string{"sleep", "0.3"}}
+			opts.RestartAlways = true
+			opts.RestartLimit = 2
+			opts.RestartLimitInterval = time.Hour
+			opts.RestartBackoff = &options.RestartBackoff{
+				Strategy:   options.RestartBackoffExponential,
+				Delay:      100 * time.Millisecond,
+				MaxDelay:   10 * time.Second,
+				Multiplier: 10,
+				ResetAfter: 200 * time.Millisecond,
+			}
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			// Without the reset, the second restart would wait for a
+			// full second.
+			starts := startTimes(ctx, waitForProcs(ctx, t, manager, 3))
+			assert.True(t, starts[2].Sub(starts[1]) < 900*time.Millisecond, "restart should not back off after a long-lived run, but waited %s", starts[2].Sub(starts[1]))
+		},
+		"StopsAtRestartLimit": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.TrueCreateOpts()
+			opts.RestartAlways = true
+			opts.RestartLimit = 2
+			opts.RestartLimitInterval = time.Hour
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			waitForProcs(ctx, t, manager, 3)
+			time.Sleep(500 * time.Millisecond)
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 3)
+		},
+		"StopsAfterManagerClose": func(ctx context.Context, t *testing.T, manager Manager) {
+			opts := testutil.TrueCreateOpts()
+			opts.RestartAlways = true
+			opts.RestartDelay = 100 * time.Millisecond
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+			require.NoError(t, manager.Close(ctx))
+
+			time.Sleep(300 * time.Millisecond)
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 1)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+}
+
+func TestManagerRestartOnWatchedPathChange(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	const debounce = 300 * time.Millisecond
+	makeWatchedFile := func(t *testing.T) string {
+		file, err := ioutil.TempFile(testutil.BuildDirectory(), "watched")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		return file.Name()
+	}
+	touch := func(t *testing.T, path string, contents string) {
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+	// This is called from assert.Eventually, so it must not fail the test.
+	runningProcs := func(ctx context.Context, manager Manager) []Process {
+		procs, _ := manager.List(ctx, options.Running)
+		return procs
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager, path string){
+		"RestartsAfterDebounce": func(ctx context.Context, t *testing.T, manager Manager, path string) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.WatchPaths = []string{path}
+			opts.WatchDebounce = debounce
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			oldPID := proc.Info(ctx).PID
+
+			changed := time.Now()
+			touch(t, path, "changed")
+
+			require.Eventually(t, func() bool {
+				return proc.Complete(ctx) && len(runningProcs(ctx, manager)) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.True(t, time.Since(changed) >= debounce)
+
+			restarted := runningProcs(ctx, manager)[0]
+			assert.NotEqual(t, proc.ID(), restarted.ID())
+			assert.NotEqual(t, oldPID, restarted.Info(ctx).PID)
+			assert.Equal(t, []string{path}, restarted.Info(ctx).Options.WatchPaths)
+		},
+		"DebouncesRepeatedChanges": func(ctx context.Context, t *testing.T, manager Manager, path string) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.WatchPaths = []string{path}
+			opts.WatchDebounce = debounce
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			for i := 0; i < 5; i++ {
+				touch(t, path, strings.Repeat("x", i+1))
+				time.Sleep(debounce / 3)
+			}
+			assert.True(t, proc.Running(ctx))
+
+			require.Eventually(t, func() bool {
+				return proc.Complete(ctx) && len(runningProcs(ctx, manager)) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			time.Sleep(2 * debounce)
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 2)
+		},
+		"RestartsWhenFileIsCreated": func(ctx context.Context, t *testing.T, manager Manager, path string) {
+			require.NoError(t, os.Remove(path))
+			opts := testutil.SleepCreateOpts(100)
+			opts.WatchPaths = []string{path}
+			opts.WatchDebounce = debounce
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			touch(t, path, "created")
+			require.Eventually(t, func() bool {
+				return proc.Complete(ctx) && len(runningProcs(ctx, manager)) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+		},
+		"StopsWatchingAfterProcessCompletes": func(ctx context.Context, t *testing.T, manager Manager, path string) {
+			opts := testutil.TrueCreateOpts()
+			opts.WatchPaths = []string{path}
+			opts.WatchDebounce = debounce
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+
+			touch(t, path, "changed")
+			time.Sleep(2 * debounce)
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 1)
+		},
+		"StopsWatchingAfterManagerClose": func(ctx context.Context, t *testing.T, manager Manager, path string) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.WatchPaths = []string{path}
+			opts.WatchDebounce = debounce
+			_, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			require.NoError(t, manager.Close(ctx))
+
+			touch(t, path, "changed")
+			time.Sleep(2 * debounce)
+			procs, err := manager.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 1)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			path := makeWatchedFile(t)
+			defer os.Remove(path)
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager, path)
+		})
+	}
+}
+
+func TestManagerHealthCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	const interval = 50 * time.Millisecond
+	// This is called from assert.Eventually, so it must not fail the test.
+	runningProcs := func(ctx context.Context, manager Manager) []Process {
+		procs, _ := manager.List(ctx, options.Running)
+		return procs
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager, url string, healthy *int32){
+		"ReportsHealthTransitions": func(ctx context.Context, t *testing.T, manager Manager, url string, healthy *int32) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.HealthCheck = &options.HealthCheck{
+				URL:              url,
+				Interval:         interval,
+				FailureThreshold: 2,
+			}
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				return proc.Info(ctx).Healthy
+			}, 5*time.Second, 10*time.Millisecond)
+
+			atomic.StoreInt32(healthy, 0)
+			assert.Eventually(t, func() bool {
+				info := proc.Info(ctx)
+				return !info.Healthy && info.HealthCheckFailures >= 2
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.True(t, proc.Running(ctx), "process should not restart by default")
+
+			atomic.StoreInt32(healthy, 1)
+			assert.Eventually(t, func() bool {
+				info := proc.Info(ctx)
+				return info.Healthy && info.HealthCheckFailures == 0
+			}, 5*time.Second, 10*time.Millisecond)
+		},
+		"StaysHealthyBelowFailureThreshold": func(ctx context.Context, t *testing.T, manager Manager, url string, healthy *int32) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.HealthCheck = &options.HealthCheck{
+				URL:              url,
+				Interval:         interval,
+				FailureThreshold: 1000,
+			}
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				return proc.Info(ctx).Healthy
+			}, 5*time.Second, 10*time.Millisecond)
+
+			atomic.StoreInt32(healthy, 0)
+			assert.Eventually(t, func() bool {
+				return proc.Info(ctx).HealthCheckFailures > 0
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.True(t, proc.Info(ctx).Healthy)
+		},
+		"RestartsWhenUnhealthy": func(ctx context.Context, t *testing.T, manager Manager, url string, healthy *int32) {
+			opts := testutil.SleepCreateOpts(100)
+			opts.HealthCheck = &options.HealthCheck{
+				URL:              url,
+				Interval:         interval,
+				FailureThreshold: 2,
+				RestartOnFailure: true,
+			}
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				return proc.Info(ctx).Healthy
+			}, 5*time.Second, 10*time.Millisecond)
+
+			atomic.StoreInt32(healthy, 0)
+			require.Eventually(t, func() bool {
+				return proc.Complete(ctx) && len(runningProcs(ctx, manager)) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.False(t, proc.Info(ctx).Healthy)
+
+			restarted := runningProcs(ctx, manager)[0]
+			assert.NotEqual(t, proc.ID(), restarted.ID())
+
+			atomic.StoreInt32(healthy, 1)
+			assert.Eventually(t, func() bool {
+				return restarted.Info(ctx).Healthy
+			}, 5*time.Second, 10*time.Millisecond)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			healthy := int32(1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.LoadInt32(&healthy) == 0 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager, srv.URL, &healthy)
+		})
+	}
+}
+
+// waitErrorProcess is a process that never completes. Its Wait returns
+// context.DeadlineExceeded for the first transientWaits calls, and a
+// non-transient error afterwards.
+type waitErrorProcess struct {
+	Process
+	transientWaits int32
+	waits          int32
+}
+
+func (p *waitErrorProcess) Wait(context.Context) (int, error) {
+	if atomic.AddInt32(&p.waits, 1) <= p.transientWaits {
+		return -1, errors.WithStack(context.DeadlineExceeded)
+	}
+	return -1, errors.New("process not found")
+}
+
+func (p *waitErrorProcess) Complete(context.Context) bool { return false }
+
+func TestWaitDone(t *testing.T) {
+	for testName, transientWaits := range map[string]int32{
+		"ClosedAfterNonTransientError": 0,
+		"RetriesAfterTransientError":   1,
+	} {
+		t.Run(testName, func(t *testing.T) {
+			proc := &waitErrorProcess{transientWaits: transientWaits}
+			select {
+			case <-WaitDone(proc):
+			case <-time.After(testutil.TestTimeout):
+				assert.Fail(t, "done channel was not closed")
+			}
+			assert.EqualValues(t, transientWaits+1, atomic.LoadInt32(&proc.waits))
+		})
+	}
+}
+
+func TestWaitAny(t *testing.T) {
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+		"ReturnsFastProcessFirst": func(ctx context.Context, t *testing.T, manager Manager) {
+			slow, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+			fast, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+
+			proc, err := WaitAny(ctx, []Process{slow, fast})
+			require.NoError(t, err)
+			assert.Equal(t, fast.ID(), proc.ID())
+			assert.True(t, proc.Complete(ctx))
+			assert.False(t, slow.Complete(ctx))
+		},
+		"ReturnsFailedProcess": func(ctx context.Context, t *testing.T, manager Manager) {
+			slow, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+			failed, err := manager.CreateProcess(ctx, testutil.FalseCreateOpts())
+			require.NoError(t, err)
+
+			proc, err := WaitAny(ctx, []Process{slow, failed})
+			require.NoError(t, err)
+			assert.Equal(t, failed.ID(), proc.ID())
+			assert.False(t, proc.Info(ctx).Successful)
+		},
+		"ReturnsContextErrorWhenCanceled": func(ctx context.Context, t *testing.T, manager Manager) {
+			slow, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+
+			tctx, tcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer tcancel()
+			proc, err := WaitAny(tctx, []Process{slow})
+			assert.Equal(t, context.DeadlineExceeded, err)
+			assert.Nil(t, proc)
+			assert.False(t, slow.Complete(ctx))
+		},
+		"FailsWithoutProcesses": func(ctx context.Context, t *testing.T, manager Manager) {
+			proc, err := WaitAny(ctx, nil)
+			assert.Error(t, err)
+			assert.Nil(t, proc)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+}
+
+func TestManagerSubscribe(t *testing.T) {
+	nextEvent := func(ctx context.Context, t *testing.T, events <-chan ProcessEvent) ProcessEvent {
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for process event")
+		case event, ok := <-events:
+			require.True(t, ok, "event channel closed unexpectedly")
+			return event
+		}
+		return ProcessEvent{}
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+		"CreatedThenCompleted": func(ctx context.Context, t *testing.T, manager Manager) {
+			events := manager.Subscribe(ctx)
+			proc, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+
+			event := nextEvent(ctx, t, events)
+			assert.Equal(t, ProcessEventCreated, event.Type)
+			assert.Equal(t, proc.ID(), event.Info.ID)
+
+			event = nextEvent(ctx, t, events)
+			assert.Equal(t, ProcessEventCompleted, event.Type)
+			assert.Equal(t, proc.ID(), event.Info.ID)
+			assert.True(t, event.Info.Complete)
+			assert.True(t, event.Info.Successful)
+		},
+		"SignaledBeforeCompleted": func(ctx context.Context, t *testing.T, manager Manager) {
+			if runtime.GOOS == "windows" {
+				t.Skip("signal triggers are not supported on windows")
+			}
+			events := manager.Subscribe(ctx)
+			proc, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+			require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+
+			assert.Equal(t, ProcessEventCreated, nextEvent(ctx, t, events).Type)
+			event := nextEvent(ctx, t, events)
+			assert.Equal(t, ProcessEventSignaled, event.Type)
+			assert.Equal(t, syscall.SIGTERM, event.Signal)
+			assert.Equal(t, proc.ID(), event.Info.ID)
+			event = nextEvent(ctx, t, events)
+			assert.Equal(t, ProcessEventCompleted, event.Type)
+			assert.False(t, event.Info.Successful)
+		},
+		"MultipleSubscribersReceiveEvents": func(ctx context.Context, t *testing.T, manager Manager) {
+			first := manager.Subscribe(ctx)
+			second := manager.Subscribe(ctx)
+			_, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+
+			assert.Equal(t, ProcessEventCreated, nextEvent(ctx, t, first).Type)
+			assert.Equal(t, ProcessEventCreated, nextEvent(ctx, t, second).Type)
+		},
+		"ChannelClosesWhenContextIsDone": func(ctx context.Context, t *testing.T, manager Manager) {
+			sctx, scancel := context.WithCancel(ctx)
+			events := manager.Subscribe(sctx)
+			scancel()
+
+			select {
+			case <-ctx.Done():
+				assert.Fail(t, "timed out waiting for event channel to close")
+			case _, ok := <-events:
+				assert.False(t, ok)
+			}
+
+			_, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+
+	t.Run("SlowSubscriberDropsEvents", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		publisher := &processEventPublisher{}
+		events := publisher.subscribe(ctx)
+		for i := 0; i < 2*ProcessEventBufferSize; i++ {
+			publisher.publish(ProcessEvent{Type: ProcessEventCreated})
+		}
+		assert.Len(t, events, ProcessEventBufferSize)
+	})
+}
+
+func TestManagerSnapshot(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process liveness checks are not reliable on windows")
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+		"RestoreReattachesToRunningProcesses": func(ctx context.Context, t *testing.T, manager Manager) {
+			running, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+			running.Tag("foo")
+
+			killed, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+
+			finished, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+			_, err = finished.Wait(ctx)
+			require.NoError(t, err)
+
+			data, err := manager.Snapshot(ctx)
+			require.NoError(t, err)
+
+			// Simulate a process exiting while the manager is down.
+			require.NoError(t, killed.Signal(ctx, syscall.SIGKILL))
+			_, err = killed.Wait(ctx)
+			require.Error(t, err)
+
+			restored, err := RestoreManager(ctx, data)
+			require.NoError(t, err)
+			assert.Equal(t, manager.ID(), restored.ID())
+
+			procs, err := restored.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Len(t, procs, 3)
+
+			proc, err := restored.Get(ctx, running.ID())
+			require.NoError(t, err)
+			info := proc.Info(ctx)
+			assert.True(t, info.IsRunning)
+			assert.Equal(t, running.Info(ctx).PID, info.PID)
+			assert.Equal(t, running.Info(ctx).Options.Args, info.Options.Args)
+			assert.Equal(t, []string{"foo"}, proc.GetTags())
+
+			procs, err = restored.Group(ctx, "foo")
+			require.NoError(t, err)
+			require.Len(t, procs, 1)
+			assert.Equal(t, running.ID(), procs[0].ID())
+
+			proc, err = restored.Get(ctx, killed.ID())
+			require.NoError(t, err)
+			assert.True(t, proc.Complete(ctx))
+			exitCode, err := proc.Wait(ctx)
+			assert.Error(t, err)
+			assert.Equal(t, -1, exitCode)
+
+			proc, err = restored.Get(ctx, finished.ID())
+			require.NoError(t, err)
+			assert.True(t, proc.Complete(ctx))
+			exitCode, err = proc.Wait(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, exitCode)
+		},
+		"RestoredProcessDetectsExit": func(ctx context.Context, t *testing.T, manager Manager) {
+			running, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+
+			data, err := manager.Snapshot(ctx)
+			require.NoError(t, err)
+
+			restored, err := RestoreManager(ctx, data)
+			require.NoError(t, err)
+			proc, err := restored.Get(ctx, running.ID())
+			require.NoError(t, err)
+			require.True(t, proc.Running(ctx))
+
+			triggered := make(chan ProcessInfo, 1)
+			require.NoError(t, proc.RegisterTrigger(ctx, func(info ProcessInfo) {
+				triggered <- info
+			}))
+
+			require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+			// The original manager's process reaps the exited process.
+			_, err = running.Wait(ctx)
+			require.Error(t, err)
+
+			_, err = proc.Wait(ctx)
+			assert.Error(t, err)
+			assert.True(t, proc.Complete(ctx))
+			select {
+			case <-ctx.Done():
+				assert.Fail(t, "timed out waiting for trigger")
+			case info := <-triggered:
+				assert.True(t, info.Complete)
+				assert.False(t, info.IsRunning)
+			}
+		},
+		"SnapshotOmitsRemoteProcesses": func(ctx context.Context, t *testing.T, manager Manager) {
+			proc := newRestoredProcess(ctx, ProcessInfo{
+				ID:       "remote",
+				Complete: true,
+				Options:  options.Create{Remote: &options.Remote{}},
+			}, nil)
+			require.NoError(t, manager.Register(ctx, proc))
+
+			data, err := manager.Snapshot(ctx)
+			require.NoError(t, err)
+
+			restored, err := RestoreManager(ctx, data)
+			require.NoError(t, err)
+			procs, err := restored.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.Empty(t, procs)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+
+	t.Run("RestoreFailsWithInvalidData", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+		defer cancel()
+
+		_, err := RestoreManager(ctx, []byte("foo"))
+		assert.Error(t, err)
+		_, err = RestoreManager(ctx, []byte(`{"processes": []}`))
+		assert.Error(t, err)
+		_, err = RestoreManager(ctx, []byte(`{"id": "foo", "processes": [{"info": {}}]}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestLimitedManager(t *testing.T) {
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, base Manager){
+		"LimitsConcurrentProcesses": func(ctx context.Context, t *testing.T, base Manager) {
+			manager := NewLimitedManager(base, 2)
+
+			procs := make(chan Process, 5)
+			wg := &sync.WaitGroup{}
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					proc, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(1))
+					if assert.NoError(t, err) {
+						procs <- proc
+					}
+				}()
+			}
+			wg.Wait()
+			close(procs)
+
+			infos := []ProcessInfo{}
+			for proc := range procs {
+				_, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				infos = append(infos, proc.Info(ctx))
+			}
+			require.Len(t, infos, 5)
+
+			for _, info := range infos {
+				running := 0
+				for _, other := range infos {
+					if !other.StartAt.After(info.StartAt) && other.EndAt.After(info.StartAt) {
+						running++
+					}
+				}
+				assert.True(t, running <= 2, "%d processes were running concurrently", running)
+			}
+		},
+		"CreateProcessRespectsContext": func(ctx context.Context, t *testing.T, base Manager) {
+			manager := NewLimitedManager(base, 1)
+			proc, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+
+			tctx, tcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer tcancel()
+			_, err = manager.CreateProcess(tctx, testutil.TrueCreateOpts())
+			assert.Error(t, err)
+
+			require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+			_, err = proc.Wait(ctx)
+			require.Error(t, err)
+
+			proc, err = manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			assert.NoError(t, err)
+		},
+		"FailedCreationReleasesSlot": func(ctx context.Context, t *testing.T, base Manager) {
+			manager := NewLimitedManager(base, 1)
+			_, err := manager.CreateProcess(ctx, &options.Create{})
+			require.Error(t, err)
+
+			proc, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			assert.NoError(t, err)
+		},
+		"CommandsAreLimited": func(ctx context.Context, t *testing.T, base Manager) {
+			manager := NewLimitedManager(base, 1)
+			proc, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+			require.NoError(t, err)
+
+			tctx, tcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer tcancel()
+			assert.Error(t, manager.CreateCommand(tctx).Append("true").Run(tctx))
+
+			require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+			_, err = proc.Wait(ctx)
+			require.Error(t, err)
+
+			assert.NoError(t, manager.CreateCommand(ctx).Append("true").Run(ctx))
+		},
+		"NonPositiveLimitIsUnlimited": func(ctx context.Context, t *testing.T, base Manager) {
+			assert.Equal(t, base, NewLimitedManager(base, 0))
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+}
+
+func TestManagerTagLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	shortSleep := func(tags ...string) *options.Create {
+		return &options.Create{Args: []string{"sleep", "0.2"}, Tags: tags}
+	}
+	longSleep := func(tags ...string) *options.Create {
+		opts := testutil.SleepCreateOpts(10)
+		opts.Tags = tags
+		return opts
+	}
+
+	for managerName, makeManager := range map[string]func(t *testing.T) Manager{
+		"Basic": func(t *testing.T) Manager {
+			m, err := newBasicProcessManager(map[string]Process{}, false, false)
+			require.NoError(t, err)
+			return m
+		},
+		"Synchronized": func(t *testing.T) Manager {
+			m, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			return m
+		},
+		"SelfClearing": func(t *testing.T) Manager {
+			m, err := NewSelfClearingProcessManager(10, false)
+			require.NoError(t, err)
+			return m
+		},
+	} {
+		t.Run(managerName, func(t *testing.T) {
+			for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+				"SerializesProcessesWithLimitedTag": func(ctx context.Context, t *testing.T, manager Manager) {
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 1))
+
+					procs := []Process{}
+					for i := 0; i < 3; i++ {
+						proc, err := manager.CreateProcess(ctx, shortSleep("build"))
+						require.NoError(t, err)
+						procs = append(procs, proc)
+					}
+
+					infos := []ProcessInfo{}
+					for _, proc := range procs {
+						_, err := proc.Wait(ctx)
+						require.NoError(t, err)
+						infos = append(infos, proc.Info(ctx))
+					}
+					for i := 1; i < len(infos); i++ {
+						assert.False(t, infos[i].StartAt.Before(infos[i-1].EndAt), "process %d started before process %d completed", i, i-1)
+					}
+				},
+				"DifferentTagsHaveIndependentLimits": func(ctx context.Context, t *testing.T, manager Manager) {
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 1))
+					require.NoError(t, manager.SetTagLimit(ctx, "test", 1))
+
+					build, err := manager.CreateProcess(ctx, longSleep("build"))
+					require.NoError(t, err)
+
+					tctx, tcancel := context.WithTimeout(ctx, time.Second)
+					defer tcancel()
+					proc, err := manager.CreateProcess(tctx, shortSleep("test"))
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+					proc, err = manager.CreateProcess(tctx, shortSleep("unlimited"))
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+
+					require.NoError(t, build.Signal(ctx, syscall.SIGKILL))
+					_, err = build.Wait(ctx)
+					require.Error(t, err)
+				},
+				"CreateProcessRespectsContext": func(ctx context.Context, t *testing.T, manager Manager) {
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 1))
+					proc, err := manager.CreateProcess(ctx, longSleep("build"))
+					require.NoError(t, err)
+
+					tctx, tcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+					defer tcancel()
+					_, err = manager.CreateProcess(tctx, shortSleep("build"))
+					assert.Error(t, err)
+
+					require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+					_, err = proc.Wait(ctx)
+					require.Error(t, err)
+
+					proc, err = manager.CreateProcess(ctx, shortSleep("build"))
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					assert.NoError(t, err)
+				},
+				"FailedCreationReleasesSlot": func(ctx context.Context, t *testing.T, manager Manager) {
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 1))
+					_, err := manager.CreateProcess(ctx, &options.Create{Tags: []string{"build"}})
+					require.Error(t, err)
+
+					tctx, tcancel := context.WithTimeout(ctx, time.Second)
+					defer tcancel()
+					proc, err := manager.CreateProcess(tctx, shortSleep("build"))
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					assert.NoError(t, err)
+				},
+				"ZeroLimitRemovesLimit": func(ctx context.Context, t *testing.T, manager Manager) {
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 1))
+					require.NoError(t, manager.SetTagLimit(ctx, "build", 0))
+
+					tctx, tcancel := context.WithTimeout(ctx, time.Second)
+					defer tcancel()
+					for i := 0; i < 2; i++ {
+						_, err := manager.CreateProcess(tctx, shortSleep("build"))
+						require.NoError(t, err)
+					}
+				},
+				"InvalidLimitsError": func(ctx context.Context, t *testing.T, manager Manager) {
+					assert.Error(t, manager.SetTagLimit(ctx, "build", -1))
+					assert.Error(t, manager.SetTagLimit(ctx, "", 1))
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+					defer cancel()
+
+					manager := makeManager(t)
+					defer func() {
+						assert.NoError(t, manager.Close(ctx))
+					}()
+
+					testCase(ctx, t, manager)
+				})
+			}
+		})
+	}
+}
+
+func TestCapturingManager(t *testing.T) {
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, base Manager){
+		"ProcessesWithoutOutputAreCaptured": func(ctx context.Context, t *testing.T, base Manager) {
+			logger, err := NewInMemoryLogger(100)
+			require.NoError(t, err)
+			manager := NewCapturingManager(base, options.Output{Loggers: []*options.LoggerConfig{logger}})
+
+			for _, word := range []string{"foo", "bar"} {
+				proc, err := manager.CreateProcess(ctx, &options.Create{Args: []string{"echo", word}})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				logs, err := GetInMemoryLogStream(ctx, proc, 100)
+				require.NoError(t, err)
+				assert.Equal(t, []string{word}, logs)
+			}
+		},
+		"OptionsCanBeReused": func(ctx context.Context, t *testing.T, base Manager) {
+			logger, err := NewInMemoryLogger(100)
+			require.NoError(t, err)
+			manager := NewCapturingManager(base, options.Output{Loggers: []*options.LoggerConfig{logger}})
+
+			opts := &options.Create{Args: []string{"echo", "foo"}}
+			for i := 0; i < 2; i++ {
+				proc, err := manager.CreateProcess(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				logs, err := GetInMemoryLogStream(ctx, proc, 100)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"foo"}, logs)
+			}
+			assert.Empty(t, opts.Output.Loggers)
+		},
+		"ProcessLoggersTakePrecedence": func(ctx context.Context, t *testing.T, base Manager) {
+			defaultLogger, err := NewInMemoryLogger(100)
+			require.NoError(t, err)
+			manager := NewCapturingManager(base, options.Output{Loggers: []*options.LoggerConfig{defaultLogger}})
+
+			logger, err := NewInMemoryLogger(100)
+			require.NoError(t, err)
+			opts := &options.Create{Args: []string{"echo", "foo"}}
+			opts.Output.Loggers = []*options.LoggerConfig{logger}
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+
+			loggers := proc.Info(ctx).Options.Output.Loggers
+			require.Len(t, loggers, 1)
+			assert.Equal(t, logger, loggers[0])
+		},
+		"ProcessWritersTakePrecedence": func(ctx context.Context, t *testing.T, base Manager) {
+			defaultOutput := util.NewLocalBuffer(bytes.Buffer{})
+			manager := NewCapturingManager(base, options.Output{Output: defaultOutput})
+
+			output := util.NewLocalBuffer(bytes.Buffer{})
+			opts := &options.Create{Args: []string{"echo", "foo"}}
+			opts.Output.Output = output
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, "foo", strings.TrimSpace(output.String()))
+			assert.Empty(t, defaultOutput.String())
+		},
+		"CommandsAreCaptured": func(ctx context.Context, t *testing.T, base Manager) {
+			output := util.NewLocalBuffer(bytes.Buffer{})
+			manager := NewCapturingManager(base, options.Output{Output: output})
+
+			require.NoError(t, manager.CreateCommand(ctx).Append("echo foo").Run(ctx))
+			assert.Equal(t, "foo", strings.TrimSpace(output.String()))
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+}
+
+func TestManagerProcessID(t *testing.T) {
+	for managerName, makeManager := range map[string]func(t *testing.T) Manager{
+		"Basic": func(t *testing.T) Manager {
+			m, err := newBasicProcessManager(map[string]Process{}, false, false)
+			require.NoError(t, err)
+			return m
+		},
+		"Synchronized": func(t *testing.T) Manager {
+			m, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			return m
+		},
+		"SelfClearing": func(t *testing.T) Manager {
+			m, err := NewSelfClearingProcessManager(10, false)
+			require.NoError(t, err)
+			return m
+		},
+	} {
+		t.Run(managerName, func(t *testing.T) {
+			for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+				"CustomIDIsUsed": func(ctx context.Context, t *testing.T, manager Manager) {
+					for _, impl := range []string{options.ProcessImplementationBasic, options.ProcessImplementationBlocking} {
+						opts := testutil.TrueCreateOpts()
+						opts.ID = "custom-" + impl
+						opts.Implementation = impl
+						proc, err := manager.CreateProcess(ctx, opts)
+						require.NoError(t, err)
+						assert.Equal(t, opts.ID, proc.ID())
+						assert.Equal(t, opts.ID, proc.Info(ctx).Options.Environment[EnvironID])
+
+						getProc, err := manager.Get(ctx, opts.ID)
+						require.NoError(t, err)
+						assert.Equal(t, proc, getProc)
+					}
+				},
+				"DuplicateIDIsRejected": func(ctx context.Context, t *testing.T, manager Manager) {
+					opts := testutil.TrueCreateOpts()
+					opts.ID = "custom"
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+
+					opts = testutil.TrueCreateOpts()
+					opts.ID = "custom"
+					dup, err := manager.CreateProcess(ctx, opts)
+					assert.Error(t, err)
+					assert.Nil(t, dup)
+
+					procs, err := manager.List(ctx, options.All)
+					require.NoError(t, err)
+					require.Len(t, procs, 1)
+					assert.Equal(t, proc, procs[0])
+				},
+				"GeneratorIsUsedForProcessesWithoutID": func(ctx context.Context, t *testing.T, manager Manager) {
+					var count int
+					manager = NewIDGeneratorManager(manager, func() string {
+						count++
+						return fmt.Sprintf("generated-%d", count)
+					})
+
+					proc, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+					require.NoError(t, err)
+					assert.Equal(t, "generated-1", proc.ID())
+
+					opts := testutil.TrueCreateOpts()
+					opts.ID = "custom"
+					proc, err = manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					assert.Equal(t, "custom", proc.ID())
+
+					require.NoError(t, manager.CreateCommand(ctx).Append("true").Run(ctx))
+					_, err = manager.Get(ctx, "generated-2")
+					assert.NoError(t, err)
+				},
+				"GeneratorIsUsedForReusedOptions": func(ctx context.Context, t *testing.T, manager Manager) {
+					var count int
+					manager = NewIDGeneratorManager(manager, func() string {
+						count++
+						return fmt.Sprintf("generated-%d", count)
+					})
+
+					opts := testutil.TrueCreateOpts()
+					first, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					second, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					assert.Equal(t, "generated-1", first.ID())
+					assert.Equal(t, "generated-2", second.ID())
+					assert.Empty(t, opts.ID)
+				},
+				"GeneratorDuplicatesAreRejected": func(ctx context.Context, t *testing.T, manager Manager) {
+					manager = NewIDGeneratorManager(manager, func() string { return "constant" })
+
+					proc, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+					require.NoError(t, err)
+					assert.Equal(t, "constant", proc.ID())
+
+					proc, err = manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+					assert.Error(t, err)
+					assert.Nil(t, proc)
+				},
+				"NilGeneratorUsesRandomIDs": func(ctx context.Context, t *testing.T, manager Manager) {
+					assert.Equal(t, manager, NewIDGeneratorManager(manager, nil))
+
+					first, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+					require.NoError(t, err)
+					second, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+					require.NoError(t, err)
+					assert.NotEqual(t, first.ID(), second.ID())
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.ManagerTestTimeout)
+					defer cancel()
+
+					manager := makeManager(t)
+					defer func() {
+						assert.NoError(t, manager.Close(ctx))
+					}()
+
+					testCase(ctx, t, manager)
+				})
+			}
+		})
+	}
+}
+
+type mockSpan struct {
+	name        string
+	traceParent string
+	attrs       map[string]interface{}
+	errs        []error
+	ended       chan struct{}
+}
+
+func (s *mockSpan) SetAttributes(attrs map[string]interface{}) {
+	for k, v := range attrs {
+		s.attrs[k] = v
+	}
+}
+
+func (s *mockSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+
+func (s *mockSpan) End() { close(s.ended) }
+
+type mockTracer struct {
+	mu    sync.Mutex
+	spans []*mockSpan
+}
+
+func (t *mockTracer) StartSpan(_ context.Context, name string) (ProcessSpan, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &mockSpan{
+		name:        name,
+		traceParent: fmt.Sprintf("00-%032x-%016x-01", len(t.spans)+1, len(t.spans)+1),
+		attrs:       map[string]interface{}{},
+		ended:       make(chan struct{}),
+	}
+	t.spans = append(t.spans, span)
+	return span, span.traceParent
+}
+
+func (t *mockTracer) getSpans() []*mockSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*mockSpan{}, t.spans...)
+}
+
+func TestTracingManager(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	waitForEnd := func(ctx context.Context, t *testing.T, span *mockSpan) {
+		select {
+		case <-span.ended:
+		case <-ctx.Done():
+			require.FailNow(t, "span did not end")
+		}
+	}
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, base Manager){
+		"RecordsSpanPerProcess": func(ctx context.Context, t *testing.T, base Manager) {
+			tracer := &mockTracer{}
+			manager := NewTracingManager(base, tracer)
+
+			procs := []Process{}
+			for _, args := range [][]string{{"true"}, {"/bin/sh", "-c", "exit 3"}} {
+				proc, err := manager.CreateProcess(ctx, &options.Create{Args: args})
+				require.NoError(t, err)
+				_, _ = proc.Wait(ctx)
+				procs = append(procs, proc)
+			}
+
+			spans := tracer.getSpans()
+			require.Len(t, spans, 2)
+			for i, span := range spans {
+				waitForEnd(ctx, t, span)
+				info := procs[i].Info(ctx)
+				assert.Equal(t, info.ID, span.attrs[SpanAttributeProcessID])
+				assert.Equal(t, info.PID, span.attrs[SpanAttributePID])
+				assert.Equal(t, info.ExitCode, span.attrs[SpanAttributeExitCode])
+				assert.Equal(t, info.EndAt.Sub(info.StartAt), span.attrs[SpanAttributeDuration])
+			}
+
+			assert.Equal(t, "true", spans[0].name)
+			assert.Empty(t, spans[0].errs)
+			assert.Equal(t, "sh", spans[1].name)
+			assert.Equal(t, 3, spans[1].attrs[SpanAttributeExitCode])
+			assert.Len(t, spans[1].errs, 1)
+		},
+		"PropagatesTraceContext": func(ctx context.Context, t *testing.T, base Manager) {
+			tracer := &mockTracer{}
+			manager := NewTracingManager(base, tracer)
+
+			output := util.NewLocalBuffer(bytes.Buffer{})
+			opts := &options.Create{Args: []string{"/bin/sh", "-c", "echo $" + EnvironTraceParent}}
+			opts.Output.Output = output
+			proc, err := manager.CreateProcess(ctx, opts)
+			require.NoError(t, err)
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+
+			spans := tracer.getSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, spans[0].traceParent, strings.TrimSpace(output.String()))
+		},
+		"DoesNotModifyCallerOptions": func(ctx context.Context, t *testing.T, base Manager) {
+			tracer := &mockTracer{}
+			manager := NewTracingManager(base, tracer)
+
+			opts := &options.Create{
+				Args:        []string{"true"},
+				Environment: map[string]string{"FOO": "bar"},
+			}
+			for i := 0; i < 2; i++ {
+				proc, err := manager.CreateProcess(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, tracer.getSpans()[i].traceParent, proc.Info(ctx).Options.Environment[EnvironTraceParent])
+			}
+			assert.Equal(t, map[string]string{"FOO": "bar"}, opts.Environment)
+		},
+		"EndsSpanWhenCreationFails": func(ctx context.Context, t *testing.T, base Manager) {
+			tracer := &mockTracer{}
+			manager := NewTracingManager(base, tracer)
+
+			_, err := manager.CreateProcess(ctx, &options.Create{})
+			require.Error(t, err)
+
+			spans := tracer.getSpans()
+			require.Len(t, spans, 1)
+			waitForEnd(ctx, t, spans[0])
+			assert.Len(t, spans[0].errs, 1)
+		},
+		"CommandsAreTraced": func(ctx context.Context, t *testing.T, base Manager) {
+			tracer := &mockTracer{}
+			manager := NewTracingManager(base, tracer)
+
+			require.NoError(t, manager.CreateCommand(ctx).Append("true", "echo foo").Run(ctx))
+
+			spans := tracer.getSpans()
+			require.Len(t, spans, 2)
+			assert.Equal(t, "true", spans[0].name)
+			assert.Equal(t, "echo", spans[1].name)
+		},
+		"NilTracerIsNoop": func(ctx context.Context, t *testing.T, base Manager) {
+			assert.Equal(t, base, NewTracingManager(base, nil))
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			testCase(ctx, t, manager)
+		})
+	}
+}
+
+func TestManagerPruneLoggers(t *testing.T) {
+	for managerName, makeManager := range map[string]func(t *testing.T) Manager{
+		"Basic": func(t *testing.T) Manager {
+			m, err := newBasicProcessManager(map[string]Process{}, false, false)
+			require.NoError(t, err)
+			return m
+		},
+		"Synchronized": func(t *testing.T) Manager {
+			m, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			return m
+		},
+	} {
+		t.Run(managerName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.ManagerTestTimeout)
+			defer cancel()
+
+			t.Run("PrunesOnlyStaleLoggersOfProcessesThatAreNotRunning", func(t *testing.T) {
+				manager := makeManager(t)
+				defer func() {
+					assert.NoError(t, manager.Close(ctx))
+				}()
+				cache := manager.LoggingCache(ctx)
+				stale := time.Now().Add(-time.Hour)
+
+				staleSender := options.NewMockSender("stale")
+				require.NoError(t, cache.Put("stale", &options.CachedLogger{ID: "stale", Output: staleSender}))
+				cache.Get("stale").Accessed = stale
+
+				freshSender := options.NewMockSender("fresh")
+				require.NoError(t, cache.Put("fresh", &options.CachedLogger{ID: "fresh", Output: freshSender}))
+
+				completed, err := manager.CreateProcess(ctx, testutil.TrueCreateOpts())
+				require.NoError(t, err)
+				_, err = completed.Wait(ctx)
+				require.NoError(t, err)
+				cache.Get(completed.ID()).Accessed = stale
+
+				running, err := manager.CreateProcess(ctx, testutil.SleepCreateOpts(10))
+				require.NoError(t, err)
+				cache.Get(running.ID()).Accessed = stale
+
+				pruned, err := manager.PruneLoggers(ctx, time.Minute)
+				require.NoError(t, err)
+				assert.Equal(t, 2, pruned)
+
+				assert.Nil(t, cache.Get("stale"))
+				assert.True(t, staleSender.Closed)
+				assert.Nil(t, cache.Get(completed.ID()))
+				assert.NotNil(t, cache.Get("fresh"))
+				assert.False(t, freshSender.Closed)
+				assert.NotNil(t, cache.Get(running.ID()))
+			})
+			t.Run("ReturnsZeroWithoutStaleLoggers", func(t *testing.T) {
+				manager := makeManager(t)
+				require.NoError(t, manager.LoggingCache(ctx).Put("fresh", &options.CachedLogger{ID: "fresh"}))
+
+				pruned, err := manager.PruneLoggers(ctx, time.Minute)
+				require.NoError(t, err)
+				assert.Zero(t, pruned)
+				assert.Equal(t, 1, manager.LoggingCache(ctx).Len())
+			})
+			t.Run("FailsWithNegativeAge", func(t *testing.T) {
+				_, err := makeManager(t).PruneLoggers(ctx, -time.Minute)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestManagerRegisterExternal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process liveness checks are not reliable on windows")
+	}
+
+	startExternal := func(t *testing.T) (*exec.Cmd, <-chan error) {
+		cmd := exec.Command("sleep", "10")
+		require.NoError(t, cmd.Start())
+		// The test process must reap the external process once it exits,
+		// since it is this process's child.
+		waitErr := make(chan error, 1)
+		go func() {
+			waitErr <- cmd.Wait()
+		}()
+		return cmd, waitErr
+	}
+
+	for managerName, makeManager := range map[string]func(t *testing.T) Manager{
+		"Basic": func(t *testing.T) Manager {
+			m, err := newBasicProcessManager(map[string]Process{}, false, false)
+			require.NoError(t, err)
+			return m
+		},
+		"Synchronized": func(t *testing.T) Manager {
+			m, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			return m
+		},
+	} {
+		t.Run(managerName, func(t *testing.T) {
+			for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, manager Manager){
+				"ImportedProcessCanBeSignaled": func(ctx context.Context, t *testing.T, manager Manager) {
+					cmd, waitErr := startExternal(t)
+
+					proc, err := manager.RegisterExternal(ctx, cmd.Process.Pid, &options.Create{
+						Args: []string{"sleep", "10"},
+						Tags: []string{"external"},
+					})
+					require.NoError(t, err)
+					assert.True(t, proc.Running(ctx))
+					info := proc.Info(ctx)
+					assert.Equal(t, cmd.Process.Pid, info.PID)
+					assert.Equal(t, []string{"sleep", "10"}, info.Options.Args)
+					assert.NotEmpty(t, proc.ID())
+
+					found, err := manager.Get(ctx, proc.ID())
+					require.NoError(t, err)
+					assert.Equal(t, proc.ID(), found.ID())
+					procs, err := manager.Group(ctx, "external")
+					require.NoError(t, err)
+					assert.Len(t, procs, 1)
+
+					require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+					assert.Error(t, <-waitErr)
+
+					exitCode, err := proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, -1, exitCode)
+					assert.True(t, proc.Complete(ctx))
+					assert.False(t, proc.Info(ctx).IsRunning)
+				},
+				"UsesIDFromOptions": func(ctx context.Context, t *testing.T, manager Manager) {
+					cmd, waitErr := startExternal(t)
+
+					proc, err := manager.RegisterExternal(ctx, cmd.Process.Pid, &options.Create{ID: "external"})
+					require.NoError(t, err)
+					assert.Equal(t, "external", proc.ID())
+
+					dup, err := manager.RegisterExternal(ctx, cmd.Process.Pid, &options.Create{ID: "external"})
+					assert.Error(t, err)
+					assert.Nil(t, dup)
+
+					require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+					assert.Error(t, <-waitErr)
+				},
+				"OptionsAreOptional": func(ctx context.Context, t *testing.T, manager Manager) {
+					cmd, waitErr := startExternal(t)
+
+					proc, err := manager.RegisterExternal(ctx, cmd.Process.Pid, nil)
+					require.NoError(t, err)
+					assert.True(t, proc.Running(ctx))
+
+					require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+					assert.Error(t, <-waitErr)
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+				},
+				"CompletesWhenProcessExitsOnItsOwn": func(ctx context.Context, t *testing.T, manager Manager) {
+					cmd := exec.Command("sleep", "1")
+					require.NoError(t, cmd.Start())
+
+					proc, err := manager.RegisterExternal(ctx, cmd.Process.Pid, nil)
+					require.NoError(t, err)
+					assert.True(t, proc.Running(ctx))
+					assert.False(t, proc.Complete(ctx))
+
+					// The process is only reaped by this process, so it
+					// must be waited on for it to stop being alive.
+					assert.NoError(t, cmd.Wait())
+					assert.Eventually(t, func() bool {
+						return proc.Complete(ctx)
+					}, 5*time.Second, 50*time.Millisecond)
+					assert.False(t, proc.Running(ctx))
+
+					exitCode, err := proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, -1, exitCode)
+				},
+				"FailsForExitedProcess": func(ctx context.Context, t *testing.T, manager Manager) {
+					cmd := exec.Command("true")
+					require.NoError(t, cmd.Run())
+
+					proc, err := manager.RegisterExternal(ctx, cmd.Process.Pid, nil)
+					assert.Error(t, err)
+					assert.Nil(t, proc)
+
+					procs, err := manager.List(ctx, options.All)
+					require.NoError(t, err)
+					assert.Empty(t, procs)
+				},
+				"FailsForInvalidPID": func(ctx context.Context, t *testing.T, manager Manager) {
+					for _, pid := range []int{0, -1} {
+						proc, err := manager.RegisterExternal(ctx, pid, nil)
+						assert.Error(t, err)
+						assert.Nil(t, proc)
+					}
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.ManagerTestTimeout)
+					defer cancel()
+
+					manager := makeManager(t)
+					defer func() {
+						assert.NoError(t, manager.Close(ctx))
+					}()
+
+					testCase(ctx, t, manager)
+				})
+			}
+		})
+	}
+}
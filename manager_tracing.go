@@ -0,0 +1,126 @@
+package jasper
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/jasper/options"
+)
+
+// EnvironTraceParent is the environment variable through which the trace
+// context of a process's span is propagated to the process by a tracing
+// manager, in the W3C Trace Context traceparent format.
+const EnvironTraceParent = "TRACEPARENT"
+
+// Span attributes that a tracing manager records for each process.
+const (
+	SpanAttributeProcessID = "jasper.process.id"
+	SpanAttributePID       = "process.pid"
+	SpanAttributeExitCode  = "process.exit_code"
+	SpanAttributeDuration  = "process.duration"
+)
+
+// ProcessTracer creates the spans that a tracing manager records for its
+// processes. It is implemented by adapters for tracing libraries, such as
+// OpenTelemetry, so that jasper does not depend on them directly.
+type ProcessTracer interface {
+	// StartSpan starts a span with the given name as a child of any span
+	// in the context. It returns the span and the traceparent value that
+	// identifies it, which may be empty if the span is not sampled.
+	StartSpan(ctx context.Context, name string) (span ProcessSpan, traceParent string)
+}
+
+// ProcessSpan is a span started by a ProcessTracer.
+type ProcessSpan interface {
+	// SetAttributes adds the attributes to the span.
+	SetAttributes(attrs map[string]interface{})
+	// RecordError records that the operation described by the span
+	// failed.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+type tracingManager struct {
+	Manager
+	tracer ProcessTracer
+}
+
+// NewTracingManager returns a manager that wraps an existing manager, but
+// records a span with the given tracer for each process that it creates. The
+// span is named after the command, lasts until the process exits, and records
+// the process's exit code and duration. The span's trace context is
+// propagated to the process through the EnvironTraceParent environment
+// variable. If the tracer is nil, the manager is returned unchanged.
+func NewTracingManager(m Manager, tracer ProcessTracer) Manager {
+	if tracer == nil {
+		return m
+	}
+
+	return &tracingManager{
+		Manager: m,
+		tracer:  tracer,
+	}
+}
+
+func (m *tracingManager) CreateProcess(ctx context.Context, opts *options.Create) (Process, error) {
+	span, traceParent := m.tracer.StartSpan(ctx, spanName(opts))
+	if traceParent != "" {
+		// Each process has its own trace context, so the caller's
+		// options must not be modified in case they are reused.
+		optsCopy := opts.Copy()
+		optsCopy.RegisterCloser(opts.Close)
+		optsCopy.AddEnvVar(EnvironTraceParent, traceParent)
+		opts = optsCopy
+	}
+
+	proc, err := m.Manager.CreateProcess(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.End()
+		return nil, errors.WithStack(err)
+	}
+
+	once := &sync.Once{}
+	end := func(info ProcessInfo) {
+		once.Do(func() {
+			span.SetAttributes(map[string]interface{}{
+				SpanAttributeProcessID: info.ID,
+				SpanAttributePID:       info.PID,
+				SpanAttributeExitCode:  info.ExitCode,
+				SpanAttributeDuration:  info.EndAt.Sub(info.StartAt),
+			})
+			if !info.Successful {
+				span.RecordError(errors.Errorf("process exited with code %d", info.ExitCode))
+			}
+			span.End()
+		})
+	}
+
+	// The trigger cannot be registered if the process has already
+	// completed or the process does not support triggers, in which case
+	// the span ends once waiting on the process returns.
+	if err = proc.RegisterTrigger(ctx, end); err != nil {
+		go func() {
+			_, _ = proc.Wait(context.Background())
+			end(proc.Info(context.Background()))
+		}()
+	}
+
+	return proc, nil
+}
+
+func (m *tracingManager) CreateCommand(ctx context.Context) *Command {
+	return m.Manager.CreateCommand(ctx).ProcConstructor(m.CreateProcess)
+}
+
+// spanName returns the name of the span for a process created with the given
+// options, which is the name of the command's executable.
+func spanName(opts *options.Create) string {
+	if len(opts.Args) == 0 {
+		return "process"
+	}
+	return filepath.Base(opts.Args[0])
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
+	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -54,6 +55,9 @@ func (c *LoggingCache) Put(id string, logger *options.CachedLogger) error {
 		}
 	}
 
+	if logger.Fallback != nil {
+		logger.SetFallback(logger.Fallback)
+	}
 	c.Cache[id] = logger
 	return nil
 }
@@ -61,6 +65,14 @@ func (c *LoggingCache) Put(id string, logger *options.CachedLogger) error {
 // Get returns an object from the in-memory logging cache.
 func (c *LoggingCache) Get(id string) *options.CachedLogger { return c.Cache[id] }
 
+// SendToMany sends the payload to each of the cached loggers with the given
+// IDs.
+func (c *LoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return jasper.SendToMany(c, ids, lp, func(logger *options.CachedLogger, lp *options.LoggingPayload) error {
+		return logger.Send(lp)
+	})
+}
+
 // Remove removes an object from the in-memory logging cache.
 func (c *LoggingCache) Remove(id string) { delete(c.Cache, id) }
 
@@ -88,6 +100,19 @@ func (c *LoggingCache) Clear(_ context.Context) error {
 	return catcher.Resolve()
 }
 
+// Drain flushes, closes and removes all objects in the in-memory logging
+// cache.
+func (c *LoggingCache) Drain(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	for _, logger := range c.Cache {
+		catcher.Add(logger.Flush(ctx))
+		catcher.Add(logger.Close())
+	}
+	c.Cache = map[string]*options.CachedLogger{}
+
+	return catcher.Resolve()
+}
+
 // Prune removes all items from the cache whose most recent access time is older
 // than lastAccessed.
 func (c *LoggingCache) Prune(lastAccessed time.Time) {
@@ -3,6 +3,8 @@ package mock
 import (
 	"context"
 	"runtime"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/jasper"
@@ -15,18 +17,27 @@ import (
 type Manager struct {
 	FailCreate      bool
 	FailRegister    bool
+	FailRegisterExt bool
 	FailList        bool
 	FailGroup       bool
+	FailSignalGroup bool
+	FailWaitForTag  bool
+	FailSetTagLimit bool
 	FailGet         bool
 	FailClose       bool
 	NilLoggingCache bool
 	FailWriteFile   bool
+	FailSnapshot    bool
+	FailPrune       bool
 	Create          func(*options.Create) Process
 	CreateConfig    Process
 	ManagerID       string
 	Procs           []jasper.Process
 	ScriptingEnv    scripting.Harness
 	LoggingCacheVal jasper.LoggingCache
+	SnapshotData    []byte
+	PrunedLoggers   int
+	TagLimits       map[string]int
 
 	// WriteFile input
 	WriteFileOptions options.WriteFile
@@ -93,6 +104,25 @@ func (m *Manager) Register(ctx context.Context, proc jasper.Process) error {
 	return nil
 }
 
+// RegisterExternal creates a new mock Process for the external process with
+// the given PID using CreateConfig as a template and puts it in Procs. If
+// FailRegisterExt is set, it returns an error.
+func (m *Manager) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	if m.FailRegisterExt {
+		return nil, mockFail()
+	}
+
+	proc := m.CreateConfig
+	proc.ProcInfo.PID = pid
+	if opts != nil {
+		proc.ProcInfo.Options = *opts
+	}
+
+	m.Procs = append(m.Procs, &proc)
+
+	return &proc, nil
+}
+
 // List returns all processes that match the given filter. If FailList is set,
 // it returns an error.
 func (m *Manager) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
@@ -150,6 +180,41 @@ func (m *Manager) Group(ctx context.Context, tag string) ([]jasper.Process, erro
 	return matchingProcs, nil
 }
 
+// SignalGroup signals all running processes in Procs that have the given tag.
+// If FailSignalGroup is set, it returns an error.
+func (m *Manager) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	if m.FailSignalGroup {
+		return mockFail()
+	}
+
+	return jasper.SignalGroup(ctx, m, tag, sig)
+}
+
+// WaitForTag waits for all processes in Procs that have the given tag. If
+// FailWaitForTag is set, it returns an error.
+func (m *Manager) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	if m.FailWaitForTag {
+		return nil, mockFail()
+	}
+
+	return jasper.WaitForTag(ctx, m, tag)
+}
+
+// SetTagLimit records the limit for the tag in TagLimits. If FailSetTagLimit
+// is set, it returns an error.
+func (m *Manager) SetTagLimit(ctx context.Context, tag string, max int) error {
+	if m.FailSetTagLimit {
+		return mockFail()
+	}
+
+	if m.TagLimits == nil {
+		m.TagLimits = map[string]int{}
+	}
+	m.TagLimits[tag] = max
+
+	return nil
+}
+
 // Get returns a process given by ID from Procs. If a matching process is not
 // found in Procs or if FailGet is set, it returns an error.
 func (m *Manager) Get(ctx context.Context, id string) (jasper.Process, error) {
@@ -163,7 +228,7 @@ func (m *Manager) Get(ctx context.Context, id string) (jasper.Process, error) {
 		}
 	}
 
-	return nil, errors.Errorf("proc with id '%s' not found", id)
+	return nil, errors.Wrapf(jasper.ErrProcessNotFound, "proc with id '%s'", id)
 }
 
 // Clear removes all processes from Procs.
@@ -181,6 +246,40 @@ func (m *Manager) Close(ctx context.Context) error {
 	return nil
 }
 
+// Stats computes the stats for the processes in Procs.
+func (m *Manager) Stats(ctx context.Context) jasper.ManagerStats {
+	return jasper.NewManagerStats(ctx, m.Procs)
+}
+
+// Subscribe returns a channel that receives no events and is closed when the
+// context is done.
+func (m *Manager) Subscribe(ctx context.Context) <-chan jasper.ProcessEvent {
+	events := make(chan jasper.ProcessEvent)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events
+}
+
+// Snapshot returns an error if FailSnapshot is set. Otherwise, it returns
+// SnapshotData.
+func (m *Manager) Snapshot(ctx context.Context) ([]byte, error) {
+	if m.FailSnapshot {
+		return nil, mockFail()
+	}
+	return m.SnapshotData, nil
+}
+
+// PruneLoggers returns an error if FailPrune is set. Otherwise, it returns
+// PrunedLoggers.
+func (m *Manager) PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error) {
+	if m.FailPrune {
+		return 0, mockFail()
+	}
+	return m.PrunedLoggers, nil
+}
+
 func (m *Manager) WriteFile(ctx context.Context, opts options.WriteFile) error {
 	if m.FailWriteFile {
 		return mockFail()
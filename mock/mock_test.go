@@ -1,9 +1,12 @@
 package mock
 
 import (
+	"context"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/remote"
 )
@@ -13,3 +16,74 @@ func TestMockInterfaces(t *testing.T) {
 	assert.Implements(t, (*jasper.Process)(nil), &Process{})
 	assert.Implements(t, (*remote.Manager)(nil), &RemoteClient{})
 }
+
+func TestManagerSignalGroup(t *testing.T) {
+	ctx := context.Background()
+	running := jasper.ProcessInfo{IsRunning: true}
+
+	failing := &Process{ProcInfo: running, Tags: []string{"group"}, FailSignal: true}
+	succeeding := &Process{ProcInfo: running, Tags: []string{"group"}}
+	completed := &Process{Tags: []string{"group"}}
+	untagged := &Process{ProcInfo: running}
+	m := &Manager{Procs: []jasper.Process{failing, succeeding, completed, untagged}}
+
+	assert.Error(t, m.SignalGroup(ctx, "group", syscall.SIGTERM))
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, succeeding.Signals)
+	assert.Empty(t, completed.Signals)
+	assert.Empty(t, untagged.Signals)
+
+	m.FailSignalGroup = true
+	assert.Error(t, m.SignalGroup(ctx, "group", syscall.SIGTERM))
+	assert.Len(t, succeeding.Signals, 1)
+}
+
+func TestManagerWaitForTag(t *testing.T) {
+	ctx := context.Background()
+
+	failing := &Process{ProcInfo: jasper.ProcessInfo{ID: "failing"}, Tags: []string{"group"}, FailWait: true}
+	succeeding := &Process{ProcInfo: jasper.ProcessInfo{ID: "succeeding"}, Tags: []string{"group"}}
+	untagged := &Process{ProcInfo: jasper.ProcessInfo{ID: "untagged"}}
+	m := &Manager{Procs: []jasper.Process{failing, succeeding, untagged}}
+
+	results, err := m.WaitForTag(ctx, "group")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Error(t, results["failing"])
+	assert.NoError(t, results["succeeding"])
+
+	m.FailWaitForTag = true
+	_, err = m.WaitForTag(ctx, "group")
+	assert.Error(t, err)
+}
+
+func TestManagerSetTagLimit(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Manager{}
+	require.NoError(t, m.SetTagLimit(ctx, "build", 3))
+	assert.Equal(t, map[string]int{"build": 3}, m.TagLimits)
+
+	m.FailSetTagLimit = true
+	assert.Error(t, m.SetTagLimit(ctx, "build", 1))
+	assert.Equal(t, 3, m.TagLimits["build"])
+}
+
+func TestProcessDone(t *testing.T) {
+	proc := &Process{}
+	done := proc.Done()
+	assert.Equal(t, done, proc.Done())
+	select {
+	case <-done:
+		assert.Fail(t, "done channel closed before process completed")
+	default:
+	}
+
+	proc.ProcInfo.Complete = true
+	assert.Equal(t, done, proc.Done())
+	select {
+	case <-done:
+	default:
+		assert.Fail(t, "done channel not closed after process completed")
+	}
+	assert.Equal(t, done, proc.Done())
+}
@@ -2,7 +2,9 @@ package mock
 
 import (
 	"context"
+	"regexp"
 	"syscall"
+	"time"
 
 	"github.com/tychoish/jasper"
 )
@@ -12,18 +14,29 @@ import (
 type Process struct {
 	FailRespawn                 bool
 	FailRegisterTrigger         bool
+	FailRegisterNamedTrigger    bool
 	FailRegisterSignalTrigger   bool
 	FailRegisterSignalTriggerID bool
 	FailSignal                  bool
+	FailStart                   bool
+	FailChildPIDs               bool
 	FailWait                    bool
+	FailWaitForOutput           bool
 	WaitExitCode                int
 
 	ProcInfo         jasper.ProcessInfo
 	Triggers         jasper.ProcessTriggerSequence
+	PostTriggers     jasper.ProcessTriggerSequence
+	ContextTriggers  []jasper.ProcessTriggerWithContext
+	NamedTriggers    map[string]jasper.ProcessTrigger
 	SignalTriggers   jasper.SignalTriggerSequence
 	SignalTriggerIDs []jasper.SignalTriggerID
 	Signals          []syscall.Signal
+	OutputPatterns   []*regexp.Regexp
 	Tags             []string
+	Children         []int
+
+	done chan struct{}
 }
 
 // ID returns the ID set in ProcInfo set by the user.
@@ -36,6 +49,11 @@ func (p *Process) Info(ctx context.Context) jasper.ProcessInfo {
 	return p.ProcInfo
 }
 
+// InfoWith returns the ProcInfo field set by the user.
+func (p *Process) InfoWith(ctx context.Context, _ jasper.InfoOptions) jasper.ProcessInfo {
+	return p.ProcInfo
+}
+
 // Running returns the IsRunning field set by the user.
 func (p *Process) Running(ctx context.Context) bool {
 	return p.ProcInfo.IsRunning
@@ -61,6 +79,36 @@ func (p *Process) ResetTags() {
 	p.Tags = []string{}
 }
 
+// Start marks the process as started and running. If FailStart is set, it
+// returns an error.
+func (p *Process) Start(ctx context.Context) error {
+	if p.FailStart {
+		return mockFail()
+	}
+
+	p.ProcInfo.NotStarted = false
+	p.ProcInfo.IsRunning = true
+
+	return nil
+}
+
+// ResolvedCommand returns the arguments, environment and working directory
+// from the options in ProcInfo.
+func (p *Process) ResolvedCommand() ([]string, []string, string) {
+	opts := p.ProcInfo.Options
+	return opts.Args, opts.ResolveEnvironment(), opts.WorkingDirectory
+}
+
+// ChildPIDs returns the PIDs set in Children. If FailChildPIDs is set, it
+// returns an error.
+func (p *Process) ChildPIDs(ctx context.Context) ([]int, error) {
+	if p.FailChildPIDs {
+		return nil, mockFail()
+	}
+
+	return p.Children, nil
+}
+
 // Signal records the signals sent to the process in Signals. If FailSignal is
 // set, it returns an error.
 func (p *Process) Signal(ctx context.Context, sig syscall.Signal) error {
@@ -83,6 +131,47 @@ func (p *Process) Wait(ctx context.Context) (int, error) {
 	return p.ProcInfo.ExitCode, nil
 }
 
+// Done returns the same channel every time it is called. The channel is
+// closed once Done is called while the Complete field set by the user is
+// true.
+func (p *Process) Done() <-chan struct{} {
+	if p.done == nil {
+		p.done = make(chan struct{})
+	}
+	if p.ProcInfo.Complete {
+		select {
+		case <-p.done:
+		default:
+			close(p.done)
+		}
+	}
+	return p.done
+}
+
+// WaitWithProgress waits for the process using Wait, calling the callback
+// with ProcInfo until it completes.
+func (p *Process) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(jasper.ProcessInfo)) error {
+	return jasper.WaitWithProgress(ctx, p, interval, cb)
+}
+
+// WaitResult waits for the process using Wait and returns the result
+// reported by ProcInfo.
+func (p *Process) WaitResult(ctx context.Context) (jasper.WaitResult, error) {
+	return jasper.WaitForResult(ctx, p)
+}
+
+// WaitForOutput records the pattern in OutputPatterns. If FailWaitForOutput is
+// set, it returns an error.
+func (p *Process) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	if p.FailWaitForOutput {
+		return mockFail()
+	}
+
+	p.OutputPatterns = append(p.OutputPatterns, pattern)
+
+	return nil
+}
+
 // Respawn creates a new Process, which has a copy of all the fields in the
 // current Process. If FailRespawn is set, it returns an error.
 func (p *Process) Respawn(ctx context.Context) (jasper.Process, error) {
@@ -105,6 +194,60 @@ func (p *Process) RegisterTrigger(ctx context.Context, t jasper.ProcessTrigger)
 	return nil
 }
 
+// RegisterTriggerWithContext records the trigger in ContextTriggers. If
+// FailRegisterTrigger is set, it returns an error.
+func (p *Process) RegisterTriggerWithContext(ctx context.Context, t jasper.ProcessTriggerWithContext) error {
+	if p.FailRegisterTrigger {
+		return mockFail()
+	}
+
+	p.ContextTriggers = append(p.ContextTriggers, t)
+
+	return nil
+}
+
+// RegisterTriggerWithPhase records the trigger in Triggers if the phase is
+// TriggerPhasePre or in PostTriggers if it is TriggerPhasePost. If
+// FailRegisterTrigger is set or the phase is invalid, it returns an error.
+func (p *Process) RegisterTriggerWithPhase(ctx context.Context, phase jasper.TriggerPhase, t jasper.ProcessTrigger) error {
+	if p.FailRegisterTrigger {
+		return mockFail()
+	}
+
+	switch phase {
+	case jasper.TriggerPhasePre:
+		p.Triggers = append(p.Triggers, t)
+	case jasper.TriggerPhasePost:
+		p.PostTriggers = append(p.PostTriggers, t)
+	default:
+		return mockFail()
+	}
+
+	return nil
+}
+
+// RegisterNamedTrigger records the trigger in Triggers and NamedTriggers. If
+// FailRegisterNamedTrigger is set or the name is already in NamedTriggers, it
+// returns an error.
+func (p *Process) RegisterNamedTrigger(ctx context.Context, name string, t jasper.ProcessTrigger) error {
+	if p.FailRegisterNamedTrigger {
+		return mockFail()
+	}
+
+	if _, ok := p.NamedTriggers[name]; ok {
+		return mockFail()
+	}
+
+	if p.NamedTriggers == nil {
+		p.NamedTriggers = map[string]jasper.ProcessTrigger{}
+	}
+
+	p.NamedTriggers[name] = t
+	p.Triggers = append(p.Triggers, t)
+
+	return nil
+}
+
 // RegisterSignalTrigger records the signal trigger in SignalTriggers. If
 // FailRegisterSignalTrigger is set, it returns an error.
 func (p *Process) RegisterSignalTrigger(ctx context.Context, t jasper.SignalTrigger) error {
@@ -15,6 +15,7 @@ type RemoteClient struct {
 	FailCloseConnection bool
 	FailDownloadFile    bool
 	FailGetLogStream    bool
+	FailStreamLogs      bool
 	FailSignalEvent     bool
 	FailCreateScripting bool
 	FailGetScripting    bool
@@ -68,6 +69,25 @@ func (c *RemoteClient) GetLogStream(ctx context.Context, id string, count int) (
 	return c.LogStream, nil
 }
 
+// StreamLogs stores the given log stream ID and returns a closed channel
+// containing the logs in LogStream. If FailStreamLogs is set, it returns an
+// error.
+func (c *RemoteClient) StreamLogs(ctx context.Context, id string) (<-chan []byte, error) {
+	if c.FailStreamLogs {
+		return nil, mockFail()
+	}
+
+	c.LogStreamID = id
+
+	logs := make(chan []byte, len(c.LogStream.Logs))
+	for _, log := range c.LogStream.Logs {
+		logs <- []byte(log)
+	}
+	close(logs)
+
+	return logs, nil
+}
+
 // SignalEvent stores the given event name. If FailSignalEvent is set, it
 // returns an error.
 func (c *RemoteClient) SignalEvent(ctx context.Context, name string) error {
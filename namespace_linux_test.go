@@ -0,0 +1,47 @@
+// +build linux
+
+package jasper
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+	"github.com/tychoish/jasper/util"
+)
+
+func TestProcessNamespaces(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating PID namespaces requires root privileges")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			output := util.NewLocalBuffer(bytes.Buffer{})
+			proc, err := NewProcess(ctx, &options.Create{
+				Args:           []string{"sh", "-c", "echo $$"},
+				Implementation: impl,
+				Namespaces:     []options.NamespaceType{options.NamespacePID},
+				Output:         options.Output{Output: output},
+			})
+			require.NoError(t, err)
+
+			exitCode, err := proc.Wait(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, exitCode)
+			assert.Equal(t, "1", strings.TrimSpace(output.String()))
+		})
+	}
+}
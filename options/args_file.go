@@ -0,0 +1,117 @@
+package options
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// ArgsFileMode determines how the arguments of a process are passed to it
+// through a temporary file rather than on the command line, which allows
+// running commands whose arguments exceed the operating system's limit on
+// the length of the command line.
+type ArgsFileMode string
+
+// Supported args file modes, which can be used in Create.ArgsFile.
+const (
+	// ArgsFileResponse writes every argument after the first to a
+	// response file, one per line, and runs the command with the single
+	// argument "@<file>". Arguments that contain whitespace, quotes or
+	// backslashes are double quoted, with quotes and backslashes escaped
+	// by a backslash. The command must support response files, as many
+	// compilers, linkers and other build tools do.
+	ArgsFileResponse ArgsFileMode = "response"
+	// ArgsFileSplit writes every argument after the first to a file and
+	// runs the command with xargs, which splits the arguments across as
+	// many invocations of the command as necessary. The process fails
+	// if any of the invocations fails. Since the file is read from
+	// the process's standard input, this mode cannot be combined with
+	// standard input.
+	ArgsFileSplit ArgsFileMode = "split"
+)
+
+// Validate checks that the args file mode is recognized.
+func (m ArgsFileMode) Validate() error {
+	switch m {
+	case ArgsFileResponse, ArgsFileSplit:
+		return nil
+	default:
+		return errors.Errorf("unrecognized args file mode '%s'", m)
+	}
+}
+
+// writeArgsFile writes all but the first of the arguments to a temporary
+// file according to the args file mode, registering a closer that removes
+// it. The arguments must include at least one argument after the first. It
+// returns the arguments that run the command with the file and, if the
+// command reads the file from its standard input, the file to use as
+// standard input.
+func (opts *Create) writeArgsFile(args []string) (_ []string, _ io.Reader, err error) {
+	file, err := ioutil.TempFile("", "jasper-args-")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "problem creating arguments file")
+	}
+	path := file.Name()
+	opts.argsFile = path
+	defer func() {
+		if err != nil {
+			grip.Warning(errors.Wrap(file.Close(), "problem closing arguments file"))
+			grip.Warning(removeArgsFile(path))
+		}
+	}()
+
+	var contents strings.Builder
+	for _, arg := range args[1:] {
+		switch opts.ArgsFile {
+		case ArgsFileResponse:
+			contents.WriteString(quoteResponseFileArg(arg))
+			contents.WriteByte('\n')
+		case ArgsFileSplit:
+			contents.WriteString(arg)
+			contents.WriteByte(0)
+		}
+	}
+
+	if _, err = io.WriteString(file, contents.String()); err != nil {
+		return nil, nil, errors.Wrap(err, "problem writing arguments file")
+	}
+
+	if opts.ArgsFile == ArgsFileSplit {
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, errors.Wrap(err, "problem rewinding arguments file")
+		}
+	}
+
+	opts.closers = append(opts.closers, func() error {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(file.Close(), "problem closing arguments file")
+		catcher.Add(removeArgsFile(path))
+		return catcher.Resolve()
+	})
+
+	if opts.ArgsFile == ArgsFileSplit {
+		return []string{"xargs", "-0", args[0]}, file, nil
+	}
+	return []string{args[0], "@" + path}, nil, nil
+}
+
+// removeArgsFile removes the arguments file at the given path, if it exists.
+func removeArgsFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "problem removing arguments file")
+	}
+	return nil
+}
+
+// quoteResponseFileArg quotes the argument, if necessary, so that it is read
+// as a single argument from a response file.
+func quoteResponseFileArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\r\n\"'\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
@@ -0,0 +1,78 @@
+package options
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/birch"
+)
+
+// BSONDocumentReader frames a stream of concatenated BSON documents,
+// using the length prefix of each document to find its boundaries.
+// Use Next to advance through the stream, Document to access the
+// current document, and Err to check for errors once Next returns
+// false.
+type BSONDocumentReader struct {
+	reader io.Reader
+	doc    []byte
+	err    error
+}
+
+// NewBSONDocumentReader returns a BSONDocumentReader that reads
+// documents from the given reader.
+func NewBSONDocumentReader(r io.Reader) *BSONDocumentReader {
+	return &BSONDocumentReader{reader: r}
+}
+
+// Next reads the next document from the stream, returning false when
+// the stream is exhausted or if it encounters an error.
+func (br *BSONDocumentReader) Next() bool {
+	if br.err != nil {
+		return false
+	}
+
+	doc := birch.DC.New()
+	_, err := doc.ReadFrom(br.reader)
+	if err == io.EOF {
+		br.doc = nil
+		return false
+	}
+	if err != nil {
+		br.doc = nil
+		br.err = errors.Wrap(err, "problem reading bson from message data")
+		return false
+	}
+
+	payload, err := doc.MarshalBSON()
+	if err != nil {
+		br.doc = nil
+		br.err = errors.Wrap(err, "problem constructing bson form")
+		return false
+	}
+
+	br.doc = payload
+	return true
+}
+
+// Document returns the document read by the last call to Next.
+func (br *BSONDocumentReader) Document() []byte { return br.doc }
+
+// Err returns the first error encountered while reading the stream.
+// Reaching the end of the stream is not an error.
+func (br *BSONDocumentReader) Err() error { return br.err }
+
+// ReadBSONDocuments reads all of the BSON documents from the reader,
+// returning an error if the stream ends with a partial document.
+func ReadBSONDocuments(r io.Reader) ([][]byte, error) {
+	out := [][]byte{}
+	iter := NewBSONDocumentReader(r)
+	for iter.Next() {
+		out = append(out, iter.Document())
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package options
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReadBSONDocuments(t *testing.T) {
+	makeStream := func(t *testing.T, num int) (*bytes.Buffer, [][]byte) {
+		buf := &bytes.Buffer{}
+		docs := [][]byte{}
+		for i := 0; i < num; i++ {
+			doc, err := bson.Marshal(map[string]interface{}{"idx": i, "msg": "hello world!"})
+			require.NoError(t, err)
+			_, err = buf.Write(doc)
+			require.NoError(t, err)
+			docs = append(docs, doc)
+		}
+		return buf, docs
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		out, err := ReadBSONDocuments(&bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+	t.Run("MultipleDocuments", func(t *testing.T) {
+		buf, docs := makeStream(t, 5)
+		out, err := ReadBSONDocuments(buf)
+		require.NoError(t, err)
+		require.Len(t, out, 5)
+		for idx := range docs {
+			assert.Equal(t, docs[idx], out[idx])
+		}
+	})
+	t.Run("TruncatedTrailingDocument", func(t *testing.T) {
+		buf, _ := makeStream(t, 3)
+		buf.Truncate(buf.Len() - 3)
+		out, err := ReadBSONDocuments(buf)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "problem reading bson from message data")
+		assert.Nil(t, out)
+	})
+	t.Run("TruncatedLengthPrefix", func(t *testing.T) {
+		buf, _ := makeStream(t, 1)
+		_, err := buf.Write([]byte{0x10, 0x00})
+		require.NoError(t, err)
+		_, err = ReadBSONDocuments(buf)
+		assert.Error(t, err)
+	})
+	t.Run("Streaming", func(t *testing.T) {
+		buf, docs := makeStream(t, 3)
+		buf.Truncate(buf.Len() - 1)
+		iter := NewBSONDocumentReader(buf)
+
+		require.True(t, iter.Next())
+		assert.Equal(t, docs[0], iter.Document())
+		require.True(t, iter.Next())
+		assert.Equal(t, docs[1], iter.Document())
+		assert.NoError(t, iter.Err())
+
+		assert.False(t, iter.Next())
+		assert.Nil(t, iter.Document())
+		assert.Error(t, iter.Err())
+		assert.False(t, iter.Next())
+	})
+}
@@ -0,0 +1,30 @@
+package options
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// validateCPUAffinity checks that the CPU indices are unique and refer to
+// CPUs that the current process is allowed to run on. A process cannot be
+// given CPUs outside of its parent's affinity mask, and when the parent is
+// pinned to specific CPUs, their indices need not start at zero.
+func validateCPUAffinity(cpus []int) error {
+	allowed, err := allowedCPUs()
+	if err != nil {
+		return errors.Wrap(err, "problem getting the CPUs available to the process")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	seen := make(map[int]struct{}, len(cpus))
+	for _, cpu := range cpus {
+		if _, ok := allowed[cpu]; !ok {
+			catcher.Errorf("CPU index %d is not one of the CPUs available to the process", cpu)
+		}
+		if _, ok := seen[cpu]; ok {
+			catcher.Errorf("CPU index %d is specified more than once", cpu)
+		}
+		seen[cpu] = struct{}{}
+	}
+	return catcher.Resolve()
+}
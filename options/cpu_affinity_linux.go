@@ -0,0 +1,39 @@
+// +build linux
+
+package options
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// maxCPUMaskWords bounds the size of the CPU mask passed to
+// sched_getaffinity(2), which fails if the mask is smaller than the kernel's.
+const maxCPUMaskWords = 1024
+
+// allowedCPUs returns the indices of the CPUs in the current process's
+// affinity mask using sched_getaffinity(2).
+func allowedCPUs() (map[int]struct{}, error) {
+	for words := 16; words <= maxCPUMaskWords; words *= 2 {
+		mask := make([]uint64, words)
+		n, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno == syscall.EINVAL {
+			continue
+		}
+		if errno != 0 {
+			return nil, errors.Wrap(errno, "problem getting CPU affinity")
+		}
+
+		cpus := map[int]struct{}{}
+		for idx := 0; idx < int(n)*8; idx++ {
+			if mask[idx/64]&(1<<uint(idx%64)) != 0 {
+				cpus[idx] = struct{}{}
+			}
+		}
+		return cpus, nil
+	}
+
+	return nil, errors.New("CPU affinity mask is too large")
+}
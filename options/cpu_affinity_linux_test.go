@@ -0,0 +1,18 @@
+// +build linux
+
+package options
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedCPUs(t *testing.T) {
+	cpus, err := allowedCPUs()
+	require.NoError(t, err)
+	// The Go runtime counts the CPUs in the affinity mask at startup.
+	assert.Len(t, cpus, runtime.NumCPU())
+}
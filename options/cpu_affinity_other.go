@@ -0,0 +1,15 @@
+// +build !linux
+
+package options
+
+import "runtime"
+
+// allowedCPUs returns the indices of all of the CPUs since CPU affinity is
+// only supported on Linux.
+func allowedCPUs() (map[int]struct{}, error) {
+	cpus := make(map[int]struct{}, runtime.NumCPU())
+	for idx := 0; idx < runtime.NumCPU(); idx++ {
+		cpus[idx] = struct{}{}
+	}
+	return cpus, nil
+}
@@ -8,7 +8,11 @@ import (
 	"hash"
 	"io"
 	"os"
+	"regexp"
+	"runtime"
 	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/shlex"
@@ -16,6 +20,7 @@ import (
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
 	"github.com/tychoish/grip/send"
+	"github.com/tychoish/jasper/internal/clock"
 	"github.com/tychoish/jasper/internal/executor"
 )
 
@@ -31,14 +36,90 @@ const (
 	// may override this option. Basic implementations are more
 	// simple than blocking implementations.
 	ProcessImplementationBasic = "basic"
+
+	// LogLevelEnvironVar is the environment variable that, when set in
+	// the process' Environment, overrides the priority of the messages
+	// that the process' output and error are logged with. Its value is
+	// the name of a priority (e.g. "debug" or "warning").
+	LogLevelEnvironVar = "JASPER_LOG_LEVEL"
+
+	// ProcessIDExpansionVar is the variable that environment values
+	// can reference to include the ID of the process, when
+	// ExpandEnvironment is set.
+	ProcessIDExpansionVar = "JASPER_PROCESS_ID"
+	// TagsExpansionVar is the variable that environment values can
+	// reference to include the comma-separated tags of the process,
+	// when ExpandEnvironment is set.
+	TagsExpansionVar = "JASPER_PROCESS_TAGS"
+
+	// processIDEnvironVar is the environment variable that processes
+	// set to their ID, which is defined as jasper.EnvironID.
+	processIDEnvironVar = "JASPER_ID"
 )
 
 // Create contains options related to starting a process. This includes
 // execution configuration, post-execution triggers, and output configuration.
 // It is not safe for concurrent access.
 type Create struct {
-	Args        []string          `bson:"args" json:"args" yaml:"args"`
+	// ID, if set, is used as the ID of the process instead of a randomly
+	// generated one. Managers reject processes with the ID of a process
+	// that they already manage. Processes that are restarted keep the ID
+	// of the process that they replace.
+	ID   string   `bson:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Args []string `bson:"args" json:"args" yaml:"args"`
+	// ShellCommand is a command string that is passed to the shell specified
+	// by Shell rather than executed directly, so it may use shell features
+	// such as pipes and variable substitution. It is mutually exclusive with
+	// Args.
+	ShellCommand string `bson:"shell_command,omitempty" json:"shell_command,omitempty" yaml:"shell_command,omitempty"`
+	// Shell is the shell and its arguments used to run ShellCommand, which
+	// is passed as the final argument. If unspecified, it defaults to
+	// "/bin/sh -c" or, on Windows, "cmd /c".
+	Shell []string `bson:"shell,omitempty" json:"shell,omitempty" yaml:"shell,omitempty"`
+	// ArgsFile, if set, passes every argument after the first to the
+	// command through a temporary file according to the mode, for
+	// commands whose arguments would exceed the operating system's limit
+	// on the length of the command line. The file is removed once the
+	// process completes. No file is used if the command has no arguments
+	// after the first. Args files are only supported for local
+	// processes that are not started with a shell command.
+	ArgsFile ArgsFileMode `bson:"args_file,omitempty" json:"args_file,omitempty" yaml:"args_file,omitempty"`
+	// Environment contains the environment variables set for the
+	// process. If LogLevelEnvironVar is set, it also sets the priority
+	// of the messages that the process' output is logged with.
 	Environment map[string]string `bson:"env,omitempty" json:"env,omitempty" yaml:"env,omitempty"`
+	// ExpandEnvironment expands references of the form ${VAR} in the
+	// values of Environment when the options are resolved. References
+	// are expanded against the process environment before expansion,
+	// including the inherited environment of local processes, as well
+	// as ProcessIDExpansionVar and TagsExpansionVar. References to
+	// undefined variables expand to the empty string.
+	ExpandEnvironment bool `bson:"expand_env,omitempty" json:"expand_env,omitempty" yaml:"expand_env,omitempty"`
+	// RedactedEnvironment contains the names of environment variables
+	// whose values are redacted from the command that the process
+	// reports with ResolvedCommand, such as variables that contain
+	// credentials.
+	RedactedEnvironment []string `bson:"redacted_env,omitempty" json:"redacted_env,omitempty" yaml:"redacted_env,omitempty"`
+	// ResourceLimits maps resource limit names (e.g. "RLIMIT_NOFILE") to
+	// the limits applied to the process. Resource limits are only
+	// supported for local processes on Unix systems.
+	ResourceLimits map[string]ResourceLimit `bson:"resource_limits,omitempty" json:"resource_limits,omitempty" yaml:"resource_limits,omitempty"`
+	// CPUAffinity restricts the process to run on the CPUs with the given
+	// indices, which must be less than runtime.NumCPU(). The affinity is
+	// applied immediately after the process starts, so it does not apply
+	// to processes that it starts before then. CPU affinity is only
+	// supported for local processes on Linux.
+	CPUAffinity []int `bson:"cpu_affinity,omitempty" json:"cpu_affinity,omitempty" yaml:"cpu_affinity,omitempty"`
+	// Namespaces are the types of Linux namespaces that the process is
+	// started in, each of which is newly created for the process.
+	// Unprivileged users can only create namespaces if the process is
+	// also started in a new user namespace. Namespaces are only
+	// supported for local processes on Linux.
+	Namespaces []NamespaceType `bson:"namespaces,omitempty" json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	// CaptureCrashInfo records information about the crash in the
+	// process' info if the process is terminated by a signal that
+	// indicates that it crashed, such as SIGSEGV or SIGABRT.
+	CaptureCrashInfo bool `bson:"capture_crash_info,omitempty" json:"capture_crash_info,omitempty" yaml:"capture_crash_info,omitempty"`
 	// OverrideEnviron sets the process environment to match the currently
 	// executing process's environment. This is ignored if Remote or Docker
 	// options are specified.
@@ -59,21 +140,127 @@ type Create struct {
 	// TimeoutSecs should be set instead of Timeout.
 	TimeoutSecs int           `bson:"timeout_secs,omitempty" json:"timeout_secs,omitempty" yaml:"timeout_secs,omitempty"`
 	Timeout     time.Duration `bson:"timeout,omitempty" json:"-" yaml:"-"`
+	// WaitTimeout, if positive, limits how long each call to the
+	// process's Wait method blocks, even if the caller's context has
+	// no deadline. If the process does not complete in time, Wait
+	// returns context.DeadlineExceeded. Unlike Timeout, it does not
+	// affect the process itself.
+	WaitTimeout time.Duration `bson:"wait_timeout,omitempty" json:"wait_timeout,omitempty" yaml:"wait_timeout,omitempty"`
 	Tags        []string      `bson:"tags,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
 	OnSuccess   []*Create     `bson:"on_success,omitempty" json:"on_success,omitempty" yaml:"on_success"`
 	OnFailure   []*Create     `bson:"on_failure,omitempty" json:"on_failure,omitempty" yaml:"on_failure"`
 	OnTimeout   []*Create     `bson:"on_timeout,omitempty" json:"on_timeout,omitempty" yaml:"on_timeout"`
+	// RestartAlways causes managed processes to be restarted whenever
+	// they exit, regardless of their exit status, until the manager is
+	// closed. RestartDelay is the time to wait before each restart.
+	// To prevent crash loops, processes are not restarted more than
+	// RestartLimit times within RestartLimitInterval, which default to
+	// DefaultRestartLimit and DefaultRestartLimitInterval. Since
+	// processes are restarted asynchronously, managers that are not
+	// thread-safe should be synchronized.
+	RestartAlways        bool          `bson:"restart_always,omitempty" json:"restart_always,omitempty" yaml:"restart_always,omitempty"`
+	RestartDelay         time.Duration `bson:"restart_delay,omitempty" json:"restart_delay,omitempty" yaml:"restart_delay,omitempty"`
+	RestartLimit         int           `bson:"restart_limit,omitempty" json:"restart_limit,omitempty" yaml:"restart_limit,omitempty"`
+	RestartLimitInterval time.Duration `bson:"restart_limit_interval,omitempty" json:"restart_limit_interval,omitempty" yaml:"restart_limit_interval,omitempty"`
+	// RestartBackoff, if set, replaces RestartDelay with a backoff that
+	// increases the delay between consecutive restarts of processes that
+	// exit soon after they start. The backoff also applies to processes
+	// that are restarted because their health check fails.
+	RestartBackoff *RestartBackoff `bson:"restart_backoff,omitempty" json:"restart_backoff,omitempty" yaml:"restart_backoff,omitempty"`
+	// WatchPaths are the paths of files that cause managed processes to
+	// be restarted when they change, until the process completes on its
+	// own or the manager is closed. When a change is detected, the
+	// process is restarted once no further changes have been detected
+	// for WatchDebounce, which defaults to DefaultWatchDebounce: the
+	// process is terminated and replaced by a new process with the same
+	// options. Files are polled for changes to their modification time,
+	// size or existence, and directories are not watched recursively.
+	// Restarts count towards the restart limit, and processes cannot
+	// both restart on changes and always restart.
+	WatchPaths    []string      `bson:"watch_paths,omitempty" json:"watch_paths,omitempty" yaml:"watch_paths,omitempty"`
+	WatchDebounce time.Duration `bson:"watch_debounce,omitempty" json:"watch_debounce,omitempty" yaml:"watch_debounce,omitempty"`
+	// HealthCheck configures a probe that managers run periodically
+	// while the process is running, reporting the outcome in the
+	// process's info. Processes cannot both restart when their health
+	// check fails and always restart or restart on changes to watched
+	// paths.
+	HealthCheck *HealthCheck `bson:"health_check,omitempty" json:"health_check,omitempty" yaml:"health_check,omitempty"`
+	// ReadinessProbe, if set, determines when the process is ready, such
+	// as when a service accepts connections. The process's info does
+	// not report it as running until the probe succeeds, and the
+	// process is killed and fails if the probe does not succeed before
+	// its timeout. Output patterns are matched by watching the
+	// process's output lines, so Resolve enables Output.WatchLines for
+	// them. Health checks begin once the process is ready.
+	ReadinessProbe *ReadinessProbe `bson:"readiness_probe,omitempty" json:"readiness_probe,omitempty" yaml:"readiness_probe,omitempty"`
+	// DependsOn specifies the IDs of processes that must complete
+	// successfully before this process starts. Managers create the
+	// process with DeferStart and return it immediately, then start it
+	// once its dependencies complete. If any dependency fails, the
+	// process completes unsuccessfully without being started. If
+	// DeferStart is also set, the process is not started automatically.
+	// This is only respected for managed processes and is only
+	// supported by the basic process implementation.
+	DependsOn []string `bson:"depends_on,omitempty" json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	// DeferStart creates the process without starting it, so that it
+	// can be started later with the process's Start method. It is only
+	// supported by the basic process implementation. The timeout, if
+	// any, begins when the process is started rather than when it is
+	// created.
+	DeferStart bool `bson:"defer_start,omitempty" json:"defer_start,omitempty" yaml:"defer_start,omitempty"`
 	// StandardInputBytes takes precedence over StandardInput. On remote
 	// interfaces, StandardInputBytes should be set instead of StandardInput.
 	StandardInput      io.Reader `bson:"-" json:"-" yaml:"-"`
 	StandardInputBytes []byte    `bson:"stdin_bytes" json:"stdin_bytes" yaml:"stdin_bytes"`
+	// PreExec hooks run in the parent process, in order, immediately before
+	// the process is started. If any hook returns an error, the process is
+	// not started. PostStart hooks run, in order, immediately after the
+	// process starts successfully and receive its PID. Hooks are only
+	// supported for local processes, so options with Remote or Docker set
+	// do not validate if they have hooks.
+	PreExec   []func() error `bson:"-" json:"-" yaml:"-"`
+	PostStart []func(int)    `bson:"-" json:"-" yaml:"-"`
 
 	closers []func() error
+	// argsFile is the path of the temporary file that contains the
+	// arguments when ArgsFile is set, which is removed if resolving the
+	// options fails.
+	argsFile string
+	// clock is used to enforce the timeout. If unset, the real clock is
+	// used.
+	clock clock.Clock
+	// startTimeout starts the timeout of options that were resolved with
+	// DeferStart. It is nil if there is no timeout to start.
+	startTimeout func() time.Time
+	// started is accessed atomically and is non-zero once the
+	// options have been resolved into a command.
+	started int32
 }
 
+const (
+	// DefaultRestartLimit is the default maximum number of times a
+	// process that should always restart is restarted within the
+	// restart limit interval.
+	DefaultRestartLimit = 5
+	// DefaultRestartLimitInterval is the default interval over which
+	// restarts are counted for the restart limit.
+	DefaultRestartLimitInterval = time.Minute
+	// DefaultWatchDebounce is the default time to wait after a watched
+	// path changes before restarting the process.
+	DefaultWatchDebounce = 500 * time.Millisecond
+)
+
+// ErrOptionsAlreadyUsed is returned when attempting to resolve options that
+// have already been used to create a command. Use Copy to create a new set
+// of options that can be resolved again.
+var ErrOptionsAlreadyUsed = errors.New("cannot resolve options that have already been used")
+
 // MakeCreation takes a command string and returns an equivalent
 // Create struct that would spawn a process corresponding to the given
-// command string.
+// command string. The command is tokenized using shell quoting rules:
+// single and double quotes group words into one argument, backslashes
+// escape the following character, and comments are dropped. It returns an
+// error if a quote is unterminated or the command has no arguments.
 func MakeCreation(cmdStr string) (*Create, error) {
 	args, err := shlex.Split(cmdStr)
 	if err != nil {
@@ -96,11 +283,14 @@ func MakeCreation(cmdStr string) (*Create, error) {
 // Validate ensures that Create is valid for non-remote interfaces.
 func (opts *Create) Validate() error {
 	catcher := grip.NewBasicCatcher()
-	catcher.NewWhen(len(opts.Args) == 0, "invalid process, must specify at least one argument")
+	catcher.NewWhen(len(opts.Args) == 0 && opts.ShellCommand == "", "invalid process, must specify at least one argument")
+	catcher.NewWhen(len(opts.Args) != 0 && opts.ShellCommand != "", "cannot specify both arguments and a shell command")
+	catcher.NewWhen(len(opts.Shell) != 0 && opts.ShellCommand == "", "cannot specify a shell without a shell command")
 
 	catcher.NewWhen(opts.Timeout < 0, "when specifying a timeout, it must be non-negative")
 	catcher.NewWhen(opts.Timeout > 0 && opts.Timeout < time.Second, "when specifying a timeout, it must be greater than one second")
 	catcher.NewWhen(opts.TimeoutSecs < 0, "when specifying timeout in seconds, it must be non-negative")
+	catcher.NewWhen(opts.WaitTimeout < 0, "wait timeout cannot be negative")
 
 	if opts.Timeout > 0 && opts.TimeoutSecs > 0 {
 		catcher.ErrorfWhen(time.Duration(opts.TimeoutSecs)*time.Second != opts.Timeout,
@@ -110,6 +300,77 @@ func (opts *Create) Validate() error {
 
 	catcher.Wrap(opts.Output.Validate(), "invalid output options")
 
+	for key := range opts.Environment {
+		catcher.NewWhen(key == "", "cannot set an environment variable with an empty name")
+		catcher.ErrorfWhen(strings.Contains(key, "="), "environment variable name '%s' cannot contain '='", key)
+	}
+	if lvl, ok := opts.Environment[LogLevelEnvironVar]; ok {
+		catcher.ErrorfWhen(!level.FromString(lvl).IsValid(), "invalid log level '%s' for %s", lvl, LogLevelEnvironVar)
+	}
+
+	if opts.ArgsFile != "" {
+		catcher.Wrap(opts.ArgsFile.Validate(), "invalid args file mode")
+		catcher.NewWhen(!opts.isLocal(), "args files are only supported for local processes")
+		catcher.NewWhen(opts.ShellCommand != "", "cannot use an args file with a shell command")
+		if opts.ArgsFile == ArgsFileSplit {
+			catcher.NewWhen(runtime.GOOS == "windows", "splitting arguments across invocations is not supported on windows")
+			catcher.NewWhen(opts.StandardInput != nil || len(opts.StandardInputBytes) != 0, "cannot split arguments across invocations with standard input")
+		}
+	}
+
+	if len(opts.ResourceLimits) != 0 {
+		catcher.NewWhen(!opts.isLocal(), "resource limits are only supported for local processes")
+		catcher.NewWhen(runtime.GOOS == "windows", "resource limits are not supported on windows")
+		catcher.Wrap(validateResourceLimits(opts.ResourceLimits), "invalid resource limits")
+	}
+
+	catcher.NewWhen(len(opts.PreExec) != 0 && !opts.isLocal(), "pre-exec hooks are only supported for local processes")
+	catcher.NewWhen(len(opts.PostStart) != 0 && !opts.isLocal(), "post-start hooks are only supported for local processes")
+
+	if len(opts.CPUAffinity) != 0 {
+		catcher.NewWhen(!opts.isLocal(), "CPU affinity is only supported for local processes")
+		catcher.ErrorfWhen(runtime.GOOS != "linux", "CPU affinity is not supported on %s", runtime.GOOS)
+		catcher.Wrap(validateCPUAffinity(opts.CPUAffinity), "invalid CPU affinity")
+	}
+
+	if len(opts.Namespaces) != 0 {
+		catcher.NewWhen(!opts.isLocal(), "namespaces are only supported for local processes")
+		catcher.ErrorfWhen(runtime.GOOS != "linux", "namespaces are not supported on %s", runtime.GOOS)
+		catcher.Wrap(validateNamespaces(opts.Namespaces, os.Geteuid() == 0), "invalid namespaces")
+	}
+
+	catcher.NewWhen(opts.RestartDelay < 0, "restart delay cannot be negative")
+	catcher.NewWhen(opts.RestartLimit < 0, "restart limit cannot be negative")
+	catcher.NewWhen(opts.RestartLimitInterval < 0, "restart limit interval cannot be negative")
+	if opts.RestartBackoff != nil {
+		catcher.Wrap(opts.RestartBackoff.Validate(), "invalid restart backoff")
+		catcher.NewWhen(opts.RestartDelay != 0, "cannot specify both a restart delay and a restart backoff")
+	}
+	catcher.NewWhen(opts.WatchDebounce < 0, "watch debounce cannot be negative")
+	catcher.NewWhen(len(opts.WatchPaths) != 0 && opts.RestartAlways, "cannot restart on changes to watched paths and always restart")
+	for _, path := range opts.WatchPaths {
+		catcher.NewWhen(path == "", "cannot watch an empty path")
+	}
+
+	if opts.HealthCheck != nil {
+		catcher.Wrap(opts.HealthCheck.Validate(), "invalid health check")
+		if opts.HealthCheck.RestartOnFailure {
+			catcher.NewWhen(opts.RestartAlways, "cannot restart on health check failure and always restart")
+			catcher.NewWhen(len(opts.WatchPaths) != 0, "cannot restart on health check failure and on changes to watched paths")
+		}
+	}
+
+	if opts.ReadinessProbe != nil {
+		catcher.Wrap(opts.ReadinessProbe.Validate(), "invalid readiness probe")
+	}
+
+	for _, id := range opts.DependsOn {
+		catcher.NewWhen(id == "", "cannot specify an empty process ID as a dependency")
+	}
+
+	catcher.NewWhen(opts.DeferStart && opts.Implementation == ProcessImplementationBlocking, "deferred start is only supported by the basic process implementation")
+	catcher.NewWhen(len(opts.DependsOn) != 0 && opts.Implementation == ProcessImplementationBlocking, "dependencies are only supported by the basic process implementation")
+
 	if opts.WorkingDirectory != "" && opts.isLocal() {
 		info, err := os.Stat(opts.WorkingDirectory)
 
@@ -161,7 +422,7 @@ func (opts *Create) Hash() hash.Hash {
 	hash := sha1.New()
 
 	_, _ = io.WriteString(hash, opts.WorkingDirectory)
-	for _, a := range opts.Args {
+	for _, a := range opts.resolveArgs() {
 		_, _ = io.WriteString(hash, a)
 	}
 
@@ -184,27 +445,61 @@ func (opts *Create) Hash() hash.Hash {
 
 // Resolve creates the command object according to the create options. It
 // returns the resolved command and the deadline when the command will be
-// terminated by timeout. If there is no deadline, it returns the zero time.
+// terminated by timeout. If there is no deadline, or if DeferStart is set, it
+// returns the zero time; the timeout of deferred options begins when
+// StartTimeout is called.
+//
+// Options may only be successfully resolved once; subsequent calls return
+// ErrOptionsAlreadyUsed.
 func (opts *Create) Resolve(ctx context.Context) (exe executor.Executor, t time.Time, resolveErr error) {
 	if ctx.Err() != nil {
 		return nil, time.Time{}, errors.New("cannot resolve command with canceled context")
 	}
 
+	if !atomic.CompareAndSwapInt32(&opts.started, 0, 1) {
+		return nil, time.Time{}, ErrOptionsAlreadyUsed
+	}
+	defer func() {
+		if resolveErr != nil {
+			atomic.StoreInt32(&opts.started, 0)
+		}
+	}()
+
 	if err := opts.Validate(); err != nil {
 		return nil, time.Time{}, errors.WithStack(err)
 	}
 
+	if opts.ReadinessProbe != nil && opts.ReadinessProbe.OutputPattern != "" {
+		opts.Output.WatchLines = true
+	}
+
 	var deadline time.Time
 	var cancel context.CancelFunc = func() {}
 	if opts.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		// Flush the output that has been captured before the process is
+		// killed, so that it is available even if the process does not
+		// exit promptly. The output is only ready to flush once the options
+		// are resolved.
+		resolved := make(chan struct{})
+		defer close(resolved)
+		var startTimeout func() time.Time
+		ctx, cancel, startTimeout = opts.withTimeout(ctx, func() {
+			<-resolved
+			if resolveErr == nil {
+				grip.Warning(errors.Wrap(opts.Output.Flush(), "problem flushing output of timed out process"))
+			}
+		})
+		if opts.DeferStart {
+			opts.startTimeout = startTimeout
+		} else {
+			deadline = startTimeout()
+		}
 		defer func() {
 			if resolveErr != nil {
 				cancel()
 			}
 		}()
 
-		deadline, _ = ctx.Deadline()
 		opts.closers = append(opts.closers, func() error {
 			cancel()
 			return nil
@@ -218,6 +513,10 @@ func (opts *Create) Resolve(ctx context.Context) (exe executor.Executor, t time.
 	defer func() {
 		if resolveErr != nil {
 			grip.Error(errors.Wrap(cmd.Close(), "problem closing process executor"))
+			if opts.argsFile != "" {
+				grip.Error(removeArgsFile(opts.argsFile))
+				opts.argsFile = ""
+			}
 		}
 	}()
 
@@ -231,21 +530,51 @@ func (opts *Create) Resolve(ctx context.Context) (exe executor.Executor, t time.
 	if !opts.OverrideEnviron && opts.isLocal() {
 		env = os.Environ()
 	}
-	for key, value := range opts.Environment {
+	environment := opts.Environment
+	if opts.ExpandEnvironment {
+		environment = opts.expandEnvironment(env)
+	}
+	for key, value := range environment {
 		env = append(env, fmt.Sprintf("%s=%s", key, value))
 	}
 	cmd.SetEnv(env)
 
+	if lvl, ok := opts.Environment[LogLevelEnvironVar]; ok {
+		opts.Output.priority = level.FromString(lvl)
+	}
+	opts.Output.processTags = opts.Tags
+
 	stdout, err := opts.Output.GetOutput()
 	if err != nil {
 		return nil, time.Time{}, errors.WithStack(err)
 	}
-	cmd.SetStdout(stdout)
 
 	stderr, err := opts.Output.GetError()
 	if err != nil {
 		return nil, time.Time{}, errors.WithStack(err)
 	}
+	stdout, stderr = opts.Output.tee(stdout, stderr)
+	stdout, stderr = opts.Output.count(stdout, stderr)
+	if opts.Output.ChecksumAlgorithm != "" {
+		stdout, stderr, err = opts.Output.checksum(stdout, stderr)
+		if err != nil {
+			return nil, time.Time{}, errors.WithStack(err)
+		}
+	}
+	if opts.Output.WatchLines {
+		stdout, stderr = opts.Output.watchLines(stdout, stderr)
+	}
+
+	if opts.Output.RecordPath != "" {
+		stdout, stderr, err = opts.Output.record(stdout, stderr)
+		if err != nil {
+			return nil, time.Time{}, errors.WithStack(err)
+		}
+	}
+	if opts.Output.MaxLinesPerSecond > 0 {
+		stdout, stderr = opts.Output.throttle(ctx.Done(), stdout, stderr)
+	}
+	cmd.SetStdout(stdout)
 	cmd.SetStderr(stderr)
 
 	if opts.StandardInput != nil {
@@ -260,6 +589,53 @@ func (opts *Create) Resolve(ctx context.Context) (exe executor.Executor, t time.
 	return cmd, deadline, nil
 }
 
+// StartTimeout starts the timeout of options that were resolved with
+// DeferStart and returns the deadline when the command will be terminated by
+// timeout. It returns the zero time if there is no timeout, or if it has
+// already been started.
+func (opts *Create) StartTimeout() time.Time {
+	if opts.startTimeout == nil {
+		return time.Time{}
+	}
+	start := opts.startTimeout
+	opts.startTimeout = nil
+	return start()
+}
+
+// withTimeout returns a context that is canceled once the timeout elapses
+// according to the options' clock, along with a function that starts the
+// timeout and returns the resulting deadline. If the timeout elapses,
+// onTimeout is called before the context is canceled.
+func (opts *Create) withTimeout(ctx context.Context, onTimeout func()) (context.Context, context.CancelFunc, func() time.Time) {
+	clk := opts.clock
+	if clk == nil {
+		clk = clock.New()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	start := func() time.Time {
+		deadline := clk.Now().Add(opts.Timeout)
+		if parentDeadline, ok := ctx.Deadline(); ok && parentDeadline.Before(deadline) {
+			deadline = parentDeadline
+		}
+
+		timer := clk.NewTimer(opts.Timeout)
+		go func() {
+			defer timer.Stop()
+			select {
+			case <-timer.C():
+				onTimeout()
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		return deadline
+	}
+
+	return ctx, cancel, start
+}
+
 func (opts *Create) resolveExecutor(ctx context.Context) (executor.Executor, error) {
 	if opts.Remote != nil {
 		if opts.Remote.UseSSHLibrary {
@@ -267,10 +643,10 @@ func (opts *Create) resolveExecutor(ctx context.Context) (executor.Executor, err
 			if err != nil {
 				return nil, errors.Wrap(err, "could not resolve SSH client and session")
 			}
-			return executor.NewSSH(ctx, client, session, opts.Args), nil
+			return executor.NewSSH(ctx, client, session, opts.resolveArgs()), nil
 		}
 
-		return executor.NewSSHBinary(ctx, opts.Remote.String(), opts.Remote.Args, opts.Args), nil
+		return executor.NewSSHBinary(ctx, opts.Remote.String(), opts.Remote.Args, opts.resolveArgs()), nil
 	}
 
 	if opts.Docker != nil {
@@ -278,10 +654,87 @@ func (opts *Create) resolveExecutor(ctx context.Context) (executor.Executor, err
 		if err != nil {
 			return nil, errors.Wrap(err, "could not resolve Docker options")
 		}
-		return executor.NewDocker(ctx, client, opts.Docker.Platform, opts.Docker.Image, opts.Args), nil
+		return executor.NewDocker(ctx, client, opts.Docker.Platform, opts.Docker.Image, opts.resolveArgs()), nil
+	}
+
+	args := opts.resolveArgs()
+	var stdin io.Reader
+	if opts.ArgsFile != "" && len(args) > 1 {
+		var err error
+		args, stdin, err = opts.writeArgsFile(args)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not write arguments to file")
+		}
+	}
+
+	if len(opts.ResourceLimits) != 0 {
+		args = wrapWithResourceLimits(opts.ResourceLimits, args)
+	}
+
+	var cmd executor.Executor
+	if len(opts.Namespaces) != 0 {
+		cmd = executor.NewLocalWithAttributes(ctx, args, namespaceAttributes(opts.Namespaces))
+	} else {
+		cmd = executor.NewLocal(ctx, args)
+	}
+	if stdin != nil {
+		cmd.SetStdin(stdin)
+	}
+
+	return cmd, nil
+}
+
+// resolveArgs returns the arguments of the command to execute, wrapping the
+// shell command in the shell if one is specified.
+func (opts *Create) resolveArgs() []string {
+	if opts.ShellCommand == "" {
+		return opts.Args
 	}
 
-	return executor.NewLocal(ctx, opts.Args), nil
+	shell := opts.Shell
+	if len(shell) == 0 {
+		shell = defaultShell(opts.platform())
+	}
+
+	cmd := opts.ShellCommand
+	if opts.Remote != nil {
+		// SSH joins the arguments into a single string that is
+		// interpreted by the remote login shell, so the command must
+		// be quoted to be passed intact to the shell.
+		cmd = quoteShellArg(cmd)
+	}
+
+	args := make([]string, 0, len(shell)+1)
+	args = append(args, shell...)
+	return append(args, cmd)
+}
+
+// platform returns the operating system on which the command will run.
+// Remote hosts are assumed to be Unix-like.
+func (opts *Create) platform() string {
+	switch {
+	case opts.Docker != nil && opts.Docker.Platform != "":
+		return opts.Docker.Platform
+	case opts.Remote != nil:
+		return "linux"
+	default:
+		return runtime.GOOS
+	}
+}
+
+// defaultShell returns the default shell used to run shell commands on the
+// given platform.
+func defaultShell(platform string) []string {
+	if platform == "windows" {
+		return []string{"cmd", "/c"}
+	}
+	return []string{"/bin/sh", "-c"}
+}
+
+// quoteShellArg quotes the string so that it is interpreted as a single
+// literal argument by a POSIX shell.
+func quoteShellArg(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'"'"'`, -1) + "'"
 }
 
 // ResolveEnvironment returns the (Create).Environment as a slice of environment
@@ -294,6 +747,35 @@ func (opts *Create) ResolveEnvironment() []string {
 	return env
 }
 
+var environmentReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvironment returns a copy of the options' environment with the
+// references in its values expanded against the base environment, the
+// options' environment and the process metadata, in increasing order of
+// precedence. Values are not expanded recursively.
+func (opts *Create) expandEnvironment(base []string) map[string]string {
+	vars := make(map[string]string, len(base)+len(opts.Environment)+2)
+	for _, kv := range base {
+		if idx := strings.Index(kv, "="); idx > 0 {
+			vars[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	for key, value := range opts.Environment {
+		vars[key] = value
+	}
+	vars[ProcessIDExpansionVar] = opts.Environment[processIDEnvironVar]
+	vars[TagsExpansionVar] = strings.Join(opts.Tags, ",")
+
+	expanded := make(map[string]string, len(opts.Environment))
+	for key, value := range opts.Environment {
+		expanded[key] = environmentReference.ReplaceAllStringFunc(value, func(ref string) string {
+			return vars[ref[2:len(ref)-1]]
+		})
+	}
+
+	return expanded
+}
+
 // AddEnvVar adds an environment variable to the Create struct on which
 // this method is called. If the Environment map is nil, this method will
 // instantiate one.
@@ -305,6 +787,25 @@ func (opts *Create) AddEnvVar(k, v string) {
 	opts.Environment[k] = v
 }
 
+// ArgsFromNullDelimited sets the arguments of the process from a sequence of
+// NUL-terminated arguments, as read by "xargs -0". Since arguments are not
+// split on whitespace, they may safely contain spaces, quotes, and newlines.
+// A NUL after the final argument is optional, and consecutive NULs produce
+// empty arguments.
+func (opts *Create) ArgsFromNullDelimited(data []byte) {
+	data = bytes.TrimSuffix(data, []byte{0})
+	if len(data) == 0 {
+		opts.Args = []string{}
+		return
+	}
+
+	args := bytes.Split(data, []byte{0})
+	opts.Args = make([]string, 0, len(args))
+	for _, arg := range args {
+		opts.Args = append(opts.Args, string(arg))
+	}
+}
+
 // Close will execute the closer functions assigned to the Create. This
 // function is often called as a trigger at the end of a process' lifetime in
 // Jasper.
@@ -330,8 +831,10 @@ func (opts *Create) RegisterCloser(fn func() error) {
 	opts.closers = append(opts.closers, fn)
 }
 
-// Copy returns a copy of the options for only the exported fields. Unexported
-// fields are cleared.
+// Copy returns a deep copy of the options for only the exported fields, so
+// that the copy can be modified and used to create a process without
+// affecting the original. Unexported fields, such as the registered closers,
+// are cleared.
 func (opts *Create) Copy() *Create {
 	optsCopy := *opts
 
@@ -340,6 +843,11 @@ func (opts *Create) Copy() *Create {
 		_ = copy(optsCopy.Args, opts.Args)
 	}
 
+	if opts.Shell != nil {
+		optsCopy.Shell = make([]string, len(opts.Shell))
+		_ = copy(optsCopy.Shell, opts.Shell)
+	}
+
 	if opts.Tags != nil {
 		optsCopy.Tags = make([]string, len(opts.Tags))
 		_ = copy(optsCopy.Tags, opts.Tags)
@@ -352,19 +860,61 @@ func (opts *Create) Copy() *Create {
 		}
 	}
 
+	if opts.RedactedEnvironment != nil {
+		optsCopy.RedactedEnvironment = make([]string, len(opts.RedactedEnvironment))
+		_ = copy(optsCopy.RedactedEnvironment, opts.RedactedEnvironment)
+	}
+
+	if opts.ResourceLimits != nil {
+		optsCopy.ResourceLimits = make(map[string]ResourceLimit, len(opts.ResourceLimits))
+		for name, limit := range opts.ResourceLimits {
+			optsCopy.ResourceLimits[name] = limit
+		}
+	}
+
+	if opts.CPUAffinity != nil {
+		optsCopy.CPUAffinity = make([]int, len(opts.CPUAffinity))
+		_ = copy(optsCopy.CPUAffinity, opts.CPUAffinity)
+	}
+
+	if opts.Namespaces != nil {
+		optsCopy.Namespaces = make([]NamespaceType, len(opts.Namespaces))
+		_ = copy(optsCopy.Namespaces, opts.Namespaces)
+	}
+
+	if opts.WatchPaths != nil {
+		optsCopy.WatchPaths = make([]string, len(opts.WatchPaths))
+		_ = copy(optsCopy.WatchPaths, opts.WatchPaths)
+	}
+
+	if opts.HealthCheck != nil {
+		optsCopy.HealthCheck = opts.HealthCheck.Copy()
+	}
+
+	if opts.ReadinessProbe != nil {
+		optsCopy.ReadinessProbe = opts.ReadinessProbe.Copy()
+	}
+
+	if opts.RestartBackoff != nil {
+		backoff := *opts.RestartBackoff
+		optsCopy.RestartBackoff = &backoff
+	}
+
+	if opts.DependsOn != nil {
+		optsCopy.DependsOn = make([]string, len(opts.DependsOn))
+		_ = copy(optsCopy.DependsOn, opts.DependsOn)
+	}
+
 	if opts.OnSuccess != nil {
-		optsCopy.OnSuccess = make([]*Create, len(opts.OnSuccess))
-		_ = copy(optsCopy.OnSuccess, opts.OnSuccess)
+		optsCopy.OnSuccess = copyCreates(opts.OnSuccess)
 	}
 
 	if opts.OnFailure != nil {
-		optsCopy.OnFailure = make([]*Create, len(opts.OnFailure))
-		_ = copy(optsCopy.OnFailure, opts.OnFailure)
+		optsCopy.OnFailure = copyCreates(opts.OnFailure)
 	}
 
 	if opts.OnTimeout != nil {
-		optsCopy.OnTimeout = make([]*Create, len(opts.OnTimeout))
-		_ = copy(optsCopy.OnTimeout, opts.OnTimeout)
+		optsCopy.OnTimeout = copyCreates(opts.OnTimeout)
 	}
 
 	if opts.StandardInputBytes != nil {
@@ -372,6 +922,16 @@ func (opts *Create) Copy() *Create {
 		_ = copy(optsCopy.StandardInputBytes, opts.StandardInputBytes)
 	}
 
+	if opts.PreExec != nil {
+		optsCopy.PreExec = make([]func() error, len(opts.PreExec))
+		_ = copy(optsCopy.PreExec, opts.PreExec)
+	}
+
+	if opts.PostStart != nil {
+		optsCopy.PostStart = make([]func(int), len(opts.PostStart))
+		_ = copy(optsCopy.PostStart, opts.PostStart)
+	}
+
 	if opts.Remote != nil {
 		optsCopy.Remote = opts.Remote.Copy()
 	}
@@ -383,6 +943,19 @@ func (opts *Create) Copy() *Create {
 	optsCopy.Output = *opts.Output.Copy()
 
 	optsCopy.closers = nil
+	optsCopy.argsFile = ""
+	optsCopy.startTimeout = nil
+	optsCopy.started = 0
 
 	return &optsCopy
 }
+
+func copyCreates(opts []*Create) []*Create {
+	out := make([]*Create, len(opts))
+	for idx := range opts {
+		if opts[idx] != nil {
+			out[idx] = opts[idx].Copy()
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package options
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// CreateBuilder constructs Create options with chainable methods, collecting
+// any invalid settings so that they are reported together by Build. The zero
+// value is not usable; use NewCreateBuilder.
+type CreateBuilder struct {
+	opts    *Create
+	catcher grip.Catcher
+}
+
+// NewCreateBuilder returns a builder for options that run the given
+// arguments, which may also be set later with Args.
+func NewCreateBuilder(args ...string) *CreateBuilder {
+	b := &CreateBuilder{
+		opts:    &Create{},
+		catcher: grip.NewBasicCatcher(),
+	}
+	return b.Args(args...)
+}
+
+// Args appends arguments to the command.
+func (b *CreateBuilder) Args(args ...string) *CreateBuilder {
+	b.opts.Args = append(b.opts.Args, args...)
+	return b
+}
+
+// Env sets an environment variable for the process.
+func (b *CreateBuilder) Env(key, value string) *CreateBuilder {
+	b.opts.AddEnvVar(key, value)
+	return b
+}
+
+// Dir sets the working directory of the process.
+func (b *CreateBuilder) Dir(dir string) *CreateBuilder {
+	b.opts.WorkingDirectory = dir
+	return b
+}
+
+// Output sets the output options of the process.
+func (b *CreateBuilder) Output(output Output) *CreateBuilder {
+	b.opts.Output = output
+	return b
+}
+
+// Timeout sets the timeout of the process, which must be positive.
+func (b *CreateBuilder) Timeout(timeout time.Duration) *CreateBuilder {
+	if timeout <= 0 {
+		b.catcher.Errorf("timeout must be positive, but got %s", timeout)
+		return b
+	}
+	b.opts.Timeout = timeout
+	return b
+}
+
+// Tags appends tags to the process.
+func (b *CreateBuilder) Tags(tags ...string) *CreateBuilder {
+	b.opts.Tags = append(b.opts.Tags, tags...)
+	return b
+}
+
+// Build validates the options and returns them. It returns an error if any
+// of the builder's methods were given invalid settings or if the options are
+// invalid. Each call returns a new copy of the options, so the builder may
+// continue to be used.
+func (b *CreateBuilder) Build() (*Create, error) {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(b.catcher.Resolve())
+
+	opts := b.opts.Copy()
+	catcher.Wrap(opts.Validate(), "invalid options")
+	if catcher.HasErrors() {
+		return nil, errors.Wrap(catcher.Resolve(), "problem building options")
+	}
+
+	return opts, nil
+}
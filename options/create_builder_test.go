@@ -0,0 +1,69 @@
+package options
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBuilder(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	t.Run("BuildsValidOptions", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		opts, err := NewCreateBuilder("echo", "hello").
+			Args("world").
+			Env("FOO", "bar").
+			Dir(cwd).
+			Output(Output{Output: output}).
+			Timeout(time.Minute).
+			Tags("tag").
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"echo", "hello", "world"}, opts.Args)
+		assert.Equal(t, map[string]string{"FOO": "bar"}, opts.Environment)
+		assert.Equal(t, cwd, opts.WorkingDirectory)
+		assert.Equal(t, output, opts.Output.Output)
+		assert.Equal(t, time.Minute, opts.Timeout)
+		assert.Equal(t, []string{"tag"}, opts.Tags)
+	})
+	t.Run("BuildReturnsIndependentCopies", func(t *testing.T) {
+		b := NewCreateBuilder("true").Env("FOO", "bar")
+		first, err := b.Build()
+		require.NoError(t, err)
+
+		second, err := b.Env("FOO", "baz").Build()
+		require.NoError(t, err)
+		assert.Equal(t, "bar", first.Environment["FOO"])
+		assert.Equal(t, "baz", second.Environment["FOO"])
+	})
+	for testName, b := range map[string]*CreateBuilder{
+		"NoArgs":                     NewCreateBuilder(),
+		"EmptyEnvName":               NewCreateBuilder("true").Env("", "bar"),
+		"NonexistentDir":             NewCreateBuilder("true").Dir("/this/directory/does/not/exist"),
+		"ZeroTimeout":                NewCreateBuilder("true").Timeout(0),
+		"NegativeTimeout":            NewCreateBuilder("true").Timeout(-time.Minute),
+		"SubsecondTimeout":           NewCreateBuilder("true").Timeout(time.Millisecond),
+		"SuppressedOutputWithWriter": NewCreateBuilder("true").Output(Output{Output: &bytes.Buffer{}, SuppressOutput: true}),
+		"OutputRedirectCycle":        NewCreateBuilder("true").Output(Output{SendOutputToError: true, SendErrorToOutput: true}),
+	} {
+		t.Run("FailsWith"+testName, func(t *testing.T) {
+			opts, err := b.Build()
+			assert.Error(t, err)
+			assert.Nil(t, opts)
+		})
+	}
+	t.Run("ReportsAllErrors", func(t *testing.T) {
+		_, err := NewCreateBuilder().Env("", "bar").Timeout(-time.Minute).Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty name")
+		assert.Contains(t, err.Error(), "timeout must be positive")
+		assert.Contains(t, err.Error(), "at least one argument")
+	})
+}
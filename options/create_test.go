@@ -7,12 +7,18 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
+	"github.com/tychoish/jasper/internal/clock"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -51,6 +57,41 @@ func TestCreateConstructor(t *testing.T) {
 			cmd:        "' foo",
 			shouldFail: true,
 		},
+		{
+			id:   "SingleQuotedArgument",
+			cmd:  `echo 'hello world' '$HOME'`,
+			args: []string{"echo", "hello world", "$HOME"},
+		},
+		{
+			id:   "DoubleQuotedArgument",
+			cmd:  `grep -e "foo bar" "it's"`,
+			args: []string{"grep", "-e", "foo bar", "it's"},
+		},
+		{
+			id:   "EscapedQuoteInDoubleQuotes",
+			cmd:  `echo "say \"hi\""`,
+			args: []string{"echo", `say "hi"`},
+		},
+		{
+			id:   "EscapedSpaces",
+			cmd:  `cat my\ file.txt other`,
+			args: []string{"cat", "my file.txt", "other"},
+		},
+		{
+			id:   "AdjacentQuotedSegments",
+			cmd:  `echo foo'bar baz'"qux"`,
+			args: []string{"echo", "foobar bazqux"},
+		},
+		{
+			id:         "UnterminatedDoubleQuote",
+			cmd:        `echo "foo`,
+			shouldFail: true,
+		},
+		{
+			id:         "TrailingEscape",
+			cmd:        `echo foo\`,
+			shouldFail: true,
+		},
 	} {
 		t.Run(test.id, func(t *testing.T) {
 			opt, err := MakeCreation(test.cmd)
@@ -80,6 +121,339 @@ func TestCreate(t *testing.T) {
 			opts.Args = []string{}
 			assert.Error(t, opts.Validate())
 		},
+		"EnvironmentValidates": func(t *testing.T, opts *Create) {
+			opts.AddEnvVar("FOO", "bar=baz")
+			assert.NoError(t, opts.Validate())
+		},
+		"EnvironmentNameWithEqualsShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.AddEnvVar("FOO=BAR", "baz")
+			assert.Error(t, opts.Validate())
+		},
+		"EmptyEnvironmentNameShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.AddEnvVar("", "baz")
+			assert.Error(t, opts.Validate())
+		},
+		"InvalidOutputShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Output.SendOutputToError = true
+			opts.Output.SendErrorToOutput = true
+			assert.Error(t, opts.Validate())
+		},
+		"ShellCommandWithoutArgsValidates": func(t *testing.T, opts *Create) {
+			opts.Args = nil
+			opts.ShellCommand = "echo foo | cat"
+			assert.NoError(t, opts.Validate())
+		},
+		"WatchPathsValidate": func(t *testing.T, opts *Create) {
+			opts.WatchPaths = []string{"foo"}
+			opts.WatchDebounce = time.Second
+			assert.NoError(t, opts.Validate())
+		},
+		"EmptyWatchPathShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.WatchPaths = []string{""}
+			assert.Error(t, opts.Validate())
+		},
+		"NegativeWatchDebounceShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.WatchPaths = []string{"foo"}
+			opts.WatchDebounce = -time.Second
+			assert.Error(t, opts.Validate())
+		},
+		"WatchPathsWithRestartAlwaysShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.WatchPaths = []string{"foo"}
+			opts.RestartAlways = true
+			assert.Error(t, opts.Validate())
+		},
+		"HealthCheckValidates": func(t *testing.T, opts *Create) {
+			opts.HealthCheck = &HealthCheck{URL: "http://localhost:8080/health", RestartOnFailure: true}
+			assert.NoError(t, opts.Validate())
+		},
+		"InvalidHealthCheckShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.HealthCheck = &HealthCheck{}
+			assert.Error(t, opts.Validate())
+		},
+		"HealthCheckRestartWithRestartAlwaysShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.HealthCheck = &HealthCheck{URL: "http://localhost:8080/health", RestartOnFailure: true}
+			opts.RestartAlways = true
+			assert.Error(t, opts.Validate())
+		},
+		"HealthCheckRestartWithWatchPathsShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.HealthCheck = &HealthCheck{URL: "http://localhost:8080/health", RestartOnFailure: true}
+			opts.WatchPaths = []string{"foo"}
+			assert.Error(t, opts.Validate())
+		},
+		"NegativeWaitTimeoutShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.WaitTimeout = -time.Second
+			assert.Error(t, opts.Validate())
+		},
+		"DeferStartValidatesForBasicImplementation": func(t *testing.T, opts *Create) {
+			opts.DeferStart = true
+			assert.NoError(t, opts.Validate())
+		},
+		"DeferStartShouldNotValidateForBlockingImplementation": func(t *testing.T, opts *Create) {
+			opts.DeferStart = true
+			opts.Implementation = ProcessImplementationBlocking
+			assert.Error(t, opts.Validate())
+		},
+		"DependsOnShouldNotValidateForBlockingImplementation": func(t *testing.T, opts *Create) {
+			opts.DependsOn = []string{"dependency"}
+			opts.Implementation = ProcessImplementationBlocking
+			assert.Error(t, opts.Validate())
+		},
+		"ShellCommandWithArgsShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.ShellCommand = "echo foo | cat"
+			assert.Error(t, opts.Validate())
+		},
+		"ShellWithoutShellCommandShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Shell = []string{"bash", "-c"}
+			assert.Error(t, opts.Validate())
+		},
+		"ResolveWrapsShellCommandInDefaultShell": func(t *testing.T, opts *Create) {
+			opts.Args = nil
+			opts.ShellCommand = "echo $HOME | cat"
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.Equal(t, append(defaultShell(runtime.GOOS), opts.ShellCommand), cmd.Args())
+		},
+		"ResolveWrapsShellCommandInShell": func(t *testing.T, opts *Create) {
+			opts.Args = nil
+			opts.Shell = []string{"bash", "-e", "-c"}
+			opts.ShellCommand = "echo $HOME | cat"
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.Equal(t, []string{"bash", "-e", "-c", opts.ShellCommand}, cmd.Args())
+		},
+		"RemoteShellCommandIsQuoted": func(t *testing.T, opts *Create) {
+			opts.Args = nil
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.ShellCommand = "echo 'foo bar' | cat"
+			assert.Equal(t, []string{"/bin/sh", "-c", `'echo '"'"'foo bar'"'"' | cat'`}, opts.resolveArgs())
+		},
+		"InvalidResourceLimitNameFailsResolve": func(t *testing.T, opts *Create) {
+			opts.ResourceLimits = map[string]ResourceLimit{"RLIMIT_FOO": {Soft: 1, Hard: 1}}
+			cmd, _, err := opts.Resolve(ctx)
+			assert.Error(t, err)
+			assert.Nil(t, cmd)
+		},
+		"ResourceLimitSoftAboveHardShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.ResourceLimits = map[string]ResourceLimit{ResourceLimitNumFiles: {Soft: 20, Hard: 10}}
+			assert.Error(t, opts.Validate())
+		},
+		"RemoteResourceLimitsShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.ResourceLimits = map[string]ResourceLimit{ResourceLimitNumFiles: {Soft: 10, Hard: 10}}
+			assert.Error(t, opts.Validate())
+		},
+		"RemoteHooksShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.PreExec = []func() error{func() error { return nil }}
+			assert.Error(t, opts.Validate())
+
+			opts.PreExec = nil
+			opts.PostStart = []func(int){func(int) {}}
+			assert.Error(t, opts.Validate())
+
+			opts.Remote = nil
+			opts.Docker = &Docker{Image: "image"}
+			assert.Error(t, opts.Validate())
+		},
+		"CPUAffinityValidatesOnLinux": func(t *testing.T, opts *Create) {
+			if runtime.GOOS != "linux" {
+				t.Skip("CPU affinity is only supported on linux")
+			}
+			allowed, err := allowedCPUs()
+			require.NoError(t, err)
+			for cpu := range allowed {
+				opts.CPUAffinity = append(opts.CPUAffinity, cpu)
+			}
+			assert.NoError(t, opts.Validate())
+		},
+		"NegativeCPUAffinityShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.CPUAffinity = []int{-1}
+			assert.Error(t, opts.Validate())
+		},
+		"UnavailableCPUAffinityShouldNotValidate": func(t *testing.T, opts *Create) {
+			allowed, err := allowedCPUs()
+			require.NoError(t, err)
+			maxCPU := 0
+			for cpu := range allowed {
+				if cpu > maxCPU {
+					maxCPU = cpu
+				}
+			}
+			opts.CPUAffinity = []int{maxCPU + 1}
+			assert.Error(t, opts.Validate())
+		},
+		"DuplicateCPUAffinityShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.CPUAffinity = []int{0, 0}
+			assert.Error(t, opts.Validate())
+		},
+		"RemoteCPUAffinityShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.CPUAffinity = []int{0}
+			assert.Error(t, opts.Validate())
+		},
+		"NamespacesValidateOnLinux": func(t *testing.T, opts *Create) {
+			if runtime.GOOS != "linux" {
+				t.Skip("namespaces are only supported on linux")
+			}
+			opts.Namespaces = []NamespaceType{NamespaceUser, NamespacePID}
+			assert.NoError(t, opts.Validate())
+		},
+		"UnrecognizedNamespaceShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Namespaces = []NamespaceType{NamespaceUser, "foo"}
+			assert.Error(t, opts.Validate())
+		},
+		"DuplicateNamespaceShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Namespaces = []NamespaceType{NamespaceUser, NamespaceUser}
+			assert.Error(t, opts.Validate())
+		},
+		"RemoteNamespacesShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.Namespaces = []NamespaceType{NamespaceUser}
+			assert.Error(t, opts.Validate())
+		},
+		"UnprivilegedNamespacesRequireUserNamespace": func(t *testing.T, opts *Create) {
+			assert.NoError(t, validateNamespaces([]NamespaceType{NamespaceUser}, false))
+			assert.NoError(t, validateNamespaces([]NamespaceType{NamespaceUser, NamespacePID, NamespaceMount}, false))
+			assert.Error(t, validateNamespaces([]NamespaceType{NamespacePID}, false))
+			assert.Error(t, validateNamespaces([]NamespaceType{NamespaceNetwork, NamespaceMount}, false))
+			assert.NoError(t, validateNamespaces([]NamespaceType{NamespaceNetwork, NamespaceMount}, true))
+		},
+		"RestartBackoffValidates": func(t *testing.T, opts *Create) {
+			opts.RestartBackoff = &RestartBackoff{Strategy: RestartBackoffExponential, Delay: time.Second}
+			assert.NoError(t, opts.Validate())
+		},
+		"InvalidRestartBackoffShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.RestartBackoff = &RestartBackoff{Strategy: "foo"}
+			assert.Error(t, opts.Validate())
+		},
+		"RestartBackoffWithRestartDelayShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.RestartDelay = time.Second
+			opts.RestartBackoff = &RestartBackoff{Strategy: RestartBackoffConstant, Delay: time.Second}
+			assert.Error(t, opts.Validate())
+		},
+		"ArgsFileValidates": func(t *testing.T, opts *Create) {
+			opts.ArgsFile = ArgsFileResponse
+			assert.NoError(t, opts.Validate())
+		},
+		"UnrecognizedArgsFileShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.ArgsFile = "foo"
+			assert.Error(t, opts.Validate())
+		},
+		"ArgsFileWithShellCommandShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Args = nil
+			opts.ShellCommand = "ls"
+			opts.ArgsFile = ArgsFileResponse
+			assert.Error(t, opts.Validate())
+		},
+		"RemoteArgsFileShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.Remote = &Remote{RemoteConfig: RemoteConfig{Host: "localhost"}}
+			opts.ArgsFile = ArgsFileResponse
+			assert.Error(t, opts.Validate())
+		},
+		"SplitArgsFileWithStandardInputShouldNotValidate": func(t *testing.T, opts *Create) {
+			opts.ArgsFile = ArgsFileSplit
+			opts.StandardInputBytes = []byte("foo")
+			assert.Error(t, opts.Validate())
+		},
+		"ResolveWritesArgsToResponseFile": func(t *testing.T, opts *Create) {
+			opts.Args = []string{"cc", "-o", "out", "with space", `with"quote`, ""}
+			opts.ArgsFile = ArgsFileResponse
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			args := cmd.Args()
+			require.Len(t, args, 2)
+			assert.Equal(t, "cc", args[0])
+			require.True(t, strings.HasPrefix(args[1], "@"))
+
+			path := strings.TrimPrefix(args[1], "@")
+			contents, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "-o\nout\n\"with space\"\n\"with\\\"quote\"\n\"\"\n", string(contents))
+
+			require.NoError(t, opts.Close())
+			_, err = os.Stat(path)
+			assert.True(t, os.IsNotExist(err))
+		},
+		"ResolveSplitsArgsWithXargs": func(t *testing.T, opts *Create) {
+			if runtime.GOOS == "windows" {
+				t.Skip("splitting arguments is not supported on windows")
+			}
+			opts.Args = []string{"echo", "foo", "bar baz"}
+			opts.ArgsFile = ArgsFileSplit
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"xargs", "-0", "echo"}, cmd.Args())
+
+			path := opts.argsFile
+			contents, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "foo\x00bar baz\x00", string(contents))
+
+			require.NoError(t, opts.Close())
+			_, err = os.Stat(path)
+			assert.True(t, os.IsNotExist(err))
+		},
+		"ResolveSkipsArgsFileWithoutArgs": func(t *testing.T, opts *Create) {
+			opts.Args = []string{"true"}
+			opts.ArgsFile = ArgsFileResponse
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"true"}, cmd.Args())
+			assert.Empty(t, opts.argsFile)
+			require.NoError(t, opts.Close())
+		},
+		"CloseRemovesTheArgsFileThatWasWritten": func(t *testing.T, opts *Create) {
+			opts.Args = []string{"echo", "foo"}
+			opts.ArgsFile = ArgsFileResponse
+			_, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+
+			path := opts.argsFile
+			opts.argsFile = ""
+			require.NoError(t, opts.Close())
+			_, err = os.Stat(path)
+			assert.True(t, os.IsNotExist(err))
+		},
+		"ResolveWrapsArgsWithResourceLimits": func(t *testing.T, opts *Create) {
+			if runtime.GOOS == "windows" {
+				t.Skip("resource limits are not supported on windows")
+			}
+			opts.ResourceLimits = map[string]ResourceLimit{
+				ResourceLimitNumFiles:     {Soft: 10, Hard: 20},
+				ResourceLimitAddressSpace: {Soft: 1024 * 1024, Hard: 2048 * 1024},
+			}
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.Equal(t, []string{
+				"/bin/sh", "-c",
+				"ulimit -S -v 1024 2>/dev/null; ulimit -H -v 2048 || exit 1; ulimit -S -v 1024 || exit 1; " +
+					"ulimit -S -n 10 2>/dev/null; ulimit -H -n 20 || exit 1; ulimit -S -n 10 || exit 1; " +
+					`exec "$@"`,
+				"sh", "ls",
+			}, cmd.Args())
+		},
+		"ResolveRoundsUpResourceLimitsAndSupportsUnlimited": func(t *testing.T, opts *Create) {
+			if runtime.GOOS == "windows" {
+				t.Skip("resource limits are not supported on windows")
+			}
+			opts.ResourceLimits = map[string]ResourceLimit{
+				ResourceLimitAddressSpace: {Soft: 1, Hard: ResourceLimitUnlimited},
+				ResourceLimitStack:        {Soft: 1025, Hard: 2048},
+			}
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.Equal(t, []string{
+				"/bin/sh", "-c",
+				"ulimit -S -v 1 2>/dev/null; ulimit -H -v unlimited || exit 1; ulimit -S -v 1 || exit 1; " +
+					"ulimit -S -s 2 2>/dev/null; ulimit -H -s 2 || exit 1; ulimit -S -s 2 || exit 1; " +
+					`exec "$@"`,
+				"sh", "ls",
+			}, cmd.Args())
+		},
 		"ZeroTimeoutShouldNotError": func(t *testing.T, opts *Create) {
 			opts.Timeout = 0
 			assert.NoError(t, opts.Validate())
@@ -174,6 +548,56 @@ func TestCreate(t *testing.T) {
 			assert.Contains(t, cmd.Env(), "foo=bar")
 			assert.NotContains(t, cmd.Env(), "bar=foo")
 		},
+		"EnvironmentIsNotExpandedByDefault": func(t *testing.T, opts *Create) {
+			opts.Environment = map[string]string{
+				"foo":      "bar",
+				"LOG_FILE": "/var/log/${foo}-${JASPER_PROCESS_ID}.log",
+			}
+
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.Contains(t, cmd.Env(), "LOG_FILE=/var/log/${foo}-${JASPER_PROCESS_ID}.log")
+		},
+		"ExpandEnvironmentExpandsReferences": func(t *testing.T, opts *Create) {
+			require.NoError(t, os.Setenv("JASPER_TEST_EXPAND", "inherited"))
+			defer func() { assert.NoError(t, os.Unsetenv("JASPER_TEST_EXPAND")) }()
+
+			opts.ExpandEnvironment = true
+			opts.Tags = []string{"a", "b"}
+			opts.Environment = map[string]string{
+				processIDEnvironVar: "id",
+				"foo":               "bar",
+				"LOG_FILE":          "/var/log/${JASPER_PROCESS_ID}.log",
+				"TAGS":              "${JASPER_PROCESS_TAGS}",
+				"SIBLING":           "${foo}-$foo",
+				"INHERITED":         "${JASPER_TEST_EXPAND}",
+				"UNDEFINED":         "[${JASPER_TEST_UNDEFINED}]",
+				"NOT_RECURSIVE":     "${SIBLING}",
+			}
+
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			env := cmd.Env()
+			assert.Contains(t, env, "LOG_FILE=/var/log/id.log")
+			assert.Contains(t, env, "TAGS=a,b")
+			assert.Contains(t, env, "SIBLING=bar-$foo")
+			assert.Contains(t, env, "INHERITED=inherited")
+			assert.Contains(t, env, "UNDEFINED=[]")
+			assert.Contains(t, env, "NOT_RECURSIVE=${foo}-$foo")
+			assert.Equal(t, "${foo}-$foo", opts.Environment["SIBLING"], "options should not be modified")
+		},
+		"ExpandEnvironmentDoesNotUseInheritedEnvironmentWhenOverridden": func(t *testing.T, opts *Create) {
+			require.NoError(t, os.Setenv("JASPER_TEST_EXPAND", "inherited"))
+			defer func() { assert.NoError(t, os.Unsetenv("JASPER_TEST_EXPAND")) }()
+
+			opts.ExpandEnvironment = true
+			opts.OverrideEnviron = true
+			opts.Environment = map[string]string{"INHERITED": "${JASPER_TEST_EXPAND}"}
+
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"INHERITED="}, cmd.Env())
+		},
 		"MultipleArgsArePropagated": func(t *testing.T, opts *Create) {
 			opts.Args = append(opts.Args, "-lha")
 			cmd, _, err := opts.Resolve(ctx)
@@ -199,6 +623,55 @@ func TestCreate(t *testing.T) {
 			assert.Error(t, cmd.Wait())
 			assert.True(t, time.Now().After(deadline))
 		},
+		"TimeoutFiresWhenMockClockAdvances": func(t *testing.T, opts *Create) {
+			start := time.Now()
+			mockClock := clock.NewMockClock(start)
+			opts.clock = mockClock
+			opts.Timeout = time.Hour
+			opts.Args = []string{"sleep", "10"}
+
+			cmd, deadline, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, start.Add(time.Hour), deadline)
+			require.NoError(t, cmd.Start())
+
+			waitErr := make(chan error, 1)
+			go func() {
+				waitErr <- cmd.Wait()
+			}()
+
+			select {
+			case <-waitErr:
+				assert.Fail(t, "command exited before timeout")
+			default:
+			}
+
+			mockClock.Advance(time.Hour)
+			select {
+			case err := <-waitErr:
+				assert.Error(t, err)
+			case <-time.After(5 * time.Second):
+				assert.Fail(t, "command did not exit after timeout")
+			}
+		},
+		"DeferredTimeoutBeginsWithStartTimeout": func(t *testing.T, opts *Create) {
+			start := time.Now()
+			mockClock := clock.NewMockClock(start)
+			opts.clock = mockClock
+			opts.Timeout = time.Hour
+			opts.DeferStart = true
+			opts.Args = []string{"true"}
+
+			cmd, deadline, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.True(t, deadline.IsZero())
+
+			mockClock.Advance(2 * time.Hour)
+			assert.Equal(t, start.Add(3*time.Hour), opts.StartTimeout())
+			assert.True(t, opts.StartTimeout().IsZero())
+			require.NoError(t, cmd.Start())
+			assert.NoError(t, cmd.Wait())
+		},
 		"ReturnedContextWrapsResolveContext": func(t *testing.T, opts *Create) {
 			opts.Args = []string{"sleep", "10"}
 			opts.Timeout = 2 * time.Second
@@ -315,6 +788,53 @@ func TestCreate(t *testing.T) {
 			assert.Error(t, err)
 			assert.Nil(t, cmd)
 		},
+		"ResolveTwiceFails": func(t *testing.T, opts *Create) {
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+
+			cmd, _, err = opts.Resolve(ctx)
+			assert.Equal(t, ErrOptionsAlreadyUsed, err)
+			assert.Nil(t, cmd)
+		},
+		"ResolveSucceedsAfterFailedResolve": func(t *testing.T, opts *Create) {
+			opts.Args = []string{}
+			cmd, _, err := opts.Resolve(ctx)
+			require.Error(t, err)
+			assert.Nil(t, cmd)
+
+			opts.Args = []string{"ls"}
+			cmd, _, err = opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.NotNil(t, cmd)
+		},
+		"CopyOfUsedOptionsCanResolve": func(t *testing.T, opts *Create) {
+			cmd, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			assert.NotNil(t, cmd)
+
+			cmd, _, err = opts.Copy().Resolve(ctx)
+			require.NoError(t, err)
+			assert.NotNil(t, cmd)
+		},
+		"ModifyingCopyDoesNotModifyOriginal": func(t *testing.T, opts *Create) {
+			opts.Environment = map[string]string{"foo": "bar"}
+			opts.Tags = []string{"tag"}
+			opts.OnSuccess = []*Create{{Args: []string{"echo", "success"}}}
+
+			optsCopy := opts.Copy()
+			optsCopy.Args[0] = "pwd"
+			optsCopy.AddEnvVar("foo", "baz")
+			optsCopy.AddEnvVar("bat", "qux")
+			optsCopy.Tags[0] = "other"
+			optsCopy.Tags = append(optsCopy.Tags, "new")
+			optsCopy.OnSuccess[0].Args[1] = "failure"
+
+			assert.Equal(t, []string{"ls"}, opts.Args)
+			assert.Equal(t, map[string]string{"foo": "bar"}, opts.Environment)
+			assert.Equal(t, []string{"tag"}, opts.Tags)
+			assert.Equal(t, []string{"echo", "success"}, opts.OnSuccess[0].Args)
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			opts := &Create{Args: []string{"ls"}}
@@ -323,6 +843,37 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateResolveConcurrently(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := &Create{Args: []string{"ls"}}
+
+	const numResolvers = 10
+	errs := make(chan error, numResolvers)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < numResolvers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := opts.Resolve(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded int
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, ErrOptionsAlreadyUsed, err)
+	}
+	assert.Equal(t, 1, succeeded)
+}
+
 func TestFileLogging(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -474,3 +1025,170 @@ func TestFileLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateLogLevelOverride(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	makeOpts := func() *Create {
+		return &Create{
+			Args: []string{"sh", "-c", "echo out; echo err >&2"},
+			Output: Output{
+				Loggers: []*LoggerConfig{
+					{
+						info: loggerConfigInfo{
+							Type:   LogInMemory,
+							Format: RawLoggerConfigFormatBSON,
+						},
+						producer: &InMemoryLoggerOptions{
+							InMemoryCap: 100,
+							Base:        BaseOptions{Format: LogFormatPlain},
+						},
+					},
+				},
+			},
+		}
+	}
+	runAndGetMessages := func(t *testing.T, opts *Create) []message.Composer {
+		exe, _, err := opts.Resolve(ctx)
+		require.NoError(t, err)
+		require.NoError(t, exe.Start())
+		require.NoError(t, exe.Wait())
+		require.NoError(t, opts.Close())
+
+		safeSender, ok := opts.Output.Loggers[0].sender.(*SafeSender)
+		require.True(t, ok)
+		sender, ok := safeSender.Sender.(*send.InMemorySender)
+		require.True(t, ok)
+		msgs := sender.Get()
+		require.Len(t, msgs, 2)
+		return msgs
+	}
+
+	t.Run("OverridesPriorityOfOutputAndError", func(t *testing.T) {
+		opts := makeOpts()
+		opts.AddEnvVar(LogLevelEnvironVar, "warning")
+
+		for _, msg := range runAndGetMessages(t, opts) {
+			assert.Equal(t, level.Warning, msg.Priority(), msg.String())
+		}
+	})
+	t.Run("DefaultsToOutputPrioritiesWithoutOverride", func(t *testing.T) {
+		for _, msg := range runAndGetMessages(t, makeOpts()) {
+			if msg.String() == "err" {
+				assert.Equal(t, level.Error, msg.Priority())
+			} else {
+				assert.Equal(t, level.Trace, msg.Priority())
+			}
+		}
+	})
+	t.Run("InvalidLevelFailsValidation", func(t *testing.T) {
+		opts := makeOpts()
+		opts.AddEnvVar(LogLevelEnvironVar, "loud")
+		assert.Error(t, opts.Validate())
+		_, _, err := opts.Resolve(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateTimeoutFlushesOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := &Create{
+		Args:    []string{"sh", "-c", "echo line1; echo line2; exec sleep 5"},
+		Timeout: time.Second,
+		Output: Output{
+			Loggers: []*LoggerConfig{
+				{
+					info: loggerConfigInfo{
+						Type:   LogInMemory,
+						Format: RawLoggerConfigFormatBSON,
+					},
+					producer: &InMemoryLoggerOptions{
+						InMemoryCap: 100,
+						Base:        BaseOptions{Format: LogFormatPlain},
+					},
+				},
+			},
+		},
+	}
+
+	exe, _, err := opts.Resolve(ctx)
+	require.NoError(t, err)
+	require.NoError(t, exe.Start())
+	assert.Error(t, exe.Wait())
+
+	// The output is checked before the options are closed, so it must have
+	// been flushed when the process timed out.
+	safeSender, ok := opts.Output.Loggers[0].sender.(*SafeSender)
+	require.True(t, ok)
+	sender, ok := safeSender.Sender.(*send.InMemorySender)
+	require.True(t, ok)
+	logOut, err := sender.GetString()
+	require.NoError(t, err)
+	out := strings.Join(logOut, "\n")
+	assert.Contains(t, out, "line1")
+	assert.Contains(t, out, "line2")
+
+	assert.NoError(t, opts.Close())
+}
+
+func TestCreateArgsFromNullDelimited(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		data     string
+		expected []string
+	}{
+		{name: "Empty", data: "", expected: []string{}},
+		{name: "OnlyTerminator", data: "\x00", expected: []string{}},
+		{name: "SingleArgument", data: "ls", expected: []string{"ls"}},
+		{name: "TerminatedArguments", data: "ls\x00-la\x00", expected: []string{"ls", "-la"}},
+		{name: "UnterminatedFinalArgument", data: "ls\x00-la", expected: []string{"ls", "-la"}},
+		{name: "ArgumentsWithSpaces", data: "echo\x00foo bar\x00 baz ", expected: []string{"echo", "foo bar", " baz "}},
+		{name: "ArgumentsWithNewlines", data: "echo\x00foo\nbar\x00\n", expected: []string{"echo", "foo\nbar", "\n"}},
+		{name: "ArgumentsWithQuotes", data: "echo\x00'foo\x00\"bar", expected: []string{"echo", "'foo", "\"bar"}},
+		{name: "EmptyArguments", data: "echo\x00\x00foo\x00\x00", expected: []string{"echo", "", "foo", ""}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &Create{Args: []string{"previous"}}
+			opts.ArgsFromNullDelimited([]byte(test.data))
+			assert.Equal(t, test.expected, opts.Args)
+		})
+	}
+	t.Run("ProcessReceivesExactArguments", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("test commands are not available on Windows")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		args := []string{"foo bar", "multi\nline", " ", "'quoted\"", ""}
+		data := []byte("sh\x00-c\x00printf '%s\\0' \"$@\"\x00sh\x00")
+		for _, arg := range args {
+			data = append(data, append([]byte(arg), 0)...)
+		}
+
+		output := &bytes.Buffer{}
+		opts := &Create{Output: Output{Output: output}}
+		opts.ArgsFromNullDelimited(data)
+
+		exe, _, err := opts.Resolve(ctx)
+		require.NoError(t, err)
+		require.NoError(t, exe.Start())
+		require.NoError(t, exe.Wait())
+
+		received := &Create{}
+		received.ArgsFromNullDelimited(output.Bytes())
+		assert.Equal(t, args, received.Args)
+	})
+}
@@ -0,0 +1,133 @@
+package options
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+const (
+	// DefaultHealthCheckInterval is the default time between health
+	// check probes.
+	DefaultHealthCheckInterval = 10 * time.Second
+	// DefaultHealthCheckFailureThreshold is the default number of
+	// consecutive failed probes after which a process is considered
+	// unhealthy.
+	DefaultHealthCheckFailureThreshold = 3
+)
+
+// HealthCheck configures a probe that managers run periodically to check
+// whether a process is healthy. Exactly one of Args, TCPAddress and URL must be
+// specified.
+type HealthCheck struct {
+	// Args is a command that is run on the manager's host, which
+	// succeeds if it exits with a zero exit code.
+	Args []string `bson:"args,omitempty" json:"args,omitempty" yaml:"args,omitempty"`
+	// TCPAddress is an address that succeeds if it accepts a TCP
+	// connection.
+	TCPAddress string `bson:"tcp_address,omitempty" json:"tcp_address,omitempty" yaml:"tcp_address,omitempty"`
+	// URL is an HTTP URL that succeeds if a GET request to it returns
+	// a 2xx or 3xx status.
+	URL string `bson:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	// Interval is the time between probes, which defaults to
+	// DefaultHealthCheckInterval. Timeout limits how long each probe
+	// may take, and defaults to the interval.
+	Interval time.Duration `bson:"interval,omitempty" json:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout  time.Duration `bson:"timeout,omitempty" json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// FailureThreshold is the number of consecutive failed probes after
+	// which the process is considered unhealthy, which defaults to
+	// DefaultHealthCheckFailureThreshold. A single successful probe
+	// makes the process healthy again.
+	FailureThreshold int `bson:"failure_threshold,omitempty" json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+	// RestartOnFailure causes the process to be restarted once it is
+	// considered unhealthy, in the same manner as processes restarted
+	// because their watched paths changed.
+	RestartOnFailure bool `bson:"restart_on_failure,omitempty" json:"restart_on_failure,omitempty" yaml:"restart_on_failure,omitempty"`
+}
+
+// Validate ensures that the health check specifies a single probe and that its
+// settings are valid.
+func (hc *HealthCheck) Validate() error {
+	probes := 0
+	for _, isSet := range []bool{len(hc.Args) != 0, hc.TCPAddress != "", hc.URL != ""} {
+		if isSet {
+			probes++
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(probes != 1, "must specify exactly one of a command, TCP address or URL to probe")
+	catcher.NewWhen(hc.Interval < 0, "interval cannot be negative")
+	catcher.NewWhen(hc.Timeout < 0, "timeout cannot be negative")
+	catcher.NewWhen(hc.FailureThreshold < 0, "failure threshold cannot be negative")
+	return catcher.Resolve()
+}
+
+// GetInterval returns the time between probes.
+func (hc *HealthCheck) GetInterval() time.Duration {
+	if hc.Interval == 0 {
+		return DefaultHealthCheckInterval
+	}
+	return hc.Interval
+}
+
+// GetFailureThreshold returns the number of consecutive failed probes after
+// which the process is considered unhealthy.
+func (hc *HealthCheck) GetFailureThreshold() int {
+	if hc.FailureThreshold == 0 {
+		return DefaultHealthCheckFailureThreshold
+	}
+	return hc.FailureThreshold
+}
+
+// Probe runs the health check once, returning an error if it fails.
+func (hc *HealthCheck) Probe(ctx context.Context) error {
+	timeout := hc.Timeout
+	if timeout == 0 {
+		timeout = hc.GetInterval()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case len(hc.Args) != 0:
+		return errors.Wrap(exec.CommandContext(ctx, hc.Args[0], hc.Args[1:]...).Run(), "health check command failed")
+	case hc.TCPAddress != "":
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", hc.TCPAddress)
+		if err != nil {
+			return errors.Wrapf(err, "could not connect to '%s'", hc.TCPAddress)
+		}
+		return errors.WithStack(conn.Close())
+	case hc.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, hc.URL, nil)
+		if err != nil {
+			return errors.Wrap(err, "problem building request")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return errors.Wrapf(err, "problem requesting '%s'", hc.URL)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+			return errors.Errorf("'%s' returned status %d", hc.URL, resp.StatusCode)
+		}
+		return nil
+	default:
+		return errors.New("health check does not specify a probe")
+	}
+}
+
+// Copy returns a copy of the health check.
+func (hc *HealthCheck) Copy() *HealthCheck {
+	hcCopy := *hc
+	if hc.Args != nil {
+		hcCopy.Args = make([]string, len(hc.Args))
+		_ = copy(hcCopy.Args, hc.Args)
+	}
+	return &hcCopy
+}
@@ -0,0 +1,85 @@
+package options
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			hc    HealthCheck
+			valid bool
+		}{
+			"Command":           {hc: HealthCheck{Args: []string{"true"}}, valid: true},
+			"TCPAddress":        {hc: HealthCheck{TCPAddress: "localhost:8080"}, valid: true},
+			"URL":               {hc: HealthCheck{URL: "http://localhost:8080"}, valid: true},
+			"NoProbe":           {hc: HealthCheck{}},
+			"MultipleProbes":    {hc: HealthCheck{TCPAddress: "localhost:8080", URL: "http://localhost:8080"}},
+			"NegativeInterval":  {hc: HealthCheck{URL: "http://localhost:8080", Interval: -time.Second}},
+			"NegativeTimeout":   {hc: HealthCheck{URL: "http://localhost:8080", Timeout: -time.Second}},
+			"NegativeThreshold": {hc: HealthCheck{URL: "http://localhost:8080", FailureThreshold: -1}},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				if testCase.valid {
+					assert.NoError(t, testCase.hc.Validate())
+				} else {
+					assert.Error(t, testCase.hc.Validate())
+				}
+			})
+		}
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		hc := HealthCheck{}
+		assert.Equal(t, DefaultHealthCheckInterval, hc.GetInterval())
+		assert.Equal(t, DefaultHealthCheckFailureThreshold, hc.GetFailureThreshold())
+	})
+	t.Run("Probe", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		t.Run("Command", func(t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("test commands are not available on Windows")
+			}
+			assert.NoError(t, (&HealthCheck{Args: []string{"true"}}).Probe(ctx))
+			assert.Error(t, (&HealthCheck{Args: []string{"false"}}).Probe(ctx))
+		})
+		t.Run("TCPAddress", func(t *testing.T) {
+			listener, err := net.Listen("tcp", "localhost:0")
+			require.NoError(t, err)
+			addr := listener.Addr().String()
+
+			assert.NoError(t, (&HealthCheck{TCPAddress: addr}).Probe(ctx))
+			require.NoError(t, listener.Close())
+			assert.Error(t, (&HealthCheck{TCPAddress: addr}).Probe(ctx))
+		})
+		t.Run("URL", func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/health" {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer srv.Close()
+
+			assert.NoError(t, (&HealthCheck{URL: srv.URL + "/health"}).Probe(ctx))
+			assert.Error(t, (&HealthCheck{URL: srv.URL + "/other"}).Probe(ctx))
+		})
+		t.Run("Timeout", func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Second)
+			}))
+			defer srv.Close()
+
+			assert.Error(t, (&HealthCheck{URL: srv.URL, Timeout: 10 * time.Millisecond}).Probe(ctx))
+		})
+	})
+}
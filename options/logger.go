@@ -176,6 +176,17 @@ func (lc *LoggerConfig) Producer() LoggerProducer { return lc.producer }
 // Type returns the type string.
 func (lc *LoggerConfig) Type() string { return lc.info.Type }
 
+// Copy returns a copy of the logger config that does not share the sender
+// resolved by the original, so that the copy may be used by a different
+// process.
+func (lc *LoggerConfig) Copy() *LoggerConfig {
+	return &LoggerConfig{
+		Registry: lc.Registry,
+		info:     lc.info,
+		producer: lc.producer,
+	}
+}
+
 // Resolve resolves the LoggerConfig and returns the resulting grip
 // send.Sender.
 func (lc *LoggerConfig) Resolve() (send.Sender, error) {
@@ -0,0 +1,226 @@
+package options
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// rotatingFile is a log file that is rotated once writing to it would
+// exceed its maximum size. The rotated file is kept as a single backup with
+// the suffix ".1", which is gzipped (".1.gz") if compression is enabled.
+type rotatingFile struct {
+	path     string
+	maxBytes int64
+	compress bool
+	file     *os.File
+	size     int64
+	mu       sync.Mutex
+}
+
+func openRotatingFile(path string, maxBytes int64, compress bool) (*rotatingFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening log file '%s'", path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrapf(err, "problem getting size of log file '%s'", path)
+		catcher.Add(file.Close())
+		return nil, catcher.Resolve()
+	}
+
+	return &rotatingFile{
+		path:     path,
+		maxBytes: maxBytes,
+		compress: compress,
+		file:     file,
+		size:     info.Size(),
+	}, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, errors.New("cannot write to closed log file")
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file to the backup and starts a new file. If
+// compressing the backup fails, the uncompressed backup is kept.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return errors.Wrapf(err, "problem closing log file '%s' for rotation", r.path)
+	}
+	r.file = nil
+
+	backup := r.path + ".1"
+	if err := os.Rename(r.path, backup); err != nil {
+		return errors.Wrapf(err, "problem rotating log file '%s'", r.path)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening log file '%s' after rotation", r.path)
+	}
+	r.file = file
+	r.size = 0
+
+	if !r.compress {
+		// Remove any compressed backup from when compression was
+		// enabled, so that there is only one backup.
+		if err = os.Remove(backup + ".gz"); err != nil && !os.IsNotExist(err) {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "problem removing stale compressed log file backup",
+				"path":    backup + ".gz",
+			}))
+		}
+		return nil
+	}
+
+	grip.Warning(message.WrapError(compressFile(backup, backup+".gz"), message.Fields{
+		"message": "problem compressing rotated log file",
+		"path":    backup,
+	}))
+
+	return nil
+}
+
+// compressFile gzips the file at src to dst and removes src.
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening file '%s'", src)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrapf(err, "problem creating file '%s'", dst)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	catcher.Wrapf(err, "problem compressing file '%s'", src)
+	catcher.Wrap(gz.Close(), "problem flushing compressed file")
+	catcher.Wrap(out.Close(), "problem closing compressed file")
+	if catcher.HasErrors() {
+		catcher.Wrap(os.Remove(dst), "problem removing incomplete compressed file")
+		return catcher.Resolve()
+	}
+
+	return errors.Wrapf(os.Remove(src), "problem removing file '%s' after compressing it", src)
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+	return errors.Wrapf(err, "problem closing log file '%s'", r.path)
+}
+
+// rotatingFileSender is a sender that closes its rotating file when it is
+// closed.
+type rotatingFileSender struct {
+	send.Sender
+	file *rotatingFile
+}
+
+func newRotatingFileSender(path string, maxBytes int64, compress bool, l send.LevelInfo) (send.Sender, error) {
+	file, err := openRotatingFile(path, maxBytes, compress)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sender := send.WrapWriter(file)
+	sender.SetName(DefaultLogName)
+	if err = sender.SetLevel(l); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "problem setting level")
+		catcher.Add(file.Close())
+		return nil, catcher.Resolve()
+	}
+
+	return &rotatingFileSender{Sender: sender, file: file}, nil
+}
+
+func (s *rotatingFileSender) Close() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(s.Sender.Close())
+	catcher.Add(s.file.Close())
+	return catcher.Resolve()
+}
+
+type logFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *logFileReader) Close() error {
+	catcher := grip.NewBasicCatcher()
+	for _, closer := range r.closers {
+		catcher.Add(closer.Close())
+	}
+	return catcher.Resolve()
+}
+
+// OpenLogFile opens a log file written by a file logger for reading. Files
+// that are gzipped, such as compressed rotated log files, are transparently
+// decompressed. The caller is responsible for closing the returned reader.
+func OpenLogFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening log file '%s'", path)
+	}
+
+	buf := bufio.NewReader(file)
+	magic, err := buf.Peek(2)
+	if err != nil && err != io.EOF {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrapf(err, "problem reading log file '%s'", path)
+		catcher.Add(file.Close())
+		return nil, catcher.Resolve()
+	}
+
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return &logFileReader{Reader: buf, closers: []io.Closer{file}}, nil
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrapf(err, "problem decompressing log file '%s'", path)
+		catcher.Add(file.Close())
+		return nil, catcher.Resolve()
+	}
+
+	return &logFileReader{Reader: gz, closers: []io.Closer{gz, file}}, nil
+}
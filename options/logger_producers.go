@@ -1,8 +1,14 @@
 package options
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
 )
 
@@ -61,6 +67,14 @@ const LogFile = "file"
 type FileLoggerOptions struct {
 	Filename string      `json:"filename " bson:"filename"`
 	Base     BaseOptions `json:"base" bson:"base"`
+	// MaxBytes, if positive, is the size at which the log file is
+	// rotated. The rotated file is kept as a single backup named with
+	// the suffix ".1", replacing any previous backup.
+	MaxBytes int64 `json:"max_bytes,omitempty" bson:"max_bytes,omitempty"`
+	// Compress gzips the backup when the log file is rotated, so that
+	// it is named with the suffix ".1.gz". Use OpenLogFile to read
+	// compressed backups.
+	Compress bool `json:"compress,omitempty" bson:"compress,omitempty"`
 }
 
 // NewFileLoggerProducer returns a LoggerProducer backed by FileLoggerOptions.
@@ -71,6 +85,8 @@ func (opts *FileLoggerOptions) Validate() error {
 	catcher := grip.NewBasicCatcher()
 
 	catcher.NewWhen(opts.Filename == "", "must specify a filename")
+	catcher.NewWhen(opts.MaxBytes < 0, "maximum file size cannot be negative")
+	catcher.NewWhen(opts.Compress && opts.MaxBytes == 0, "cannot compress rotated files without a maximum file size")
 	catcher.Add(opts.Base.Validate())
 	return catcher.Resolve()
 }
@@ -81,7 +97,15 @@ func (opts *FileLoggerOptions) Configure() (send.Sender, error) {
 		return nil, errors.Wrap(err, "invalid options")
 	}
 
-	sender, err := send.NewPlainFileLogger(DefaultLogName, opts.Filename, opts.Base.Level)
+	var (
+		sender send.Sender
+		err    error
+	)
+	if opts.MaxBytes > 0 {
+		sender, err = newRotatingFileSender(opts.Filename, opts.MaxBytes, opts.Compress, opts.Base.Level)
+	} else {
+		sender, err = send.NewPlainFileLogger(DefaultLogName, opts.Filename, opts.Base.Level)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "problem creating base file logger")
 	}
@@ -93,6 +117,131 @@ func (opts *FileLoggerOptions) Configure() (send.Sender, error) {
 	return sender, nil
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// JSON Lines Logger
+///////////////////////////////////////////////////////////////////////////////
+
+// LogJSONLines is the type name for the JSON lines file logger.
+const LogJSONLines = "json-lines"
+
+// JSONLinesLoggerOptions packages the options for creating a logger that
+// writes each message to a file as a single line containing a JSON object.
+// The format in the base options is ignored.
+type JSONLinesLoggerOptions struct {
+	Filename string      `json:"filename" bson:"filename"`
+	Base     BaseOptions `json:"base" bson:"base"`
+}
+
+// NewJSONLinesLoggerProducer returns a LoggerProducer backed by
+// JSONLinesLoggerOptions.
+func NewJSONLinesLoggerProducer() LoggerProducer { return &JSONLinesLoggerOptions{} }
+
+// Validate ensures JSONLinesLoggerOptions is valid.
+func (opts *JSONLinesLoggerOptions) Validate() error {
+	if opts.Base.Format == "" {
+		opts.Base.Format = LogFormatJSON
+	}
+
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(opts.Filename == "", "must specify a filename")
+	catcher.Add(opts.Base.Validate())
+	return catcher.Resolve()
+}
+
+func (*JSONLinesLoggerOptions) Type() string { return LogJSONLines }
+func (opts *JSONLinesLoggerOptions) Configure() (send.Sender, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid options")
+	}
+
+	sender, err := send.NewPlainFileLogger(DefaultLogName, opts.Filename, opts.Base.Level)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating base JSON lines logger")
+	}
+
+	sender, err = NewSafeSender(sender, opts.Base)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating safe JSON lines logger")
+	}
+	if err = sender.SetFormatter(makeJSONLinesFormatter()); err != nil {
+		return nil, errors.Wrap(err, "problem setting JSON lines format")
+	}
+	return sender, nil
+}
+
+// makeJSONLinesFormatter returns a formatter that renders each message as a
+// single line JSON object containing the message's level and the time it was
+// created, or the time it was formatted if the message does not record when
+// it was created. Structured messages have their fields included in the
+// object, and fields named "level" or "time" take precedence over the
+// message's level and time; all other messages are included as a string in
+// the "message" field. Each message in a group is rendered on its own line.
+func makeJSONLinesFormatter() send.MessageFormatter {
+	var format send.MessageFormatter
+	format = func(m message.Composer) (string, error) {
+		if group, ok := m.(*message.GroupComposer); ok {
+			lines := []string{}
+			for _, msg := range group.Messages() {
+				line, err := format(msg)
+				if err != nil {
+					return "", errors.WithStack(err)
+				}
+				lines = append(lines, line)
+			}
+			return strings.Join(lines, "\n"), nil
+		}
+
+		doc := map[string]interface{}{}
+		if fields, ok := m.Raw().(message.Fields); ok {
+			for k, v := range fields {
+				doc[k] = v
+			}
+		} else {
+			doc["message"] = m.String()
+		}
+		if _, ok := doc["level"]; !ok {
+			doc["level"] = m.Priority().String()
+		}
+		if _, ok := doc["time"]; !ok {
+			doc["time"] = messageTime(m).Format(time.RFC3339Nano)
+		}
+
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return "", errors.Wrap(err, "problem marshalling message to JSON")
+		}
+		return string(out), nil
+	}
+	return format
+}
+
+// messageTime returns the time that the message was created, which is
+// recorded in the message.Base embedded in most composers, or the current
+// time if the message does not record it.
+func messageTime(m message.Composer) time.Time {
+	val := reflect.ValueOf(m)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return time.Now()
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return time.Now()
+	}
+
+	field := val.FieldByName("Base")
+	if !field.IsValid() || !field.CanInterface() {
+		return time.Now()
+	}
+	base, ok := field.Interface().(message.Base)
+	if !ok || base.Time.IsZero() {
+		return time.Now()
+	}
+	return base.Time
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Inherited Logger
 ///////////////////////////////////////////////////////////////////////////////
@@ -216,3 +365,90 @@ func (opts *SplunkLoggerOptions) Configure() (send.Sender, error) {
 	}
 	return sender, nil
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// Syslog Logger
+///////////////////////////////////////////////////////////////////////////////
+
+// LogSyslog is the type name for the syslog logger.
+const LogSyslog = "syslog"
+
+// syslogFacilities maps the names of syslog facilities to their codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// SyslogLoggerOptions packages the options for creating a logger that sends
+// messages to syslog. Syslog is not supported on Windows.
+type SyslogLoggerOptions struct {
+	// Network and Address specify the syslog server to connect to, such
+	// as "udp" and "localhost:514". If both are empty, messages are sent
+	// to the local syslog server.
+	Network string `json:"network,omitempty" bson:"network,omitempty"`
+	Address string `json:"address,omitempty" bson:"address,omitempty"`
+	// Facility is the name of the syslog facility, such as "daemon" or
+	// "local0", which defaults to "user".
+	Facility string `json:"facility,omitempty" bson:"facility,omitempty"`
+	// Tag is the tag of each message, which defaults to DefaultLogName.
+	Tag  string      `json:"tag,omitempty" bson:"tag,omitempty"`
+	Base BaseOptions `json:"base" bson:"base"`
+}
+
+// NewSyslogLoggerProducer returns a LoggerProducer backed by
+// SyslogLoggerOptions.
+func NewSyslogLoggerProducer() LoggerProducer { return &SyslogLoggerOptions{} }
+
+// Validate ensures SyslogLoggerOptions is valid.
+func (opts *SyslogLoggerOptions) Validate() error {
+	if opts.Facility == "" {
+		opts.Facility = "user"
+	}
+	if opts.Tag == "" {
+		opts.Tag = DefaultLogName
+	}
+
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen((opts.Network == "") != (opts.Address == ""), "must specify both a network and an address, or neither to use the local syslog server")
+	_, ok := syslogFacilities[opts.Facility]
+	catcher.ErrorfWhen(!ok, "unrecognized syslog facility '%s'", opts.Facility)
+	catcher.Add(opts.Base.Validate())
+	return catcher.Resolve()
+}
+
+func (*SyslogLoggerOptions) Type() string { return LogSyslog }
+func (opts *SyslogLoggerOptions) Configure() (send.Sender, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid options")
+	}
+
+	sender, err := newSyslogSender(opts.Network, opts.Address, syslogFacilities[opts.Facility], opts.Tag, opts.Base.Level)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating base syslog logger")
+	}
+
+	sender, err = NewSafeSender(sender, opts.Base)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating safe syslog logger")
+	}
+	return sender, nil
+}
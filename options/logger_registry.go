@@ -11,9 +11,11 @@ var globalLoggerRegistry LoggerRegistry = &basicLoggerRegistry{
 	factories: map[string]LoggerProducerFactory{
 		LogDefault:   NewDefaultLoggerProducer,
 		LogFile:      NewFileLoggerProducer,
+		LogJSONLines: NewJSONLinesLoggerProducer,
 		LogInherited: NewInheritedLoggerProducer,
 		LogInMemory:  NewInMemoryLoggerProducer,
 		LogSplunk:    NewSplunkLoggerProducer,
+		LogSyslog:    NewSyslogLoggerProducer,
 	},
 	marshalers: map[RawLoggerConfigFormat]Marshaler{
 		RawLoggerConfigFormatJSON: json.Marshal,
@@ -0,0 +1,14 @@
+// +build windows plan9
+
+package options
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip/send"
+)
+
+func newSyslogSender(_, _ string, _ int, _ string, _ send.LevelInfo) (send.Sender, error) {
+	return nil, errors.Errorf("syslog is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,75 @@
+// +build !windows,!plan9
+
+package options
+
+import (
+	"log/syslog"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// syslogSender sends messages to syslog with the severity that corresponds to
+// their priority.
+type syslogSender struct {
+	writer *syslog.Writer
+	*send.Base
+}
+
+func newSyslogSender(network, address string, facility int, tag string, l send.LevelInfo) (send.Sender, error) {
+	writer, err := syslog.Dial(network, address, syslog.Priority(facility<<3)|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem connecting to syslog")
+	}
+
+	s := &syslogSender{
+		writer: writer,
+		Base:   send.MakeBase(DefaultLogName, func() {}, writer.Close),
+	}
+	if err = s.SetLevel(l); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "problem setting level")
+		catcher.Add(writer.Close())
+		return nil, catcher.Resolve()
+	}
+
+	return s, nil
+}
+
+func (s *syslogSender) Send(m message.Composer) {
+	if !s.Level().ShouldLog(m) {
+		return
+	}
+
+	msg, err := s.Formatter()(m)
+	if err != nil {
+		s.ErrorHandler()(err, m)
+		return
+	}
+
+	s.ErrorHandler()(s.write(m.Priority(), msg), m)
+}
+
+func (s *syslogSender) write(p level.Priority, msg string) error {
+	switch p {
+	case level.Emergency:
+		return s.writer.Emerg(msg)
+	case level.Alert:
+		return s.writer.Alert(msg)
+	case level.Critical:
+		return s.writer.Crit(msg)
+	case level.Error:
+		return s.writer.Err(msg)
+	case level.Warning:
+		return s.writer.Warning(msg)
+	case level.Notice:
+		return s.writer.Notice(msg)
+	case level.Info:
+		return s.writer.Info(msg)
+	default:
+		return s.writer.Debug(msg)
+	}
+}
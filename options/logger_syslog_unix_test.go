@@ -0,0 +1,73 @@
+// +build !windows,!plan9
+
+package options
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+)
+
+func TestSyslogLogger(t *testing.T) {
+	t.Run("IsRegistered", func(t *testing.T) {
+		factory, ok := GetGlobalLoggerRegistry().Resolve(LogSyslog)
+		require.True(t, ok)
+		assert.Equal(t, LogSyslog, factory().Type())
+	})
+	t.Run("ValidateSetsDefaults", func(t *testing.T) {
+		opts := &SyslogLoggerOptions{Base: BaseOptions{Format: LogFormatPlain}}
+		require.NoError(t, opts.Validate())
+		assert.Equal(t, "user", opts.Facility)
+		assert.Equal(t, DefaultLogName, opts.Tag)
+	})
+	t.Run("ValidateFailsWithUnrecognizedFacility", func(t *testing.T) {
+		opts := &SyslogLoggerOptions{Facility: "local9"}
+		assert.Error(t, opts.Validate())
+	})
+	t.Run("ValidateFailsWithNetworkButNoAddress", func(t *testing.T) {
+		opts := &SyslogLoggerOptions{Network: "udp"}
+		assert.Error(t, opts.Validate())
+	})
+	t.Run("ValidateFailsWithAddressButNoNetwork", func(t *testing.T) {
+		opts := &SyslogLoggerOptions{Address: "localhost:514"}
+		assert.Error(t, opts.Validate())
+	})
+	t.Run("SendsToServer", func(t *testing.T) {
+		server, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer server.Close()
+
+		config := &LoggerConfig{}
+		require.NoError(t, config.Set(&SyslogLoggerOptions{
+			Network:  "udp",
+			Address:  server.LocalAddr().String(),
+			Facility: "local3",
+			Tag:      "jasper-test",
+			Base:     BaseOptions{Format: LogFormatPlain},
+		}))
+		sender, err := config.Resolve()
+		require.NoError(t, err)
+
+		logger := &CachedLogger{Output: sender}
+		require.NoError(t, logger.Send(&LoggingPayload{Data: "hello syslog", Priority: level.Warning}))
+		defer func() {
+			assert.NoError(t, logger.Close())
+		}()
+
+		require.NoError(t, server.SetReadDeadline(time.Now().Add(5*time.Second)))
+		buf := make([]byte, 1024)
+		n, _, err := server.ReadFrom(buf)
+		require.NoError(t, err)
+		msg := string(buf[:n])
+
+		// The priority is the facility code (19 for local3) times eight
+		// plus the severity code (4 for warning).
+		assert.Contains(t, msg, "<156>")
+		assert.Contains(t, msg, "jasper-test")
+		assert.Contains(t, msg, "hello syslog")
+	})
+}
@@ -1,12 +1,20 @@
 package options
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -424,3 +432,191 @@ func TestLoggerConfigMarshalJSON(t *testing.T) {
 		assert.Equal(t, config.info.Config, roundTripped.info.Config)
 	})
 }
+
+func TestJSONLinesLogger(t *testing.T) {
+	t.Run("IsRegistered", func(t *testing.T) {
+		assert.True(t, GetGlobalLoggerRegistry().Check(LogJSONLines))
+	})
+	t.Run("RequiresFilename", func(t *testing.T) {
+		opts := &JSONLinesLoggerOptions{}
+		assert.Error(t, opts.Validate())
+	})
+	t.Run("WritesOneJSONObjectPerLine", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "jsonlines")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		defer func() {
+			assert.NoError(t, os.RemoveAll(file.Name()))
+		}()
+
+		config := &LoggerConfig{}
+		require.NoError(t, config.Set(&JSONLinesLoggerOptions{Filename: file.Name()}))
+		sender, err := config.Resolve()
+		require.NoError(t, err)
+
+		logger := &CachedLogger{Output: sender}
+		for _, lp := range []*LoggingPayload{
+			{Data: message.Fields{"op": "start", "count": 1}, Priority: level.Info},
+			{Data: "hello\nworld", Priority: level.Warning},
+			{Data: []message.Fields{{"op": "one"}, {"op": "two"}}, Priority: level.Error},
+		} {
+			require.NoError(t, logger.Send(lp))
+		}
+		require.NoError(t, logger.Close())
+
+		data, err := ioutil.ReadFile(file.Name())
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 4)
+
+		docs := make([]map[string]interface{}, len(lines))
+		for idx, line := range lines {
+			require.True(t, json.Valid([]byte(line)), line)
+			require.NoError(t, json.Unmarshal([]byte(line), &docs[idx]))
+			assert.NotEmpty(t, docs[idx]["time"])
+		}
+
+		assert.Equal(t, "start", docs[0]["op"])
+		assert.EqualValues(t, 1, docs[0]["count"])
+		assert.Equal(t, level.Info.String(), docs[0]["level"])
+		assert.Equal(t, "hello\nworld", docs[1]["message"])
+		assert.Equal(t, level.Warning.String(), docs[1]["level"])
+		assert.Equal(t, "one", docs[2]["op"])
+		assert.Equal(t, "two", docs[3]["op"])
+		assert.Equal(t, level.Error.String(), docs[3]["level"])
+	})
+}
+
+func TestJSONLinesFormatter(t *testing.T) {
+	format := makeJSONLinesFormatter()
+	parse := func(t *testing.T, m message.Composer) map[string]interface{} {
+		line, err := format(m)
+		require.NoError(t, err)
+		doc := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &doc))
+		return doc
+	}
+
+	t.Run("KeepsLevelAndTimeFields", func(t *testing.T) {
+		doc := parse(t, message.NewSimpleFields(level.Info, message.Fields{"level": "custom", "time": "yesterday"}))
+		assert.Equal(t, "custom", doc["level"])
+		assert.Equal(t, "yesterday", doc["time"])
+	})
+	t.Run("UsesMessageCreationTime", func(t *testing.T) {
+		m := message.NewFields(level.Info, message.Fields{"op": "start"})
+		created := m.Raw().(message.Fields)["metadata"].(*message.Base).Time
+		require.False(t, created.IsZero())
+		time.Sleep(10 * time.Millisecond)
+
+		doc := parse(t, m)
+		assert.Equal(t, created.Format(time.RFC3339Nano), doc["time"])
+		assert.Equal(t, level.Info.String(), doc["level"])
+	})
+	t.Run("UsesCurrentTimeWithoutCreationTime", func(t *testing.T) {
+		before := time.Now()
+		doc := parse(t, message.NewSimpleFields(level.Info, message.Fields{"op": "start"}))
+		formatted, err := time.Parse(time.RFC3339Nano, doc["time"].(string))
+		require.NoError(t, err)
+		assert.False(t, formatted.Before(before.Truncate(time.Second)))
+	})
+}
+
+func TestFileLoggerRotation(t *testing.T) {
+	t.Run("ValidateRejectsInvalidRotation", func(t *testing.T) {
+		opts := &FileLoggerOptions{Filename: "foo", MaxBytes: -1}
+		assert.Error(t, opts.Validate())
+		opts = &FileLoggerOptions{Filename: "foo", Compress: true}
+		assert.Error(t, opts.Validate())
+	})
+
+	// Each message is 10 bytes, so a maximum size of 25 bytes rotates the
+	// file after every two messages.
+	sendMessages := func(t *testing.T, opts *FileLoggerOptions) {
+		config := &LoggerConfig{}
+		require.NoError(t, config.Set(opts))
+		sender, err := config.Resolve()
+		require.NoError(t, err)
+
+		logger := &CachedLogger{Output: sender}
+		for i := 0; i < 5; i++ {
+			require.NoError(t, logger.Send(&LoggingPayload{Data: fmt.Sprintf("message %d", i), Priority: level.Info}))
+		}
+		require.NoError(t, logger.Close())
+	}
+	readLogFile := func(t *testing.T, path string) string {
+		r, err := OpenLogFile(path)
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	for testName, testCase := range map[string]func(t *testing.T, path string){
+		"CompressesBackup": func(t *testing.T, path string) {
+			sendMessages(t, &FileLoggerOptions{
+				Filename: path,
+				MaxBytes: 25,
+				Compress: true,
+				Base:     BaseOptions{Format: LogFormatPlain},
+			})
+
+			_, err := os.Stat(path + ".1")
+			assert.True(t, os.IsNotExist(err))
+
+			file, err := os.Open(path + ".1.gz")
+			require.NoError(t, err)
+			defer file.Close()
+			gz, err := gzip.NewReader(file)
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(gz)
+			require.NoError(t, err)
+			require.NoError(t, gz.Close())
+			assert.Equal(t, "message 2\nmessage 3\n", string(data))
+
+			assert.Equal(t, "message 2\nmessage 3\n", readLogFile(t, path+".1.gz"))
+			assert.Equal(t, "message 4\n", readLogFile(t, path))
+		},
+		"KeepsUncompressedBackupWithoutCompression": func(t *testing.T, path string) {
+			sendMessages(t, &FileLoggerOptions{
+				Filename: path,
+				MaxBytes: 25,
+				Base:     BaseOptions{Format: LogFormatPlain},
+			})
+
+			data, err := ioutil.ReadFile(path + ".1")
+			require.NoError(t, err)
+			assert.Equal(t, "message 2\nmessage 3\n", string(data))
+			assert.Equal(t, "message 2\nmessage 3\n", readLogFile(t, path+".1"))
+
+			_, err = os.Stat(path + ".1.gz")
+			assert.True(t, os.IsNotExist(err))
+		},
+		"DoesNotRotateWithoutMaximumSize": func(t *testing.T, path string) {
+			sendMessages(t, &FileLoggerOptions{
+				Filename: path,
+				Base:     BaseOptions{Format: LogFormatPlain},
+			})
+
+			assert.Equal(t, "message 0\nmessage 1\nmessage 2\nmessage 3\nmessage 4\n", readLogFile(t, path))
+			_, err := os.Stat(path + ".1")
+			assert.True(t, os.IsNotExist(err))
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "file-logger-rotation")
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, os.RemoveAll(dir))
+			}()
+
+			testCase(t, filepath.Join(dir, "log"))
+		})
+	}
+	t.Run("OpenLogFileFailsForMissingFile", func(t *testing.T) {
+		_, err := OpenLogFile(filepath.Join(os.TempDir(), "nonexistent-log-file"))
+		assert.Error(t, err)
+	})
+}
@@ -2,13 +2,16 @@ package options
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/json"
-	"io"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/tychoish/birch"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
 	"github.com/tychoish/grip/message"
@@ -23,12 +26,212 @@ type CachedLogger struct {
 	Manager  string    `bson:"manager_id" json:"manager_id" yaml:"manager_id"`
 	Accessed time.Time `bson:"accessed" json:"accessed" yaml:"accessed"`
 
+	// DropWhenUnconfigured causes Send to silently drop messages
+	// rather than returning an error when neither Output nor Error
+	// is set. Use DroppedMessages to get the number of messages
+	// that have been dropped.
+	DropWhenUnconfigured bool `bson:"drop_when_unconfigured,omitempty" json:"drop_when_unconfigured,omitempty" yaml:"drop_when_unconfigured,omitempty"`
+
+	// Error and Output are the senders for error and output messages.
+	// To add senders to a logger that is already in use, use
+	// AddErrorSender and AddOutputSender rather than modifying these
+	// directly.
 	Error  send.Sender `bson:"-" json:"-" yaml:"-"`
 	Output send.Sender `bson:"-" json:"-" yaml:"-"`
+	// Streams are senders for messages sent to named streams other than
+	// LoggingStreamOutput and LoggingStreamError, such as "audit".
+	// Messages sent to streams without a sender are sent to Output.
+	Streams map[string]send.Sender `bson:"-" json:"-" yaml:"-"`
+	// Fallback, if set, receives the messages that the logger's
+	// senders fail to send (e.g. because a network sink is down).
+	// Since senders report failures to their error handler rather
+	// than returning them, the logger wraps the error handler of
+	// each of its senders. The handlers are installed by SetFallback,
+	// or when the logger is added to a LoggingCache, so set Fallback
+	// with SetFallback on a logger that is already cached. Use
+	// Failovers to get the number of messages that have been sent to
+	// the fallback.
+	Fallback send.Sender `bson:"-" json:"-" yaml:"-"`
+
+	dropped       int64
+	failovers     int64
+	errorSenders  senderSet
+	outputSenders senderSet
+	// publisher is a pointer so that copies of the logger share its
+	// subscribers.
+	publisher *messagePublisher
+	// fallbackSenders are the senders that have the fallback error
+	// handler installed.
+	fallbackSenders []send.Sender
+	// mu guards the senders. It only protects accessing and replacing the
+	// senders, not sending messages, so it is not held for long.
+	mu sync.RWMutex
+}
+
+// senderSet tracks the senders that a multi-sender created by a
+// CachedLogger sends to.
+type senderSet struct {
+	multi   send.Sender
+	senders []send.Sender
+}
+
+// add returns a sender that sends to the current sender as well as the new
+// sender.
+func (ss *senderSet) add(current, sender send.Sender) send.Sender {
+	if current == nil {
+		ss.multi = nil
+		ss.senders = nil
+		return sender
+	}
+
+	senders := ss.members(current)
+	senders = append(senders[:len(senders):len(senders)], sender)
+	ss.senders = senders
+	ss.multi = send.NewConfiguredMultiSender(senders...)
+
+	return ss.multi
+}
+
+// members returns the senders that the current sender sends to.
+func (ss *senderSet) members(current send.Sender) []send.Sender {
+	if current == nil {
+		return nil
+	}
+	if ss.multi != nil && current == ss.multi {
+		return ss.senders
+	}
+	return []send.Sender{current}
+}
+
+// AddOutputSender adds a sender that receives the logger's output messages
+// in addition to its existing output sender. It is safe to call while the
+// logger is in use. Close closes the added sender.
+func (cl *CachedLogger) AddOutputSender(sender send.Sender) error {
+	if sender == nil {
+		return errors.New("cannot add nil output sender")
+	}
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.Output = cl.outputSenders.add(cl.Output, sender)
+	if cl.Fallback != nil {
+		cl.installFallbackHandlers()
+	}
+
+	return nil
+}
+
+// AddErrorSender adds a sender that receives the logger's error messages in
+// addition to its existing error sender. It is safe to call while the logger
+// is in use. Close closes the added sender.
+func (cl *CachedLogger) AddErrorSender(sender send.Sender) error {
+	if sender == nil {
+		return errors.New("cannot add nil error sender")
+	}
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.Error = cl.errorSenders.add(cl.Error, sender)
+	if cl.Fallback != nil {
+		cl.installFallbackHandlers()
+	}
+
+	return nil
+}
+
+// DroppedMessages returns the number of payloads that Send has dropped
+// because no sender was configured.
+func (cl *CachedLogger) DroppedMessages() int64 {
+	return atomic.LoadInt64(&cl.dropped)
+}
+
+// Failovers returns the number of messages that were sent to the Fallback
+// sender because the primary sender failed to send them.
+func (cl *CachedLogger) Failovers() int64 {
+	return atomic.LoadInt64(&cl.failovers)
+}
+
+// SetFallback sets the Fallback sender and installs the error handlers that
+// send it the messages that the logger's senders fail to send. Senders that
+// are added later with AddErrorSender or AddOutputSender get the handler
+// when they are added. It is safe to call while the logger is in use.
+func (cl *CachedLogger) SetFallback(fallback send.Sender) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.Fallback = fallback
+	if fallback != nil {
+		cl.installFallbackHandlers()
+	}
+}
+
+// fallbackError wraps the error passed to the error handler that a sender had
+// before the fallback handler was installed, so that the fallback handler
+// does not handle the same error twice if the previous handler calls it.
+type fallbackError struct {
+	error
+}
+
+func (e *fallbackError) Cause() error { return e.error }
+
+// installFallbackHandlers sets the error handler of each of the logger's
+// senders that does not have one yet, including the senders that a sender
+// created by AddErrorSender or AddOutputSender sends to. The handler sends
+// the messages that fail to the Fallback sender and then calls the sender's
+// previous error handler. The caller must hold the logger's senders lock.
+func (cl *CachedLogger) installFallbackHandlers() {
+	senders := []send.Sender{cl.Output, cl.Error}
+	senders = append(senders, cl.outputSenders.members(cl.Output)...)
+	senders = append(senders, cl.errorSenders.members(cl.Error)...)
+	for _, sender := range cl.Streams {
+		senders = append(senders, sender)
+	}
+
+	for _, sender := range senders {
+		if sender == nil || containsSender(cl.fallbackSenders, sender) {
+			continue
+		}
+		cl.fallbackSenders = append(cl.fallbackSenders, sender)
+
+		previous := sender.ErrorHandler()
+		// This only errors if the handler is nil.
+		_ = sender.SetErrorHandler(func(err error, m message.Composer) {
+			if err == nil {
+				return
+			}
+			if _, ok := err.(*fallbackError); ok {
+				return
+			}
+
+			cl.mu.RLock()
+			fallback := cl.Fallback
+			cl.mu.RUnlock()
+			if fallback != nil {
+				atomic.AddInt64(&cl.failovers, 1)
+				fallback.Send(m)
+			}
+
+			if previous != nil {
+				previous(&fallbackError{error: err}, m)
+			}
+		})
+	}
 }
 
-func (cl *CachedLogger) getSender(preferError bool) (send.Sender, error) {
-	if preferError && cl.Error != nil {
+// getSender returns the sender for the named stream. Messages for the error
+// stream are sent to Output if Error is not set, and messages for any other
+// stream without a sender are sent to Output, or to Error if Output is not
+// set.
+func (cl *CachedLogger) getSender(stream string) (send.Sender, error) {
+	if stream != LoggingStreamOutput && stream != LoggingStreamError {
+		if sender := cl.Streams[stream]; sender != nil {
+			return sender, nil
+		}
+	}
+
+	if stream == LoggingStreamError && cl.Error != nil {
 		return cl.Error, nil
 	} else if cl.Output != nil {
 		return cl.Output, nil
@@ -39,17 +242,56 @@ func (cl *CachedLogger) getSender(preferError bool) (send.Sender, error) {
 	return nil, errors.New("no output configured")
 }
 
+// Flush flushes any messages buffered by the underlying output for the
+// cached logger.
+func (cl *CachedLogger) Flush(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	for _, sender := range cl.senders() {
+		catcher.Add(sender.Flush(ctx))
+	}
+	return catcher.Resolve()
+}
+
 // Close closes the underlying output for the cached logger.
 func (cl *CachedLogger) Close() error {
 	catcher := grip.NewBasicCatcher()
-	if cl.Output != nil {
-		catcher.Check(cl.Output.Close)
+	for _, sender := range cl.senders() {
+		catcher.Check(sender.Close)
 	}
+	return catcher.Resolve()
+}
 
-	if cl.Error != nil && cl.Output != cl.Error {
-		catcher.Check(cl.Error.Close)
+// senders returns the unique senders for output, error and the named
+// streams. Senders may be shared between streams, so each one is only
+// returned once.
+func (cl *CachedLogger) senders() []send.Sender {
+	cl.mu.RLock()
+	var all []send.Sender
+	all = append(all, cl.outputSenders.members(cl.Output)...)
+	all = append(all, cl.errorSenders.members(cl.Error)...)
+	for _, sender := range cl.Streams {
+		if sender != nil {
+			all = append(all, sender)
+		}
 	}
-	return catcher.Resolve()
+	cl.mu.RUnlock()
+
+	var senders []send.Sender
+	for _, sender := range all {
+		if !containsSender(senders, sender) {
+			senders = append(senders, sender)
+		}
+	}
+	return senders
+}
+
+func containsSender(senders []send.Sender, sender send.Sender) bool {
+	for _, s := range senders {
+		if s == sender {
+			return true
+		}
+	}
+	return false
 }
 
 // LoggingPayload captures the arguments to the SendMessages operation.
@@ -61,6 +303,79 @@ type LoggingPayload struct {
 	PreferSendToError bool                 `bson:"prefer_send_to_error,omitempty" json:"prefer_send_to_error,omitempty" yaml:"prefer_send_to_error,omitempty"`
 	AddMetadata       bool                 `bson:"add_metadata,omitempty" json:"add_metadata,omitempty" yaml:"add_metadata,omitempty"`
 	Format            LoggingPayloadFormat `bson:"payload_format,omitempty" json:"payload_format,omitempty" yaml:"payload_format,omitempty"`
+	// Stream is the name of the stream that the payload is sent to,
+	// which takes precedence over PreferSendToError. By default, it is
+	// LoggingStreamError if PreferSendToError is set and
+	// LoggingStreamOutput otherwise.
+	Stream string `bson:"stream,omitempty" json:"stream,omitempty" yaml:"stream,omitempty"`
+	// Delimiter separates messages in string and byte slice
+	// payloads when IsMulti is set. By default, strings are split
+	// on newlines and byte slices are split on null bytes. The
+	// delimiter does not affect BSON payloads, which are framed by
+	// document length.
+	Delimiter string `bson:"delimiter,omitempty" json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+	// Framing specifies how byte slice payloads are split into
+	// messages when IsMulti is set. By default, BSON payloads use
+	// BSON framing and other payloads are split by the delimiter.
+	Framing LoggingPayloadFraming `bson:"framing,omitempty" json:"framing,omitempty" yaml:"framing,omitempty"`
+	// MaxPayloadBytes, if positive, is the maximum total size of the
+	// string and byte slice data in the payload. For multi-message
+	// payloads, the limit applies to the aggregate size of all
+	// messages. Payloads that exceed the limit are rejected with a
+	// *PayloadTooLargeError.
+	MaxPayloadBytes int `bson:"max_payload_bytes,omitempty" json:"max_payload_bytes,omitempty" yaml:"max_payload_bytes,omitempty"`
+	// Deduplicate collapses consecutive identical messages in string
+	// and byte slice payloads into a single message when IsMulti is
+	// set. Collapsed messages are annotated with the number of times
+	// the message was repeated under LoggingPayloadRepeatCountKey.
+	// Messages are never reordered, so only adjacent duplicates are
+	// collapsed.
+	Deduplicate bool `bson:"deduplicate,omitempty" json:"deduplicate,omitempty" yaml:"deduplicate,omitempty"`
+	// Schema, if set, is used to validate each message in JSON
+	// payloads before they are converted for the sender. Messages that
+	// do not conform to the schema are rejected with a
+	// *SchemaValidationError, regardless of the sender's preferred
+	// payload format.
+	Schema *JSONSchema `bson:"-" json:"-" yaml:"-"`
+	// Process, if set, describes the process that produced the
+	// payload. When AddMetadata is set, it is added to each message
+	// in JSON and BSON payloads as a nested document under
+	// LoggingPayloadProcessKey, unless the message already has a
+	// field with that key.
+	Process *LoggingPayloadProcess `bson:"process,omitempty" json:"process,omitempty" yaml:"process,omitempty"`
+}
+
+// LoggingPayloadProcess describes the process that produced a logging
+// payload.
+type LoggingPayloadProcess struct {
+	ID   string   `bson:"process_id" json:"process_id" yaml:"process_id"`
+	Tags []string `bson:"tags" json:"tags" yaml:"tags"`
+}
+
+// LoggingPayloadRepeatCountKey is the annotation key for the number of
+// times a message was repeated in a deduplicated logging payload.
+const LoggingPayloadRepeatCountKey = "repeat_count"
+
+// The names of the streams that cached loggers send to with their Output and
+// Error senders.
+const (
+	LoggingStreamOutput = "output"
+	LoggingStreamError  = "error"
+)
+
+// LoggingPayloadProcessKey is the key of the field that describes the
+// process that produced a structured message.
+const LoggingPayloadProcessKey = "process"
+
+// PayloadTooLargeError is returned when the data in a logging payload
+// exceeds its MaxPayloadBytes.
+type PayloadTooLargeError struct {
+	Size int
+	Max  int
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("payload of %d bytes exceeds the maximum size of %d bytes", e.Size, e.Max)
 }
 
 // LoggingPayloadFormat is an set enumerated values describing the
@@ -71,45 +386,198 @@ const (
 	LoggingPayloadFormatBSON   = "bson"
 	LoggingPayloadFormatJSON   = "json"
 	LoggingPayloadFormatSTRING = "string"
+	// LoggingPayloadFormatLogfmt parses each message as a line of
+	// logfmt (e.g. `key=value key2="v 2"`) into structured fields.
+	// Parts of the line that are not valid key=value pairs are
+	// preserved under LogfmtRawKey.
+	LoggingPayloadFormatLogfmt = "logfmt"
 )
 
+// LoggingPayloadFraming describes how the messages in a byte slice payload are
+// separated.
+type LoggingPayloadFraming string
+
+const (
+	// LoggingPayloadFramingNUL separates messages by the payload's
+	// delimiter, which is a null byte by default.
+	LoggingPayloadFramingNUL LoggingPayloadFraming = "nul"
+	// LoggingPayloadFramingBSON reads messages as consecutive BSON
+	// documents.
+	LoggingPayloadFramingBSON LoggingPayloadFraming = "bson"
+	// LoggingPayloadFramingLengthPrefixed reads each message as a
+	// 4-byte, big-endian, unsigned length followed by that many bytes.
+	LoggingPayloadFramingLengthPrefixed LoggingPayloadFraming = "length-prefixed"
+)
+
+// LoggingPayloadPriority wraps a level.Priority so that it is represented
+// by its name (e.g. "info") in JSON. Numeric priorities are also accepted
+// when unmarshalling for compatibility.
+type LoggingPayloadPriority level.Priority
+
+// MarshalJSON encodes the priority as its name. Priorities that do not
+// have a name are encoded as numbers so they round trip.
+func (p LoggingPayloadPriority) MarshalJSON() ([]byte, error) {
+	pri := level.Priority(p)
+	if name := pri.String(); pri == level.Invalid || level.FromString(name) == pri {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int16(pri))
+}
+
+// UnmarshalJSON decodes the priority from either its name or its numeric
+// value.
+func (p *LoggingPayloadPriority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		pri := level.FromString(name)
+		if pri == level.Invalid && strings.TrimSpace(strings.ToLower(name)) != level.Invalid.String() {
+			return errors.Errorf("unrecognized priority '%s'", name)
+		}
+		*p = LoggingPayloadPriority(pri)
+		return nil
+	}
+
+	var num int16
+	if err := json.Unmarshal(data, &num); err != nil {
+		return errors.Wrap(err, "priority must be a name or a number")
+	}
+	*p = LoggingPayloadPriority(num)
+	return nil
+}
+
+// MarshalJSON encodes the payload, representing the priority by its name.
+func (lp LoggingPayload) MarshalJSON() ([]byte, error) {
+	type payload LoggingPayload
+	return json.Marshal(struct {
+		payload
+		Priority LoggingPayloadPriority `json:"priority"`
+	}{
+		payload:  payload(lp),
+		Priority: LoggingPayloadPriority(lp.Priority),
+	})
+}
+
+// UnmarshalJSON decodes the payload, accepting the priority as either its
+// name or its numeric value.
+func (lp *LoggingPayload) UnmarshalJSON(data []byte) error {
+	type payload LoggingPayload
+	out := struct {
+		*payload
+		Priority LoggingPayloadPriority `json:"priority"`
+	}{
+		payload: (*payload)(lp),
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	lp.Priority = level.Priority(out.Priority)
+	return nil
+}
+
 // Validate checks that the required fields are populated for the payload and
 // the format is valid.
 func (lp *LoggingPayload) Validate() error {
 	catcher := grip.NewBasicCatcher()
 	catcher.NewWhen(lp.Data == nil, "data cannot be empty")
 	switch lp.Format {
-	case "", LoggingPayloadFormatBSON, LoggingPayloadFormatJSON, LoggingPayloadFormatSTRING:
+	case "", LoggingPayloadFormatBSON, LoggingPayloadFormatJSON, LoggingPayloadFormatSTRING, LoggingPayloadFormatLogfmt:
 	default:
 		catcher.Errorf("invalid payload format '%s'", lp.Format)
 	}
-	return catcher.Resolve()
+	switch lp.Framing {
+	case "", LoggingPayloadFramingNUL, LoggingPayloadFramingBSON, LoggingPayloadFramingLengthPrefixed:
+	default:
+		catcher.Errorf("invalid payload framing '%s'", lp.Framing)
+	}
+	catcher.NewWhen(lp.MaxPayloadBytes < 0, "maximum payload size cannot be negative")
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if lp.MaxPayloadBytes > 0 {
+		if size := payloadSize(lp.Data); size > lp.MaxPayloadBytes {
+			return &PayloadTooLargeError{Size: size, Max: lp.MaxPayloadBytes}
+		}
+	}
+
+	return nil
+}
+
+// payloadSize returns the total size of the string and byte slice data in
+// the payload.
+func payloadSize(value interface{}) int {
+	switch data := value.(type) {
+	case string:
+		return len(data)
+	case []byte:
+		return len(data)
+	case []string:
+		var size int
+		for _, str := range data {
+			size += len(str)
+		}
+		return size
+	case [][]byte:
+		var size int
+		for _, dt := range data {
+			size += len(dt)
+		}
+		return size
+	case []interface{}:
+		var size int
+		for _, dt := range data {
+			size += payloadSize(dt)
+		}
+		return size
+	default:
+		return 0
+	}
 }
 
 // Send resolves a sender from the cached logger (either the error or
 // output endpoint), and then sends the message from the data
 // payload. This method ultimately is responsible for converting the
-// payload to a message format.
+// payload to a message format, including converting it to the format
+// preferred by the sender if it is a PayloadFormatSender.
 func (cl *CachedLogger) Send(lp *LoggingPayload) error {
 	if err := lp.Validate(); err != nil {
 		return errors.Wrap(err, "invalid logging payload")
 	}
 
-	sender, err := cl.getSender(lp.PreferSendToError)
+	cl.mu.RLock()
+	sender, err := cl.getSender(lp.stream())
+	publisher := cl.publisher
+	cl.mu.RUnlock()
 	if err != nil {
+		if cl.DropWhenUnconfigured {
+			atomic.AddInt64(&cl.dropped, 1)
+			return nil
+		}
 		return errors.WithStack(err)
 	}
 
-	msg, err := lp.convert()
+	msg, err := lp.convertFor(sender)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
 	sender.Send(msg)
+	publisher.publish(msg)
 
 	return nil
 }
 
+// stream returns the name of the stream that the payload is sent to.
+func (lp *LoggingPayload) stream() string {
+	if lp.Stream != "" {
+		return lp.Stream
+	}
+	if lp.PreferSendToError {
+		return LoggingStreamError
+	}
+	return LoggingStreamOutput
+}
+
 func (lp *LoggingPayload) convert() (message.Composer, error) {
 	if lp.IsMulti {
 		return lp.convertMultiMessage(lp.Data)
@@ -120,7 +588,11 @@ func (lp *LoggingPayload) convert() (message.Composer, error) {
 func (lp *LoggingPayload) convertMultiMessage(value interface{}) (message.Composer, error) {
 	switch data := value.(type) {
 	case string:
-		return lp.convertMultiMessage(strings.Split(data, "\n"))
+		delim := lp.Delimiter
+		if delim == "" {
+			delim = "\n"
+		}
+		return lp.convertMultiMessage(strings.Split(data, delim))
 	case []byte:
 		payload, err := lp.splitByteSlice(data)
 		if err != nil {
@@ -128,25 +600,13 @@ func (lp *LoggingPayload) convertMultiMessage(value interface{}) (message.Compos
 		}
 		return lp.convertMultiMessage(payload)
 	case []string:
-		batch := []message.Composer{}
-		for _, str := range data {
-			elem, err := lp.produceMessage([]byte(str))
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			batch = append(batch, elem)
+		payload := make([][]byte, len(data))
+		for idx, str := range data {
+			payload[idx] = []byte(str)
 		}
-		return message.NewGroupComposer(batch), nil
+		return lp.produceMessages(payload)
 	case [][]byte:
-		batch := []message.Composer{}
-		for _, dt := range data {
-			elem, err := lp.produceMessage(dt)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			batch = append(batch, elem)
-		}
-		return message.NewGroupComposer(batch), nil
+		return lp.produceMessages(data)
 	case []interface{}:
 		batch := []message.Composer{}
 		for _, dt := range data {
@@ -195,6 +655,35 @@ func (lp *LoggingPayload) convertMessage(value interface{}) (message.Composer, e
 	}
 }
 
+// produceMessages produces a group of messages from the data. If
+// Deduplicate is set, consecutive identical messages are collapsed into one
+// message annotated with its repeat count.
+func (lp *LoggingPayload) produceMessages(data [][]byte) (message.Composer, error) {
+	batch := []message.Composer{}
+	for idx := 0; idx < len(data); {
+		count := 1
+		if lp.Deduplicate {
+			for idx+count < len(data) && bytes.Equal(data[idx], data[idx+count]) {
+				count++
+			}
+		}
+
+		elem, err := lp.produceMessage(data[idx])
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if count > 1 {
+			if err = elem.Annotate(LoggingPayloadRepeatCountKey, count); err != nil {
+				return nil, errors.Wrap(err, "problem annotating repeated message")
+			}
+		}
+
+		batch = append(batch, elem)
+		idx += count
+	}
+	return message.NewGroupComposer(batch), nil
+}
+
 func (lp *LoggingPayload) produceMessage(data []byte) (message.Composer, error) {
 	switch lp.Format {
 	case LoggingPayloadFormatJSON:
@@ -204,6 +693,7 @@ func (lp *LoggingPayload) produceMessage(data []byte) (message.Composer, error)
 		}
 
 		if lp.AddMetadata {
+			lp.addProcessMetadata(payload)
 			return message.NewFields(lp.Priority, payload), nil
 		}
 
@@ -218,6 +708,14 @@ func (lp *LoggingPayload) produceMessage(data []byte) (message.Composer, error)
 		if err := unmarshler(data, &payload); err != nil {
 			return nil, errors.Wrap(err, "problem parsing bson from message body")
 		}
+		if lp.AddMetadata {
+			lp.addProcessMetadata(payload)
+			return message.NewFields(lp.Priority, payload), nil
+		}
+
+		return message.NewSimpleFields(lp.Priority, payload), nil
+	case LoggingPayloadFormatLogfmt:
+		payload := parseLogfmt(data)
 		if lp.AddMetadata {
 			return message.NewFields(lp.Priority, payload), nil
 		}
@@ -232,28 +730,74 @@ func (lp *LoggingPayload) produceMessage(data []byte) (message.Composer, error)
 	}
 }
 
-func (lp *LoggingPayload) splitByteSlice(data []byte) (interface{}, error) {
-	if lp.Format != LoggingPayloadFormatBSON {
-		return bytes.Split(data, []byte("\x00")), nil
+// addProcessMetadata adds the description of the payload's process, if any,
+// to the structured message.
+func (lp *LoggingPayload) addProcessMetadata(payload message.Fields) {
+	if lp.Process == nil {
+		return
+	}
+	if _, ok := payload[LoggingPayloadProcessKey]; ok {
+		return
 	}
 
-	out := [][]byte{}
-	buf := bytes.NewBuffer(data)
-	for {
-		doc := birch.DC.New()
-		_, err := doc.ReadFrom(buf)
-		if err == io.EOF {
-			break
+	tags := lp.Process.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	payload[LoggingPayloadProcessKey] = message.Fields{
+		"process_id": lp.Process.ID,
+		"tags":       tags,
+	}
+}
+
+func (lp *LoggingPayload) splitByteSlice(data []byte) (interface{}, error) {
+	framing := lp.Framing
+	if framing == "" {
+		framing = LoggingPayloadFramingNUL
+		if lp.Format == LoggingPayloadFormatBSON {
+			framing = LoggingPayloadFramingBSON
 		}
+	}
+
+	switch framing {
+	case LoggingPayloadFramingBSON:
+		out, err := ReadBSONDocuments(bytes.NewBuffer(data))
 		if err != nil {
-			return nil, errors.Wrap(err, "problem reading bson from message data")
+			return nil, errors.WithStack(err)
 		}
+		return out, nil
+	case LoggingPayloadFramingLengthPrefixed:
+		return splitLengthPrefixed(data)
+	default:
+		delim := []byte(lp.Delimiter)
+		if len(delim) == 0 {
+			delim = []byte("\x00")
+		}
+		return bytes.Split(data, delim), nil
+	}
+}
 
-		payload, err := doc.MarshalBSON()
-		if err != nil {
-			return nil, errors.Wrap(err, "problem constructing bson form")
+// lengthPrefixSize is the size of the length that precedes each message in a
+// length-prefixed payload.
+const lengthPrefixSize = 4
+
+// splitLengthPrefixed splits the data into the messages of a length-prefixed
+// payload, returning an error with the offset of the first frame that is
+// incomplete.
+func splitLengthPrefixed(data []byte) ([][]byte, error) {
+	out := [][]byte{}
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < lengthPrefixSize {
+			return nil, errors.Errorf("truncated length prefix at offset %d: need %d bytes, have %d", offset, lengthPrefixSize, len(data)-offset)
+		}
+		size := uint64(binary.BigEndian.Uint32(data[offset:]))
+		start := offset + lengthPrefixSize
+		if remaining := uint64(len(data) - start); size > remaining {
+			return nil, errors.Errorf("truncated frame at offset %d: length prefix is %d bytes, but only %d remain", offset, size, remaining)
 		}
-		out = append(out, payload)
+		end := start + int(size)
+		out = append(out, data[start:end])
+		offset = end
 	}
 
 	return out, nil
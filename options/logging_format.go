@@ -0,0 +1,271 @@
+package options
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// PayloadFormatSender is implemented by senders that prefer to receive
+// messages in a particular format. When a CachedLogger sends a payload to a
+// sender that implements this interface, the payload is converted to the
+// preferred format, if possible:
+//
+// Senders that prefer LoggingPayloadFormatSTRING only handle unstructured
+// text, so JSON payloads are sent as the JSON text itself, and BSON
+// payloads and structured data are converted to JSON text.
+//
+// Senders that prefer LoggingPayloadFormatJSON or LoggingPayloadFormatBSON
+// handle structured messages, so string payloads that consist entirely of
+// JSON documents are sent as structured messages.
+//
+// If a payload cannot be converted, sending it returns a
+// *PayloadFormatError.
+type PayloadFormatSender interface {
+	send.Sender
+	PreferredPayloadFormat() LoggingPayloadFormat
+}
+
+type payloadFormatSender struct {
+	send.Sender
+	format LoggingPayloadFormat
+}
+
+// NewPayloadFormatSender wraps the sender so that it prefers to receive
+// messages in the given payload format. See PayloadFormatSender for how
+// payloads are converted.
+func NewPayloadFormatSender(sender send.Sender, format LoggingPayloadFormat) send.Sender {
+	return &payloadFormatSender{Sender: sender, format: format}
+}
+
+func (s *payloadFormatSender) PreferredPayloadFormat() LoggingPayloadFormat { return s.format }
+
+// PayloadFormatError is returned when a logging payload cannot be converted
+// to the format preferred by the sender it is sent to.
+type PayloadFormatError struct {
+	Format LoggingPayloadFormat
+	Err    error
+}
+
+func (e *PayloadFormatError) Error() string {
+	return fmt.Sprintf("cannot convert payload to %s format: %s", e.Format, e.Err)
+}
+
+// Unwrap returns the reason that the payload could not be converted.
+func (e *PayloadFormatError) Unwrap() error { return e.Err }
+
+// convertFor converts the payload to a message in the format preferred by
+// the sender.
+func (lp *LoggingPayload) convertFor(sender send.Sender) (message.Composer, error) {
+	// The schema applies to the payload as it was produced, so it is
+	// checked before the payload is converted for any sender.
+	if err := lp.validateSchema(); err != nil {
+		return nil, err
+	}
+
+	formatSender, ok := sender.(PayloadFormatSender)
+	if !ok {
+		return lp.convert()
+	}
+
+	switch format := formatSender.PreferredPayloadFormat(); format {
+	case LoggingPayloadFormatSTRING:
+		converted, err := lp.toUnstructured()
+		if err != nil {
+			return nil, &PayloadFormatError{Format: format, Err: err}
+		}
+		return converted.convert()
+	case LoggingPayloadFormatJSON, LoggingPayloadFormatBSON:
+		if msg, ok := lp.toStructured(); ok {
+			return msg, nil
+		}
+	}
+
+	return lp.convert()
+}
+
+// validateSchema checks each message in a JSON payload against the
+// payload's schema, if any, returning a *SchemaValidationError for the first
+// message that does not conform.
+func (lp *LoggingPayload) validateSchema() error {
+	if lp.Schema == nil || lp.Format != LoggingPayloadFormatJSON {
+		return nil
+	}
+
+	switch lp.Data.(type) {
+	case string, []byte, []string, [][]byte:
+	default:
+		return nil
+	}
+
+	docs, err := lp.documents()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, doc := range docs {
+		payload := map[string]interface{}{}
+		if err := json.Unmarshal(doc, &payload); err != nil {
+			return errors.Wrap(err, "problem parsing json from message body")
+		}
+		if err := lp.Schema.Validate(payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// documents returns the messages in the payload's string or byte slice
+// data, splitting multi-message payloads in the same way as when they are
+// sent.
+func (lp *LoggingPayload) documents() ([][]byte, error) {
+	switch data := lp.Data.(type) {
+	case []byte:
+		if !lp.IsMulti {
+			return [][]byte{data}, nil
+		}
+		split, err := lp.splitByteSlice(data)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return split.([][]byte), nil
+	case string:
+		if !lp.IsMulti {
+			return [][]byte{[]byte(data)}, nil
+		}
+		delim := lp.Delimiter
+		if delim == "" {
+			delim = "\n"
+		}
+		parts := strings.Split(data, delim)
+		docs := make([][]byte, len(parts))
+		for idx := range parts {
+			docs[idx] = []byte(parts[idx])
+		}
+		return docs, nil
+	case [][]byte:
+		return data, nil
+	case []string:
+		docs := make([][]byte, len(data))
+		for idx := range data {
+			docs[idx] = []byte(data[idx])
+		}
+		return docs, nil
+	default:
+		return nil, errors.Errorf("cannot read documents from data of type %T", lp.Data)
+	}
+}
+
+// toUnstructured returns a copy of the payload in which structured data and
+// documents are represented as JSON text.
+func (lp *LoggingPayload) toUnstructured() (*LoggingPayload, error) {
+	out := *lp
+
+	switch data := lp.Data.(type) {
+	case message.Fields:
+		doc, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem encoding fields as json")
+		}
+		out.Data = doc
+		out.IsMulti = false
+		out.Format = LoggingPayloadFormatSTRING
+		return &out, nil
+	case []message.Fields:
+		docs := make([][]byte, 0, len(data))
+		for _, fields := range data {
+			doc, err := json.Marshal(fields)
+			if err != nil {
+				return nil, errors.Wrap(err, "problem encoding fields as json")
+			}
+			docs = append(docs, doc)
+		}
+		out.Data = docs
+		out.IsMulti = true
+		out.Format = LoggingPayloadFormatSTRING
+		return &out, nil
+	}
+
+	switch lp.Format {
+	case LoggingPayloadFormatJSON, LoggingPayloadFormatLogfmt:
+		// JSON documents and logfmt lines are already text, so they
+		// can be sent as-is.
+		out.Format = LoggingPayloadFormatSTRING
+	case LoggingPayloadFormatBSON:
+		docs, err := lp.bsonDocuments()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for idx := range docs {
+			if docs[idx], err = bsonToJSON(docs[idx]); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		if lp.IsMulti {
+			out.Data = docs
+		} else {
+			out.Data = bytes.Join(docs, nil)
+		}
+		out.Format = LoggingPayloadFormatSTRING
+	}
+
+	return &out, nil
+}
+
+// bsonDocuments returns the BSON documents in the payload's data, which are
+// split in the same way as when the payload is sent without conversion.
+func (lp *LoggingPayload) bsonDocuments() ([][]byte, error) {
+	docs, err := lp.documents()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading bson documents")
+	}
+	return docs, nil
+}
+
+func bsonToJSON(doc []byte) ([]byte, error) {
+	unmarshaler := GetGlobalLoggerRegistry().Unmarshaler(RawLoggerConfigFormatBSON)
+	if unmarshaler == nil {
+		return nil, errors.New("no suitable unmarshaller provided")
+	}
+
+	fields := message.Fields{}
+	if err := unmarshaler(doc, &fields); err != nil {
+		return nil, errors.Wrap(err, "problem parsing bson from message body")
+	}
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem encoding bson document as json")
+	}
+	return out, nil
+}
+
+// toStructured converts string payloads to structured messages if every
+// message in the payload is a JSON document. It returns false if the payload
+// is not a string payload or cannot be converted, in which case the payload
+// should be sent as-is.
+func (lp *LoggingPayload) toStructured() (message.Composer, bool) {
+	if lp.Format != "" && lp.Format != LoggingPayloadFormatSTRING {
+		return nil, false
+	}
+
+	switch lp.Data.(type) {
+	case string, []byte, []string, [][]byte:
+	default:
+		return nil, false
+	}
+
+	candidate := *lp
+	candidate.Format = LoggingPayloadFormatJSON
+	msg, err := candidate.convert()
+	if err != nil {
+		return nil, false
+	}
+
+	return msg, true
+}
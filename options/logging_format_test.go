@@ -0,0 +1,219 @@
+package options
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLoggingPayloadFormatNegotiation(t *testing.T) {
+	makeLogger := func(t *testing.T, format LoggingPayloadFormat) (*CachedLogger, *send.InMemorySender) {
+		sender, err := send.NewInMemorySender("negotiation", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 10)
+		require.NoError(t, err)
+		if format == "" {
+			return &CachedLogger{Output: sender}, sender.(*send.InMemorySender)
+		}
+		return &CachedLogger{Output: NewPayloadFormatSender(sender, format)}, sender.(*send.InMemorySender)
+	}
+	isFields := func(msg message.Composer) bool {
+		_, ok := msg.Raw().(message.Fields)
+		return ok
+	}
+
+	doc, err := bson.Marshal(map[string]string{"msg": "hello world!"})
+	require.NoError(t, err)
+
+	t.Run("BytesOnlySender", func(t *testing.T) {
+		t.Run("SendsJSONAsText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     `{"msg":"hello world!"}`,
+				Format:   LoggingPayloadFormatJSON,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			assert.Equal(t, `{"msg":"hello world!"}`, msgs[0].String())
+			assert.False(t, isFields(msgs[0]))
+		})
+		t.Run("SendsInvalidJSONAsText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     `{"msg":`,
+				Format:   LoggingPayloadFormatJSON,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			assert.Equal(t, `{"msg":`, msgs[0].String())
+		})
+		t.Run("SendsMultipleJSONDocumentsAsText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     "{\"idx\":0}\n{\"idx\":1}",
+				Format:   LoggingPayloadFormatJSON,
+				IsMulti:  true,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			group := requireIsGroup(t, 2, msgs[0])
+			assert.Equal(t, `{"idx":0}`, group[0].String())
+			assert.Equal(t, `{"idx":1}`, group[1].String())
+		})
+		t.Run("ConvertsBSONToJSONText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     doc,
+				Format:   LoggingPayloadFormatBSON,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			assert.Equal(t, `{"msg":"hello world!"}`, msgs[0].String())
+		})
+		t.Run("ConvertsMultipleBSONDocumentsToJSONText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     bytes.Repeat(doc, 2),
+				Format:   LoggingPayloadFormatBSON,
+				IsMulti:  true,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			group := requireIsGroup(t, 2, msgs[0])
+			assert.Equal(t, `{"msg":"hello world!"}`, group[0].String())
+			assert.Equal(t, `{"msg":"hello world!"}`, group[1].String())
+		})
+		t.Run("ConvertsLengthPrefixedBSONDocumentsToJSONText", func(t *testing.T) {
+			prefix := make([]byte, lengthPrefixSize)
+			binary.BigEndian.PutUint32(prefix, uint32(len(doc)))
+			frame := append(prefix, doc...)
+
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     bytes.Repeat(frame, 2),
+				Format:   LoggingPayloadFormatBSON,
+				Framing:  LoggingPayloadFramingLengthPrefixed,
+				IsMulti:  true,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			group := requireIsGroup(t, 2, msgs[0])
+			assert.Equal(t, `{"msg":"hello world!"}`, group[0].String())
+			assert.Equal(t, `{"msg":"hello world!"}`, group[1].String())
+		})
+		t.Run("FailsWithTruncatedLengthPrefixedFrame", func(t *testing.T) {
+			prefix := make([]byte, lengthPrefixSize)
+			binary.BigEndian.PutUint32(prefix, uint32(len(doc)+1))
+
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			err := logger.Send(&LoggingPayload{
+				Data:     append(prefix, doc...),
+				Format:   LoggingPayloadFormatBSON,
+				Framing:  LoggingPayloadFramingLengthPrefixed,
+				IsMulti:  true,
+				Priority: level.Info,
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "truncated frame at offset 0")
+			assert.Empty(t, sender.Get())
+		})
+		t.Run("ConvertsFieldsToJSONText", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     message.Fields{"msg": "hello world!"},
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			assert.Equal(t, `{"msg":"hello world!"}`, msgs[0].String())
+		})
+		t.Run("FailsWithInvalidBSON", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			err := logger.Send(&LoggingPayload{
+				Data:     []byte("\x01\x00"),
+				Format:   LoggingPayloadFormatBSON,
+				Priority: level.Info,
+			})
+			require.Error(t, err)
+			formatErr, ok := errors.Cause(err).(*PayloadFormatError)
+			require.True(t, ok)
+			assert.Equal(t, LoggingPayloadFormat(LoggingPayloadFormatSTRING), formatErr.Format)
+			assert.Empty(t, sender.Get())
+		})
+		t.Run("FailsWithUnencodableFields", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatSTRING)
+			err := logger.Send(&LoggingPayload{
+				Data:     message.Fields{"func": func() {}},
+				Priority: level.Info,
+			})
+			require.Error(t, err)
+			_, ok := errors.Cause(err).(*PayloadFormatError)
+			assert.True(t, ok)
+			assert.Empty(t, sender.Get())
+		})
+	})
+	t.Run("StructuredSender", func(t *testing.T) {
+		t.Run("ConvertsJSONStringsToFields", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatJSON)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     `{"msg":"hello world!"}`,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			assert.True(t, isFields(msgs[0]))
+			assert.Equal(t, `[msg='hello world!']`, msgs[0].String())
+		})
+		t.Run("FallsBackToStringsThatAreNotJSON", func(t *testing.T) {
+			logger, sender := makeLogger(t, LoggingPayloadFormatJSON)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:     "{\"msg\":\"hello world!\"}\nnot json",
+				IsMulti:  true,
+				Priority: level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			group := requireIsGroup(t, 2, msgs[0])
+			assert.False(t, isFields(group[0]))
+			assert.Equal(t, "not json", group[1].String())
+		})
+	})
+	t.Run("SenderWithoutPreferenceIsUnchanged", func(t *testing.T) {
+		logger, sender := makeLogger(t, "")
+		require.NoError(t, logger.Send(&LoggingPayload{
+			Data:     `{"msg":"hello world!"}`,
+			Format:   LoggingPayloadFormatJSON,
+			Priority: level.Info,
+		}))
+		require.NoError(t, logger.Send(&LoggingPayload{
+			Data:     `{"msg":"hello world!"}`,
+			Priority: level.Info,
+		}))
+
+		msgs := sender.Get()
+		require.Len(t, msgs, 2)
+		assert.True(t, isFields(msgs[0]))
+		assert.False(t, isFields(msgs[1]))
+	})
+}
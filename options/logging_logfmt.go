@@ -0,0 +1,124 @@
+package options
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tychoish/grip/message"
+)
+
+// LogfmtRawKey is the key under which the parts of a logfmt message that are
+// not valid key=value pairs are preserved, separated by spaces.
+const LogfmtRawKey = "_raw"
+
+// parseLogfmt parses a logfmt message (e.g. `key=value key2="v 2"`) into
+// fields. Values are always strings, except that keys without a value are
+// set to true. Quoted values may contain Go string escapes. Malformed pairs
+// do not cause the rest of the message to be discarded; instead, they are
+// preserved under LogfmtRawKey. If a key appears more than once, the last
+// value is used.
+func parseLogfmt(data []byte) message.Fields {
+	fields := message.Fields{}
+	raw := []string{}
+
+	s := string(data)
+	for i := 0; i < len(s); {
+		if isLogfmtSpace(s[i]) {
+			i++
+			continue
+		}
+
+		key, value, end, ok := parseLogfmtPair(s, i)
+		if ok {
+			fields[key] = value
+		} else {
+			raw = append(raw, s[i:end])
+		}
+		i = end
+	}
+
+	if len(raw) != 0 {
+		fields[LogfmtRawKey] = strings.Join(raw, " ")
+	}
+
+	return fields
+}
+
+// parseLogfmtPair parses the pair that begins at start and returns the index
+// immediately after it. If the pair is malformed, it returns false and the
+// index immediately after the malformed text.
+func parseLogfmtPair(s string, start int) (string, interface{}, int, bool) {
+	i := start
+	for i < len(s) && !isLogfmtSpace(s[i]) && s[i] != '=' && s[i] != '"' {
+		i++
+	}
+	key := s[start:i]
+
+	switch {
+	case key == "" || (i < len(s) && s[i] == '"'):
+		return "", nil, skipLogfmtToken(s, i), false
+	case i == len(s) || isLogfmtSpace(s[i]):
+		return key, true, i, true
+	}
+
+	// Skip the '='.
+	i++
+	if i == len(s) || isLogfmtSpace(s[i]) {
+		return key, "", i, true
+	}
+
+	if s[i] != '"' {
+		end := i
+		for ; end < len(s) && !isLogfmtSpace(s[end]); end++ {
+			if s[end] == '"' {
+				return "", nil, skipLogfmtToken(s, end), false
+			}
+		}
+		return key, s[i:end], end, true
+	}
+
+	end := i + 1
+	for end < len(s) && s[end] != '"' {
+		if s[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(s) {
+		return "", nil, len(s), false
+	}
+	// Skip the closing quote.
+	end++
+	if end < len(s) && !isLogfmtSpace(s[end]) {
+		return "", nil, skipLogfmtToken(s, end), false
+	}
+
+	value, err := strconv.Unquote(s[i:end])
+	if err != nil {
+		return "", nil, end, false
+	}
+
+	return key, value, end, true
+}
+
+// skipLogfmtToken returns the index of the end of the token that contains i,
+// which must not be within quotes. Whitespace within quotes that begin at or
+// after i is considered part of the token.
+func skipLogfmtToken(s string, i int) int {
+	quoted := false
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case isLogfmtSpace(s[i]) && !quoted:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isLogfmtSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
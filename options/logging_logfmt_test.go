@@ -0,0 +1,165 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+func TestParseLogfmt(t *testing.T) {
+	for name, test := range map[string]struct {
+		line     string
+		expected message.Fields
+	}{
+		"Empty": {
+			line:     "",
+			expected: message.Fields{},
+		},
+		"SimplePairs": {
+			line:     "level=info msg=started port=8080",
+			expected: message.Fields{"level": "info", "msg": "started", "port": "8080"},
+		},
+		"QuotedValues": {
+			line:     `msg="hello world" path="/tmp/a b"`,
+			expected: message.Fields{"msg": "hello world", "path": "/tmp/a b"},
+		},
+		"EscapesInQuotedValues": {
+			line:     `msg="say \"hi\"\tthen\\leave" unicode="é"`,
+			expected: message.Fields{"msg": "say \"hi\"\tthen\\leave", "unicode": "é"},
+		},
+		"EmptyValues": {
+			line:     `a= b="" c=d`,
+			expected: message.Fields{"a": "", "b": "", "c": "d"},
+		},
+		"KeyWithoutValue": {
+			line:     "debug msg=hi",
+			expected: message.Fields{"debug": true, "msg": "hi"},
+		},
+		"ValueContainingEquals": {
+			line:     "url=http://host/?a=b",
+			expected: message.Fields{"url": "http://host/?a=b"},
+		},
+		"ExtraWhitespace": {
+			line:     "  a=1\t\tb=2  \r",
+			expected: message.Fields{"a": "1", "b": "2"},
+		},
+		"DuplicateKeysUseLastValue": {
+			line:     "a=1 a=2",
+			expected: message.Fields{"a": "2"},
+		},
+		"MissingKey": {
+			line:     "=foo a=1",
+			expected: message.Fields{"a": "1", LogfmtRawKey: "=foo"},
+		},
+		"UnterminatedQuote": {
+			line:     `a=1 msg="unterminated value`,
+			expected: message.Fields{"a": "1", LogfmtRawKey: `msg="unterminated value`},
+		},
+		"QuoteInUnquotedValue": {
+			line:     `a=b"c d" e=f`,
+			expected: message.Fields{"e": "f", LogfmtRawKey: `a=b"c d"`},
+		},
+		"TextAfterQuotedValue": {
+			line:     `a="b"c e=f`,
+			expected: message.Fields{"e": "f", LogfmtRawKey: `a="b"c`},
+		},
+		"QuotedKey": {
+			line:     `"a b"=c e=f`,
+			expected: message.Fields{"e": "f", LogfmtRawKey: `"a b"=c`},
+		},
+		"InvalidEscape": {
+			line:     `a="\q" e=f`,
+			expected: message.Fields{"e": "f", LogfmtRawKey: `a="\q"`},
+		},
+		"MultipleMalformedPairs": {
+			line:     `=x a=1 =y`,
+			expected: message.Fields{"a": "1", LogfmtRawKey: "=x =y"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseLogfmt([]byte(test.line)))
+		})
+	}
+}
+
+func TestLogfmtLoggingPayload(t *testing.T) {
+	makeLogger := func(t *testing.T) (*CachedLogger, *send.InMemorySender) {
+		sender, err := send.NewInMemorySender("logfmt", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 10)
+		require.NoError(t, err)
+		return &CachedLogger{Output: sender}, sender.(*send.InMemorySender)
+	}
+
+	t.Run("ValidatesFormat", func(t *testing.T) {
+		lp := &LoggingPayload{Data: "a=1", Format: LoggingPayloadFormatLogfmt}
+		assert.NoError(t, lp.Validate())
+	})
+	t.Run("SendsFields", func(t *testing.T) {
+		logger, sender := makeLogger(t)
+		require.NoError(t, logger.Send(&LoggingPayload{
+			Data:     `level=info msg="hello world"`,
+			Format:   LoggingPayloadFormatLogfmt,
+			Priority: level.Info,
+		}))
+
+		msgs := sender.Get()
+		require.Len(t, msgs, 1)
+		fields, ok := msgs[0].Raw().(message.Fields)
+		require.True(t, ok)
+		assert.Equal(t, message.Fields{"level": "info", "msg": "hello world"}, fields)
+	})
+	t.Run("SendsEachLineOfMultiPayload", func(t *testing.T) {
+		logger, sender := makeLogger(t)
+		require.NoError(t, logger.Send(&LoggingPayload{
+			Data:     "a=1\nb=2 =bad",
+			Format:   LoggingPayloadFormatLogfmt,
+			IsMulti:  true,
+			Priority: level.Info,
+		}))
+
+		msgs := sender.Get()
+		require.Len(t, msgs, 1)
+		group, ok := msgs[0].(*message.GroupComposer)
+		require.True(t, ok)
+		lines := group.Messages()
+		require.Len(t, lines, 2)
+		assert.Equal(t, message.Fields{"a": "1"}, lines[0].Raw())
+		assert.Equal(t, message.Fields{"b": "2", LogfmtRawKey: "=bad"}, lines[1].Raw())
+	})
+	t.Run("RespectsAddMetadata", func(t *testing.T) {
+		for _, addMetadata := range []bool{true, false} {
+			logger, sender := makeLogger(t)
+			require.NoError(t, logger.Send(&LoggingPayload{
+				Data:        "a=1",
+				Format:      LoggingPayloadFormatLogfmt,
+				AddMetadata: addMetadata,
+				Priority:    level.Info,
+			}))
+
+			msgs := sender.Get()
+			require.Len(t, msgs, 1)
+			fields, ok := msgs[0].Raw().(message.Fields)
+			require.True(t, ok)
+			assert.Equal(t, "1", fields["a"])
+			_, hasMetadata := fields["metadata"]
+			assert.Equal(t, addMetadata, hasMetadata)
+		}
+	})
+	t.Run("SendsTextToStringSenders", func(t *testing.T) {
+		sender, err := send.NewInMemorySender("logfmt", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 10)
+		require.NoError(t, err)
+		logger := &CachedLogger{Output: NewPayloadFormatSender(sender, LoggingPayloadFormatSTRING)}
+		require.NoError(t, logger.Send(&LoggingPayload{
+			Data:     `a=1 msg="hello world"`,
+			Format:   LoggingPayloadFormatLogfmt,
+			Priority: level.Info,
+		}))
+
+		msgs := sender.(*send.InMemorySender).Get()
+		require.Len(t, msgs, 1)
+		assert.Equal(t, `a=1 msg="hello world"`, msgs[0].String())
+	})
+}
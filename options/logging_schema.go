@@ -0,0 +1,211 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JSONSchema is a compiled JSON schema used to validate structured logging
+// payloads. It supports the subset of JSON schema that describes the shape
+// of log messages: the "type", "properties", "required", "items" and "enum"
+// keywords. Other keywords are ignored.
+type JSONSchema struct {
+	types      []string
+	properties map[string]*JSONSchema
+	required   []string
+	items      *JSONSchema
+	enum       []interface{}
+}
+
+type jsonSchemaDocument struct {
+	Type       json.RawMessage            `json:"type"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+	Items      json.RawMessage            `json:"items"`
+	Enum       []interface{}              `json:"enum"`
+}
+
+var jsonSchemaTypes = map[string]struct{}{
+	"object":  {},
+	"array":   {},
+	"string":  {},
+	"number":  {},
+	"integer": {},
+	"boolean": {},
+	"null":    {},
+}
+
+// CompileJSONSchema parses a JSON schema document so that it can be used to
+// validate logging payloads.
+func CompileJSONSchema(data []byte) (*JSONSchema, error) {
+	doc := jsonSchemaDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "problem parsing json schema")
+	}
+
+	schema := &JSONSchema{
+		required: doc.Required,
+		enum:     doc.Enum,
+	}
+
+	if len(doc.Type) != 0 {
+		var name string
+		if err := json.Unmarshal(doc.Type, &name); err == nil {
+			schema.types = []string{name}
+		} else if err = json.Unmarshal(doc.Type, &schema.types); err != nil {
+			return nil, errors.New("schema type must be a string or a list of strings")
+		}
+		for _, t := range schema.types {
+			if _, ok := jsonSchemaTypes[t]; !ok {
+				return nil, errors.Errorf("unsupported schema type '%s'", t)
+			}
+		}
+	}
+
+	if len(doc.Properties) != 0 {
+		schema.properties = make(map[string]*JSONSchema, len(doc.Properties))
+		for name, raw := range doc.Properties {
+			prop, err := CompileJSONSchema(raw)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem compiling schema for property '%s'", name)
+			}
+			schema.properties[name] = prop
+		}
+	}
+
+	if len(doc.Items) != 0 {
+		items, err := CompileJSONSchema(doc.Items)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem compiling schema for items")
+		}
+		schema.items = items
+	}
+
+	return schema, nil
+}
+
+// SchemaValidationError is returned when a structured logging payload does
+// not conform to its schema. Field is the dotted path of the field that
+// failed validation, and is empty if the payload as a whole is invalid.
+type SchemaValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *SchemaValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("payload does not match schema: %s", e.Reason)
+	}
+	return fmt.Sprintf("payload field '%s' does not match schema: %s", e.Field, e.Reason)
+}
+
+// Validate checks that the value, as decoded by encoding/json, conforms to
+// the schema. It returns a *SchemaValidationError describing the first field
+// that does not conform.
+func (s *JSONSchema) Validate(value interface{}) error {
+	return s.validate("", value)
+}
+
+func (s *JSONSchema) validate(path string, value interface{}) error {
+	if len(s.types) != 0 && !s.matchesType(value) {
+		return &SchemaValidationError{
+			Field:  path,
+			Reason: fmt.Sprintf("expected type %s but got %s", strings.Join(s.types, " or "), jsonTypeName(value)),
+		}
+	}
+
+	if len(s.enum) != 0 && !s.matchesEnum(value) {
+		return &SchemaValidationError{Field: path, Reason: "value is not one of the allowed values"}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				return &SchemaValidationError{Field: joinSchemaPath(path, name), Reason: "required field is missing"}
+			}
+		}
+
+		names := make([]string, 0, len(s.properties))
+		for name := range s.properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			field, ok := v[name]
+			if !ok {
+				continue
+			}
+			if err := s.properties[name].validate(joinSchemaPath(path, name), field); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.items == nil {
+			return nil
+		}
+		for idx, item := range v {
+			if err := s.items.validate(fmt.Sprintf("%s[%d]", path, idx), item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *JSONSchema) matchesType(value interface{}) bool {
+	name := jsonTypeName(value)
+	for _, t := range s.types {
+		if t == name {
+			return true
+		}
+		if t == "number" && name == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *JSONSchema) matchesEnum(value interface{}) bool {
+	for _, allowed := range s.enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
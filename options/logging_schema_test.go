@@ -0,0 +1,151 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/send"
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Run("Compile", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			schema string
+			valid  bool
+		}{
+			"Empty":            {schema: `{}`, valid: true},
+			"TypeString":       {schema: `{"type":"object"}`, valid: true},
+			"TypeList":         {schema: `{"type":["string","null"]}`, valid: true},
+			"UnsupportedType":  {schema: `{"type":"date"}`, valid: false},
+			"MalformedType":    {schema: `{"type":1}`, valid: false},
+			"InvalidJSON":      {schema: `{"type":`, valid: false},
+			"InvalidProperty":  {schema: `{"properties":{"a":{"type":"date"}}}`, valid: false},
+			"InvalidItems":     {schema: `{"items":{"type":"date"}}`, valid: false},
+			"IgnoresKeywords":  {schema: `{"$schema":"http://json-schema.org/draft-07/schema#","title":"log"}`, valid: true},
+			"NestedProperties": {schema: `{"properties":{"a":{"properties":{"b":{"type":"string"}}}}}`, valid: true},
+		} {
+			t.Run(name, func(t *testing.T) {
+				schema, err := CompileJSONSchema([]byte(test.schema))
+				if test.valid {
+					assert.NoError(t, err)
+					assert.NotNil(t, schema)
+				} else {
+					assert.Error(t, err)
+					assert.Nil(t, schema)
+				}
+			})
+		}
+	})
+	t.Run("Validate", func(t *testing.T) {
+		schema, err := CompileJSONSchema([]byte(`{
+			"type": "object",
+			"required": ["level"],
+			"properties": {
+				"level": {"type": "string", "enum": ["info", "error"]},
+				"count": {"type": "integer"},
+				"ctx": {
+					"type": "object",
+					"required": ["host"],
+					"properties": {"host": {"type": "string"}}
+				},
+				"tags": {"type": "array", "items": {"type": "string"}}
+			}
+		}`))
+		require.NoError(t, err)
+
+		for name, test := range map[string]struct {
+			value interface{}
+			field string
+		}{
+			"Conforming":         {value: map[string]interface{}{"level": "info", "count": float64(2), "extra": true}},
+			"MissingRequired":    {value: map[string]interface{}{"msg": "hi"}, field: "level"},
+			"WrongType":          {value: map[string]interface{}{"level": float64(1)}, field: "level"},
+			"NotInEnum":          {value: map[string]interface{}{"level": "debug"}, field: "level"},
+			"NotInteger":         {value: map[string]interface{}{"level": "info", "count": 1.5}, field: "count"},
+			"NestedMissing":      {value: map[string]interface{}{"level": "info", "ctx": map[string]interface{}{}}, field: "ctx.host"},
+			"ArrayItemWrongType": {value: map[string]interface{}{"level": "info", "tags": []interface{}{"a", false}}, field: "tags[1]"},
+			"NotObject":          {value: "info", field: ""},
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := schema.Validate(test.value)
+				if name == "Conforming" {
+					assert.NoError(t, err)
+					return
+				}
+				require.Error(t, err)
+				schemaErr, ok := err.(*SchemaValidationError)
+				require.True(t, ok)
+				assert.Equal(t, test.field, schemaErr.Field)
+				assert.NotEmpty(t, schemaErr.Reason)
+			})
+		}
+	})
+	t.Run("LoggingPayload", func(t *testing.T) {
+		schema, err := CompileJSONSchema([]byte(`{"type":"object","required":["level"]}`))
+		require.NoError(t, err)
+
+		t.Run("Passes", func(t *testing.T) {
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: sender}
+			lp := &LoggingPayload{
+				Data:     `{"level":"info","msg":"hello"}`,
+				Format:   LoggingPayloadFormatJSON,
+				Priority: level.Info,
+				Schema:   schema,
+			}
+			require.NoError(t, cl.Send(lp))
+			assert.True(t, sender.HasMessage())
+		})
+		t.Run("Fails", func(t *testing.T) {
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: sender}
+			lp := &LoggingPayload{
+				Data:     `{"msg":"hello"}`,
+				Format:   LoggingPayloadFormatJSON,
+				Priority: level.Info,
+				Schema:   schema,
+			}
+			err := cl.Send(lp)
+			require.Error(t, err)
+			schemaErr, ok := errors.Cause(err).(*SchemaValidationError)
+			require.True(t, ok)
+			assert.Equal(t, "level", schemaErr.Field)
+			assert.False(t, sender.HasMessage())
+		})
+		t.Run("FailsForStringSender", func(t *testing.T) {
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: NewPayloadFormatSender(sender, LoggingPayloadFormatSTRING)}
+			lp := &LoggingPayload{
+				Data:     `{"msg":"hello"}`,
+				Format:   LoggingPayloadFormatJSON,
+				Priority: level.Info,
+				Schema:   schema,
+			}
+			err := cl.Send(lp)
+			require.Error(t, err)
+			schemaErr, ok := errors.Cause(err).(*SchemaValidationError)
+			require.True(t, ok)
+			assert.Equal(t, "level", schemaErr.Field)
+			assert.False(t, sender.HasMessage())
+		})
+		t.Run("MultiFailsOnAnyMessage", func(t *testing.T) {
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: sender}
+			lp := &LoggingPayload{
+				Data:     "{\"level\":\"info\"}\n{\"msg\":\"hello\"}",
+				Format:   LoggingPayloadFormatJSON,
+				IsMulti:  true,
+				Priority: level.Info,
+				Schema:   schema,
+			}
+			err := cl.Send(lp)
+			require.Error(t, err)
+			_, ok := errors.Cause(err).(*SchemaValidationError)
+			assert.True(t, ok)
+			assert.False(t, sender.HasMessage())
+		})
+	})
+}
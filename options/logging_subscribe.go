@@ -0,0 +1,78 @@
+package options
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tychoish/grip/message"
+)
+
+// LoggerSubscriptionBufferSize is the number of messages buffered for each
+// subscriber to a CachedLogger. Messages are dropped for subscribers whose
+// buffer is full.
+const LoggerSubscriptionBufferSize = 128
+
+// messagePublisher sends the messages that a CachedLogger sends to
+// subscribers. It is safe for concurrent use.
+type messagePublisher struct {
+	subscribers map[chan message.Composer]struct{}
+	mu          sync.Mutex
+}
+
+// Subscribe returns a channel that receives every message that the logger
+// sends with Send, in addition to the logger's senders, until the context is
+// done, at which point the channel is closed. Messages are dropped if the
+// subscriber does not keep up with them.
+func (cl *CachedLogger) Subscribe(ctx context.Context) <-chan message.Composer {
+	cl.mu.Lock()
+	if cl.publisher == nil {
+		cl.publisher = &messagePublisher{}
+	}
+	publisher := cl.publisher
+	cl.mu.Unlock()
+
+	return publisher.subscribe(ctx)
+}
+
+func (p *messagePublisher) subscribe(ctx context.Context) <-chan message.Composer {
+	msgs := make(chan message.Composer, LoggerSubscriptionBufferSize)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.subscribers == nil {
+		p.subscribers = map[chan message.Composer]struct{}{}
+	}
+	p.subscribers[msgs] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		delete(p.subscribers, msgs)
+		close(msgs)
+	}()
+
+	return msgs
+}
+
+// publish sends the message to every subscriber without blocking, so the
+// message is dropped for subscribers that are not keeping up. It is safe to
+// call on a nil publisher.
+func (p *messagePublisher) publish(m message.Composer) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for msgs := range p.subscribers {
+		select {
+		case msgs <- m:
+		default:
+		}
+	}
+}
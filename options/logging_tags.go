@@ -0,0 +1,151 @@
+package options
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// ProcessTagsMetadataKey is the key of the annotation that contains the tags
+// of the process on the messages logged from its output and error.
+const ProcessTagsMetadataKey = "process_tags"
+
+// messageTags returns the tags of the process that logged the message, which
+// are stored in the message's ProcessTagsMetadataKey annotation, if any.
+// Composers do not expose their annotations, so they are read from the
+// Context field of the message.Base that the composer embeds.
+func messageTags(m message.Composer) []string {
+	val := reflect.ValueOf(m)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	field := val.FieldByName("Context")
+	if !field.IsValid() || !field.CanInterface() {
+		return nil
+	}
+	fields, ok := field.Interface().(message.Fields)
+	if !ok {
+		return nil
+	}
+	tags, _ := fields[ProcessTagsMetadataKey].([]string)
+	return tags
+}
+
+// processTagSender is a sender that adds the process' tags to the messages
+// sent to it.
+type processTagSender struct {
+	send.Sender
+	tags []string
+}
+
+func (s *processTagSender) Send(m message.Composer) {
+	// Annotating only fails if the message already has the tags.
+	_ = m.Annotate(ProcessTagsMetadataKey, s.tags)
+	s.Sender.Send(m)
+}
+
+// tagMessages wraps the sender so that messages sent to it carry the
+// process' tags, if there are any.
+func (o *Output) tagMessages(sender send.Sender) send.Sender {
+	if len(o.processTags) == 0 {
+		return sender
+	}
+	return &processTagSender{Sender: sender, tags: o.processTags}
+}
+
+type tagRouterSender struct {
+	*send.Base
+	routes   map[string]send.Sender
+	fallback send.Sender
+}
+
+// NewTagRouterSender returns a sender that dispatches the messages logged
+// from the output of processes based on the processes' tags. Each message is
+// sent once to every sender whose tag the process has. Messages from
+// processes without any routed tags, as well as messages that were not logged
+// from process output, are sent to the fallback sender. Since the loggers of
+// a process are closed when it exits, closing the returned sender does not
+// close the routed or fallback senders, so that it can be shared by several
+// processes.
+func NewTagRouterSender(name string, l send.LevelInfo, routes map[string]send.Sender, fallback send.Sender) (send.Sender, error) {
+	if fallback == nil {
+		return nil, errors.New("must specify a fallback sender")
+	}
+	for tag, sender := range routes {
+		if sender == nil {
+			return nil, errors.Errorf("sender for tag '%s' cannot be nil", tag)
+		}
+	}
+
+	s := &tagRouterSender{
+		Base:     send.NewBase(name),
+		routes:   make(map[string]send.Sender, len(routes)),
+		fallback: fallback,
+	}
+	for tag, sender := range routes {
+		s.routes[tag] = sender
+	}
+
+	if err := s.SetLevel(l); err != nil {
+		return nil, errors.Wrap(err, "problem setting level")
+	}
+
+	return s, nil
+}
+
+func (s *tagRouterSender) Send(m message.Composer) {
+	if !s.Level().ShouldLog(m) {
+		return
+	}
+
+	for _, sender := range s.route(m) {
+		sender.Send(m)
+	}
+}
+
+// route returns the distinct senders that the message should be sent to.
+func (s *tagRouterSender) route(m message.Composer) []send.Sender {
+	senders := []send.Sender{}
+	seen := map[send.Sender]struct{}{}
+	for _, tag := range messageTags(m) {
+		sender, ok := s.routes[tag]
+		if !ok {
+			continue
+		}
+		if _, ok = seen[sender]; ok {
+			continue
+		}
+		seen[sender] = struct{}{}
+		senders = append(senders, sender)
+	}
+
+	if len(senders) == 0 {
+		return []send.Sender{s.fallback}
+	}
+	return senders
+}
+
+func (s *tagRouterSender) Flush(ctx context.Context) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(s.fallback.Flush(ctx))
+	seen := map[send.Sender]struct{}{s.fallback: {}}
+	for _, sender := range s.routes {
+		if _, ok := seen[sender]; ok {
+			continue
+		}
+		seen[sender] = struct{}{}
+		catcher.Add(sender.Flush(ctx))
+	}
+	return catcher.Resolve()
+}
@@ -0,0 +1,114 @@
+package options
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+func TestTagRouterSender(t *testing.T) {
+	levelInfo := send.LevelInfo{Default: level.Info, Threshold: level.Trace}
+	makeSink := func(t *testing.T, name string) *send.InMemorySender {
+		sender, err := send.NewInMemorySender(name, levelInfo, 100)
+		require.NoError(t, err)
+		return sender.(*send.InMemorySender)
+	}
+
+	t.Run("RequiresFallback", func(t *testing.T) {
+		_, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{}, nil)
+		assert.Error(t, err)
+	})
+	t.Run("RejectsNilRoutes", func(t *testing.T) {
+		_, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{"foo": nil}, makeSink(t, "fallback"))
+		assert.Error(t, err)
+	})
+	t.Run("SendsUntaggedMessagesToFallback", func(t *testing.T) {
+		sink, fallback := makeSink(t, "sink"), makeSink(t, "fallback")
+		router, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{"foo": sink}, fallback)
+		require.NoError(t, err)
+
+		router.Send(message.NewDefaultMessage(level.Info, "hello"))
+		assert.Empty(t, sink.Get())
+		assert.Equal(t, []string{"hello"}, messageStrings(fallback))
+	})
+	t.Run("SendsOnceToEachMatchingSender", func(t *testing.T) {
+		sink, other, fallback := makeSink(t, "sink"), makeSink(t, "other"), makeSink(t, "fallback")
+		router, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{
+			"foo": sink,
+			"bar": sink,
+			"baz": other,
+		}, fallback)
+		require.NoError(t, err)
+
+		tagged := &processTagSender{Sender: router, tags: []string{"foo", "bar", "baz"}}
+		tagged.Send(message.NewDefaultMessage(level.Info, "hello"))
+		assert.Equal(t, []string{"hello"}, messageStrings(sink))
+		assert.Equal(t, []string{"hello"}, messageStrings(other))
+		assert.Empty(t, fallback.Get())
+	})
+	t.Run("RoutesOnTagsAnnotation", func(t *testing.T) {
+		sink, fallback := makeSink(t, "sink"), makeSink(t, "fallback")
+		router, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{"foo": sink}, fallback)
+		require.NoError(t, err)
+
+		msg := message.NewDefaultMessage(level.Info, "hello")
+		require.NoError(t, msg.Annotate(ProcessTagsMetadataKey, []string{"foo"}))
+		router.Send(msg)
+		assert.Equal(t, []string{"hello"}, messageStrings(sink))
+		assert.Empty(t, fallback.Get())
+	})
+	t.Run("RoutesProcessOutputByTags", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("test commands are not available on Windows")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fooSink, barSink, fallback := makeSink(t, "foo"), makeSink(t, "bar"), makeSink(t, "fallback")
+		router, err := NewTagRouterSender("router", levelInfo, map[string]send.Sender{
+			"foo": fooSink,
+			"bar": barSink,
+		}, fallback)
+		require.NoError(t, err)
+
+		for _, test := range []struct {
+			output string
+			tags   []string
+		}{
+			{output: "from foo", tags: []string{"foo"}},
+			{output: "from bar", tags: []string{"other", "bar"}},
+			{output: "from untagged"},
+		} {
+			opts := &Create{
+				Args: []string{"echo", test.output},
+				Tags: test.tags,
+				Output: Output{
+					Loggers: []*LoggerConfig{{
+						info:   loggerConfigInfo{Type: LogInherited, Format: RawLoggerConfigFormatBSON},
+						sender: router,
+					}},
+				},
+			}
+			exe, _, err := opts.Resolve(ctx)
+			require.NoError(t, err)
+			require.NoError(t, exe.Start())
+			require.NoError(t, exe.Wait())
+			require.NoError(t, opts.Close())
+		}
+
+		assert.Equal(t, []string{"from foo"}, messageStrings(fooSink))
+		assert.Equal(t, []string{"from bar"}, messageStrings(barSink))
+		assert.Equal(t, []string{"from untagged"}, messageStrings(fallback))
+
+		msgs := barSink.Get()
+		require.Len(t, msgs, 1)
+		assert.Equal(t, []string{"other", "bar"}, messageTags(msgs[0]))
+	})
+}
@@ -2,10 +2,16 @@ package options
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tychoish/grip"
@@ -75,6 +81,25 @@ func TestLoggingCache(t *testing.T) {
 		t.Run("Unconfigured", func(t *testing.T) {
 			err := cl.Send(lp)
 			assert.Error(t, err)
+			assert.Zero(t, cl.DroppedMessages())
+		})
+		t.Run("UnconfiguredWithValidPayload", func(t *testing.T) {
+			logger := &CachedLogger{}
+			lp := &LoggingPayload{Data: "hello, world!", Priority: level.Info}
+			assert.Error(t, logger.Send(lp))
+			assert.Zero(t, logger.DroppedMessages())
+		})
+		t.Run("UnconfiguredWithDrop", func(t *testing.T) {
+			logger := &CachedLogger{DropWhenUnconfigured: true}
+			lp := &LoggingPayload{Data: "hello, world!", Priority: level.Info}
+			assert.NoError(t, logger.Send(lp))
+			assert.NoError(t, logger.Send(lp))
+			assert.EqualValues(t, 2, logger.DroppedMessages())
+		})
+		t.Run("UnconfiguredWithDropStillValidates", func(t *testing.T) {
+			logger := &CachedLogger{DropWhenUnconfigured: true}
+			assert.Error(t, logger.Send(&LoggingPayload{}))
+			assert.Zero(t, logger.DroppedMessages())
 		})
 		t.Run("IvalidMessage", func(t *testing.T) {
 			lp.Format = LoggingPayloadFormatJSON
@@ -85,6 +110,328 @@ func TestLoggingCache(t *testing.T) {
 		})
 
 	})
+	t.Run("Fallback", func(t *testing.T) {
+		lp := &LoggingPayload{Data: "hello world!", Priority: level.Info}
+		t.Run("ReceivesMessagesThatFailToSend", func(t *testing.T) {
+			primary := NewMockSender("primary")
+			primary.FailSend = true
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: primary}
+			cl.SetFallback(fallback)
+
+			require.NoError(t, cl.Send(lp))
+			require.NoError(t, cl.Send(lp))
+
+			assert.EqualValues(t, 2, cl.Failovers())
+			require.Equal(t, 2, fallback.Len())
+			assert.Equal(t, "hello world!", fallback.GetMessage().Message.String())
+		})
+		t.Run("NotUsedWhenPrimarySucceeds", func(t *testing.T) {
+			primary := send.MakeInternalLogger()
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: primary}
+			cl.SetFallback(fallback)
+
+			require.NoError(t, cl.Send(lp))
+
+			assert.Zero(t, cl.Failovers())
+			assert.Equal(t, 1, primary.Len())
+			assert.Zero(t, fallback.Len())
+		})
+		t.Run("ReceivesMessagesThatFailToSendToAddedSender", func(t *testing.T) {
+			first := send.MakeInternalLogger()
+			second := NewMockSender("second")
+			second.FailSend = true
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: first}
+			cl.SetFallback(fallback)
+			require.NoError(t, cl.AddOutputSender(second))
+
+			require.NoError(t, cl.Send(lp))
+
+			assert.EqualValues(t, 1, cl.Failovers())
+			assert.Equal(t, 1, first.Len())
+			assert.Equal(t, 1, fallback.Len())
+		})
+		t.Run("UsedForErrorSender", func(t *testing.T) {
+			primary := NewMockSender("primary")
+			primary.FailSend = true
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Error: primary}
+			cl.SetFallback(fallback)
+
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "oops", Priority: level.Error, PreferSendToError: true}))
+
+			assert.EqualValues(t, 1, cl.Failovers())
+			require.Equal(t, 1, fallback.Len())
+			assert.Equal(t, "oops", fallback.GetMessage().Message.String())
+		})
+		t.Run("PreviousErrorHandlerIsCalled", func(t *testing.T) {
+			primary := &staticHandlerSender{MockSender: NewMockSender("primary")}
+			primary.FailSend = true
+			var handled []error
+			require.NoError(t, primary.SetErrorHandler(func(err error, _ message.Composer) {
+				handled = append(handled, err)
+			}))
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: primary}
+			cl.SetFallback(fallback)
+			cl.SetFallback(fallback)
+
+			require.NoError(t, cl.Send(lp))
+
+			assert.EqualValues(t, 1, cl.Failovers())
+			assert.Equal(t, 1, fallback.Len())
+			require.Len(t, handled, 1)
+			assert.Equal(t, "mock sender failed to send", errors.Cause(handled[0]).Error())
+		})
+		t.Run("DirectlyAssignedFallbackIsNotInstalled", func(t *testing.T) {
+			primary := NewMockSender("primary")
+			primary.FailSend = true
+			fallback := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: primary, Fallback: fallback}
+
+			require.NoError(t, cl.Send(lp))
+			assert.Zero(t, cl.Failovers())
+			assert.Zero(t, fallback.Len())
+		})
+		t.Run("FailuresAreLostWithoutFallback", func(t *testing.T) {
+			primary := NewMockSender("primary")
+			primary.FailSend = true
+			cl := &CachedLogger{Output: primary}
+
+			require.NoError(t, cl.Send(lp))
+			assert.Zero(t, cl.Failovers())
+		})
+	})
+	t.Run("Streams", func(t *testing.T) {
+		t.Run("NamedStreamIsRoutedToItsSender", func(t *testing.T) {
+			output := send.MakeInternalLogger()
+			audit := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: output, Streams: map[string]send.Sender{"audit": audit}}
+
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "user logged in", Priority: level.Info, Stream: "audit"}))
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "hello world!", Priority: level.Info}))
+
+			require.Equal(t, 1, audit.Len())
+			assert.Equal(t, "user logged in", audit.GetMessage().Message.String())
+			require.Equal(t, 1, output.Len())
+			assert.Equal(t, "hello world!", output.GetMessage().Message.String())
+		})
+		t.Run("StreamTakesPrecedenceOverPreferSendToError", func(t *testing.T) {
+			errSender := send.MakeInternalLogger()
+			audit := send.MakeInternalLogger()
+			cl := &CachedLogger{Error: errSender, Streams: map[string]send.Sender{"audit": audit}}
+
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "audit", Priority: level.Error, PreferSendToError: true, Stream: "audit"}))
+
+			assert.Equal(t, 1, audit.Len())
+			assert.Zero(t, errSender.Len())
+		})
+		t.Run("ErrorStreamUsesErrorSender", func(t *testing.T) {
+			output := send.MakeInternalLogger()
+			errSender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: output, Error: errSender}
+
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "oops", Priority: level.Error, Stream: LoggingStreamError}))
+
+			assert.Equal(t, 1, errSender.Len())
+			assert.Zero(t, output.Len())
+		})
+		t.Run("UnknownStreamFallsBackToOutput", func(t *testing.T) {
+			output := send.MakeInternalLogger()
+			errSender := send.MakeInternalLogger()
+			audit := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: output, Error: errSender, Streams: map[string]send.Sender{"audit": audit}}
+
+			require.NoError(t, cl.Send(&LoggingPayload{Data: "hello world!", Priority: level.Info, Stream: "metrics"}))
+
+			assert.Equal(t, 1, output.Len())
+			assert.Zero(t, errSender.Len())
+			assert.Zero(t, audit.Len())
+		})
+		t.Run("UnknownStreamWithoutSendersFails", func(t *testing.T) {
+			cl := &CachedLogger{Streams: map[string]send.Sender{"audit": send.MakeInternalLogger()}}
+			assert.Error(t, cl.Send(&LoggingPayload{Data: "hello world!", Priority: level.Info, Stream: "metrics"}))
+		})
+		t.Run("CloseClosesStreamSenders", func(t *testing.T) {
+			output := NewMockSender("output")
+			audit := NewMockSender("audit")
+			cl := &CachedLogger{Output: output, Streams: map[string]send.Sender{"audit": audit, "shared": output}}
+
+			require.NoError(t, cl.Close())
+			assert.True(t, output.Closed)
+			assert.True(t, audit.Closed)
+		})
+	})
+	t.Run("AddSenders", func(t *testing.T) {
+		lp := &LoggingPayload{Data: "hello world!", Priority: level.Info}
+		t.Run("OutputSenderReceivesSubsequentMessages", func(t *testing.T) {
+			first := send.MakeInternalLogger()
+			second := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: first}
+
+			require.NoError(t, cl.Send(lp))
+			require.NoError(t, cl.AddOutputSender(second))
+			require.NoError(t, cl.Send(lp))
+
+			assert.Equal(t, 2, first.Len())
+			require.Equal(t, 1, second.Len())
+			assert.Equal(t, "hello world!", second.GetMessage().Message.String())
+		})
+		t.Run("ErrorSenderReceivesSubsequentMessages", func(t *testing.T) {
+			errLP := &LoggingPayload{Data: "hello world!", Priority: level.Error, PreferSendToError: true}
+			first := send.MakeInternalLogger()
+			second := send.MakeInternalLogger()
+			third := send.MakeInternalLogger()
+			cl := &CachedLogger{Error: first}
+
+			require.NoError(t, cl.Send(errLP))
+			require.NoError(t, cl.AddErrorSender(second))
+			require.NoError(t, cl.Send(errLP))
+			require.NoError(t, cl.AddErrorSender(third))
+			require.NoError(t, cl.Send(errLP))
+
+			assert.Equal(t, 3, first.Len())
+			assert.Equal(t, 2, second.Len())
+			assert.Equal(t, 1, third.Len())
+		})
+		t.Run("AddToUnconfiguredLogger", func(t *testing.T) {
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{}
+			require.NoError(t, cl.AddOutputSender(sender))
+			require.NoError(t, cl.Send(lp))
+			assert.Equal(t, 1, sender.Len())
+		})
+		t.Run("NilSenderFails", func(t *testing.T) {
+			cl := &CachedLogger{}
+			assert.Error(t, cl.AddOutputSender(nil))
+			assert.Error(t, cl.AddErrorSender(nil))
+		})
+		t.Run("CloseClosesAllSenders", func(t *testing.T) {
+			output := NewMockSender("output")
+			error := NewMockSender("error")
+			addedOutput := NewMockSender("added-output")
+			addedError := NewMockSender("added-error")
+			cl := &CachedLogger{Output: output, Error: error}
+
+			require.NoError(t, cl.AddOutputSender(addedOutput))
+			require.NoError(t, cl.AddErrorSender(addedError))
+			require.NoError(t, cl.Close())
+			assert.True(t, output.Closed)
+			assert.True(t, error.Closed)
+			assert.True(t, addedOutput.Closed)
+			assert.True(t, addedError.Closed)
+		})
+		t.Run("CloseClosesSharedSenderOnce", func(t *testing.T) {
+			shared := NewMockSender("shared")
+			added := NewMockSender("added")
+			cl := &CachedLogger{Output: shared, Error: shared}
+
+			require.NoError(t, cl.AddOutputSender(added))
+			require.NoError(t, cl.Close())
+			assert.True(t, shared.Closed)
+			assert.True(t, added.Closed)
+		})
+		t.Run("ConcurrentAddAndSend", func(t *testing.T) {
+			cl := &CachedLogger{Output: send.MakeInternalLogger()}
+			senders := make([]*send.InternalSender, 10)
+			wg := &sync.WaitGroup{}
+			for i := range senders {
+				senders[i] = send.MakeInternalLogger()
+				wg.Add(2)
+				go func(sender send.Sender) {
+					defer wg.Done()
+					assert.NoError(t, cl.AddOutputSender(sender))
+				}(senders[i])
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, cl.Send(lp))
+				}()
+			}
+			wg.Wait()
+
+			require.NoError(t, cl.Send(lp))
+			for _, sender := range senders {
+				assert.True(t, sender.HasMessage())
+			}
+		})
+	})
+	t.Run("Subscribe", func(t *testing.T) {
+		newPayload := func(msg string) *LoggingPayload {
+			return &LoggingPayload{Data: msg, Priority: level.Info}
+		}
+		t.Run("ReceivesSentMessages", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sender := send.MakeInternalLogger()
+			cl := &CachedLogger{Output: sender}
+			msgs := cl.Subscribe(ctx)
+			for _, msg := range []string{"one", "two", "three"} {
+				require.NoError(t, cl.Send(newPayload(msg)))
+			}
+
+			for _, expected := range []string{"one", "two", "three"} {
+				select {
+				case msg := <-msgs:
+					assert.Equal(t, expected, msg.String())
+				case <-time.After(time.Second):
+					require.FailNow(t, "timed out waiting for message")
+				}
+			}
+			assert.Equal(t, 3, sender.Len(), "messages should still be sent to the logger's sender")
+		})
+		t.Run("MultipleSubscribers", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cl := &CachedLogger{Output: send.MakeInternalLogger()}
+			first := cl.Subscribe(ctx)
+			second := cl.Subscribe(ctx)
+			require.NoError(t, cl.Send(newPayload("hello")))
+
+			for _, msgs := range []<-chan message.Composer{first, second} {
+				require.Len(t, msgs, 1)
+				assert.Equal(t, "hello", (<-msgs).String())
+			}
+		})
+		t.Run("ClosesWhenContextIsDone", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cl := &CachedLogger{Output: send.MakeInternalLogger()}
+			msgs := cl.Subscribe(ctx)
+			cancel()
+
+			select {
+			case _, ok := <-msgs:
+				assert.False(t, ok)
+			case <-time.After(time.Second):
+				require.FailNow(t, "timed out waiting for subscription to close")
+			}
+			assert.NoError(t, cl.Send(newPayload("hello")))
+		})
+		t.Run("DropsMessagesForSlowSubscribers", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cl := &CachedLogger{Output: NewMockSender("output")}
+			msgs := cl.Subscribe(ctx)
+			for i := 0; i < LoggerSubscriptionBufferSize+10; i++ {
+				require.NoError(t, cl.Send(newPayload("hello")))
+			}
+
+			assert.Len(t, msgs, LoggerSubscriptionBufferSize)
+		})
+		t.Run("UnsentMessagesAreNotPublished", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cl := &CachedLogger{DropWhenUnconfigured: true}
+			msgs := cl.Subscribe(ctx)
+			require.NoError(t, cl.Send(newPayload("hello")))
+			assert.Len(t, msgs, 0)
+		})
+	})
 	t.Run("OutputTargeting", func(t *testing.T) {
 		output := send.MakeInternalLogger()
 		error := send.MakeInternalLogger()
@@ -177,6 +524,62 @@ func TestLoggingCache(t *testing.T) {
 					assert.Contains(t, string(raw), "metadata")
 				})
 			})
+			t.Run("ProcessMetadata", func(t *testing.T) {
+				proc := &LoggingPayloadProcess{ID: "foo", Tags: []string{"bar", "baz"}}
+				doc, err := bson.Marshal(map[string]string{"msg": "hello world!"})
+				require.NoError(t, err)
+
+				for format, data := range map[LoggingPayloadFormat][]byte{
+					LoggingPayloadFormatJSON: []byte(`{"msg":"hello world!"}`),
+					LoggingPayloadFormatBSON: doc,
+				} {
+					t.Run(string(format), func(t *testing.T) {
+						t.Run("AddsProcessFields", func(t *testing.T) {
+							lp := &LoggingPayload{Format: format, AddMetadata: true, Process: proc}
+							msg, err := lp.produceMessage(data)
+							require.NoError(t, err)
+
+							fields, ok := msg.Raw().(message.Fields)
+							require.True(t, ok)
+							assert.Equal(t, "hello world!", fields["msg"])
+							assert.Equal(t, message.Fields{
+								"process_id": "foo",
+								"tags":       []string{"bar", "baz"},
+							}, fields[LoggingPayloadProcessKey])
+						})
+						t.Run("RequiresAddMetadata", func(t *testing.T) {
+							lp := &LoggingPayload{Format: format, Process: proc}
+							msg, err := lp.produceMessage(data)
+							require.NoError(t, err)
+
+							fields, ok := msg.Raw().(message.Fields)
+							require.True(t, ok)
+							assert.NotContains(t, fields, LoggingPayloadProcessKey)
+						})
+						t.Run("ProcessWithoutTags", func(t *testing.T) {
+							lp := &LoggingPayload{Format: format, AddMetadata: true, Process: &LoggingPayloadProcess{ID: "foo"}}
+							msg, err := lp.produceMessage(data)
+							require.NoError(t, err)
+
+							fields, ok := msg.Raw().(message.Fields)
+							require.True(t, ok)
+							assert.Equal(t, message.Fields{
+								"process_id": "foo",
+								"tags":       []string{},
+							}, fields[LoggingPayloadProcessKey])
+						})
+					})
+				}
+				t.Run("ExistingFieldIsPreserved", func(t *testing.T) {
+					lp := &LoggingPayload{Format: LoggingPayloadFormatJSON, AddMetadata: true, Process: proc}
+					msg, err := lp.produceMessage([]byte(`{"msg":"hello world!","process":"mine"}`))
+					require.NoError(t, err)
+
+					fields, ok := msg.Raw().(message.Fields)
+					require.True(t, ok)
+					assert.Equal(t, "mine", fields[LoggingPayloadProcessKey])
+				})
+			})
 			t.Run("String", func(t *testing.T) {
 				lp := &LoggingPayload{Format: LoggingPayloadFormatSTRING}
 
@@ -253,6 +656,25 @@ func TestLoggingCache(t *testing.T) {
 					assert.Equal(t, "hello", group[0].String())
 					assert.Equal(t, "world", group[1].String())
 				})
+				t.Run("CRLFDelimiter", func(t *testing.T) {
+					lp.Delimiter = "\r\n"
+					defer func() { lp.Delimiter = "" }()
+					msg, err := lp.convertMultiMessage("hello\r\nworld\r\n!")
+					require.NoError(t, err)
+					group := requireIsGroup(t, 3, msg)
+					assert.Equal(t, "hello", group[0].String())
+					assert.Equal(t, "world", group[1].String())
+					assert.Equal(t, "!", group[2].String())
+				})
+				t.Run("CustomDelimiter", func(t *testing.T) {
+					lp.Delimiter = "\x1e"
+					defer func() { lp.Delimiter = "" }()
+					msg, err := lp.convertMultiMessage("hello\nworld\x1ejasper")
+					require.NoError(t, err)
+					group := requireIsGroup(t, 2, msg)
+					assert.Equal(t, "hello\nworld", group[0].String())
+					assert.Equal(t, "jasper", group[1].String())
+				})
 			})
 			t.Run("Byte", func(t *testing.T) {
 				t.Run("Strings", func(t *testing.T) {
@@ -263,6 +685,96 @@ func TestLoggingCache(t *testing.T) {
 					assert.Equal(t, "world", group[1].String())
 
 				})
+				t.Run("CRLFDelimiter", func(t *testing.T) {
+					lp.Delimiter = "\r\n"
+					defer func() { lp.Delimiter = "" }()
+					msg, err := lp.convertMultiMessage([]byte("hello\r\nworld"))
+					require.NoError(t, err)
+					group := requireIsGroup(t, 2, msg)
+					assert.Equal(t, "hello", group[0].String())
+					assert.Equal(t, "world", group[1].String())
+				})
+				t.Run("CustomDelimiter", func(t *testing.T) {
+					lp.Delimiter = "|"
+					defer func() { lp.Delimiter = "" }()
+					msg, err := lp.convertMultiMessage([]byte("hello\x00|world"))
+					require.NoError(t, err)
+					group := requireIsGroup(t, 2, msg)
+					assert.Equal(t, "hello\x00", group[0].String())
+					assert.Equal(t, "world", group[1].String())
+				})
+				t.Run("LengthPrefixed", func(t *testing.T) {
+					lp.Framing = LoggingPayloadFramingLengthPrefixed
+					defer func() { lp.Framing = "" }()
+					frame := func(data string) []byte {
+						out := make([]byte, 4, 4+len(data))
+						binary.BigEndian.PutUint32(out, uint32(len(data)))
+						return append(out, data...)
+					}
+
+					t.Run("ValidFrames", func(t *testing.T) {
+						var data []byte
+						for _, msg := range []string{"hello", "world\x00\n", "", "jasper"} {
+							data = append(data, frame(msg)...)
+						}
+
+						msg, err := lp.convertMultiMessage(data)
+						require.NoError(t, err)
+						group := requireIsGroup(t, 4, msg)
+						assert.Equal(t, "hello", group[0].String())
+						assert.Equal(t, "world\x00\n", group[1].String())
+						assert.Equal(t, "jasper", group[3].String())
+					})
+					t.Run("TruncatedTrailingFrame", func(t *testing.T) {
+						data := append(frame("hello"), frame("world")[:7]...)
+						_, err := lp.convertMultiMessage(data)
+						require.Error(t, err)
+						assert.Contains(t, err.Error(), "offset 9")
+					})
+					t.Run("TruncatedLengthPrefix", func(t *testing.T) {
+						data := append(frame("hello"), 0, 0)
+						_, err := lp.convertMultiMessage(data)
+						require.Error(t, err)
+						assert.Contains(t, err.Error(), "offset 9")
+					})
+					t.Run("IgnoresDelimiter", func(t *testing.T) {
+						lp.Delimiter = "l"
+						defer func() { lp.Delimiter = "" }()
+						msg, err := lp.convertMultiMessage(frame("hello"))
+						require.NoError(t, err)
+						group := requireIsGroup(t, 1, msg)
+						assert.Equal(t, "hello", group[0].String())
+					})
+				})
+				t.Run("BSONFraming", func(t *testing.T) {
+					lp.Framing = LoggingPayloadFramingBSON
+					defer func() { lp.Framing = "" }()
+					doc, err := bson.Marshal(map[string]interface{}{"msg": "hello world!"})
+					require.NoError(t, err)
+
+					msg, err := lp.convertMultiMessage(append(doc, doc...))
+					require.NoError(t, err)
+					requireIsGroup(t, 2, msg)
+				})
+				t.Run("NULFramingWithBSONFormat", func(t *testing.T) {
+					lp.Format = LoggingPayloadFormatBSON
+					lp.Framing = LoggingPayloadFramingNUL
+					lp.Delimiter = "|"
+					defer func() {
+						lp.Format = ""
+						lp.Framing = ""
+						lp.Delimiter = ""
+					}()
+					first, err := bson.Marshal(map[string]interface{}{"idx": 0})
+					require.NoError(t, err)
+					second, err := bson.Marshal(map[string]interface{}{"idx": 1})
+					require.NoError(t, err)
+
+					msg, err := lp.convertMultiMessage(append(append(first, '|'), second...))
+					require.NoError(t, err)
+					msgs := requireIsGroup(t, 2, msg)
+					assert.Contains(t, msgs[1].String(), "idx='1'")
+				})
 				t.Run("BSON", func(t *testing.T) {
 					lp.Format = LoggingPayloadFormatBSON
 					defer func() { lp.Format = "" }()
@@ -327,6 +839,190 @@ func TestLoggingCache(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Deduplicate", func(t *testing.T) {
+		repeatCount := func(t *testing.T, msg message.Composer) interface{} {
+			raw, err := json.Marshal(msg.Raw())
+			require.NoError(t, err)
+			out := struct {
+				Metadata struct {
+					Context map[string]interface{} `json:"context"`
+				} `json:"metadata"`
+			}{}
+			require.NoError(t, json.Unmarshal(raw, &out))
+			return out.Metadata.Context[LoggingPayloadRepeatCountKey]
+		}
+
+		for _, data := range []interface{}{
+			"a\na\na\nb\na\na\nc",
+			[]string{"a", "a", "a", "b", "a", "a", "c"},
+			[][]byte{[]byte("a"), []byte("a"), []byte("a"), []byte("b"), []byte("a"), []byte("a"), []byte("c")},
+		} {
+			t.Run(fmt.Sprintf("%T", data), func(t *testing.T) {
+				t.Run("CollapsesConsecutiveLines", func(t *testing.T) {
+					lp := &LoggingPayload{Data: data, IsMulti: true, Deduplicate: true, Priority: level.Info}
+					msg, err := lp.convert()
+					require.NoError(t, err)
+					msgs := requireIsGroup(t, 4, msg)
+
+					assert.Equal(t, "a", msgs[0].String())
+					assert.EqualValues(t, 3, repeatCount(t, msgs[0]))
+					assert.Equal(t, "b", msgs[1].String())
+					assert.Nil(t, repeatCount(t, msgs[1]))
+					assert.Equal(t, "a", msgs[2].String())
+					assert.EqualValues(t, 2, repeatCount(t, msgs[2]))
+					assert.Equal(t, "c", msgs[3].String())
+					assert.Nil(t, repeatCount(t, msgs[3]))
+				})
+				t.Run("DisabledByDefault", func(t *testing.T) {
+					lp := &LoggingPayload{Data: data, IsMulti: true, Priority: level.Info}
+					msg, err := lp.convert()
+					require.NoError(t, err)
+					msgs := requireIsGroup(t, 7, msg)
+					for _, m := range msgs {
+						assert.Nil(t, repeatCount(t, m))
+					}
+				})
+			})
+		}
+		t.Run("JSONFormat", func(t *testing.T) {
+			lp := &LoggingPayload{
+				Data:        []string{`{"msg":"a"}`, `{"msg":"a"}`, `{"msg":"b"}`},
+				Format:      LoggingPayloadFormatJSON,
+				IsMulti:     true,
+				Deduplicate: true,
+				Priority:    level.Info,
+			}
+			msg, err := lp.convert()
+			require.NoError(t, err)
+			msgs := requireIsGroup(t, 2, msg)
+
+			fields, ok := msgs[0].Raw().(message.Fields)
+			require.True(t, ok)
+			assert.Equal(t, "a", fields["msg"])
+			assert.EqualValues(t, 2, fields[LoggingPayloadRepeatCountKey])
+			fields, ok = msgs[1].Raw().(message.Fields)
+			require.True(t, ok)
+			assert.NotContains(t, fields, LoggingPayloadRepeatCountKey)
+		})
+	})
+	t.Run("Framing", func(t *testing.T) {
+		for _, framing := range []LoggingPayloadFraming{"", LoggingPayloadFramingNUL, LoggingPayloadFramingBSON, LoggingPayloadFramingLengthPrefixed} {
+			lp := &LoggingPayload{Data: "hello", Framing: framing}
+			assert.NoError(t, lp.Validate())
+		}
+		lp := &LoggingPayload{Data: "hello", Framing: "varint"}
+		assert.Error(t, lp.Validate())
+	})
+	t.Run("MaxPayloadBytes", func(t *testing.T) {
+		t.Run("NegativeIsInvalid", func(t *testing.T) {
+			lp := &LoggingPayload{Data: "hello", MaxPayloadBytes: -1}
+			assert.Error(t, lp.Validate())
+		})
+		t.Run("UnlimitedByDefault", func(t *testing.T) {
+			lp := &LoggingPayload{Data: string(make([]byte, 1024*1024))}
+			assert.NoError(t, lp.Validate())
+		})
+		for name, data := range map[string]interface{}{
+			"String":         "hello",
+			"ByteSlice":      []byte("hello"),
+			"StringSlice":    []string{"he", "llo"},
+			"MultiByteSlice": [][]byte{[]byte("he"), []byte("llo")},
+			"InterfaceSlice": []interface{}{"he", []byte("llo")},
+		} {
+			t.Run(name, func(t *testing.T) {
+				for _, isMulti := range []bool{true, false} {
+					t.Run(fmt.Sprintf("IsMulti%t", isMulti), func(t *testing.T) {
+						t.Run("AtCap", func(t *testing.T) {
+							lp := &LoggingPayload{Data: data, IsMulti: isMulti, MaxPayloadBytes: 5}
+							assert.NoError(t, lp.Validate())
+
+							sender := send.MakeInternalLogger()
+							cl := &CachedLogger{Output: sender}
+							assert.NoError(t, cl.Send(lp))
+							assert.True(t, sender.HasMessage())
+						})
+						t.Run("OverCap", func(t *testing.T) {
+							lp := &LoggingPayload{Data: data, IsMulti: isMulti, MaxPayloadBytes: 4}
+							err := lp.Validate()
+							require.Error(t, err)
+							tooLarge, ok := err.(*PayloadTooLargeError)
+							require.True(t, ok)
+							assert.Equal(t, 5, tooLarge.Size)
+							assert.Equal(t, 4, tooLarge.Max)
+
+							sender := send.MakeInternalLogger()
+							cl := &CachedLogger{Output: sender}
+							err = cl.Send(lp)
+							require.Error(t, err)
+							_, ok = errors.Cause(err).(*PayloadTooLargeError)
+							assert.True(t, ok)
+							assert.False(t, sender.HasMessage())
+						})
+					})
+				}
+			})
+		}
+	})
+}
+
+func TestLoggingPayloadPriorityJSON(t *testing.T) {
+	t.Run("MarshalsPriorityAsName", func(t *testing.T) {
+		lp := LoggingPayload{LoggerID: "foo", Data: "bar", Priority: level.Error}
+		data, err := json.Marshal(lp)
+		require.NoError(t, err)
+
+		out := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, "error", out["priority"])
+		assert.Equal(t, "foo", out["logger_id"])
+		assert.Equal(t, "bar", out["data"])
+	})
+	t.Run("RoundTripsName", func(t *testing.T) {
+		for _, pri := range []level.Priority{level.Emergency, level.Alert, level.Critical, level.Error, level.Warning, level.Notice, level.Info, level.Debug, level.Trace} {
+			lp := LoggingPayload{LoggerID: "foo", Data: "bar", Priority: pri, IsMulti: true}
+			data, err := json.Marshal(&lp)
+			require.NoError(t, err)
+
+			out := LoggingPayload{}
+			require.NoError(t, json.Unmarshal(data, &out))
+			assert.Equal(t, lp, out)
+		}
+	})
+	t.Run("RoundTripsUnnamedPriorityAsNumber", func(t *testing.T) {
+		lp := LoggingPayload{Data: "bar", Priority: level.Priority(45)}
+		data, err := json.Marshal(lp)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"priority":45`)
+
+		out := LoggingPayload{}
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, level.Priority(45), out.Priority)
+	})
+	t.Run("UnmarshalsNumericPriority", func(t *testing.T) {
+		out := LoggingPayload{}
+		require.NoError(t, json.Unmarshal([]byte(`{"logger_id":"foo","data":"bar","priority":40}`), &out))
+		assert.Equal(t, level.Info, out.Priority)
+		assert.Equal(t, "foo", out.LoggerID)
+		assert.Equal(t, "bar", out.Data)
+	})
+	t.Run("UnmarshalsNameCaseInsensitively", func(t *testing.T) {
+		out := LoggingPayload{}
+		require.NoError(t, json.Unmarshal([]byte(`{"priority":"Warning"}`), &out))
+		assert.Equal(t, level.Warning, out.Priority)
+	})
+	t.Run("MissingPriorityIsInvalid", func(t *testing.T) {
+		out := LoggingPayload{}
+		require.NoError(t, json.Unmarshal([]byte(`{"data":"bar"}`), &out))
+		assert.Equal(t, level.Invalid, out.Priority)
+	})
+	t.Run("UnrecognizedNameFails", func(t *testing.T) {
+		out := LoggingPayload{}
+		assert.Error(t, json.Unmarshal([]byte(`{"priority":"loud"}`), &out))
+	})
+	t.Run("NonNumericNonStringFails", func(t *testing.T) {
+		out := LoggingPayload{}
+		assert.Error(t, json.Unmarshal([]byte(`{"priority":true}`), &out))
+	})
 }
 
 type ex struct{}
@@ -340,3 +1036,23 @@ func requireIsGroup(t *testing.T, size int, msg message.Composer) []message.Comp
 	require.Len(t, msgs, size)
 	return msgs
 }
+
+// staticHandlerSender is a MockSender whose ErrorHandler returns the handler
+// itself, rather than a function that calls the current handler.
+type staticHandlerSender struct {
+	*MockSender
+	handler send.ErrorHandler
+}
+
+func (s *staticHandlerSender) SetErrorHandler(eh send.ErrorHandler) error {
+	s.handler = eh
+	return nil
+}
+
+func (s *staticHandlerSender) ErrorHandler() send.ErrorHandler { return s.handler }
+
+func (s *staticHandlerSender) Send(m message.Composer) {
+	if s.FailSend {
+		s.handler(errors.New("mock sender failed to send"), m)
+	}
+}
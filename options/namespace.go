@@ -0,0 +1,61 @@
+package options
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// NamespaceType is a type of Linux namespace in which a process can be
+// isolated.
+type NamespaceType string
+
+// Supported namespace types, which can be used in Create.Namespaces.
+const (
+	// NamespacePID isolates the process IDs visible to the process, so
+	// the process sees itself as PID 1.
+	NamespacePID NamespaceType = "pid"
+	// NamespaceMount isolates the mount points visible to the process.
+	NamespaceMount NamespaceType = "mount"
+	// NamespaceNetwork isolates the network devices, addresses and ports
+	// available to the process.
+	NamespaceNetwork NamespaceType = "network"
+	// NamespaceIPC isolates System V IPC objects and POSIX message queues.
+	NamespaceIPC NamespaceType = "ipc"
+	// NamespaceUTS isolates the hostname and domain name.
+	NamespaceUTS NamespaceType = "uts"
+	// NamespaceUser isolates the user and group IDs, which allows
+	// unprivileged users to create namespaces.
+	NamespaceUser NamespaceType = "user"
+)
+
+// Validate checks that the namespace type is recognized.
+func (t NamespaceType) Validate() error {
+	switch t {
+	case NamespacePID, NamespaceMount, NamespaceNetwork, NamespaceIPC, NamespaceUTS, NamespaceUser:
+		return nil
+	default:
+		return errors.Errorf("unrecognized namespace type '%s'", t)
+	}
+}
+
+// validateNamespaces checks that the namespace types are recognized and
+// unique, and that the caller is allowed to create them. Unless privileged,
+// callers can only create namespaces if the process is also started in a new
+// user namespace.
+func validateNamespaces(namespaces []NamespaceType, privileged bool) error {
+	catcher := grip.NewBasicCatcher()
+	seen := make(map[NamespaceType]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		catcher.Add(ns.Validate())
+		if _, ok := seen[ns]; ok {
+			catcher.Errorf("namespace type '%s' is specified more than once", ns)
+		}
+		seen[ns] = struct{}{}
+	}
+
+	if _, ok := seen[NamespaceUser]; !ok && len(seen) != 0 && !privileged {
+		catcher.New("unprivileged users can only create namespaces within a new user namespace")
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,24 @@
+// +build linux
+
+package options
+
+import "syscall"
+
+var namespaceCloneFlags = map[NamespaceType]uintptr{
+	NamespacePID:     syscall.CLONE_NEWPID,
+	NamespaceMount:   syscall.CLONE_NEWNS,
+	NamespaceNetwork: syscall.CLONE_NEWNET,
+	NamespaceIPC:     syscall.CLONE_NEWIPC,
+	NamespaceUTS:     syscall.CLONE_NEWUTS,
+	NamespaceUser:    syscall.CLONE_NEWUSER,
+}
+
+// namespaceAttributes returns the attributes that start a process in new
+// namespaces of the given types.
+func namespaceAttributes(namespaces []NamespaceType) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{}
+	for _, ns := range namespaces {
+		attr.Cloneflags |= namespaceCloneFlags[ns]
+	}
+	return attr
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package options
+
+import "syscall"
+
+// namespaceAttributes returns nil since namespaces are only supported on
+// Linux.
+func namespaceAttributes(_ []NamespaceType) *syscall.SysProcAttr {
+	return nil
+}
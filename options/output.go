@@ -1,15 +1,22 @@
 package options
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
 	"github.com/tychoish/jasper/util"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
 )
 
 // Output provides a common way to define and represent the
@@ -25,11 +32,93 @@ type Output struct {
 	// to. They are closed and cleaned up when the process exits. If this
 	// behavior is not desired, use Output instead of Loggers.
 	Loggers []*LoggerConfig `bson:"loggers" json:"loggers,omitempty" yaml:"loggers"`
-
-	outputSender *send.WriterSender
-	errorSender  *send.WriterSender
-	outputMulti  io.Writer
-	errorMulti   io.Writer
+	// Encoding is the name of the character encoding of the process'
+	// output (e.g. "windows-1252" or "gbk"). When specified, output
+	// and error are transcoded to UTF-8 before they are written or
+	// logged. Names are resolved using the WHATWG encoding
+	// standard.
+	Encoding string `bson:"encoding,omitempty" json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	// TreatStderrAsError classifies a process that exits successfully as
+	// having failed if it wrote anything to standard error.
+	TreatStderrAsError bool `bson:"treat_stderr_as_error,omitempty" json:"treat_stderr_as_error,omitempty" yaml:"treat_stderr_as_error,omitempty"`
+	// RecordPath, if set, is the path of a file to which a record of
+	// the process' output and error is written. The record can be
+	// replayed to a logger with ReplayOutput.
+	RecordPath string `bson:"record_path,omitempty" json:"record_path,omitempty" yaml:"record_path,omitempty"`
+	// OutputWriter and ErrorWriter, if set, receive a copy of the raw
+	// bytes that the process writes to standard output and error,
+	// respectively, before any transcoding or logging. Writes to them
+	// are synchronized, so the same writer may be used for both. Errors
+	// writing to them are logged but do not interrupt the process.
+	OutputWriter io.Writer `bson:"-" json:"-" yaml:"-"`
+	ErrorWriter  io.Writer `bson:"-" json:"-" yaml:"-"`
+	// ChecksumAlgorithm, if set, is the algorithm (e.g. "sha256") used
+	// to compute separate checksums of everything that the process
+	// writes to standard output and error, which are available from
+	// Checksum.
+	ChecksumAlgorithm string `bson:"checksum_algorithm,omitempty" json:"checksum_algorithm,omitempty" yaml:"checksum_algorithm,omitempty"`
+	// WatchLines allows the lines that the process writes to standard
+	// output and error to be matched as they are written using
+	// WatchOutput. Create.Resolve sets it if the process has a readiness
+	// probe that matches an output pattern.
+	WatchLines bool `bson:"watch_lines,omitempty" json:"watch_lines,omitempty" yaml:"watch_lines,omitempty"`
+	// WatchHistoryInitial and WatchHistoryMax are the initial and
+	// maximum number of the most recent lines of output and error that
+	// are kept for WatchOutput to match when WatchLines is set, which
+	// default to DefaultWatchHistoryInitial and DefaultWatchHistoryMax.
+	// The history doubles in size as it fills, up to WatchHistoryMax
+	// lines, after which the oldest lines are discarded. Once the
+	// process has not written a line for a minute, the history shrinks
+	// back to WatchHistoryInitial lines, keeping the most recent ones.
+	// These only size the watch history; output captured by loggers
+	// (e.g. the in-memory logger's InMemoryCap) is not affected.
+	WatchHistoryInitial int `bson:"watch_history_initial,omitempty" json:"watch_history_initial,omitempty" yaml:"watch_history_initial,omitempty"`
+	WatchHistoryMax     int `bson:"watch_history_max,omitempty" json:"watch_history_max,omitempty" yaml:"watch_history_max,omitempty"`
+	// StripPrefixPattern, if set, is matched against the beginning of
+	// each line of output and error before it is logged, and the
+	// matched prefix is removed (e.g. to avoid duplicating timestamps
+	// that the process already writes). Lines that do not begin with a
+	// match are logged unchanged. It does not affect the Output and
+	// Error writers.
+	StripPrefixPattern *regexp.Regexp `bson:"-" json:"-" yaml:"-"`
+	// StripANSI removes ANSI escape sequences, such as colors, from
+	// output and error before they are logged, and before any prefix is
+	// stripped. It does not affect the Output and Error writers.
+	StripANSI bool `bson:"strip_ansi,omitempty" json:"strip_ansi,omitempty" yaml:"strip_ansi,omitempty"`
+	// TimestampLines annotates each message logged from output and error
+	// with the time at which its line was read from the process, using
+	// the LineReadTimeMetadataKey key. Unlike the time of the message
+	// itself, which may only be recorded once the message is sent to the
+	// logger, this reflects when the process wrote the line. A final line
+	// without a trailing newline is read when the output is closed.
+	TimestampLines bool `bson:"timestamp_lines,omitempty" json:"timestamp_lines,omitempty" yaml:"timestamp_lines,omitempty"`
+	// MaxLinesPerSecond, if positive, limits the rate at which the lines
+	// that the process writes to standard output and error, combined,
+	// are read, allowing bursts of up to one second of lines. Output is
+	// not dropped: once the limit is reached, the process blocks when
+	// the pipes that its output is read from are full. The limit is
+	// lifted if the process's context is canceled.
+	MaxLinesPerSecond int `bson:"max_lines_per_second,omitempty" json:"max_lines_per_second,omitempty" yaml:"max_lines_per_second,omitempty"`
+
+	// priority, if set, overrides the priority of the messages that
+	// output and error are logged with.
+	priority      level.Priority
+	processTags   []string
+	outputCounter *byteCounter
+	errorCounter  *byteCounter
+	checksums     *outputChecksums
+	watcher       *outputWatcher
+	recorder      *outputRecorder
+	// outputSender and errorSender send the lines written to them to
+	// outputLogger and errorLogger, respectively.
+	outputSender    io.WriteCloser
+	errorSender     io.WriteCloser
+	outputLogger    send.Sender
+	errorLogger     send.Sender
+	outputMulti     io.Writer
+	errorMulti      io.Writer
+	outputTranscode *transform.Writer
+	errorTranscode  *transform.Writer
 }
 
 func (o Output) outputIsNull() bool {
@@ -97,9 +186,53 @@ func (o *Output) Validate() error {
 		catcher.Add(errors.New("cannot create redirect cycle between output and error"))
 	}
 
+	if o.MaxLinesPerSecond < 0 {
+		catcher.Add(errors.New("maximum lines per second cannot be negative"))
+	}
+
+	catcher.NewWhen(o.WatchHistoryInitial < 0, "initial watch history size cannot be negative")
+	catcher.NewWhen(o.WatchHistoryMax < 0, "maximum watch history size cannot be negative")
+	catcher.NewWhen(o.WatchHistoryMax > 0 && o.WatchHistoryInitial > o.WatchHistoryMax, "initial watch history size cannot exceed the maximum")
+
+	if o.Encoding != "" {
+		_, err := o.resolveEncoding()
+		catcher.Add(err)
+	}
+
+	if o.ChecksumAlgorithm != "" {
+		_, err := newChecksumHash(o.ChecksumAlgorithm)
+		catcher.Add(err)
+	}
+
 	return catcher.Resolve()
 }
 
+func (o *Output) resolveEncoding() (encoding.Encoding, error) {
+	enc, err := htmlindex.Get(o.Encoding)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown output encoding '%s'", o.Encoding)
+	}
+
+	return enc, nil
+}
+
+// transcode wraps the writer so that data written in the configured
+// encoding is converted to UTF-8. The returned transform.Writer is
+// nil if no encoding is configured.
+func (o *Output) transcode(wr io.Writer) (io.Writer, *transform.Writer, error) {
+	if o.Encoding == "" {
+		return wr, nil, nil
+	}
+
+	enc, err := o.resolveEncoding()
+	if err != nil {
+		return ioutil.Discard, nil, err
+	}
+
+	tw := transform.NewWriter(wr, enc.NewDecoder())
+	return tw, tw, nil
+}
+
 // GetOutput returns a Writer that has the stdout output from the process that
 // the Output that this method is called on is attached to. The caller is
 // responsible for calling Close when the loggers are not needed anymore.
@@ -137,16 +270,26 @@ func (o *Output) GetOutput() (io.Writer, error) {
 				return ioutil.Discard, err
 			}
 		}
-		o.outputSender = send.NewWriterSender(outMulti)
+		outMulti = o.stripANSI(o.stripPrefixes(o.timestampLines(o.tagMessages(outMulti))))
+		o.outputLogger = outMulti
+		o.outputSender = o.lineSender(outMulti)
 	}
 
+	var outMulti io.Writer
 	if !o.outputIsNull() && o.outputLogging() {
-		o.outputMulti = io.MultiWriter(o.Output, o.outputSender)
+		outMulti = io.MultiWriter(o.Output, o.outputSender)
 	} else if !o.outputIsNull() {
-		o.outputMulti = o.Output
+		outMulti = o.Output
 	} else {
-		o.outputMulti = o.outputSender
+		outMulti = o.outputSender
+	}
+
+	wr, transcoder, err := o.transcode(outMulti)
+	if err != nil {
+		return ioutil.Discard, err
 	}
+	o.outputTranscode = transcoder
+	o.outputMulti = wr
 
 	return o.outputMulti, nil
 }
@@ -181,21 +324,168 @@ func (o *Output) GetError() (io.Writer, error) {
 		if err != nil {
 			return ioutil.Discard, err
 		}
+		errMulti = o.stripANSI(o.stripPrefixes(o.timestampLines(o.tagMessages(errMulti))))
 		// This will not close the Loggers' underlying senders.
-		o.errorSender = send.NewWriterSender(errMulti)
+		o.errorLogger = errMulti
+		o.errorSender = o.lineSender(errMulti)
 	}
 
+	var errMulti io.Writer
 	if !o.errorIsNull() && o.errorLogging() {
-		o.errorMulti = io.MultiWriter(o.Error, o.errorSender)
+		errMulti = io.MultiWriter(o.Error, o.errorSender)
 	} else if !o.errorIsNull() {
-		o.errorMulti = o.Error
+		errMulti = o.Error
 	} else {
-		o.errorMulti = o.errorSender
+		errMulti = o.errorSender
+	}
+
+	wr, transcoder, err := o.transcode(errMulti)
+	if err != nil {
+		return ioutil.Discard, err
 	}
+	o.errorTranscode = transcoder
+	o.errorMulti = wr
 
 	return o.errorMulti, nil
 }
 
+// messagePriority returns the priority of the messages logged to the sender,
+// which is the sender's default priority unless it has been overridden.
+func (o *Output) messagePriority(sender send.Sender) level.Priority {
+	if o.priority.IsValid() {
+		return o.priority
+	}
+	return sender.Level().Default
+}
+
+// lineSender returns a writer that sends the lines written to it as messages
+// to the sender. Closing it sends any buffered output without closing the
+// sender. Unless TimestampLines is set, short lines may be buffered and sent
+// together.
+func (o *Output) lineSender(sender send.Sender) io.WriteCloser {
+	if o.TimestampLines {
+		return newLineWriter(sender, o.messagePriority(sender))
+	}
+	return send.MakeWriterSender(sender, o.messagePriority(sender))
+}
+
+// byteCounter is a writer that counts the bytes and lines written through
+// it.
+type byteCounter struct {
+	io.Writer
+	mu       sync.Mutex
+	count    int64
+	newlines int
+	partial  bool
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	n, err := c.Writer.Write(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count += int64(n)
+	if n > 0 {
+		c.newlines += bytes.Count(p[:n], []byte("\n"))
+		c.partial = p[n-1] != '\n'
+	}
+
+	return n, err
+}
+
+func (c *byteCounter) written() int64 {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// lines returns the number of lines written, including a final line that
+// does not end in a newline.
+func (c *byteCounter) lines() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.partial {
+		return c.newlines + 1
+	}
+	return c.newlines
+}
+
+// count wraps the process's standard output and error writers so that the
+// number of bytes and lines the process writes to each is recorded.
+func (o *Output) count(stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	o.outputCounter = &byteCounter{Writer: stdout}
+	o.errorCounter = &byteCounter{Writer: stderr}
+	return o.outputCounter, o.errorCounter
+}
+
+// OutputBytesWritten returns the number of bytes the process has written to
+// standard output.
+func (o *Output) OutputBytesWritten() int64 {
+	return o.outputCounter.written()
+}
+
+// OutputLinesWritten returns the number of lines the process has written to
+// standard output. A final line that does not end in a newline is counted.
+func (o *Output) OutputLinesWritten() int {
+	return o.outputCounter.lines()
+}
+
+// ErrorBytesWritten returns the number of bytes the process has written to
+// standard error.
+func (o *Output) ErrorBytesWritten() int64 {
+	return o.errorCounter.written()
+}
+
+// ErrorLinesWritten returns the number of lines the process has written to
+// standard error. A final line that does not end in a newline is counted.
+func (o *Output) ErrorLinesWritten() int {
+	return o.errorCounter.lines()
+}
+
+// teeWriter is a writer that copies the data written to it to a second
+// writer. Failures to write to the second writer are logged rather than
+// returned.
+type teeWriter struct {
+	io.Writer
+	tee    io.Writer
+	stream string
+	mu     *sync.Mutex
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	_, teeErr := w.tee.Write(p)
+	w.mu.Unlock()
+	grip.Warning(message.WrapError(teeErr, message.Fields{
+		"message": "problem writing raw process output",
+		"stream":  w.stream,
+	}))
+
+	return w.Writer.Write(p)
+}
+
+// tee wraps the process's standard output and error writers so that the
+// output is also written to OutputWriter and ErrorWriter, if they are set.
+func (o *Output) tee(stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	mu := &sync.Mutex{}
+	if o.OutputWriter != nil {
+		stdout = &teeWriter{Writer: stdout, tee: o.OutputWriter, stream: "output", mu: mu}
+	}
+	if o.ErrorWriter != nil {
+		stderr = &teeWriter{Writer: stderr, tee: o.ErrorWriter, stream: "error", mu: mu}
+	}
+
+	return stdout, stderr
+}
+
 // Copy returns a copy of the options for only the exported fields. Unexported
 // fields are cleared.
 func (o *Output) Copy() *Output {
@@ -203,8 +493,19 @@ func (o *Output) Copy() *Output {
 
 	optsCopy.outputSender = nil
 	optsCopy.errorSender = nil
+	optsCopy.outputLogger = nil
+	optsCopy.errorLogger = nil
 	optsCopy.outputMulti = nil
 	optsCopy.errorMulti = nil
+	optsCopy.outputTranscode = nil
+	optsCopy.errorTranscode = nil
+	optsCopy.outputCounter = nil
+	optsCopy.errorCounter = nil
+	optsCopy.checksums = nil
+	optsCopy.watcher = nil
+	optsCopy.recorder = nil
+	optsCopy.priority = level.Invalid
+	optsCopy.processTags = nil
 
 	if o.Loggers != nil {
 		optsCopy.Loggers = make([]*LoggerConfig, len(o.Loggers))
@@ -214,25 +515,55 @@ func (o *Output) Copy() *Output {
 	return &optsCopy
 }
 
+// Flush sends the output and error that the loggers have buffered so far,
+// such as a final line that does not end in a newline, to the underlying
+// senders. The loggers can still be written to afterwards.
+func (o *Output) Flush() error {
+	catcher := grip.NewBasicCatcher()
+	// Closing the line senders sends their buffered data without
+	// closing the underlying senders.
+	if o.outputSender != nil {
+		catcher.Wrap(o.outputSender.Close(), "problem flushing output sender")
+	}
+	if o.errorSender != nil && o.errorSender != o.outputSender {
+		catcher.Wrap(o.errorSender.Close(), "problem flushing error sender")
+	}
+	return catcher.Resolve()
+}
+
 // Close calls all of the processes' output senders' Close method.
 func (o *Output) Close() error {
 	catcher := grip.NewBasicCatcher()
+	// Flush any partially transcoded output before closing the
+	// senders.
+	if o.outputTranscode != nil {
+		catcher.Wrap(o.outputTranscode.Close(), "problem flushing transcoded output")
+	}
+	if o.errorTranscode != nil {
+		catcher.Wrap(o.errorTranscode.Close(), "problem flushing transcoded error")
+	}
 	// Close the outputSender and errorSender, which does not close the
-	// underlying send.Sender.
+	// loggers that they send to.
 	if o.outputSender != nil {
 		catcher.Wrap(o.outputSender.Close(), "problem closing output sender")
 	}
 	if o.errorSender != nil {
 		catcher.Wrap(o.errorSender.Close(), "problem closing error sender")
 	}
-	// Close the sender wrapped by the send.WriterSender.
-	if o.outputSender != nil {
-		catcher.Wrap(o.outputSender.Sender.Close(), "problem closing wrapped output sender")
+	// Close the loggers that the line senders send to.
+	if o.outputLogger != nil {
+		catcher.Wrap(o.outputLogger.Close(), "problem closing wrapped output sender")
 	}
 	// Since senders are shared, only close error's senders if output hasn't
 	// already closed them.
-	if o.errorSender != nil && (o.SuppressOutput || o.SendOutputToError) {
-		catcher.Wrap(o.errorSender.Sender.Close(), "problem closing wrapped error sender")
+	if o.errorLogger != nil && (o.SuppressOutput || o.SendOutputToError) {
+		catcher.Wrap(o.errorLogger.Close(), "problem closing wrapped error sender")
+	}
+	if o.recorder != nil {
+		catcher.Wrap(o.recorder.Close(), "problem closing output recorder")
+	}
+	if o.watcher != nil {
+		o.watcher.close()
 	}
 
 	return catcher.Resolve()
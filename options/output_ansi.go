@@ -0,0 +1,37 @@
+package options
+
+import (
+	"regexp"
+
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// ansiEscapePattern matches ANSI escape sequences, including control sequences
+// (e.g. colors and cursor movement), operating system commands (e.g. window
+// titles and hyperlinks) and other two-character escape sequences.
+var ansiEscapePattern = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// ansiStripSender is a sender that removes ANSI escape sequences from the
+// output sent to it.
+type ansiStripSender struct {
+	send.Sender
+}
+
+// Send removes the escape sequences from the message.
+func (s *ansiStripSender) Send(m message.Composer) {
+	msg := m.String()
+	if stripped := ansiEscapePattern.ReplaceAllString(msg, ""); stripped != msg {
+		m = message.NewDefaultMessage(m.Priority(), stripped)
+	}
+	s.Sender.Send(m)
+}
+
+// stripANSI wraps the sender so that ANSI escape sequences are removed from
+// the output sent to it, if StripANSI is set.
+func (o *Output) stripANSI(sender send.Sender) send.Sender {
+	if !o.StripANSI {
+		return sender
+	}
+	return &ansiStripSender{Sender: sender}
+}
@@ -0,0 +1,112 @@
+package options
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Algorithms that can be used to compute checksums of process output.
+const (
+	ChecksumMD5    = "md5"
+	ChecksumSHA1   = "sha1"
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumMD5:
+		return md5.New(), nil
+	case ChecksumSHA1:
+		return sha1.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, errors.Errorf("unsupported checksum algorithm '%s'", algorithm)
+	}
+}
+
+// OutputChecksum contains the hex-encoded checksums of everything that a
+// process wrote to standard output and error.
+type OutputChecksum struct {
+	Algorithm string `bson:"algorithm" json:"algorithm" yaml:"algorithm"`
+	Output    string `bson:"output" json:"output" yaml:"output"`
+	Error     string `bson:"error" json:"error" yaml:"error"`
+}
+
+// checksumWriter is a writer that computes a running checksum of the data
+// written through it. Since output and error may be written to the same
+// underlying writer, the checksum writers for a process share a mutex.
+type checksumWriter struct {
+	io.Writer
+	hash hash.Hash
+	mu   *sync.Mutex
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, _ = w.hash.Write(p)
+	return w.Writer.Write(p)
+}
+
+func (w *checksumWriter) sum() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return fmt.Sprintf("%x", w.hash.Sum(nil))
+}
+
+type outputChecksums struct {
+	algorithm string
+	output    *checksumWriter
+	error     *checksumWriter
+}
+
+// checksum wraps the process's standard output and error writers so that
+// checksums of the output and error are computed using ChecksumAlgorithm.
+func (o *Output) checksum(stdout, stderr io.Writer) (io.Writer, io.Writer, error) {
+	outputHash, err := newChecksumHash(o.ChecksumAlgorithm)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	errorHash, err := newChecksumHash(o.ChecksumAlgorithm)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	mu := &sync.Mutex{}
+	o.checksums = &outputChecksums{
+		algorithm: o.ChecksumAlgorithm,
+		output:    &checksumWriter{Writer: stdout, hash: outputHash, mu: mu},
+		error:     &checksumWriter{Writer: stderr, hash: errorHash, mu: mu},
+	}
+
+	return o.checksums.output, o.checksums.error, nil
+}
+
+// Checksum returns the checksums of the output and error that the process
+// has written so far. It returns nil if ChecksumAlgorithm is not set or the
+// process has not started.
+func (o *Output) Checksum() *OutputChecksum {
+	if o.checksums == nil {
+		return nil
+	}
+
+	return &OutputChecksum{
+		Algorithm: o.checksums.algorithm,
+		Output:    o.checksums.output.sum(),
+		Error:     o.checksums.error.sum(),
+	}
+}
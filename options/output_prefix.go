@@ -0,0 +1,44 @@
+package options
+
+import (
+	"strings"
+
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// prefixStripSender is a sender that removes the prefix matching the output
+// options' StripPrefixPattern from each line of output sent to it.
+type prefixStripSender struct {
+	send.Sender
+	output *Output
+}
+
+// Send strips the prefix from each line of the message. A single message may
+// contain several lines, since the output is buffered before it is sent.
+func (s *prefixStripSender) Send(m message.Composer) {
+	lines := strings.Split(m.String(), "\n")
+	stripped := false
+	for i, line := range lines {
+		loc := s.output.StripPrefixPattern.FindStringIndex(line)
+		if loc != nil && loc[0] == 0 && loc[1] > 0 {
+			lines[i] = line[loc[1]:]
+			stripped = true
+		}
+	}
+
+	if stripped {
+		m = message.NewDefaultMessage(m.Priority(), strings.Join(lines, "\n"))
+	}
+	s.Sender.Send(m)
+}
+
+// stripPrefixes wraps the sender so that the prefix matching
+// StripPrefixPattern is removed from each line sent to it, if a pattern is
+// set.
+func (o *Output) stripPrefixes(sender send.Sender) send.Sender {
+	if o.StripPrefixPattern == nil {
+		return sender
+	}
+	return &prefixStripSender{Sender: sender, output: o}
+}
@@ -0,0 +1,171 @@
+package options
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// Streams of process output in an output record.
+const (
+	outputRecordStreamOutput = "output"
+	outputRecordStreamError  = "error"
+)
+
+// outputRecordEvent is a single write of process output in an output
+// record. Records are framed as one JSON document per line.
+type outputRecordEvent struct {
+	Stream string        `json:"stream"`
+	Offset time.Duration `json:"offset"`
+	Data   []byte        `json:"data"`
+}
+
+// outputRecorder writes a record of the output events of a process to a file.
+type outputRecorder struct {
+	file  *os.File
+	enc   *json.Encoder
+	start time.Time
+	mu    sync.Mutex
+}
+
+func newOutputRecorder(path string) (*outputRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem creating output record file '%s'", path)
+	}
+
+	return &outputRecorder{
+		file:  file,
+		enc:   json.NewEncoder(file),
+		start: time.Now(),
+	}, nil
+}
+
+// writer returns a writer that writes to the given writer and records the
+// writes as events on the stream.
+func (r *outputRecorder) writer(stream string, wr io.Writer) io.Writer {
+	return &recordingWriter{recorder: r, stream: stream, writer: wr}
+}
+
+func (r *outputRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return errors.Wrap(r.file.Close(), "problem closing output record file")
+}
+
+type recordingWriter struct {
+	recorder *outputRecorder
+	stream   string
+	writer   io.Writer
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	// Writing and recording under the same lock ensures that events are
+	// recorded in the order that they are written.
+	w.recorder.mu.Lock()
+	defer w.recorder.mu.Unlock()
+
+	n, err := w.writer.Write(p)
+
+	grip.Error(message.WrapError(w.recorder.enc.Encode(outputRecordEvent{
+		Stream: w.stream,
+		Offset: time.Since(w.recorder.start),
+		Data:   p,
+	}), message.Fields{
+		"message": "problem recording output",
+		"path":    w.recorder.file.Name(),
+	}))
+
+	return n, err
+}
+
+// record wraps the process's standard output and error writers so that
+// the output is recorded to RecordPath.
+func (o *Output) record(stdout, stderr io.Writer) (io.Writer, io.Writer, error) {
+	recorder, err := newOutputRecorder(o.RecordPath)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	o.recorder = recorder
+
+	return recorder.writer(outputRecordStreamOutput, stdout), recorder.writer(outputRecordStreamError, stderr), nil
+}
+
+// ReplayOutput sends the process output recorded at the path (using
+// Output.RecordPath) to the logger, in order and with the original timing
+// between writes. Standard output is sent to the logger's output sender and
+// standard error to its error sender, falling back to the other sender if
+// only one is configured.
+func ReplayOutput(path string, logger *CachedLogger) error {
+	return ReplayOutputWithSpeed(path, logger, 1)
+}
+
+// ReplayOutputWithSpeed is the same as ReplayOutput, but the time between
+// writes is divided by the speed, so a speed of 2 replays the output twice as
+// fast as it was recorded. A speed of 0 replays the output without waiting
+// between writes.
+func ReplayOutputWithSpeed(path string, logger *CachedLogger, speed float64) error {
+	if speed < 0 {
+		return errors.New("replay speed cannot be negative")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening output record file '%s'", path)
+	}
+	defer file.Close()
+
+	logger.mu.RLock()
+	outputSender, outputErr := logger.getSender(LoggingStreamOutput)
+	errorSender, errorErr := logger.getSender(LoggingStreamError)
+	logger.mu.RUnlock()
+	if outputErr != nil || errorErr != nil {
+		return errors.New("cannot replay output to logger without output configured")
+	}
+
+	writers := map[string]*send.WriterSender{
+		outputRecordStreamOutput: send.MakeWriterSender(outputSender, level.Info),
+		outputRecordStreamError:  send.MakeWriterSender(errorSender, level.Error),
+	}
+
+	catcher := grip.NewBasicCatcher()
+	start := time.Now()
+	dec := json.NewDecoder(file)
+	for {
+		event := outputRecordEvent{}
+		if err = dec.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			catcher.Wrap(err, "problem reading output record event")
+			break
+		}
+
+		writer, ok := writers[event.Stream]
+		if !ok {
+			catcher.Errorf("unrecognized output record stream '%s'", event.Stream)
+			break
+		}
+
+		if speed > 0 {
+			time.Sleep(time.Until(start.Add(time.Duration(float64(event.Offset) / speed))))
+		}
+
+		_, err = writer.Write(event.Data)
+		catcher.Wrapf(err, "problem replaying %s", event.Stream)
+	}
+
+	// Flush any output that does not end in a newline.
+	catcher.Wrap(writers[outputRecordStreamOutput].Close(), "problem flushing replayed output")
+	catcher.Wrap(writers[outputRecordStreamError].Close(), "problem flushing replayed error")
+
+	return catcher.Resolve()
+}
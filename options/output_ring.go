@@ -0,0 +1,168 @@
+package options
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWatchHistoryInitial is the number of lines that the watch
+	// history can initially hold if Output.WatchHistoryInitial is not set.
+	DefaultWatchHistoryInitial = 64
+	// DefaultWatchHistoryMax is the maximum number of lines that the watch
+	// history can hold if Output.WatchHistoryMax is not set.
+	DefaultWatchHistoryMax = 1024
+	// outputRingIdleTimeout is how long the output ring must go without
+	// new lines before it shrinks back to its initial size.
+	outputRingIdleTimeout = time.Minute
+)
+
+// outputRing is a thread-safe ring buffer of the most recent lines of output.
+// It starts with room for a small number of lines and doubles in size as it
+// fills, up to a maximum, after which the oldest lines are overwritten. Once
+// no lines have been added for its idle timeout, it shrinks back to its
+// initial size, keeping only the most recent lines.
+type outputRing struct {
+	mu        sync.Mutex
+	buf       []string
+	start     int
+	size      int
+	initial   int
+	max       int
+	idle      time.Duration
+	lastAdded time.Time
+	timer     *time.Timer
+	closed    bool
+}
+
+func newOutputRing(initial, max int) *outputRing {
+	return &outputRing{
+		buf:     make([]string, initial),
+		initial: initial,
+		max:     max,
+		idle:    outputRingIdleTimeout,
+	}
+}
+
+// ringSize returns the initial and maximum number of lines of the watch
+// history's ring, using the defaults for the values that are not set.
+func (o *Output) ringSize() (int, int) {
+	max := o.WatchHistoryMax
+	if max == 0 {
+		max = DefaultWatchHistoryMax
+		if o.WatchHistoryInitial > max {
+			max = o.WatchHistoryInitial
+		}
+	}
+	initial := o.WatchHistoryInitial
+	if initial == 0 {
+		initial = DefaultWatchHistoryInitial
+		if initial > max {
+			initial = max
+		}
+	}
+	return initial, max
+}
+
+func (r *outputRing) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size == len(r.buf) {
+		if len(r.buf) < r.max {
+			capacity := 2 * len(r.buf)
+			if capacity > r.max {
+				capacity = r.max
+			}
+			r.resize(capacity)
+		} else {
+			r.buf[r.start] = line
+			r.start = (r.start + 1) % len(r.buf)
+			r.touch()
+			return
+		}
+	}
+
+	r.buf[(r.start+r.size)%len(r.buf)] = line
+	r.size++
+	r.touch()
+}
+
+// touch records that a line was added and, if the ring has grown, ensures
+// that it is checked for idleness. The ring's lock must be held.
+func (r *outputRing) touch() {
+	r.lastAdded = time.Now()
+	if r.timer == nil && !r.closed && len(r.buf) > r.initial {
+		r.timer = time.AfterFunc(r.idle, r.shrinkIfIdle)
+	}
+}
+
+// shrinkIfIdle shrinks the ring to its initial size if no lines have been
+// added for the idle timeout, or checks again once the timeout could have
+// elapsed.
+func (r *outputRing) shrinkIfIdle() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timer = nil
+	if r.closed {
+		return
+	}
+	if remaining := r.idle - time.Since(r.lastAdded); remaining > 0 {
+		r.timer = time.AfterFunc(remaining, r.shrinkIfIdle)
+		return
+	}
+
+	r.resize(r.initial)
+}
+
+// resize copies the most recent lines that fit into a buffer of the given
+// capacity. The ring's lock must be held.
+func (r *outputRing) resize(capacity int) {
+	buf := make([]string, capacity)
+	dropped := 0
+	if r.size > capacity {
+		dropped = r.size - capacity
+	}
+	for i := dropped; i < r.size; i++ {
+		buf[i-dropped] = r.buf[(r.start+i)%len(r.buf)]
+	}
+
+	r.buf = buf
+	r.start = 0
+	r.size -= dropped
+}
+
+// lines returns the lines in the ring, oldest first.
+func (r *outputRing) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]string, r.size)
+	for i := range out {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
+
+// capacity returns the number of lines that the ring can currently hold
+// before it must grow or overwrite lines.
+func (r *outputRing) capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.buf)
+}
+
+// close stops checking the ring for idleness, so that it keeps its lines
+// once no more output will be written.
+func (r *outputRing) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
@@ -2,12 +2,26 @@ package options
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
 )
 
@@ -252,6 +266,62 @@ func TestOutputOptions(t *testing.T) {
 			require.Equal(t, 1, len(logErr))
 			assert.Equal(t, msg, strings.Join(logErr, ""))
 		},
+		"UnknownEncodingIsInvalid": func(t *testing.T, opts Output) {
+			opts.Output = stdout
+			opts.Encoding = "not-an-encoding"
+			assert.Error(t, opts.Validate())
+			_, err := opts.GetOutput()
+			assert.Error(t, err)
+		},
+		"UnknownChecksumAlgorithmIsInvalid": func(t *testing.T, opts Output) {
+			opts.ChecksumAlgorithm = "crc32"
+			assert.Error(t, opts.Validate())
+		},
+		"KnownChecksumAlgorithmIsValid": func(t *testing.T, opts Output) {
+			for _, algorithm := range []string{ChecksumMD5, ChecksumSHA1, ChecksumSHA256, ChecksumSHA512} {
+				opts.ChecksumAlgorithm = algorithm
+				assert.NoError(t, opts.Validate(), algorithm)
+			}
+			assert.Nil(t, opts.Checksum(), "checksum should not be available before the process starts")
+		},
+		"KnownEncodingIsValid": func(t *testing.T, opts Output) {
+			opts.Output = stdout
+			for _, enc := range []string{"windows-1252", "cp1252", "gbk", "utf-8"} {
+				opts.Encoding = enc
+				assert.NoError(t, opts.Validate(), enc)
+			}
+		},
+		"OutputIsTranscodedToUTF8": func(t *testing.T, opts Output) {
+			buf := &bytes.Buffer{}
+			opts.Output = buf
+			opts.Encoding = "windows-1252"
+			out, err := opts.GetOutput()
+			require.NoError(t, err)
+
+			_, err = out.Write([]byte("caf\xe9 \x93quoted\x94"))
+			require.NoError(t, err)
+			require.NoError(t, opts.Close())
+
+			assert.True(t, utf8.Valid(buf.Bytes()))
+			assert.Equal(t, "café “quoted”", buf.String())
+		},
+		"ErrorIsTranscodedToUTF8": func(t *testing.T, opts Output) {
+			buf := &bytes.Buffer{}
+			opts.Error = buf
+			opts.Encoding = "gbk"
+			errOut, err := opts.GetError()
+			require.NoError(t, err)
+
+			// Split a multibyte character across writes.
+			_, err = errOut.Write([]byte{0xc4, 0xe3, 0xba})
+			require.NoError(t, err)
+			_, err = errOut.Write([]byte{0xc3})
+			require.NoError(t, err)
+			require.NoError(t, opts.Close())
+
+			assert.True(t, utf8.Valid(buf.Bytes()))
+			assert.Equal(t, "你好", buf.String())
+		},
 		// "": func(t *testing.T, opts Output) {}
 	}
 
@@ -283,3 +353,649 @@ func TestOutputIntegrationTableTest(t *testing.T) {
 	}
 
 }
+
+func TestOutputRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "output-record")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	recordPath := filepath.Join(dir, "record")
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
+
+	opts := Output{RecordPath: recordPath}
+	stdout, stderr, err := opts.record(stdoutBuf, stderrBuf)
+	require.NoError(t, err)
+
+	_, err = stdout.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = stderr.Write([]byte("second\n"))
+	require.NoError(t, err)
+	_, err = stdout.Write([]byte("third"))
+	require.NoError(t, err)
+	require.NoError(t, opts.Close())
+
+	assert.Equal(t, "first\nthird", stdoutBuf.String())
+	assert.Equal(t, "second\n", stderrBuf.String())
+
+	makeLogger := func(t *testing.T) (*CachedLogger, *send.InMemorySender, *send.InMemorySender) {
+		levelInfo := send.LevelInfo{Default: level.Info, Threshold: level.Trace}
+		outputSender, err := send.NewInMemorySender("output", levelInfo, 10)
+		require.NoError(t, err)
+		errorSender, err := send.NewInMemorySender("error", levelInfo, 10)
+		require.NoError(t, err)
+		return &CachedLogger{Output: outputSender, Error: errorSender}, outputSender.(*send.InMemorySender), errorSender.(*send.InMemorySender)
+	}
+
+	for _, speed := range []float64{0, 1, 100} {
+		t.Run(fmt.Sprintf("Speed%v", speed), func(t *testing.T) {
+			logger, outputSender, errorSender := makeLogger(t)
+			require.NoError(t, ReplayOutputWithSpeed(recordPath, logger, speed))
+
+			assert.Equal(t, []string{"first\nthird"}, messageStrings(outputSender))
+			assert.Equal(t, []string{"second"}, messageStrings(errorSender))
+		})
+	}
+	t.Run("DefaultSpeed", func(t *testing.T) {
+		logger, outputSender, _ := makeLogger(t)
+		require.NoError(t, ReplayOutput(recordPath, logger))
+
+		assert.Equal(t, []string{"first\nthird"}, messageStrings(outputSender))
+	})
+	t.Run("FailsWithNegativeSpeed", func(t *testing.T) {
+		logger, _, _ := makeLogger(t)
+		assert.Error(t, ReplayOutputWithSpeed(recordPath, logger, -1))
+	})
+	t.Run("FailsWithMissingRecord", func(t *testing.T) {
+		logger, _, _ := makeLogger(t)
+		assert.Error(t, ReplayOutput(filepath.Join(dir, "nonexistent"), logger))
+	})
+	t.Run("FailsWithoutSenders", func(t *testing.T) {
+		assert.Error(t, ReplayOutput(recordPath, &CachedLogger{}))
+	})
+	t.Run("FailsWithUnwritableRecordPath", func(t *testing.T) {
+		opts := Output{RecordPath: filepath.Join(dir, "nonexistent", "record")}
+		_, _, err := opts.record(stdoutBuf, stderrBuf)
+		assert.Error(t, err)
+	})
+}
+
+func TestOutputCount(t *testing.T) {
+	opts := &Output{}
+	assert.Zero(t, opts.OutputBytesWritten())
+	assert.Zero(t, opts.OutputLinesWritten())
+	assert.Zero(t, opts.ErrorBytesWritten())
+	assert.Zero(t, opts.ErrorLinesWritten())
+
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
+	stdout, stderr := opts.count(stdoutBuf, stderrBuf)
+
+	for _, data := range []string{"one\ntw", "o\n", "\nthree"} {
+		_, err := stdout.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, len("one\ntwo\n\nthree"), opts.OutputBytesWritten())
+	assert.Equal(t, 4, opts.OutputLinesWritten())
+	assert.Equal(t, "one\ntwo\n\nthree", stdoutBuf.String())
+
+	_, err := stderr.Write([]byte("error\n"))
+	require.NoError(t, err)
+	assert.EqualValues(t, len("error\n"), opts.ErrorBytesWritten())
+	assert.Equal(t, 1, opts.ErrorLinesWritten())
+
+	optsCopy := opts.Copy()
+	assert.Zero(t, optsCopy.OutputBytesWritten())
+	assert.Zero(t, optsCopy.ErrorLinesWritten())
+}
+
+func TestOutputThrottle(t *testing.T) {
+	lines := func(start, end int) string {
+		var buf strings.Builder
+		for i := start; i <= end; i++ {
+			fmt.Fprintf(&buf, "%d\n", i)
+		}
+		return buf.String()
+	}
+
+	t.Run("AllowsBurstWithoutWaiting", func(t *testing.T) {
+		opts := &Output{MaxLinesPerSecond: 10}
+		buf := &bytes.Buffer{}
+		stdout, _ := opts.throttle(nil, buf, ioutil.Discard)
+
+		start := time.Now()
+		_, err := stdout.Write([]byte(lines(1, 10)))
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) < 500*time.Millisecond)
+		assert.Equal(t, lines(1, 10), buf.String())
+	})
+	t.Run("LimitsLinesAfterBurst", func(t *testing.T) {
+		opts := &Output{MaxLinesPerSecond: 10}
+		stdoutBuf := &bytes.Buffer{}
+		stderrBuf := &bytes.Buffer{}
+		stdout, stderr := opts.throttle(nil, stdoutBuf, stderrBuf)
+
+		start := time.Now()
+		n, err := stdout.Write([]byte(lines(1, 15)))
+		require.NoError(t, err)
+		assert.Equal(t, len(lines(1, 15)), n)
+		_, err = stderr.Write([]byte(lines(16, 20)))
+		require.NoError(t, err)
+
+		elapsed := time.Since(start)
+		assert.True(t, elapsed >= 800*time.Millisecond, "ten lines beyond the burst should take about a second, took %s", elapsed)
+		assert.Equal(t, lines(1, 15), stdoutBuf.String())
+		assert.Equal(t, lines(16, 20), stderrBuf.String())
+	})
+	t.Run("DoesNotLimitPartialLines", func(t *testing.T) {
+		opts := &Output{MaxLinesPerSecond: 1}
+		buf := &bytes.Buffer{}
+		stdout, _ := opts.throttle(nil, buf, ioutil.Discard)
+
+		_, err := stdout.Write([]byte("one\n"))
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = stdout.Write([]byte("two"))
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) < 500*time.Millisecond)
+		assert.Equal(t, "one\ntwo", buf.String())
+	})
+	t.Run("StopsLimitingWhenDone", func(t *testing.T) {
+		opts := &Output{MaxLinesPerSecond: 1}
+		buf := &bytes.Buffer{}
+		done := make(chan struct{})
+		stdout, _ := opts.throttle(done, buf, ioutil.Discard)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			close(done)
+		}()
+		start := time.Now()
+		_, err := stdout.Write([]byte(lines(1, 100)))
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) < 2*time.Second)
+		assert.Equal(t, lines(1, 100), buf.String())
+	})
+	t.Run("NegativeLimitIsInvalid", func(t *testing.T) {
+		opts := &Output{MaxLinesPerSecond: -1}
+		assert.Error(t, opts.Validate())
+	})
+}
+
+func TestOutputWatchLines(t *testing.T) {
+	received := func(result <-chan bool) (bool, bool) {
+		select {
+		case ok := <-result:
+			return ok, true
+		default:
+			return false, false
+		}
+	}
+	makeOutput := func(t *testing.T) (*Output, io.Writer, io.Writer) {
+		opts := &Output{WatchLines: true}
+		stdout, stderr := opts.watchLines(&bytes.Buffer{}, &bytes.Buffer{})
+		return opts, stdout, stderr
+	}
+
+	t.Run("FailsWithoutWatchLines", func(t *testing.T) {
+		opts := &Output{}
+		_, _, err := opts.WatchOutput(regexp.MustCompile("foo"))
+		assert.Error(t, err)
+	})
+	t.Run("FailsBeforeStarting", func(t *testing.T) {
+		opts := &Output{WatchLines: true}
+		_, _, err := opts.WatchOutput(regexp.MustCompile("foo"))
+		assert.Error(t, err)
+	})
+	t.Run("FailsWithNilPattern", func(t *testing.T) {
+		opts, _, _ := makeOutput(t)
+		_, _, err := opts.WatchOutput(nil)
+		assert.Error(t, err)
+	})
+	t.Run("MatchesCompleteLines", func(t *testing.T) {
+		opts, stdout, stderr := makeOutput(t)
+		result, stop, err := opts.WatchOutput(regexp.MustCompile("^server started$"))
+		require.NoError(t, err)
+		defer stop()
+
+		_, err = stdout.Write([]byte("server "))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("unrelated\n"))
+		require.NoError(t, err)
+		_, ok := received(result)
+		assert.False(t, ok)
+
+		_, err = stdout.Write([]byte("started\r\nmore"))
+		require.NoError(t, err)
+		matched, ok := received(result)
+		require.True(t, ok)
+		assert.True(t, matched)
+	})
+	t.Run("MatchesHistory", func(t *testing.T) {
+		opts, stdout, _ := makeOutput(t)
+		_, err := stdout.Write([]byte("banner\n"))
+		require.NoError(t, err)
+
+		result, stop, err := opts.WatchOutput(regexp.MustCompile("banner"))
+		require.NoError(t, err)
+		defer stop()
+		matched, ok := received(result)
+		require.True(t, ok)
+		assert.True(t, matched)
+	})
+	t.Run("NewOutputIgnoresHistory", func(t *testing.T) {
+		opts, stdout, _ := makeOutput(t)
+		_, err := stdout.Write([]byte("reloaded\nrel"))
+		require.NoError(t, err)
+
+		result, stop, err := opts.WatchNewOutput(regexp.MustCompile("^reloaded$"))
+		require.NoError(t, err)
+		defer stop()
+		_, ok := received(result)
+		assert.False(t, ok)
+
+		_, err = stdout.Write([]byte("oaded\n"))
+		require.NoError(t, err)
+		matched, ok := received(result)
+		require.True(t, ok)
+		assert.True(t, matched)
+	})
+	t.Run("MatchesPartialLineOnClose", func(t *testing.T) {
+		opts, _, stderr := makeOutput(t)
+		result, stop, err := opts.WatchOutput(regexp.MustCompile("^done$"))
+		require.NoError(t, err)
+		defer stop()
+
+		_, err = stderr.Write([]byte("done"))
+		require.NoError(t, err)
+		require.NoError(t, opts.Close())
+		matched, ok := received(result)
+		require.True(t, ok)
+		assert.True(t, matched)
+	})
+	t.Run("ReportsNoMatchOnClose", func(t *testing.T) {
+		opts, stdout, _ := makeOutput(t)
+		result, stop, err := opts.WatchOutput(regexp.MustCompile("never"))
+		require.NoError(t, err)
+		defer stop()
+
+		_, err = stdout.Write([]byte("something else\n"))
+		require.NoError(t, err)
+		require.NoError(t, opts.Close())
+		matched, ok := received(result)
+		require.True(t, ok)
+		assert.False(t, matched)
+
+		result, _, err = opts.WatchOutput(regexp.MustCompile("never"))
+		require.NoError(t, err)
+		matched, ok = received(result)
+		require.True(t, ok)
+		assert.False(t, matched)
+	})
+}
+
+func TestOutputRing(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			opts  Output
+			valid bool
+		}{
+			"Defaults":              {opts: Output{}, valid: true},
+			"InitialBelowMax":       {opts: Output{WatchHistoryInitial: 8, WatchHistoryMax: 64}, valid: true},
+			"OnlyInitial":           {opts: Output{WatchHistoryInitial: 4096}, valid: true},
+			"NegativeInitial":       {opts: Output{WatchHistoryInitial: -1}},
+			"NegativeMax":           {opts: Output{WatchHistoryMax: -1}},
+			"InitialExceedsMaximum": {opts: Output{WatchHistoryInitial: 64, WatchHistoryMax: 8}},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				if testCase.valid {
+					assert.NoError(t, testCase.opts.Validate())
+				} else {
+					assert.Error(t, testCase.opts.Validate())
+				}
+			})
+		}
+	})
+	t.Run("Size", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			opts    Output
+			initial int
+			max     int
+		}{
+			"Defaults":        {opts: Output{}, initial: DefaultWatchHistoryInitial, max: DefaultWatchHistoryMax},
+			"Configured":      {opts: Output{WatchHistoryInitial: 8, WatchHistoryMax: 64}, initial: 8, max: 64},
+			"SmallMax":        {opts: Output{WatchHistoryMax: 16}, initial: 16, max: 16},
+			"LargeInitial":    {opts: Output{WatchHistoryInitial: 4096}, initial: 4096, max: 4096},
+			"OnlyMaxIsLarger": {opts: Output{WatchHistoryMax: 4096}, initial: DefaultWatchHistoryInitial, max: 4096},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				initial, max := testCase.opts.ringSize()
+				assert.Equal(t, testCase.initial, initial)
+				assert.Equal(t, testCase.max, max)
+			})
+		}
+	})
+	t.Run("GrowsUnderBurst", func(t *testing.T) {
+		ring := newOutputRing(4, 64)
+		assert.Equal(t, 4, ring.capacity())
+
+		const writers = 4
+		const linesPerWriter = 10
+		wg := &sync.WaitGroup{}
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(writer int) {
+				defer wg.Done()
+				for j := 0; j < linesPerWriter; j++ {
+					ring.add(fmt.Sprintf("%d-%d", writer, j))
+				}
+			}(i)
+		}
+		wg.Wait()
+		defer ring.close()
+
+		assert.Equal(t, 64, ring.capacity())
+		lines := ring.lines()
+		require.Len(t, lines, writers*linesPerWriter)
+		for i := 0; i < writers; i++ {
+			for j := 0; j < linesPerWriter; j++ {
+				assert.Contains(t, lines, fmt.Sprintf("%d-%d", i, j))
+			}
+		}
+	})
+	t.Run("GrowsByDoubling", func(t *testing.T) {
+		ring := newOutputRing(2, 100)
+		defer ring.close()
+
+		capacities := []int{}
+		for i := 0; i < 100; i++ {
+			ring.add(strconv.Itoa(i))
+			if capacity := ring.capacity(); len(capacities) == 0 || capacities[len(capacities)-1] != capacity {
+				capacities = append(capacities, capacity)
+			}
+		}
+		assert.Equal(t, []int{2, 4, 8, 16, 32, 64, 100}, capacities)
+	})
+	t.Run("RespectsCap", func(t *testing.T) {
+		ring := newOutputRing(2, 10)
+		defer ring.close()
+
+		for i := 0; i < 25; i++ {
+			ring.add(strconv.Itoa(i))
+		}
+		assert.Equal(t, 10, ring.capacity())
+		assert.Equal(t, []string{"15", "16", "17", "18", "19", "20", "21", "22", "23", "24"}, ring.lines())
+	})
+	t.Run("ShrinksWhenIdle", func(t *testing.T) {
+		ring := newOutputRing(2, 10)
+		ring.idle = 10 * time.Millisecond
+		defer ring.close()
+
+		for i := 0; i < 8; i++ {
+			ring.add(strconv.Itoa(i))
+		}
+		assert.Equal(t, 8, ring.capacity())
+
+		assert.Eventually(t, func() bool {
+			return ring.capacity() == 2
+		}, time.Second, ring.idle)
+		assert.Equal(t, []string{"6", "7"}, ring.lines())
+
+		ring.add("8")
+		assert.Equal(t, 4, ring.capacity())
+		assert.Equal(t, []string{"6", "7", "8"}, ring.lines())
+	})
+	t.Run("KeepsLinesAfterClose", func(t *testing.T) {
+		ring := newOutputRing(2, 10)
+		ring.idle = time.Millisecond
+		for i := 0; i < 8; i++ {
+			ring.add(strconv.Itoa(i))
+		}
+		ring.close()
+
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 8, ring.capacity())
+		assert.Len(t, ring.lines(), 8)
+	})
+	t.Run("LimitsWatchHistory", func(t *testing.T) {
+		opts := &Output{WatchLines: true, WatchHistoryInitial: 1, WatchHistoryMax: 2}
+		stdout, _ := opts.watchLines(&bytes.Buffer{}, &bytes.Buffer{})
+		_, err := stdout.Write([]byte("first\nsecond\nthird\n"))
+		require.NoError(t, err)
+		defer func() { assert.NoError(t, opts.Close()) }()
+
+		result, stop, err := opts.WatchOutput(regexp.MustCompile("^first$"))
+		require.NoError(t, err)
+		stop()
+		select {
+		case <-result:
+			assert.Fail(t, "line that was discarded from the ring should not match")
+		default:
+		}
+
+		result, stop, err = opts.WatchOutput(regexp.MustCompile("^second$"))
+		require.NoError(t, err)
+		defer stop()
+		select {
+		case matched := <-result:
+			assert.True(t, matched)
+		default:
+			assert.Fail(t, "line in the ring should match")
+		}
+	})
+}
+
+func TestOutputStripPrefixPattern(t *testing.T) {
+	timestamp := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z\s*`)
+	makeOutput := func(t *testing.T, pattern *regexp.Regexp) (*Output, *send.InMemorySender, *bytes.Buffer) {
+		sender, err := send.NewInMemorySender("strip", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 100)
+		require.NoError(t, err)
+		raw := &bytes.Buffer{}
+		opts := &Output{
+			Output:             raw,
+			StripPrefixPattern: pattern,
+			Loggers: []*LoggerConfig{{
+				info:   loggerConfigInfo{Type: LogInherited, Format: RawLoggerConfigFormatBSON},
+				sender: sender,
+			}},
+		}
+		return opts, sender.(*send.InMemorySender), raw
+	}
+	write := func(t *testing.T, wr io.Writer, data string) {
+		_, err := wr.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	// Short writes are buffered, so one message may contain several lines.
+	loggedLines := func(sender *send.InMemorySender) []string {
+		return strings.Split(strings.Join(messageStrings(sender), "\n"), "\n")
+	}
+
+	t.Run("RemovesMatchingPrefixes", func(t *testing.T) {
+		opts, sender, raw := makeOutput(t, timestamp)
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+		stderr, err := opts.GetError()
+		require.NoError(t, err)
+
+		write(t, stdout, "2021-01-02T03:04:05Z starting\nno timestamp here\n")
+		write(t, stdout, "2021-01-02T03:04:06Z done\n")
+		write(t, stderr, "2021-01-02T03:04:07Z warning\n")
+		require.NoError(t, opts.Close())
+
+		assert.ElementsMatch(t, []string{"starting", "no timestamp here", "done", "warning"}, loggedLines(sender))
+		assert.True(t, strings.HasPrefix(raw.String(), "2021-01-02T03:04:05Z starting\n"))
+	})
+	t.Run("IgnoresMatchesAfterStartOfLine", func(t *testing.T) {
+		opts, sender, _ := makeOutput(t, regexp.MustCompile(`\[\w+\] `))
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+
+		write(t, stdout, "[info] hello\nsay [info] hello\n")
+		require.NoError(t, opts.Close())
+
+		assert.Equal(t, []string{"hello", "say [info] hello"}, loggedLines(sender))
+	})
+	t.Run("PreservesProcessTags", func(t *testing.T) {
+		opts, sender, _ := makeOutput(t, timestamp)
+		opts.processTags = []string{"foo"}
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+
+		write(t, stdout, "2021-01-02T03:04:05Z tagged\n")
+		require.NoError(t, opts.Close())
+
+		msgs := sender.Get()
+		require.Len(t, msgs, 1)
+		assert.Equal(t, "tagged", msgs[0].String())
+		assert.Equal(t, []string{"foo"}, messageTags(msgs[0]))
+	})
+	t.Run("NilPatternDoesNotModifyOutput", func(t *testing.T) {
+		opts, sender, _ := makeOutput(t, nil)
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+
+		write(t, stdout, "2021-01-02T03:04:05Z unchanged\n")
+		require.NoError(t, opts.Close())
+
+		assert.Equal(t, []string{"2021-01-02T03:04:05Z unchanged"}, messageStrings(sender))
+	})
+}
+
+func TestOutputStripANSI(t *testing.T) {
+	const colorized = "\x1b[1;31merror:\x1b[0m \x1b[4mfile.go\x1b[24m failed\n\x1b]0;title\x07\x1b[32mok\x1b[m\n"
+	makeOutput := func(t *testing.T, strip bool) (*Output, *send.InMemorySender, *bytes.Buffer) {
+		sender, err := send.NewInMemorySender("ansi", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 100)
+		require.NoError(t, err)
+		raw := &bytes.Buffer{}
+		opts := &Output{
+			Output:    raw,
+			StripANSI: strip,
+			Loggers: []*LoggerConfig{{
+				info:   loggerConfigInfo{Type: LogInherited, Format: RawLoggerConfigFormatBSON},
+				sender: sender,
+			}},
+		}
+		return opts, sender.(*send.InMemorySender), raw
+	}
+	loggedLines := func(sender *send.InMemorySender) []string {
+		return strings.Split(strings.Join(messageStrings(sender), "\n"), "\n")
+	}
+
+	t.Run("RemovesEscapeSequencesWhenEnabled", func(t *testing.T) {
+		opts, sender, raw := makeOutput(t, true)
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+		stderr, err := opts.GetError()
+		require.NoError(t, err)
+
+		_, err = stdout.Write([]byte(colorized))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("\x1b[33mwarning\x1b[0m\n"))
+		require.NoError(t, err)
+		require.NoError(t, opts.Close())
+
+		assert.ElementsMatch(t, []string{"error: file.go failed", "ok", "warning"}, loggedLines(sender))
+		assert.Equal(t, colorized, raw.String())
+	})
+	t.Run("PreservesEscapeSequencesWhenDisabled", func(t *testing.T) {
+		opts, sender, _ := makeOutput(t, false)
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+
+		_, err = stdout.Write([]byte(colorized))
+		require.NoError(t, err)
+		require.NoError(t, opts.Close())
+
+		assert.Equal(t, strings.Split(strings.TrimSuffix(colorized, "\n"), "\n"), loggedLines(sender))
+	})
+	t.Run("StripsBeforePrefixPattern", func(t *testing.T) {
+		opts, sender, _ := makeOutput(t, true)
+		opts.StripPrefixPattern = regexp.MustCompile(`^\[\w+\] `)
+		stdout, err := opts.GetOutput()
+		require.NoError(t, err)
+
+		_, err = stdout.Write([]byte("\x1b[36m[info]\x1b[0m hello\n"))
+		require.NoError(t, err)
+		require.NoError(t, opts.Close())
+
+		assert.Equal(t, []string{"hello"}, loggedLines(sender))
+	})
+}
+
+func TestOutputTimestampLines(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runProcess := func(t *testing.T, timestamp bool) []message.Composer {
+		sender, err := send.NewInMemorySender("timestamps", send.LevelInfo{Default: level.Info, Threshold: level.Trace}, 100)
+		require.NoError(t, err)
+		opts := &Create{
+			Args: []string{"sh", "-c", "echo one; sleep 0.5; echo two >&2; sleep 0.5; echo three"},
+			Output: Output{
+				TimestampLines: timestamp,
+				Loggers: []*LoggerConfig{{
+					info:   loggerConfigInfo{Type: LogInherited, Format: RawLoggerConfigFormatBSON},
+					sender: sender,
+				}},
+			},
+		}
+		exe, _, err := opts.Resolve(ctx)
+		require.NoError(t, err)
+		require.NoError(t, exe.Start())
+		require.NoError(t, exe.Wait())
+		require.NoError(t, opts.Close())
+
+		return sender.(*send.InMemorySender).Get()
+	}
+	readTime := func(t *testing.T, msg message.Composer) (time.Time, bool) {
+		raw, err := json.Marshal(msg.Raw())
+		require.NoError(t, err)
+		var parsed struct {
+			Metadata struct {
+				Context map[string]time.Time `json:"context"`
+			} `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &parsed))
+		readAt, ok := parsed.Metadata.Context[LineReadTimeMetadataKey]
+		return readAt, ok
+	}
+
+	t.Run("ReflectsSpacingOfLines", func(t *testing.T) {
+		start := time.Now()
+		msgs := runProcess(t, true)
+		end := time.Now()
+		require.Len(t, msgs, 3)
+
+		var readTimes []time.Time
+		for _, msg := range msgs {
+			readAt, ok := readTime(t, msg)
+			require.True(t, ok, "message '%s' should have a read time", msg.String())
+			assert.False(t, readAt.Before(start))
+			assert.False(t, readAt.After(end))
+			readTimes = append(readTimes, readAt)
+		}
+		assert.Equal(t, []string{"one", "two", "three"}, []string{msgs[0].String(), msgs[1].String(), msgs[2].String()})
+		assert.True(t, readTimes[1].Sub(readTimes[0]) >= 400*time.Millisecond, "lines were read %s apart", readTimes[1].Sub(readTimes[0]))
+		assert.True(t, readTimes[2].Sub(readTimes[1]) >= 400*time.Millisecond, "lines were read %s apart", readTimes[2].Sub(readTimes[1]))
+	})
+	t.Run("IsNotAnnotatedWhenDisabled", func(t *testing.T) {
+		msgs := runProcess(t, false)
+		require.NotEmpty(t, msgs)
+		for _, msg := range msgs {
+			_, ok := readTime(t, msg)
+			assert.False(t, ok)
+		}
+	})
+}
+
+func messageStrings(sender *send.InMemorySender) []string {
+	var msgs []string
+	for _, msg := range sender.Get() {
+		msgs = append(msgs, msg.String())
+	}
+	return msgs
+}
@@ -0,0 +1,97 @@
+package options
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// lineLimiter is a token bucket that limits the rate at which lines are
+// written, allowing bursts of up to one second of lines. Once done is closed,
+// lines are no longer limited.
+type lineLimiter struct {
+	mu       sync.Mutex
+	rate     int
+	tokens   float64
+	last     time.Time
+	done     <-chan struct{}
+	interval time.Duration
+}
+
+func newLineLimiter(rate int, done <-chan struct{}) *lineLimiter {
+	return &lineLimiter{
+		rate:     rate,
+		tokens:   float64(rate),
+		last:     time.Now(),
+		done:     done,
+		interval: time.Second / time.Duration(rate),
+	}
+}
+
+// wait blocks until a line may be written. Waiting lines are served in
+// order, since the lock is held while waiting.
+func (l *lineLimiter) wait() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if l.tokens > float64(l.rate) {
+		l.tokens = float64(l.rate)
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		delay := time.Duration((1 - l.tokens) * float64(l.interval))
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-l.done:
+			return
+		case <-timer.C:
+		}
+		l.tokens++
+		l.last = time.Now()
+	}
+	l.tokens--
+}
+
+// throttledWriter is a writer that waits for the line limiter before writing
+// each complete line. Since the writer does not return until the lines have
+// been written, the process is blocked once the pipe that its output is read
+// from is full.
+type throttledWriter struct {
+	io.Writer
+	limiter *lineLimiter
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if idx := bytes.IndexByte(p, '\n'); idx >= 0 {
+			chunk = p[:idx+1]
+			w.limiter.wait()
+		}
+
+		n, err := w.Writer.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+	}
+
+	return written, nil
+}
+
+// throttle wraps the process's standard output and error writers so that
+// the lines written to them, combined, are limited to MaxLinesPerSecond. Once
+// done is closed, such as when the process is killed because its context is
+// canceled, the remaining output is written without limit.
+func (o *Output) throttle(done <-chan struct{}, stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	limiter := newLineLimiter(o.MaxLinesPerSecond, done)
+
+	return &throttledWriter{Writer: stdout, limiter: limiter}, &throttledWriter{Writer: stderr, limiter: limiter}
+}
@@ -0,0 +1,91 @@
+package options
+
+import (
+	"bytes"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tychoish/grip/level"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/send"
+)
+
+// LineReadTimeMetadataKey is the key of the annotation that contains the time
+// at which each line of the process' output or error was read, when
+// TimestampLines is set.
+const LineReadTimeMetadataKey = "read_time"
+
+// lineTimestampSender is a sender that annotates the messages sent to it with
+// the time at which they were sent.
+type lineTimestampSender struct {
+	send.Sender
+}
+
+func (s *lineTimestampSender) Send(m message.Composer) {
+	// Annotating only fails if the message already has a read time.
+	_ = m.Annotate(LineReadTimeMetadataKey, time.Now())
+	s.Sender.Send(m)
+}
+
+// timestampLines wraps the sender so that messages sent to it are annotated
+// with the time at which they were read, if TimestampLines is set.
+func (o *Output) timestampLines(sender send.Sender) send.Sender {
+	if !o.TimestampLines {
+		return sender
+	}
+	return &lineTimestampSender{Sender: sender}
+}
+
+// lineWriter is a writer that sends each line written to it to a sender as
+// soon as the line is complete, unlike send.WriterSender, which buffers
+// short lines.
+type lineWriter struct {
+	sender   send.Sender
+	priority level.Priority
+	buffer   []byte
+	mu       sync.Mutex
+}
+
+func newLineWriter(sender send.Sender, priority level.Priority) *lineWriter {
+	return &lineWriter{sender: sender, priority: priority}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+	for {
+		idx := bytes.IndexByte(w.buffer, '\n')
+		if idx < 0 {
+			break
+		}
+		w.send(w.buffer[:idx])
+		w.buffer = w.buffer[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+// send sends a copy of the line to the sender.
+func (w *lineWriter) send(line []byte) {
+	line = bytes.TrimRightFunc(line, unicode.IsSpace)
+	msg := make([]byte, len(line))
+	copy(msg, line)
+	w.sender.Send(message.NewBytesMessage(w.priority, msg))
+}
+
+// Close sends the final line, if it is incomplete, without closing the
+// sender.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buffer) != 0 {
+		w.send(w.buffer)
+		w.buffer = nil
+	}
+
+	return nil
+}
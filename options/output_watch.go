@@ -0,0 +1,195 @@
+package options
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// outputWatchMaxLineSize is the maximum size of a line of output that is
+// buffered while waiting for a newline. Longer lines are matched in pieces.
+const outputWatchMaxLineSize = 64 * 1024
+
+// outputWatch is a pattern that is waiting to match a line of output.
+type outputWatch struct {
+	pattern *regexp.Regexp
+	result  chan bool
+}
+
+// outputWatcher splits the output and error of a process into lines and
+// notifies the watches whose pattern matches a line. The most recent lines
+// are retained in the history so that a watch that is added after the process
+// has started can match lines written before it was added.
+type outputWatcher struct {
+	mu      sync.Mutex
+	partial map[string][]byte
+	history *outputRing
+	watches map[*outputWatch]struct{}
+	closed  bool
+}
+
+func newOutputWatcher(history *outputRing) *outputWatcher {
+	return &outputWatcher{
+		partial: map[string][]byte{},
+		history: history,
+		watches: map[*outputWatch]struct{}{},
+	}
+}
+
+// watchingWriter is a writer that passes the lines written through it to an
+// output watcher.
+type watchingWriter struct {
+	io.Writer
+	watcher *outputWatcher
+	stream  string
+}
+
+// Write writes to the underlying writer before scanning the data so that the
+// output has been written by the time a watch is notified of a match.
+func (w *watchingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.watcher.scan(w.stream, p)
+	return n, err
+}
+
+func (w *outputWatcher) scan(stream string, p []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	buf := append(w.partial[stream], p...)
+	for {
+		idx := bytes.IndexByte(buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.addLine(string(bytes.TrimSuffix(buf[:idx], []byte("\r"))))
+		buf = buf[idx+1:]
+	}
+	for len(buf) > outputWatchMaxLineSize {
+		w.addLine(string(buf[:outputWatchMaxLineSize]))
+		buf = buf[outputWatchMaxLineSize:]
+	}
+
+	w.partial[stream] = append([]byte(nil), buf...)
+}
+
+// addLine records the line and notifies the watches that match it. The
+// watcher's lock must be held.
+func (w *outputWatcher) addLine(line string) {
+	w.history.add(line)
+
+	for watch := range w.watches {
+		if watch.pattern.MatchString(line) {
+			watch.result <- true
+			delete(w.watches, watch)
+		}
+	}
+}
+
+// watch adds a watch for the pattern. If matchHistory is set, the lines that
+// have already been written are also matched.
+func (w *outputWatcher) watch(pattern *regexp.Regexp, matchHistory bool) (<-chan bool, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watch := &outputWatch{pattern: pattern, result: make(chan bool, 1)}
+	if matchHistory {
+		for _, line := range w.history.lines() {
+			if pattern.MatchString(line) {
+				watch.result <- true
+				return watch.result, func() {}
+			}
+		}
+	}
+	if w.closed {
+		watch.result <- false
+		return watch.result, func() {}
+	}
+
+	w.watches[watch] = struct{}{}
+
+	return watch.result, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.watches, watch)
+	}
+}
+
+// close matches any remaining partial lines and notifies the watches that
+// have not matched that no more output will be written.
+func (w *outputWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+
+	for _, stream := range []string{outputRecordStreamOutput, outputRecordStreamError} {
+		if len(w.partial[stream]) != 0 {
+			w.addLine(string(w.partial[stream]))
+		}
+	}
+	w.partial = map[string][]byte{}
+	w.history.close()
+	w.closed = true
+
+	for watch := range w.watches {
+		watch.result <- false
+		delete(w.watches, watch)
+	}
+}
+
+// watchLines wraps the process's standard output and error writers so that
+// their lines can be matched with WatchOutput. This is only done if
+// WatchLines is set. The lines are retained in a ring sized according to
+// WatchHistoryInitial and WatchHistoryMax.
+func (o *Output) watchLines(stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	o.watcher = newOutputWatcher(newOutputRing(o.ringSize()))
+
+	return &watchingWriter{Writer: stdout, watcher: o.watcher, stream: outputRecordStreamOutput},
+		&watchingWriter{Writer: stderr, watcher: o.watcher, stream: outputRecordStreamError}
+}
+
+// WatchOutput returns a channel that receives true once a line of the
+// process's output or error matches the pattern, or false if the output is
+// closed without a match. Lines are matched without their trailing newline.
+// The most recent lines written before WatchOutput is called are also
+// matched, so that output is not missed if the process writes it before the
+// caller begins watching. The returned function stops watching and should be
+// called once the caller is no longer interested in the result. It returns an
+// error if WatchLines is not set or the process has not started.
+func (o *Output) WatchOutput(pattern *regexp.Regexp) (<-chan bool, func(), error) {
+	return o.watchOutput(pattern, true)
+}
+
+// WatchNewOutput is the same as WatchOutput, except that only the lines
+// written after WatchNewOutput is called are matched. A line that is
+// partially written when WatchNewOutput is called can still match once it
+// is complete.
+func (o *Output) WatchNewOutput(pattern *regexp.Regexp) (<-chan bool, func(), error) {
+	return o.watchOutput(pattern, false)
+}
+
+func (o *Output) watchOutput(pattern *regexp.Regexp, matchHistory bool) (<-chan bool, func(), error) {
+	if pattern == nil {
+		return nil, nil, errors.New("must specify a pattern to watch for")
+	}
+	if !o.WatchLines {
+		return nil, nil, errors.New("watching output lines is not enabled")
+	}
+	if o.watcher == nil {
+		return nil, nil, errors.New("cannot watch output before the process has started")
+	}
+
+	result, stop := o.watcher.watch(pattern, matchHistory)
+	return result, stop, nil
+}
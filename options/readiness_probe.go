@@ -0,0 +1,127 @@
+package options
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+const (
+	// DefaultReadinessProbeInterval is the default time between readiness
+	// probes of a TCP address or URL.
+	DefaultReadinessProbeInterval = time.Second
+	// DefaultReadinessProbeTimeout is the default time that a process may
+	// take to become ready.
+	DefaultReadinessProbeTimeout = time.Minute
+)
+
+// ReadinessProbe configures a probe that determines when a process is ready,
+// such as when a service accepts connections. Exactly one of TCPAddress, URL
+// and OutputPattern must be specified.
+type ReadinessProbe struct {
+	// TCPAddress is an address that succeeds once it accepts a TCP
+	// connection.
+	TCPAddress string `bson:"tcp_address,omitempty" json:"tcp_address,omitempty" yaml:"tcp_address,omitempty"`
+	// URL is an HTTP URL that succeeds once a GET request to it returns a
+	// 2xx or 3xx status.
+	URL string `bson:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	// OutputPattern is a regular expression that succeeds once it
+	// matches a line of the process's output or error.
+	OutputPattern string `bson:"output_pattern,omitempty" json:"output_pattern,omitempty" yaml:"output_pattern,omitempty"`
+	// Interval is the time between probes of the TCP address or URL,
+	// which defaults to DefaultReadinessProbeInterval.
+	Interval time.Duration `bson:"interval,omitempty" json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Timeout is how long the process may take to become ready, which
+	// defaults to DefaultReadinessProbeTimeout. If the probe has not
+	// succeeded once it elapses, the process is killed and fails.
+	Timeout time.Duration `bson:"timeout,omitempty" json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// Validate ensures that the readiness probe specifies a single probe and that
+// its settings are valid.
+func (rp *ReadinessProbe) Validate() error {
+	probes := 0
+	for _, isSet := range []bool{rp.TCPAddress != "", rp.URL != "", rp.OutputPattern != ""} {
+		if isSet {
+			probes++
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(probes != 1, "must specify exactly one of a TCP address, URL or output pattern to probe")
+	if rp.OutputPattern != "" {
+		_, err := regexp.Compile(rp.OutputPattern)
+		catcher.Wrap(err, "invalid output pattern")
+	}
+	catcher.NewWhen(rp.Interval < 0, "interval cannot be negative")
+	catcher.NewWhen(rp.Timeout < 0, "timeout cannot be negative")
+	return catcher.Resolve()
+}
+
+// GetInterval returns the time between probes.
+func (rp *ReadinessProbe) GetInterval() time.Duration {
+	if rp.Interval == 0 {
+		return DefaultReadinessProbeInterval
+	}
+	return rp.Interval
+}
+
+// GetTimeout returns the time that the process may take to become ready.
+func (rp *ReadinessProbe) GetTimeout() time.Duration {
+	if rp.Timeout == 0 {
+		return DefaultReadinessProbeTimeout
+	}
+	return rp.Timeout
+}
+
+// Wait blocks until the probe succeeds or the context is done, probing the
+// TCP address or URL every interval or watching the output for the pattern.
+// The output must be the output of the running process.
+func (rp *ReadinessProbe) Wait(ctx context.Context, output *Output) error {
+	if rp.OutputPattern != "" {
+		pattern, err := regexp.Compile(rp.OutputPattern)
+		if err != nil {
+			return errors.Wrap(err, "invalid output pattern")
+		}
+		matched, stop, err := output.WatchOutput(pattern)
+		if err != nil {
+			return errors.Wrap(err, "problem watching process output")
+		}
+		defer stop()
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case ok := <-matched:
+			if !ok {
+				return errors.New("output closed without matching pattern")
+			}
+			return nil
+		}
+	}
+
+	hc := &HealthCheck{TCPAddress: rp.TCPAddress, URL: rp.URL, Interval: rp.GetInterval()}
+	ticker := time.NewTicker(rp.GetInterval())
+	defer ticker.Stop()
+	for {
+		err := hc.Probe(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(err, "readiness probe did not succeed")
+		case <-ticker.C:
+		}
+	}
+}
+
+// Copy returns a copy of the readiness probe.
+func (rp *ReadinessProbe) Copy() *ReadinessProbe {
+	rpCopy := *rp
+	return &rpCopy
+}
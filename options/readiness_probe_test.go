@@ -0,0 +1,127 @@
+package options
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessProbe(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			rp    ReadinessProbe
+			valid bool
+		}{
+			"TCPAddress":           {rp: ReadinessProbe{TCPAddress: "localhost:8080"}, valid: true},
+			"URL":                  {rp: ReadinessProbe{URL: "http://localhost:8080"}, valid: true},
+			"OutputPattern":        {rp: ReadinessProbe{OutputPattern: "^ready$"}, valid: true},
+			"NoProbe":              {rp: ReadinessProbe{}},
+			"MultipleProbes":       {rp: ReadinessProbe{TCPAddress: "localhost:8080", OutputPattern: "ready"}},
+			"InvalidOutputPattern": {rp: ReadinessProbe{OutputPattern: "("}},
+			"NegativeInterval":     {rp: ReadinessProbe{URL: "http://localhost:8080", Interval: -time.Second}},
+			"NegativeTimeout":      {rp: ReadinessProbe{URL: "http://localhost:8080", Timeout: -time.Second}},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				if testCase.valid {
+					assert.NoError(t, testCase.rp.Validate())
+				} else {
+					assert.Error(t, testCase.rp.Validate())
+				}
+			})
+		}
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		rp := ReadinessProbe{}
+		assert.Equal(t, DefaultReadinessProbeInterval, rp.GetInterval())
+		assert.Equal(t, DefaultReadinessProbeTimeout, rp.GetTimeout())
+	})
+	t.Run("ResolveEnablesWatchLinesForOutputPattern", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := &Create{Args: []string{"true"}, ReadinessProbe: &ReadinessProbe{OutputPattern: "ready"}}
+		require.NoError(t, opts.Validate())
+		assert.False(t, opts.Output.WatchLines)
+
+		cmd, _, err := opts.Resolve(ctx)
+		require.NoError(t, err)
+		assert.True(t, opts.Output.WatchLines)
+		require.NoError(t, cmd.Close())
+		require.NoError(t, opts.Close())
+
+		optsCopy := opts.Copy()
+		optsCopy.ReadinessProbe.OutputPattern = "other"
+		assert.Equal(t, "ready", opts.ReadinessProbe.OutputPattern)
+	})
+	t.Run("Wait", func(t *testing.T) {
+		const interval = 10 * time.Millisecond
+
+		t.Run("TCPAddress", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			listener, err := net.Listen("tcp", "localhost:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			assert.NoError(t, (&ReadinessProbe{TCPAddress: listener.Addr().String(), Interval: interval}).Wait(ctx, nil))
+		})
+		t.Run("URLRetriesUntilReady", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var requests int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+
+			assert.NoError(t, (&ReadinessProbe{URL: srv.URL, Interval: interval}).Wait(ctx, nil))
+			assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+		})
+		t.Run("URLFailsAtDeadline", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*interval)
+			defer cancel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer srv.Close()
+
+			assert.Error(t, (&ReadinessProbe{URL: srv.URL, Interval: interval}).Wait(ctx, nil))
+		})
+		t.Run("OutputPattern", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			output := &Output{WatchLines: true}
+			stdout, _ := output.watchLines(&bytes.Buffer{}, &bytes.Buffer{})
+			_, err := stdout.Write([]byte("starting\nready\n"))
+			require.NoError(t, err)
+
+			assert.NoError(t, (&ReadinessProbe{OutputPattern: "^ready$"}).Wait(ctx, output))
+		})
+		t.Run("OutputPatternFailsWhenOutputCloses", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			output := &Output{WatchLines: true}
+			stdout, _ := output.watchLines(&bytes.Buffer{}, &bytes.Buffer{})
+			_, err := stdout.Write([]byte("starting\n"))
+			require.NoError(t, err)
+			require.NoError(t, output.Close())
+
+			assert.Error(t, (&ReadinessProbe{OutputPattern: "^ready$"}).Wait(ctx, output))
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package options
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tychoish/grip"
+)
+
+// ResourceLimit describes the soft and hard values of a resource limit
+// (rlimit). Limits on memory are specified in bytes and are rounded up to
+// whole kibibytes, and limits on CPU time are specified in seconds. Use
+// ResourceLimitUnlimited to remove a limit.
+type ResourceLimit struct {
+	Soft uint64 `bson:"soft" json:"soft" yaml:"soft"`
+	Hard uint64 `bson:"hard" json:"hard" yaml:"hard"`
+}
+
+// Supported resource limit names, which can be used as keys in
+// Create.ResourceLimits.
+const (
+	// ResourceLimitCPU limits the CPU time of the process in seconds.
+	ResourceLimitCPU = "RLIMIT_CPU"
+	// ResourceLimitNumFiles limits the number of files that the process
+	// can have open at once.
+	ResourceLimitNumFiles = "RLIMIT_NOFILE"
+	// ResourceLimitAddressSpace limits the size of the process's virtual
+	// memory in bytes.
+	ResourceLimitAddressSpace = "RLIMIT_AS"
+	// ResourceLimitData limits the size of the process's data segment in
+	// bytes.
+	ResourceLimitData = "RLIMIT_DATA"
+	// ResourceLimitStack limits the size of the process's stack in bytes.
+	ResourceLimitStack = "RLIMIT_STACK"
+	// ResourceLimitLockedMemory limits the amount of memory that the
+	// process can lock in bytes.
+	ResourceLimitLockedMemory = "RLIMIT_MEMLOCK"
+)
+
+// ResourceLimitUnlimited is the value of a soft or hard resource limit that
+// does not limit the resource (RLIM_INFINITY).
+const ResourceLimitUnlimited uint64 = math.MaxUint64
+
+type resourceLimitFlag struct {
+	flag  string
+	scale uint64
+}
+
+// resourceLimitFlags maps the supported resource limits to the ulimit flags
+// used to set them and the scale of the ulimit flag's units. Only limits whose
+// units are the same across common shells are supported.
+var resourceLimitFlags = map[string]resourceLimitFlag{
+	ResourceLimitCPU:          {flag: "-t", scale: 1},
+	ResourceLimitNumFiles:     {flag: "-n", scale: 1},
+	ResourceLimitAddressSpace: {flag: "-v", scale: 1024},
+	ResourceLimitData:         {flag: "-d", scale: 1024},
+	ResourceLimitStack:        {flag: "-s", scale: 1024},
+	ResourceLimitLockedMemory: {flag: "-l", scale: 1024},
+}
+
+// format returns the limit in the ulimit flag's units, rounding up so that
+// small nonzero limits are not truncated to zero.
+func (f resourceLimitFlag) format(limit uint64) string {
+	if limit == ResourceLimitUnlimited {
+		return "unlimited"
+	}
+
+	scaled := limit / f.scale
+	if limit%f.scale != 0 {
+		scaled++
+	}
+	return strconv.FormatUint(scaled, 10)
+}
+
+func validateResourceLimits(limits map[string]ResourceLimit) error {
+	catcher := grip.NewBasicCatcher()
+	for name, limit := range limits {
+		if _, ok := resourceLimitFlags[name]; !ok {
+			catcher.Errorf("unsupported resource limit '%s'", name)
+			continue
+		}
+		catcher.ErrorfWhen(limit.Soft > limit.Hard, "soft limit (%d) for resource limit '%s' cannot exceed the hard limit (%d)", limit.Soft, name, limit.Hard)
+	}
+	return catcher.Resolve()
+}
+
+// wrapWithResourceLimits returns the arguments wrapped in a shell that applies
+// the resource limits before executing the command, so the limits only apply
+// to the child process. The limits must be valid.
+func wrapWithResourceLimits(limits map[string]ResourceLimit, args []string) []string {
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var script strings.Builder
+	for _, name := range names {
+		limit := limits[name]
+		flag := resourceLimitFlags[name]
+		soft := flag.format(limit.Soft)
+		hard := flag.format(limit.Hard)
+		// Lowering the hard limit below the current soft limit fails,
+		// so the soft limit is set both before and after the hard
+		// limit. The first attempt is allowed to fail in case the
+		// hard limit is being raised.
+		fmt.Fprintf(&script, "ulimit -S %[1]s %[2]s 2>/dev/null; ulimit -H %[1]s %[3]s || exit 1; ulimit -S %[1]s %[2]s || exit 1; ", flag.flag, soft, hard)
+	}
+	script.WriteString(`exec "$@"`)
+
+	wrapped := make([]string, 0, len(args)+4)
+	wrapped = append(wrapped, "/bin/sh", "-c", script.String(), "sh")
+	return append(wrapped, args...)
+}
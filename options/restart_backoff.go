@@ -0,0 +1,129 @@
+package options
+
+import (
+	"math"
+	"time"
+
+	"github.com/tychoish/grip"
+)
+
+const (
+	// RestartBackoffConstant waits the same delay before each restart.
+	RestartBackoffConstant = "constant"
+	// RestartBackoffExponential multiplies the delay before each
+	// consecutive restart, up to the maximum delay.
+	RestartBackoffExponential = "exponential"
+
+	// DefaultRestartBackoffMultiplier is the default factor by which
+	// exponential backoff increases the delay before each consecutive
+	// restart.
+	DefaultRestartBackoffMultiplier = 2
+	// DefaultRestartBackoffMaxDelay is the default maximum delay before
+	// a restart.
+	DefaultRestartBackoffMaxDelay = 5 * time.Minute
+	// DefaultRestartBackoffResetAfter is the default time that a process
+	// must run before its backoff is reset.
+	DefaultRestartBackoffResetAfter = time.Minute
+)
+
+// BackoffStrategy determines how long to wait before restarting a process.
+type BackoffStrategy interface {
+	// Delay returns the time to wait before the given consecutive
+	// restart, counting from zero.
+	Delay(attempt int) time.Duration
+}
+
+// ConstantBackoff is a BackoffStrategy that waits the same interval before
+// each restart.
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+// Delay returns the interval, regardless of the attempt.
+func (b ConstantBackoff) Delay(_ int) time.Duration { return b.Interval }
+
+// ExponentialBackoff is a BackoffStrategy that waits the initial delay before
+// the first restart, and multiplies the delay before each consecutive restart
+// up to the maximum delay.
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Delay returns the delay before the given attempt, which is at most the
+// maximum delay.
+func (b ExponentialBackoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt))
+	if delay >= float64(b.Max) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		return b.Max
+	}
+	return time.Duration(delay)
+}
+
+// RestartBackoff configures the delay before each restart of processes that
+// are restarted when they exit or when their health check fails. Consecutive
+// restarts are restarts of processes that ran for less than ResetAfter, which
+// defaults to DefaultRestartBackoffResetAfter; once a process runs for at
+// least that long, the backoff starts over.
+type RestartBackoff struct {
+	// Strategy is either RestartBackoffConstant or
+	// RestartBackoffExponential.
+	Strategy string `bson:"strategy" json:"strategy" yaml:"strategy"`
+	// Delay is the delay before the first restart, and before every
+	// restart if the strategy is constant.
+	Delay time.Duration `bson:"delay,omitempty" json:"delay,omitempty" yaml:"delay,omitempty"`
+	// MaxDelay caps the delay of exponential backoff, which defaults to
+	// DefaultRestartBackoffMaxDelay. Multiplier defaults to
+	// DefaultRestartBackoffMultiplier.
+	MaxDelay   time.Duration `bson:"max_delay,omitempty" json:"max_delay,omitempty" yaml:"max_delay,omitempty"`
+	Multiplier float64       `bson:"multiplier,omitempty" json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	ResetAfter time.Duration `bson:"reset_after,omitempty" json:"reset_after,omitempty" yaml:"reset_after,omitempty"`
+}
+
+// Validate ensures that the strategy is recognized and that its settings are
+// valid.
+func (b *RestartBackoff) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	switch b.Strategy {
+	case RestartBackoffConstant:
+	case RestartBackoffExponential:
+		catcher.NewWhen(b.Multiplier != 0 && b.Multiplier < 1, "multiplier must be at least 1")
+		catcher.ErrorfWhen(b.MaxDelay > 0 && b.MaxDelay < b.Delay, "max delay (%s) cannot be less than the delay (%s)", b.MaxDelay, b.Delay)
+	default:
+		catcher.Errorf("unrecognized restart backoff strategy '%s'", b.Strategy)
+	}
+	catcher.NewWhen(b.Delay < 0, "delay cannot be negative")
+	catcher.NewWhen(b.MaxDelay < 0, "max delay cannot be negative")
+	catcher.NewWhen(b.ResetAfter < 0, "reset after cannot be negative")
+	return catcher.Resolve()
+}
+
+// Resolve returns the backoff strategy described by the options.
+func (b *RestartBackoff) Resolve() BackoffStrategy {
+	if b.Strategy == RestartBackoffConstant {
+		return ConstantBackoff{Interval: b.Delay}
+	}
+
+	backoff := ExponentialBackoff{
+		Initial:    b.Delay,
+		Max:        b.MaxDelay,
+		Multiplier: b.Multiplier,
+	}
+	if backoff.Max == 0 {
+		backoff.Max = DefaultRestartBackoffMaxDelay
+	}
+	if backoff.Multiplier == 0 {
+		backoff.Multiplier = DefaultRestartBackoffMultiplier
+	}
+	return backoff
+}
+
+// GetResetAfter returns the time that a process must run before its backoff
+// is reset.
+func (b *RestartBackoff) GetResetAfter() time.Duration {
+	if b.ResetAfter == 0 {
+		return DefaultRestartBackoffResetAfter
+	}
+	return b.ResetAfter
+}
@@ -0,0 +1,65 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for testName, testCase := range map[string]struct {
+			backoff RestartBackoff
+			valid   bool
+		}{
+			"Constant":               {backoff: RestartBackoff{Strategy: RestartBackoffConstant, Delay: time.Second}, valid: true},
+			"Exponential":            {backoff: RestartBackoff{Strategy: RestartBackoffExponential, Delay: time.Second, MaxDelay: time.Minute, Multiplier: 1.5}, valid: true},
+			"ExponentialDefaults":    {backoff: RestartBackoff{Strategy: RestartBackoffExponential}, valid: true},
+			"NoStrategy":             {backoff: RestartBackoff{Delay: time.Second}},
+			"UnrecognizedStrategy":   {backoff: RestartBackoff{Strategy: "foo"}},
+			"NegativeDelay":          {backoff: RestartBackoff{Strategy: RestartBackoffConstant, Delay: -time.Second}},
+			"NegativeMaxDelay":       {backoff: RestartBackoff{Strategy: RestartBackoffExponential, MaxDelay: -time.Second}},
+			"NegativeResetAfter":     {backoff: RestartBackoff{Strategy: RestartBackoffConstant, ResetAfter: -time.Second}},
+			"MultiplierLessThanOne":  {backoff: RestartBackoff{Strategy: RestartBackoffExponential, Multiplier: 0.5}},
+			"MaxDelayLessThanDelay":  {backoff: RestartBackoff{Strategy: RestartBackoffExponential, Delay: time.Minute, MaxDelay: time.Second}},
+			"ConstantIgnoresMaximum": {backoff: RestartBackoff{Strategy: RestartBackoffConstant, Delay: time.Minute, MaxDelay: time.Second}, valid: true},
+		} {
+			t.Run(testName, func(t *testing.T) {
+				if testCase.valid {
+					assert.NoError(t, testCase.backoff.Validate())
+				} else {
+					assert.Error(t, testCase.backoff.Validate())
+				}
+			})
+		}
+	})
+	t.Run("ConstantDelayDoesNotChange", func(t *testing.T) {
+		backoff := (&RestartBackoff{Strategy: RestartBackoffConstant, Delay: time.Second}).Resolve()
+		for attempt := 0; attempt < 5; attempt++ {
+			assert.Equal(t, time.Second, backoff.Delay(attempt))
+		}
+	})
+	t.Run("ExponentialDelayIncreasesUpToMaximum", func(t *testing.T) {
+		backoff := (&RestartBackoff{
+			Strategy:   RestartBackoffExponential,
+			Delay:      time.Second,
+			MaxDelay:   10 * time.Second,
+			Multiplier: 3,
+		}).Resolve()
+		for attempt, expected := range []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 10 * time.Second, 10 * time.Second} {
+			assert.Equal(t, expected, backoff.Delay(attempt))
+		}
+		assert.Equal(t, 10*time.Second, backoff.Delay(10000))
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		backoff := &RestartBackoff{Strategy: RestartBackoffExponential, Delay: time.Second}
+		assert.Equal(t, DefaultRestartBackoffResetAfter, backoff.GetResetAfter())
+
+		exponential, ok := backoff.Resolve().(ExponentialBackoff)
+		require.True(t, ok)
+		assert.Equal(t, DefaultRestartBackoffMaxDelay, exponential.Max)
+		assert.EqualValues(t, DefaultRestartBackoffMultiplier, exponential.Multiplier)
+	})
+}
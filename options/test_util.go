@@ -14,6 +14,9 @@ import (
 type MockSender struct {
 	*send.Base
 	Closed bool
+	// FailSend causes Send to report an error to the sender's error
+	// handler.
+	FailSend bool
 }
 
 // NewMockSender returns a MockSender with the given name.
@@ -23,8 +26,13 @@ func NewMockSender(name string) *MockSender {
 	}
 }
 
-// Send noops.
-func (*MockSender) Send(_ message.Composer) {}
+// Send noops, unless FailSend is set, in which case it reports an error to
+// the sender's error handler.
+func (s *MockSender) Send(m message.Composer) {
+	if s.FailSend {
+		s.ErrorHandler()(errors.New("mock sender failed to send"), m)
+	}
+}
 
 // Flush noops.
 func (*MockSender) Flush(_ context.Context) error { return nil }
@@ -1,12 +1,17 @@
 package jasper
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
 	"github.com/tychoish/grip/send"
 	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/util"
 )
 
 // NewInMemoryLogger is a basic constructor that constructs a logger
@@ -26,6 +31,35 @@ func NewInMemoryLogger(maxSize int) (*options.LoggerConfig, error) {
 	return config, nil
 }
 
+// RunCommand creates a process with the manager, waits for it to complete,
+// and returns its combined standard output and error along with the error
+// from waiting on it, similarly to (*exec.Cmd).CombinedOutput. The output is
+// captured in addition to any loggers in the options, but replaces the
+// options' output and error writers. The given options are not modified.
+// Output cannot be captured for processes created by remote managers.
+func RunCommand(ctx context.Context, m Manager, opts *options.Create) ([]byte, error) {
+	if opts == nil {
+		return nil, errors.New("cannot run command with nil options")
+	}
+
+	output := util.NewLocalBuffer(bytes.Buffer{})
+	opts = opts.Copy()
+	opts.Output.Output = output
+	opts.Output.Error = output
+	opts.Output.SuppressOutput = false
+	opts.Output.SuppressError = false
+	opts.Output.SendOutputToError = false
+	opts.Output.SendErrorToOutput = false
+
+	proc, err := m.CreateProcess(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating process")
+	}
+
+	_, err = proc.Wait(ctx)
+	return []byte(output.String()), errors.Wrap(err, "problem running process")
+}
+
 // LogStream represents the output of reading the in-memory log buffer as a
 // stream, containing the logs (if any) and whether or not the stream is done
 // reading.
@@ -44,7 +78,81 @@ func GetInMemoryLogStream(ctx context.Context, proc Process, count int) ([]strin
 	if proc == nil {
 		return nil, errors.New("cannot get output logs from nil process")
 	}
-	for _, logger := range proc.Info(ctx).Options.Output.Loggers {
+
+	inMemorySender, err := getInMemorySender(proc.Info(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, _, err := inMemorySender.GetCount(count)
+	if err != nil {
+		if err != io.EOF {
+			err = errors.Wrap(err, "failed to get logs from in-memory stream")
+		}
+		return nil, err
+	}
+
+	return formatInMemoryMessages(inMemorySender, msgs)
+}
+
+// GetOutputReversed returns at most count of the most recent lines of output
+// from the in-memory output logs for the given Process proc, with the most
+// recent line first. If the logs contain fewer than count lines, all of them
+// are returned. Like GetInMemoryLogStream, this assumes that there is an
+// in-memory logger attached to the process's output, and does not work for
+// remote interfaces. Unlike GetInMemoryLogStream, this does not advance the
+// read position of the log stream.
+func GetOutputReversed(ctx context.Context, proc Process, count int) ([]string, error) {
+	if proc == nil {
+		return nil, errors.New("cannot get output logs from nil process")
+	}
+	if count <= 0 {
+		return nil, errors.New("count must be positive")
+	}
+
+	lines, err := getInMemoryLogTail(proc.Info(ctx), count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines, nil
+}
+
+// getInMemoryLogTail returns at most count of the most recent lines from the
+// in-memory output logger described by the process info. Unlike
+// GetInMemoryLogStream, this does not advance the read position of the log
+// stream.
+func getInMemoryLogTail(info ProcessInfo, count int) ([]string, error) {
+	inMemorySender, err := getInMemorySender(info)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := formatInMemoryMessages(inMemorySender, inMemorySender.Get())
+	if err != nil {
+		return nil, err
+	}
+
+	// A single log message may contain several lines of output.
+	lines := []string{}
+	for _, log := range logs {
+		lines = append(lines, strings.Split(strings.TrimRight(log, "\n"), "\n")...)
+	}
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+
+	return lines, nil
+}
+
+// getInMemorySender returns the first in-memory sender attached to the
+// process's output.
+func getInMemorySender(info ProcessInfo) (*send.InMemorySender, error) {
+	for _, logger := range info.Options.Output.Loggers {
 		if logger.Type() != options.LogInMemory {
 			continue
 		}
@@ -64,24 +172,35 @@ func GetInMemoryLogStream(ctx context.Context, proc Process, count int) ([]strin
 			continue
 		}
 
-		msgs, _, err := inMemorySender.GetCount(count)
+		return inMemorySender, nil
+	}
+	return nil, errors.New("could not find in-memory output logs")
+}
+
+func formatInMemoryMessages(sender *send.InMemorySender, msgs []message.Composer) ([]string, error) {
+	strs := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		str, err := sender.Formatter()(msg)
 		if err != nil {
-			if err != io.EOF {
-				err = errors.Wrap(err, "failed to get logs from in-memory stream")
-			}
 			return nil, err
 		}
+		strs = append(strs, str)
+	}
 
-		strs := make([]string, 0, len(msgs))
-		for _, msg := range msgs {
-			str, err := inMemorySender.Formatter()(msg)
-			if err != nil {
-				return nil, err
-			}
-			strs = append(strs, str)
-		}
+	return strs, nil
+}
 
-		return strs, nil
+// infoWith populates the optional fields of the process info according to
+// the options.
+func infoWith(info ProcessInfo, opts InfoOptions) ProcessInfo {
+	if opts.IncludeOutputTail > 0 {
+		tail, err := getInMemoryLogTail(info, opts.IncludeOutputTail)
+		grip.Debug(message.WrapError(err, message.Fields{
+			"message": "could not get output tail",
+			"process": info.ID,
+		}))
+		info.OutputTail = tail
 	}
-	return nil, errors.New("could not find in-memory output logs")
+
+	return info
 }
@@ -1,11 +1,13 @@
 package jasper
 
 import (
+	"bytes"
 	"context"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tychoish/jasper/options"
@@ -119,3 +121,189 @@ func TestGetInMemoryLogStream(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOutputReversed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, makeProc := range map[string]ProcessConstructor{
+		"Basic":    newBasicProcess,
+		"Blocking": newBlockingProcess,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			makeProcWithLogger := func(t *testing.T) Process {
+				logger, err := NewInMemoryLogger(100)
+				require.NoError(t, err)
+				opts := &options.Create{Args: []string{"sh", "-c", "for i in 1 2 3 4 5; do echo line$i; done"}}
+				opts.Output.Loggers = []*options.LoggerConfig{logger}
+
+				proc, err := makeProc(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+				return proc
+			}
+
+			t.Run("FailsWithNilProcess", func(t *testing.T) {
+				lines, err := GetOutputReversed(ctx, nil, 1)
+				assert.Error(t, err)
+				assert.Nil(t, lines)
+			})
+			t.Run("FailsWithInvalidCount", func(t *testing.T) {
+				lines, err := GetOutputReversed(ctx, makeProcWithLogger(t), 0)
+				assert.Error(t, err)
+				assert.Nil(t, lines)
+			})
+			t.Run("FailsWithoutInMemoryLogger", func(t *testing.T) {
+				proc, err := makeProc(ctx, &options.Create{Args: []string{"echo", "foo"}})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				lines, err := GetOutputReversed(ctx, proc, 1)
+				assert.Error(t, err)
+				assert.Nil(t, lines)
+			})
+			t.Run("ReturnsMostRecentLinesFirst", func(t *testing.T) {
+				lines, err := GetOutputReversed(ctx, makeProcWithLogger(t), 3)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"line5", "line4", "line3"}, lines)
+			})
+			t.Run("ReturnsAllLinesWhenFewerThanCount", func(t *testing.T) {
+				lines, err := GetOutputReversed(ctx, makeProcWithLogger(t), 10)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"line5", "line4", "line3", "line2", "line1"}, lines)
+			})
+			t.Run("DoesNotAdvanceLogStream", func(t *testing.T) {
+				proc := makeProcWithLogger(t)
+				_, err := GetOutputReversed(ctx, proc, 2)
+				require.NoError(t, err)
+
+				logs, err := GetInMemoryLogStream(ctx, proc, 100)
+				require.NoError(t, err)
+				assert.Equal(t, "line1\nline2\nline3\nline4\nline5", strings.TrimSpace(strings.Join(logs, "\n")))
+			})
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("failed write") }
+
+func TestOutputWriters(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	makeOpts := func(t *testing.T) *options.Create {
+		config := &options.LoggerConfig{}
+		require.NoError(t, config.Set(&options.InMemoryLoggerOptions{
+			InMemoryCap: 100,
+			Base: options.BaseOptions{
+				Format: options.LogFormatPlain,
+			},
+		}))
+		return &options.Create{
+			Args: []string{"sh", "-c", "echo foo; echo bar >&2"},
+			Output: options.Output{
+				Loggers: []*options.LoggerConfig{config},
+			},
+		}
+	}
+
+	for procType, makeProc := range map[string]ProcessConstructor{
+		"Basic":    newBasicProcess,
+		"Blocking": newBlockingProcess,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("ReceiveRawOutputAlongsideLogger", func(t *testing.T) {
+				opts := makeOpts(t)
+				output := &bytes.Buffer{}
+				errOutput := &bytes.Buffer{}
+				opts.Output.OutputWriter = output
+				opts.Output.ErrorWriter = errOutput
+
+				proc, err := makeProc(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				assert.Equal(t, "foo\n", output.String())
+				assert.Equal(t, "bar\n", errOutput.String())
+
+				logs, err := GetInMemoryLogStream(ctx, proc, 100)
+				require.NoError(t, err)
+				assert.Contains(t, logs, "foo")
+				assert.Contains(t, logs, "bar")
+			})
+			t.Run("ShareWriter", func(t *testing.T) {
+				opts := makeOpts(t)
+				output := &bytes.Buffer{}
+				opts.Output.OutputWriter = output
+				opts.Output.ErrorWriter = output
+
+				proc, err := makeProc(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				assert.Contains(t, output.String(), "foo\n")
+				assert.Contains(t, output.String(), "bar\n")
+			})
+			t.Run("WriteErrorsAreNotFatal", func(t *testing.T) {
+				opts := makeOpts(t)
+				opts.Output.OutputWriter = failingWriter{}
+
+				proc, err := makeProc(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				logs, err := GetInMemoryLogStream(ctx, proc, 100)
+				require.NoError(t, err)
+				assert.Contains(t, logs, "foo")
+			})
+		})
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			manager, err := NewSynchronizedManager(false)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, manager.Close(ctx))
+			}()
+
+			t.Run("ReturnsOutput", func(t *testing.T) {
+				opts := &options.Create{Args: []string{"echo", "hi"}, Implementation: impl}
+				output, err := RunCommand(ctx, manager, opts)
+				require.NoError(t, err)
+				assert.Equal(t, "hi\n", string(output))
+				assert.Nil(t, opts.Output.Output, "options should not be modified")
+			})
+			t.Run("ReturnsCombinedOutputAndErrorOfFailedCommand", func(t *testing.T) {
+				opts := &options.Create{
+					Args:           []string{"sh", "-c", "echo foo; echo bar >&2; exit 2"},
+					Implementation: impl,
+				}
+				output, err := RunCommand(ctx, manager, opts)
+				assert.Error(t, err)
+				assert.Contains(t, string(output), "foo\n")
+				assert.Contains(t, string(output), "bar\n")
+			})
+			t.Run("FailsWithInvalidOptions", func(t *testing.T) {
+				output, err := RunCommand(ctx, manager, &options.Create{Implementation: impl})
+				assert.Error(t, err)
+				assert.Empty(t, output)
+			})
+		})
+	}
+}
@@ -2,8 +2,15 @@ package jasper
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/recovery"
+	"github.com/tychoish/jasper/internal/executor"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -39,3 +46,215 @@ func NewProcess(ctx context.Context, opts *options.Create) (Process, error) {
 	}
 	return &synchronizedProcess{proc: proc}, nil
 }
+
+// classifyStderrAsError returns a ProcessError if the process completed
+// successfully but wrote to standard error and its output options treat
+// that as a failure.
+func classifyStderrAsError(info ProcessInfo) error {
+	if !info.Successful || !info.Options.Output.TreatStderrAsError {
+		return nil
+	}
+
+	written := info.Options.Output.ErrorBytesWritten()
+	if written == 0 {
+		return nil
+	}
+
+	return &ProcessError{
+		ID:       info.ID,
+		ExitCode: info.ExitCode,
+		Reason:   fmt.Sprintf("wrote %d bytes to standard error", written),
+	}
+}
+
+// withOutputCounts returns the info with the current number of bytes and
+// lines that the process has written to standard output and error.
+func withOutputCounts(info ProcessInfo) ProcessInfo {
+	output := &info.Options.Output
+	info.OutputBytes = output.OutputBytesWritten()
+	info.OutputLines = output.OutputLinesWritten()
+	info.ErrorBytes = output.ErrorBytesWritten()
+	info.ErrorLines = output.ErrorLinesWritten()
+	return info
+}
+
+// startExecutor starts the executor, running the options' pre-exec hooks
+// before starting it and its post-start hooks once it has started. If the
+// options specify a CPU affinity, it is applied once the process has started,
+// before the post-start hooks run. If it cannot be applied, the process is
+// killed.
+func startExecutor(opts *options.Create, exec executor.Executor) error {
+	for idx, hook := range opts.PreExec {
+		if err := hook(); err != nil {
+			return errors.Wrapf(err, "pre-exec hook %d failed", idx)
+		}
+	}
+
+	if err := exec.Start(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	pid := exec.PID()
+	if len(opts.CPUAffinity) != 0 {
+		if err := setCPUAffinity(pid, opts.CPUAffinity); err != nil {
+			catcher := grip.NewBasicCatcher()
+			catcher.Add(err)
+			catcher.Wrap(exec.Signal(syscall.SIGKILL), "problem killing process")
+			_ = exec.Wait()
+			return catcher.Resolve()
+		}
+	}
+
+	for _, hook := range opts.PostStart {
+		hook(pid)
+	}
+
+	return nil
+}
+
+// waitExecutor waits for the executor to finish. If waiting panics, the panic
+// is logged and returned as an error rather than crashing the program.
+func waitExecutor(exec executor.Executor) (err error) {
+	defer func() { err = recovery.HandlePanicWithError(recover(), err, "waiting for process") }()
+	return exec.Wait()
+}
+
+// waitContext returns a context for waiting on a process that is done once
+// the wait timeout elapses, if it is positive.
+func waitContext(ctx context.Context, waitTimeout time.Duration) (context.Context, context.CancelFunc) {
+	if waitTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, waitTimeout)
+}
+
+// waitCanceledError returns the error for a wait that stopped because its
+// wait context is done. If the caller's context is not done, the wait timed
+// out according to the process's wait timeout.
+func waitCanceledError(ctx context.Context, msg string) error {
+	if ctx.Err() == nil {
+		return context.DeadlineExceeded
+	}
+	return errors.New(msg)
+}
+
+// RedactedEnvValue replaces the values of the environment variables listed
+// in (options.Create).RedactedEnvironment in the commands reported by
+// Process.ResolvedCommand.
+const RedactedEnvValue = "<redacted>"
+
+// resolvedCommand is the command line that a process was created with.
+type resolvedCommand struct {
+	args []string
+	env  []string
+	dir  string
+}
+
+// newResolvedCommand records the command line of the executor, redacting the
+// environment variables that the options specify.
+func newResolvedCommand(exec executor.Executor, opts *options.Create) resolvedCommand {
+	return resolvedCommand{
+		args: append([]string{}, exec.Args()...),
+		env:  redactEnv(exec.Env(), opts.RedactedEnvironment),
+		dir:  exec.Dir(),
+	}
+}
+
+// get returns copies of the command's arguments and environment, along with
+// its working directory.
+func (c resolvedCommand) get() ([]string, []string, string) {
+	return append([]string{}, c.args...), append([]string{}, c.env...), c.dir
+}
+
+// redactEnv returns a copy of the environment, in which the values of the
+// given variables are replaced with RedactedEnvValue.
+func redactEnv(env []string, redacted []string) []string {
+	redactedKeys := make(map[string]struct{}, len(redacted))
+	for _, key := range redacted {
+		redactedKeys[key] = struct{}{}
+	}
+
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		key := kv
+		if idx := strings.Index(kv, "="); idx >= 0 {
+			key = kv[:idx]
+		}
+		if _, ok := redactedKeys[key]; ok {
+			kv = key + "=" + RedactedEnvValue
+		}
+		out = append(out, kv)
+	}
+
+	return out
+}
+
+var (
+	// ErrProcessComplete is returned by Process.Signal when the process has
+	// already terminated, so that callers racing with the process's exit
+	// can distinguish it from a failure to deliver the signal.
+	ErrProcessComplete = errors.New("cannot signal a process that has terminated")
+	// ErrProcessNotStarted is returned by Process.Signal when the process
+	// has not been started.
+	ErrProcessNotStarted = errors.New("cannot signal a process that has not started")
+	// ErrProcessNotFound is returned by Manager.Get when the manager does
+	// not have a process with the given ID.
+	ErrProcessNotFound = errors.New("process does not exist")
+)
+
+// processChildPIDs returns the PIDs of all descendants of the local process
+// described by the info.
+func processChildPIDs(info ProcessInfo) ([]int, error) {
+	switch {
+	case info.Options.Remote != nil || info.Options.Docker != nil:
+		return nil, errors.New("cannot get child PIDs of remote processes")
+	case info.NotStarted:
+		return nil, errors.New("cannot get child PIDs of a process that has not started")
+	case info.Complete:
+		return nil, errors.New("cannot get child PIDs of a process that has terminated")
+	}
+
+	return childPIDs(info.PID)
+}
+
+// abortableProcess is implemented by processes that record that they were
+// terminated because their manager was closed.
+type abortableProcess interface {
+	markAborted()
+}
+
+// endReason determines why a completed process stopped running, given
+// whether it was terminated by a signal, whether its manager aborted it,
+// whether it was killed because it did not become ready, and the error of the
+// context used to create it.
+func endReason(info ProcessInfo, signaled, aborted, notReady bool, ctxErr error) EndReason {
+	switch {
+	case info.Timeout:
+		return EndReasonTimedOut
+	case notReady:
+		return EndReasonNotReady
+	case aborted:
+		return EndReasonAborted
+	case ctxErr != nil && !info.Successful:
+		return EndReasonContextCanceled
+	case signaled:
+		return EndReasonSignaled
+	default:
+		return EndReasonExited
+	}
+}
+
+// appendSignalHistory returns the signal history with the signal added,
+// discarding the oldest events beyond SignalHistoryLimit. The history is
+// copied rather than modified in place, since it may be shared with
+// previously returned process info.
+func appendSignalHistory(history []SignalHistoryEvent, sig syscall.Signal) []SignalHistoryEvent {
+	if len(history) >= SignalHistoryLimit {
+		history = history[len(history)-SignalHistoryLimit+1:]
+	}
+
+	return append(append(make([]SignalHistoryEvent, 0, len(history)+1), history...), SignalHistoryEvent{
+		Signal: sig,
+		Time:   time.Now(),
+	})
+}
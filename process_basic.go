@@ -3,6 +3,7 @@ package jasper
 import (
 	"context"
 	"os"
+	"regexp"
 	"runtime"
 	"sync"
 	"syscall"
@@ -20,15 +21,30 @@ type basicProcess struct {
 	exec           executor.Executor
 	err            error
 	id             string
-	tags           map[string]struct{}
+	tags           *tagSet
 	triggers       ProcessTriggerSequence
+	postTriggers   ProcessTriggerSequence
+	ctxTriggers    contextTriggers
+	namedTriggers  map[string]ProcessTrigger
 	signalTriggers SignalTriggerSequence
 	waitProcessed  chan struct{}
+	aborted        bool
+	// readinessErr is the error from the readiness probe, if the process
+	// was killed because it did not become ready.
+	readinessErr error
+	// start starts the process. It is nil once the process has been
+	// started.
+	start func() error
+	// command is the command line that the process was created with.
+	command resolvedCommand
 	sync.RWMutex
 }
 
 func newBasicProcess(ctx context.Context, opts *options.Create) (Process, error) {
-	id := uuid.New().String()
+	id := opts.ID
+	if id == "" {
+		id = uuid.New().String()
+	}
 	opts.AddEnvVar(EnvironID, id)
 
 	exec, deadline, err := opts.Resolve(ctx)
@@ -39,14 +55,12 @@ func newBasicProcess(ctx context.Context, opts *options.Create) (Process, error)
 	p := &basicProcess{
 		id:            id,
 		exec:          exec,
-		tags:          make(map[string]struct{}),
+		command:       newResolvedCommand(exec, opts),
+		tags:          newTagSet(opts.Tags),
+		namedTriggers: make(map[string]ProcessTrigger),
 		waitProcessed: make(chan struct{}),
 	}
 
-	for _, t := range opts.Tags {
-		p.tags[t] = struct{}{}
-	}
-
 	if err = p.RegisterTrigger(ctx, makeOptionsCloseTrigger()); err != nil {
 		catcher := grip.NewBasicCatcher()
 		catcher.Add(err)
@@ -55,30 +69,73 @@ func newBasicProcess(ctx context.Context, opts *options.Create) (Process, error)
 		return nil, errors.Wrap(catcher.Resolve(), "problem registering options close trigger")
 	}
 
-	if err = exec.Start(); err != nil {
-		catcher := grip.NewBasicCatcher()
-		catcher.Add(err)
-		catcher.Wrap(opts.Close(), "problem closing options")
-		catcher.Wrap(exec.Close(), "problem closing executor")
-		return nil, errors.Wrap(catcher.Resolve(), "problem starting process execution")
-	}
-
-	p.info.StartAt = time.Now()
 	p.info.ID = p.id
 	p.info.Options = *opts
+	p.info.Options.Tags = p.tags.list()
 	if opts.Remote != nil {
 		p.info.Host = opts.Remote.Host
 	} else {
 		p.info.Host, _ = os.Hostname()
 	}
-	p.info.IsRunning = true
-	p.info.PID = exec.PID()
 
-	go p.transition(ctx, deadline)
+	p.start = func() error {
+		if opts.DeferStart {
+			deadline = opts.StartTimeout()
+		}
+		if err := startExecutor(opts, exec); err != nil {
+			catcher := grip.NewBasicCatcher()
+			catcher.Add(err)
+			catcher.Wrap(opts.Close(), "problem closing options")
+			catcher.Wrap(exec.Close(), "problem closing executor")
+			return newStartError(err, errors.Wrap(catcher.Resolve(), "problem starting process execution"))
+		}
+
+		p.info.StartAt = time.Now()
+		p.info.NotStarted = false
+		p.info.IsRunning = opts.ReadinessProbe == nil
+		p.info.PID = exec.PID()
+
+		go p.transition(ctx, deadline)
+		if opts.ReadinessProbe != nil {
+			go p.awaitReadiness(ctx, opts)
+		}
+
+		return nil
+	}
+
+	if opts.DeferStart {
+		p.info.NotStarted = true
+		return p, nil
+	}
+
+	if err = p.Start(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	return p, nil
 }
 
+func (p *basicProcess) Start(_ context.Context) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.start == nil {
+		return errors.New("cannot start a process that has already been started")
+	}
+
+	start := p.start
+	p.start = nil
+	return start()
+}
+
+func (p *basicProcess) ResolvedCommand() ([]string, []string, string) {
+	return p.command.get()
+}
+
+func (p *basicProcess) ChildPIDs(ctx context.Context) ([]int, error) {
+	return processChildPIDs(p.Info(ctx))
+}
+
 func (p *basicProcess) transition(ctx context.Context, deadline time.Time) {
 	defer p.exec.Close()
 
@@ -86,20 +143,28 @@ func (p *basicProcess) transition(ctx context.Context, deadline time.Time) {
 
 	go func() {
 		defer close(waitFinished)
-		waitFinished <- p.exec.Wait()
+		waitFinished <- waitExecutor(p.exec)
 	}()
 
+	var postTriggers ProcessTriggerSequence
+	var postInfo ProcessInfo
 	finish := func(err error) {
 		p.Lock()
 		defer p.Unlock()
 		defer close(p.waitProcessed)
 		finishTime := time.Now()
+		// Waiting on the process reads all of the output that it wrote,
+		// even if it was signaled, but the senders may still buffer
+		// some of it.
+		grip.Warning(errors.Wrap(p.info.Options.Output.Flush(), "problem flushing process output"))
 		p.err = err
 		p.info.EndAt = finishTime
 		p.info.IsRunning = false
 		p.info.Complete = true
-		if sig, signaled := p.exec.SignalInfo(); signaled {
+		sig, signaled := p.exec.SignalInfo()
+		if signaled {
 			p.info.ExitCode = int(sig)
+			p.info.Signal = sig
 			if !deadline.IsZero() {
 				p.info.Timeout = sig == syscall.SIGKILL && finishTime.After(deadline)
 			}
@@ -111,9 +176,61 @@ func (p *basicProcess) transition(ctx context.Context, deadline time.Time) {
 			}
 		}
 		p.info.Successful = p.exec.Success()
-		p.triggers.Run(p.info)
+		p.info.CrashInfo = newCrashInfo(&p.info.Options, p.exec)
+		p.info.OutputTruncated = p.info.Timeout
+		p.info.EndReason = endReason(p.info, signaled, p.aborted, p.readinessErr != nil, ctx.Err())
+		p.info.OutputChecksum = p.info.Options.Output.Checksum()
+		p.info = withOutputCounts(p.info)
+		if err == nil {
+			if procErr := classifyStderrAsError(p.info); procErr != nil {
+				p.err = procErr
+				p.info.Successful = false
+			}
+		}
+		if p.readinessErr != nil {
+			catcher := grip.NewBasicCatcher()
+			catcher.Add(p.err)
+			catcher.Add(p.readinessErr)
+			p.err = catcher.Resolve()
+			p.info.Successful = false
+		}
+		if err := p.triggers.runRecovered(p.info); err != nil {
+			catcher := grip.NewBasicCatcher()
+			catcher.Add(p.err)
+			catcher.Wrap(err, "process trigger panicked")
+			p.err = catcher.Resolve()
+			p.info.Successful = false
+		}
+		postTriggers, postInfo = p.postTriggers, p.info
 	}
 	finish(<-waitFinished)
+
+	// Post triggers start once Wait has been released.
+	go postTriggers.runRecovered(postInfo)
+}
+
+// awaitReadiness marks the process as running once its readiness probe
+// succeeds, or kills it if the probe times out. The process is already killed
+// if the context is done.
+func (p *basicProcess) awaitReadiness(ctx context.Context, opts *options.Create) {
+	err := awaitReadiness(ctx, opts, p.waitProcessed)
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return
+	}
+	if err == nil {
+		p.info.IsRunning = true
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.readinessErr = err
+	grip.Warning(errors.Wrapf(p.exec.Signal(syscall.SIGKILL), "problem killing process '%s' that did not become ready", p.id))
 }
 
 func (p *basicProcess) ID() string {
@@ -123,32 +240,133 @@ func (p *basicProcess) Info(_ context.Context) ProcessInfo {
 	p.RLock()
 	defer p.RUnlock()
 
-	return p.info
+	return withOutputCounts(p.info)
+}
+
+func (p *basicProcess) InfoWith(ctx context.Context, opts InfoOptions) ProcessInfo {
+	return infoWith(p.Info(ctx), opts)
+}
+
+func (p *basicProcess) markAborted() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.aborted = true
+}
+
+func (p *basicProcess) setDependencies(deps []DependencyInfo) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.info.Dependencies = deps
+}
+
+// failBeforeStart completes a process that was created with DeferStart
+// without starting it. It does nothing if the process has already been
+// started or completed.
+func (p *basicProcess) failBeforeStart(err error) {
+	p.Lock()
+	if p.info.Complete || !p.info.NotStarted {
+		p.Unlock()
+		return
+	}
+
+	p.start = nil
+	grip.Warning(errors.Wrap(p.exec.Close(), "problem closing executor"))
+	p.err = err
+	p.info.EndAt = time.Now()
+	p.info.Complete = true
+	p.info.Successful = false
+	p.info.EndReason = EndReasonDependencyFailed
+	if triggerErr := p.triggers.runRecovered(p.info); triggerErr != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(p.err)
+		catcher.Wrap(triggerErr, "process trigger panicked")
+		p.err = catcher.Resolve()
+	}
+	postTriggers, postInfo := p.postTriggers, p.info
+	close(p.waitProcessed)
+	p.Unlock()
+
+	// Post triggers start once Wait has been released.
+	go postTriggers.runRecovered(postInfo)
 }
 
-func (p *basicProcess) Complete(ctx context.Context) bool {
-	return !p.Running(ctx)
+func (p *basicProcess) setHealth(healthy bool, failures int) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.info.Healthy = healthy
+	p.info.HealthCheckFailures = failures
 }
 
+func (p *basicProcess) Complete(_ context.Context) bool {
+	p.RLock()
+	defer p.RUnlock()
+	return p.info.Complete
+}
+
+// Running reports whether the process has started and not yet completed.
+// Unlike its info, it reports that the process is running while it is waiting
+// for its readiness probe to succeed, so that it can still be signaled.
 func (p *basicProcess) Running(_ context.Context) bool {
 	p.RLock()
 	defer p.RUnlock()
-	return p.info.IsRunning
+	return !p.info.NotStarted && !p.info.Complete
 }
 
 func (p *basicProcess) Signal(_ context.Context, sig syscall.Signal) error {
+	sent, triggerErr, err := p.signal(sig)
+	if err != nil {
+		return err
+	}
+
+	if sent {
+		p.Lock()
+		p.info.SignalHistory = appendSignalHistory(p.info.SignalHistory, makeCompatible(sig))
+		p.Unlock()
+	}
+	return errors.Wrap(triggerErr, "signal trigger panicked")
+}
+
+// signal runs the signal triggers and sends the signal unless a trigger skips
+// it. It only holds the read lock, so that signal triggers can read the
+// process's info.
+func (p *basicProcess) signal(sig syscall.Signal) (sent bool, triggerErr error, err error) {
 	p.RLock()
 	defer p.RUnlock()
 
 	if p.info.Complete {
-		return errors.New("cannot signal a process that has terminated")
+		return false, nil, ErrProcessComplete
+	}
+	if p.info.NotStarted || p.exec == nil {
+		return false, nil, ErrProcessNotStarted
 	}
 
-	if skipSignal := p.signalTriggers.Run(p.info, sig); !skipSignal {
-		sig = makeCompatible(sig)
-		return errors.Wrapf(p.exec.Signal(sig), "problem sending signal '%s' to '%s'", sig, p.id)
+	skipSignal, triggerErr := p.signalTriggers.runRecovered(p.info, sig)
+	if skipSignal {
+		return false, triggerErr, nil
 	}
-	return nil
+	if err := p.exec.Signal(makeCompatible(sig)); err != nil {
+		return false, triggerErr, errors.Wrapf(err, "problem sending signal '%s' to '%s'", sig, p.id)
+	}
+	return true, triggerErr, nil
+}
+
+func (p *basicProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(ProcessInfo)) error {
+	return WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *basicProcess) WaitResult(ctx context.Context) (WaitResult, error) {
+	return WaitForResult(ctx, p)
+}
+
+func (p *basicProcess) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	p.RLock()
+	output := p.info.Options.Output
+	p.RUnlock()
+
+	return waitForOutput(ctx, &output, pattern)
 }
 
 func (p *basicProcess) Respawn(ctx context.Context) (Process, error) {
@@ -156,10 +374,21 @@ func (p *basicProcess) Respawn(ctx context.Context) (Process, error) {
 	defer p.RUnlock()
 
 	optsCopy := p.info.Options.Copy()
+	optsCopy.DeferStart = false
 	return newBasicProcess(ctx, optsCopy)
 }
 
+func (p *basicProcess) Done() <-chan struct{} { return p.waitProcessed }
+
 func (p *basicProcess) Wait(ctx context.Context) (int, error) {
+	exitCode, err := p.wait(ctx)
+	if p.Complete(ctx) {
+		p.ctxTriggers.run(ctx, p.Info(ctx))
+	}
+	return exitCode, err
+}
+
+func (p *basicProcess) wait(ctx context.Context) (int, error) {
 	if p.Complete(ctx) {
 		p.RLock()
 		defer p.RUnlock()
@@ -167,12 +396,20 @@ func (p *basicProcess) Wait(ctx context.Context) (int, error) {
 		return p.info.ExitCode, p.err
 	}
 
+	p.RLock()
+	waitCtx, cancel := waitContext(ctx, p.info.Options.WaitTimeout)
+	p.RUnlock()
+	defer cancel()
+
 	select {
-	case <-ctx.Done():
-		return -1, errors.New("operation canceled")
+	case <-waitCtx.Done():
+		return -1, waitCanceledError(ctx, "operation canceled")
 	case <-p.waitProcessed:
 	}
 
+	p.RLock()
+	defer p.RUnlock()
+
 	return p.info.ExitCode, p.err
 }
 
@@ -193,6 +430,74 @@ func (p *basicProcess) RegisterTrigger(_ context.Context, trigger ProcessTrigger
 	return nil
 }
 
+func (p *basicProcess) RegisterTriggerWithContext(_ context.Context, trigger ProcessTriggerWithContext) error {
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.ctxTriggers.add(trigger)
+
+	return nil
+}
+
+func (p *basicProcess) RegisterTriggerWithPhase(ctx context.Context, phase TriggerPhase, trigger ProcessTrigger) error {
+	if err := phase.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if phase == TriggerPhasePre {
+		return p.RegisterTrigger(ctx, trigger)
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.postTriggers = append(p.postTriggers, trigger)
+
+	return nil
+}
+
+func (p *basicProcess) RegisterNamedTrigger(_ context.Context, name string, trigger ProcessTrigger) error {
+	if name == "" {
+		return errors.New("cannot register trigger with an empty name")
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	if _, ok := p.namedTriggers[name]; ok {
+		return errors.Errorf("trigger named '%s' is already registered", name)
+	}
+
+	p.namedTriggers[name] = trigger
+	p.triggers = append(p.triggers, trigger)
+
+	return nil
+}
+
 func (p *basicProcess) RegisterSignalTrigger(_ context.Context, trigger SignalTrigger) error {
 	if trigger == nil {
 		return errors.New("cannot register nil trigger")
@@ -219,28 +524,22 @@ func (p *basicProcess) RegisterSignalTriggerID(ctx context.Context, id SignalTri
 }
 
 func (p *basicProcess) Tag(t string) {
-	_, ok := p.tags[t]
-	if ok {
-		return
-	}
-
-	p.tags[t] = struct{}{}
 	p.Lock()
 	defer p.Unlock()
-	p.info.Options.Tags = append(p.info.Options.Tags, t)
+
+	if p.tags.add(t) {
+		p.info.Options.Tags = p.tags.list()
+	}
 }
 
 func (p *basicProcess) ResetTags() {
-	p.tags = make(map[string]struct{})
 	p.Lock()
 	defer p.Unlock()
-	p.info.Options.Tags = []string{}
+
+	p.tags.reset()
+	p.info.Options.Tags = p.tags.list()
 }
 
 func (p *basicProcess) GetTags() []string {
-	out := []string{}
-	for t := range p.tags {
-		out = append(out, t)
-	}
-	return out
+	return p.tags.list()
 }
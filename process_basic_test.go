@@ -0,0 +1,102 @@
+package jasper
+
+import (
+	"context"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestBasicProcessSignalHistory(t *testing.T) {
+	t.Run("RecordsSignalsInOrder", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("signals cannot be ignored on Windows")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+		defer cancel()
+
+		// Ignore SIGTERM so that the process is still running to receive
+		// SIGKILL.
+		proc, err := newBasicProcess(ctx, &options.Create{
+			Args: []string{"sh", "-c", "trap '' TERM; exec sleep 10"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, proc.Info(ctx).SignalHistory)
+
+		require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+		require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+		_, err = proc.Wait(ctx)
+		assert.Error(t, err)
+
+		history := proc.Info(ctx).SignalHistory
+		require.Len(t, history, 2)
+		assert.Equal(t, syscall.SIGTERM, history[0].Signal)
+		assert.Equal(t, syscall.SIGKILL, history[1].Signal)
+		assert.False(t, history[1].Time.Before(history[0].Time))
+	})
+	t.Run("OmitsSkippedSignals", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+		defer cancel()
+
+		proc, err := newBasicProcess(ctx, testutil.SleepCreateOpts(10))
+		require.NoError(t, err)
+		require.NoError(t, proc.RegisterSignalTrigger(ctx, func(_ ProcessInfo, sig syscall.Signal) bool {
+			return sig == syscall.SIGTERM
+		}))
+
+		require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+		assert.Empty(t, proc.Info(ctx).SignalHistory)
+
+		require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+		_, err = proc.Wait(ctx)
+		assert.Error(t, err)
+
+		history := proc.Info(ctx).SignalHistory
+		require.Len(t, history, 1)
+		assert.Equal(t, syscall.SIGKILL, history[0].Signal)
+	})
+	t.Run("SignalTriggersCanReadInfo", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+		defer cancel()
+
+		proc, err := newBasicProcess(ctx, testutil.SleepCreateOpts(10))
+		require.NoError(t, err)
+		require.NoError(t, proc.RegisterSignalTrigger(ctx, func(_ ProcessInfo, _ syscall.Signal) bool {
+			return !proc.Running(ctx) || proc.Info(ctx).Complete
+		}))
+
+		signaled := make(chan error, 1)
+		go func() { signaled <- proc.Signal(ctx, syscall.SIGKILL) }()
+		select {
+		case err = <-signaled:
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "signal trigger deadlocked")
+		}
+
+		_, err = proc.Wait(ctx)
+		assert.Error(t, err)
+		history := proc.Info(ctx).SignalHistory
+		require.Len(t, history, 1)
+		assert.Equal(t, syscall.SIGKILL, history[0].Signal)
+	})
+	t.Run("IsBounded", func(t *testing.T) {
+		proc := &basicProcess{}
+		for i := 0; i < SignalHistoryLimit+5; i++ {
+			proc.info.SignalHistory = appendSignalHistory(proc.info.SignalHistory, syscall.Signal(i))
+		}
+		info := proc.Info(context.Background())
+		require.Len(t, info.SignalHistory, SignalHistoryLimit)
+		assert.Equal(t, syscall.Signal(5), info.SignalHistory[0].Signal)
+		assert.Equal(t, syscall.Signal(SignalHistoryLimit+4), info.SignalHistory[SignalHistoryLimit-1].Signal)
+
+		proc.info.SignalHistory = appendSignalHistory(proc.info.SignalHistory, syscall.SIGKILL)
+		assert.Equal(t, syscall.Signal(SignalHistoryLimit+4), info.SignalHistory[SignalHistoryLimit-1].Signal, "previously returned history should not change")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/rand"
 	"os"
+	"regexp"
 	"runtime"
 	"sync"
 	"syscall"
@@ -16,21 +17,36 @@ import (
 	"github.com/tychoish/jasper/options"
 )
 
+// processExitTimeout is how long a blocking process waits for its process to
+// exit after its context is canceled before it completes anyway.
+const processExitTimeout = 5 * time.Second
+
 type blockingProcess struct {
 	id       string
 	ops      chan func(executor.Executor)
 	complete chan struct{}
 	err      error
+	command  resolvedCommand
 
 	mu             sync.RWMutex
-	tags           map[string]struct{}
+	tags           *tagSet
 	triggers       ProcessTriggerSequence
+	postTriggers   ProcessTriggerSequence
+	ctxTriggers    contextTriggers
+	namedTriggers  map[string]ProcessTrigger
 	signalTriggers SignalTriggerSequence
 	info           ProcessInfo
+	aborted        bool
+	// readinessErr is the error from the readiness probe, if the process
+	// was killed because it did not become ready.
+	readinessErr error
 }
 
 func newBlockingProcess(ctx context.Context, opts *options.Create) (Process, error) {
-	id := uuid.New().String()
+	id := opts.ID
+	if id == "" {
+		id = uuid.New().String()
+	}
 	opts.AddEnvVar(EnvironID, id)
 
 	exec, deadline, err := opts.Resolve(ctx)
@@ -39,14 +55,12 @@ func newBlockingProcess(ctx context.Context, opts *options.Create) (Process, err
 	}
 
 	p := &blockingProcess{
-		id:       id,
-		tags:     make(map[string]struct{}),
-		ops:      make(chan func(executor.Executor)),
-		complete: make(chan struct{}),
-	}
-
-	for _, t := range opts.Tags {
-		p.tags[t] = struct{}{}
+		id:            id,
+		tags:          newTagSet(opts.Tags),
+		namedTriggers: make(map[string]ProcessTrigger),
+		command:       newResolvedCommand(exec, opts),
+		ops:           make(chan func(executor.Executor)),
+		complete:      make(chan struct{}),
 	}
 
 	if err = p.RegisterTrigger(ctx, makeOptionsCloseTrigger()); err != nil {
@@ -56,20 +70,21 @@ func newBlockingProcess(ctx context.Context, opts *options.Create) (Process, err
 		return nil, errors.Wrap(catcher.Resolve(), "problem registering options close trigger")
 	}
 
-	if err = exec.Start(); err != nil {
+	if err = startExecutor(opts, exec); err != nil {
 		catcher := grip.NewBasicCatcher()
 		catcher.Wrap(opts.Close(), "problem closing options")
 		catcher.Add(err)
-		return nil, errors.Wrap(catcher.Resolve(), "problem starting command")
+		return nil, newStartError(err, errors.Wrap(catcher.Resolve(), "problem starting command"))
 	}
 
 	p.info = ProcessInfo{
 		ID:        id,
 		PID:       exec.PID(),
 		Options:   *opts,
-		IsRunning: true,
+		IsRunning: opts.ReadinessProbe == nil,
 		StartAt:   time.Now(),
 	}
+	p.info.Options.Tags = p.tags.list()
 	if opts.Remote != nil {
 		p.info.Host = opts.Remote.Host
 	} else {
@@ -77,16 +92,62 @@ func newBlockingProcess(ctx context.Context, opts *options.Create) (Process, err
 	}
 
 	go p.reactor(ctx, deadline, exec)
+	if opts.ReadinessProbe != nil {
+		go p.awaitReadiness(ctx, opts)
+	}
 
 	return p, nil
 }
 
+// awaitReadiness marks the process as running once its readiness probe
+// succeeds, or kills it if the probe times out. The process is already killed
+// if the context is done.
+func (p *blockingProcess) awaitReadiness(ctx context.Context, opts *options.Create) {
+	err := awaitReadiness(ctx, opts, p.complete)
+
+	p.mu.Lock()
+	if p.info.Complete {
+		p.mu.Unlock()
+		return
+	}
+	if err == nil {
+		p.info.IsRunning = true
+		p.mu.Unlock()
+		return
+	}
+	if ctx.Err() != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.readinessErr = err
+	p.mu.Unlock()
+
+	kill := func(exec executor.Executor) {
+		grip.Warning(errors.Wrapf(exec.Signal(syscall.SIGKILL), "problem killing process '%s' that did not become ready", p.id))
+	}
+	select {
+	case p.ops <- kill:
+	case <-p.complete:
+	}
+}
+
+// setInfo replaces the process's info. The tags are always taken from the
+// process's tag set, since they may have changed since the info was read.
 func (p *blockingProcess) setInfo(info ProcessInfo) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	info.Options.Tags = p.tags.list()
 	p.info = info
 }
 
+func (p *blockingProcess) setHealth(healthy bool, failures int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.info.Healthy = healthy
+	p.info.HealthCheckFailures = failures
+}
+
 func (p *blockingProcess) hasCompleteInfo() bool {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -97,7 +158,21 @@ func (p *blockingProcess) hasCompleteInfo() bool {
 func (p *blockingProcess) getInfo() ProcessInfo {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	return p.info
+	return withOutputCounts(p.info)
+}
+
+func (p *blockingProcess) markAborted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.aborted = true
+}
+
+func (p *blockingProcess) isAborted() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.aborted
 }
 
 func (p *blockingProcess) setErr(err error) {
@@ -120,8 +195,13 @@ func (p *blockingProcess) reactor(ctx context.Context, deadline time.Time, exec
 	signal := make(chan error)
 	go func() {
 		defer close(signal)
-		signal <- exec.Wait()
+		signal <- waitExecutor(exec)
 	}()
+	// Post triggers start once the process is marked complete, which
+	// releases Wait.
+	var postTriggers ProcessTriggerSequence
+	var postInfo ProcessInfo
+	defer func() { go postTriggers.runRecovered(postInfo) }()
 	defer close(p.complete)
 
 	for {
@@ -134,14 +214,21 @@ func (p *blockingProcess) reactor(ctx context.Context, deadline time.Time, exec
 				p.mu.RLock()
 				defer p.mu.RUnlock()
 				p.info.EndAt = finishTime
+				// Waiting on the process reads all of the output that
+				// it wrote, even if it was signaled, but the senders
+				// may still buffer some of it.
+				grip.Warning(errors.Wrap(p.info.Options.Output.Flush(), "problem flushing process output"))
 
 				info = p.info
 				info.Complete = true
 				info.IsRunning = false
 
 				info.Successful = exec.Success()
-				if sig, signaled := exec.SignalInfo(); signaled {
+				info.CrashInfo = newCrashInfo(&info.Options, exec)
+				sig, signaled := exec.SignalInfo()
+				if signaled {
 					info.ExitCode = int(sig)
+					info.Signal = sig
 					if !deadline.IsZero() {
 						info.Timeout = sig == syscall.SIGKILL && finishTime.After(deadline)
 					}
@@ -152,27 +239,67 @@ func (p *blockingProcess) reactor(ctx context.Context, deadline time.Time, exec
 						info.Timeout = exitCode == 1 && finishTime.After(deadline)
 					}
 				}
+				info.OutputTruncated = info.Timeout
+				info.EndReason = endReason(info, signaled, p.aborted, p.readinessErr != nil, ctx.Err())
+				info.OutputChecksum = info.Options.Output.Checksum()
+				info = withOutputCounts(info)
+				if err == nil {
+					if procErr := classifyStderrAsError(info); procErr != nil {
+						err = procErr
+						info.Successful = false
+					}
+				}
+				if p.readinessErr != nil {
+					catcher := grip.NewBasicCatcher()
+					catcher.Add(err)
+					catcher.Add(p.readinessErr)
+					err = catcher.Resolve()
+					info.Successful = false
+				}
 			}()
 
 			p.mu.RLock()
-			p.triggers.Run(info)
+			triggerErr := p.triggers.runRecovered(info)
+			postTriggers = p.postTriggers
 			p.mu.RUnlock()
+			if triggerErr != nil {
+				catcher := grip.NewBasicCatcher()
+				catcher.Add(err)
+				catcher.Wrap(triggerErr, "process trigger panicked")
+				err = catcher.Resolve()
+				info.Successful = false
+			}
 			p.setErr(err)
 			p.setInfo(info)
+			postInfo = info
 			return
 		case <-ctx.Done():
-			// note, the process might take a moment to
-			// die when it gets here.
+			// The process is killed once the context is canceled, so
+			// wait for it to exit in order to read the rest of its
+			// output before the triggers close the output.
+			select {
+			case <-signal:
+			case <-time.After(processExitTimeout):
+			}
 			info := p.getInfo()
+			grip.Warning(errors.Wrap(info.Options.Output.Flush(), "problem flushing process output"))
 			info.Complete = true
 			info.IsRunning = false
 			info.Successful = false
 			info.EndAt = time.Now()
+			info.EndReason = endReason(info, false, p.isAborted(), false, ctx.Err())
+			info.OutputChecksum = info.Options.Output.Checksum()
 
 			p.mu.RLock()
-			p.triggers.Run(info)
+			triggerErr := p.triggers.runRecovered(info)
+			postTriggers = p.postTriggers
 			p.mu.RUnlock()
+			if triggerErr != nil {
+				p.setErr(errors.Wrap(triggerErr, "process trigger panicked"))
+				info.Successful = false
+			}
 			p.setInfo(info)
+			postInfo = info
 
 			return
 		case op := <-p.ops:
@@ -189,7 +316,9 @@ func (p *blockingProcess) Info(ctx context.Context) ProcessInfo {
 		return p.getInfo()
 	}
 
-	out := make(chan ProcessInfo)
+	// The channel is buffered so that the reactor does not block if the
+	// context is canceled before the info is received.
+	out := make(chan ProcessInfo, 1)
 	operation := func(exec executor.Executor) {
 		out <- p.getInfo()
 		close(out)
@@ -212,6 +341,10 @@ func (p *blockingProcess) Info(ctx context.Context) ProcessInfo {
 	}
 }
 
+func (p *blockingProcess) InfoWith(ctx context.Context, opts InfoOptions) ProcessInfo {
+	return infoWith(p.Info(ctx), opts)
+}
+
 func (p *blockingProcess) Running(ctx context.Context) bool {
 	if p.hasCompleteInfo() {
 		return false
@@ -255,9 +388,23 @@ func (p *blockingProcess) Complete(_ context.Context) bool {
 	return p.hasCompleteInfo()
 }
 
+// Start returns an error, since blocking processes are always started when
+// they are created.
+func (p *blockingProcess) Start(_ context.Context) error {
+	return errors.New("cannot start a process that has already been started")
+}
+
+func (p *blockingProcess) ResolvedCommand() ([]string, []string, string) {
+	return p.command.get()
+}
+
+func (p *blockingProcess) ChildPIDs(ctx context.Context) ([]int, error) {
+	return processChildPIDs(p.Info(ctx))
+}
+
 func (p *blockingProcess) Signal(ctx context.Context, sig syscall.Signal) error {
 	if p.hasCompleteInfo() {
-		return errors.New("cannot signal a process that has terminated")
+		return ErrProcessComplete
 	}
 
 	out := make(chan error)
@@ -265,17 +412,18 @@ func (p *blockingProcess) Signal(ctx context.Context, sig syscall.Signal) error
 		defer close(out)
 
 		if exec == nil {
-			out <- errors.New("cannot signal nil process")
+			out <- ErrProcessNotStarted
 			return
 		}
 
-		if skipSignal := p.signalTriggers.Run(p.getInfo(), sig); !skipSignal {
+		skipSignal, triggerErr := p.signalTriggers.runRecovered(p.getInfo(), sig)
+		catcher := grip.NewBasicCatcher()
+		if !skipSignal {
 			sig = makeCompatible(sig)
-			out <- errors.Wrapf(exec.Signal(sig), "problem sending signal '%s' to '%s'",
-				sig, p.id)
-		} else {
-			out <- nil
+			catcher.Wrapf(exec.Signal(sig), "problem sending signal '%s' to '%s'", sig, p.id)
 		}
+		catcher.Wrap(triggerErr, "signal trigger panicked")
+		out <- catcher.Resolve()
 
 	}
 	select {
@@ -286,12 +434,12 @@ func (p *blockingProcess) Signal(ctx context.Context, sig syscall.Signal) error
 		case <-ctx.Done():
 			return errors.New("context canceled")
 		case <-p.complete:
-			return errors.New("cannot signal after process is complete")
+			return ErrProcessComplete
 		}
 	case <-ctx.Done():
 		return errors.New("context canceled")
 	case <-p.complete:
-		return errors.New("cannot signal after process is complete")
+		return ErrProcessComplete
 	}
 }
 
@@ -312,6 +460,71 @@ func (p *blockingProcess) RegisterTrigger(_ context.Context, trigger ProcessTrig
 	return nil
 }
 
+func (p *blockingProcess) RegisterTriggerWithContext(_ context.Context, trigger ProcessTriggerWithContext) error {
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	if p.hasCompleteInfo() {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.ctxTriggers.add(trigger)
+
+	return nil
+}
+
+func (p *blockingProcess) RegisterTriggerWithPhase(ctx context.Context, phase TriggerPhase, trigger ProcessTrigger) error {
+	if err := phase.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if phase == TriggerPhasePre {
+		return p.RegisterTrigger(ctx, trigger)
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.postTriggers = append(p.postTriggers, trigger)
+
+	return nil
+}
+
+func (p *blockingProcess) RegisterNamedTrigger(_ context.Context, name string, trigger ProcessTrigger) error {
+	if name == "" {
+		return errors.New("cannot register trigger with an empty name")
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	if _, ok := p.namedTriggers[name]; ok {
+		return errors.Errorf("trigger named '%s' is already registered", name)
+	}
+
+	p.namedTriggers[name] = trigger
+	p.triggers = append(p.triggers, trigger)
+
+	return nil
+}
+
 func (p *blockingProcess) RegisterSignalTrigger(_ context.Context, trigger SignalTrigger) error {
 	if trigger == nil {
 		return errors.New("cannot register nil trigger")
@@ -337,11 +550,24 @@ func (p *blockingProcess) RegisterSignalTriggerID(ctx context.Context, id Signal
 	return errors.Wrap(p.RegisterSignalTrigger(ctx, makeTrigger()), "failed to register signal trigger")
 }
 
+func (p *blockingProcess) Done() <-chan struct{} { return p.complete }
+
 func (p *blockingProcess) Wait(ctx context.Context) (int, error) {
+	exitCode, err := p.wait(ctx)
+	if p.hasCompleteInfo() {
+		p.ctxTriggers.run(ctx, p.getInfo())
+	}
+	return exitCode, err
+}
+
+func (p *blockingProcess) wait(ctx context.Context) (int, error) {
 	if p.hasCompleteInfo() {
 		return p.getInfo().ExitCode, p.getErr()
 	}
 
+	waitCtx, cancel := waitContext(ctx, p.getInfo().Options.WaitTimeout)
+	defer cancel()
+
 	out := make(chan error)
 	waiter := func(exec executor.Executor) {
 		if !p.hasCompleteInfo() {
@@ -359,8 +585,8 @@ func (p *blockingProcess) Wait(ctx context.Context) (int, error) {
 			timer.Reset(time.Duration(rand.Int63n(50)) * time.Millisecond)
 		case p.ops <- waiter:
 			continue
-		case <-ctx.Done():
-			return -1, errors.New("wait operation canceled")
+		case <-waitCtx.Done():
+			return -1, waitCanceledError(ctx, "wait operation canceled")
 		case err := <-out:
 			return p.getInfo().ExitCode, errors.WithStack(err)
 		case <-p.complete:
@@ -369,6 +595,19 @@ func (p *blockingProcess) Wait(ctx context.Context) (int, error) {
 	}
 }
 
+func (p *blockingProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(ProcessInfo)) error {
+	return WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *blockingProcess) WaitResult(ctx context.Context) (WaitResult, error) {
+	return WaitForResult(ctx, p)
+}
+
+func (p *blockingProcess) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	output := p.getInfo().Options.Output
+	return waitForOutput(ctx, &output, pattern)
+}
+
 func (p *blockingProcess) Respawn(ctx context.Context) (Process, error) {
 	opts := p.Info(ctx).Options
 	optsCopy := opts.Copy()
@@ -379,30 +618,19 @@ func (p *blockingProcess) Tag(t string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	_, ok := p.tags[t]
-	if ok {
-		return
+	if p.tags.add(t) {
+		p.info.Options.Tags = p.tags.list()
 	}
-
-	p.tags[t] = struct{}{}
-	p.info.Options.Tags = append(p.info.Options.Tags, t)
 }
 
 func (p *blockingProcess) ResetTags() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.tags = make(map[string]struct{})
-	p.info.Options.Tags = []string{}
+	p.tags.reset()
+	p.info.Options.Tags = p.tags.list()
 }
 
 func (p *blockingProcess) GetTags() []string {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	out := []string{}
-	for t := range p.tags {
-		out = append(out, t)
-	}
-	return out
+	return p.tags.list()
 }
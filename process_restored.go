@@ -0,0 +1,413 @@
+package jasper
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/tychoish/jasper/options"
+)
+
+const (
+	// restoredProcessPollInterval is how soon a restored process is first
+	// checked to see if it has exited. The interval doubles after each
+	// check, up to restoredProcessMaxPollInterval, so that short-lived
+	// processes are noticed quickly without frequently polling
+	// long-running ones.
+	restoredProcessPollInterval = 100 * time.Millisecond
+	// restoredProcessMaxPollInterval is the longest time between checks
+	// of a restored process.
+	restoredProcessMaxPollInterval = 2 * time.Second
+)
+
+// restoredProcess is a process that was started by a previous manager and
+// reattached from a snapshot, or that was started outside of jasper and
+// registered with a manager. Since it is not a child of this process, its
+// exit can only be detected by polling its PID and its exit code cannot be
+// determined.
+type restoredProcess struct {
+	id             string
+	info           ProcessInfo
+	tags           *tagSet
+	triggers       ProcessTriggerSequence
+	postTriggers   ProcessTriggerSequence
+	ctxTriggers    contextTriggers
+	namedTriggers  map[string]ProcessTrigger
+	signalTriggers SignalTriggerSequence
+	complete       chan struct{}
+	sync.RWMutex
+}
+
+// newRestoredProcess reattaches to the process described by the info if it is
+// still running. Otherwise, the process is marked complete.
+func newRestoredProcess(ctx context.Context, info ProcessInfo, tags []string) *restoredProcess {
+	p := &restoredProcess{
+		id:            info.ID,
+		info:          info,
+		tags:          newTagSet(tags),
+		namedTriggers: make(map[string]ProcessTrigger),
+		complete:      make(chan struct{}),
+	}
+	p.info.Options.Tags = p.tags.list()
+
+	if info.Complete {
+		close(p.complete)
+		return p
+	}
+
+	if !processIsAlive(info.PID) {
+		p.finish()
+		return p
+	}
+
+	go p.poll(ctx)
+
+	return p
+}
+
+// newExternalProcess wraps a running process that was not started by jasper
+// so that it can be managed like a restored process. The options describe the
+// process, but are not used to start it. It returns an error if there is no
+// running process with the PID.
+func newExternalProcess(ctx context.Context, pid int, opts *options.Create) (*restoredProcess, error) {
+	if pid <= 0 {
+		return nil, errors.Errorf("invalid PID %d", pid)
+	}
+	if !processIsAlive(pid) {
+		return nil, errors.Errorf("process with PID %d is not running", pid)
+	}
+
+	info := ProcessInfo{
+		ID:        uuid.New().String(),
+		PID:       pid,
+		IsRunning: true,
+		StartAt:   time.Now(),
+	}
+	if opts != nil {
+		info.Options = *opts.Copy()
+		if opts.ID != "" {
+			info.ID = opts.ID
+		}
+	}
+	info.Host, _ = os.Hostname()
+
+	return newRestoredProcess(ctx, info, info.Options.Tags), nil
+}
+
+// poll checks whether the process is still alive with exponential backoff,
+// marking it complete once it has exited.
+func (p *restoredProcess) poll(ctx context.Context) {
+	interval := restoredProcessPollInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if !processIsAlive(p.info.PID) {
+				p.finish()
+				return
+			}
+			interval = nextPollInterval(interval)
+			timer.Reset(interval)
+		}
+	}
+}
+
+func nextPollInterval(interval time.Duration) time.Duration {
+	interval *= 2
+	if interval > restoredProcessMaxPollInterval {
+		return restoredProcessMaxPollInterval
+	}
+	return interval
+}
+
+// finish marks the process as complete and runs its triggers.
+func (p *restoredProcess) finish() {
+	p.Lock()
+	p.info.IsRunning = false
+	p.info.Complete = true
+	p.info.Successful = false
+	p.info.ExitCode = -1
+	p.info.EndAt = time.Now()
+	p.triggers.Run(p.info)
+	postTriggers, info := p.postTriggers, p.info
+	close(p.complete)
+	p.Unlock()
+
+	// Post triggers start once Wait has been released.
+	go postTriggers.Run(info)
+}
+
+func (p *restoredProcess) ID() string {
+	return p.id
+}
+
+func (p *restoredProcess) Info(_ context.Context) ProcessInfo {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.info
+}
+
+func (p *restoredProcess) InfoWith(ctx context.Context, opts InfoOptions) ProcessInfo {
+	return infoWith(p.Info(ctx), opts)
+}
+
+func (p *restoredProcess) Running(ctx context.Context) bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.info.IsRunning
+}
+
+func (p *restoredProcess) Complete(ctx context.Context) bool {
+	return !p.Running(ctx)
+}
+
+// Start returns an error, since restored processes have always been started.
+func (p *restoredProcess) Start(_ context.Context) error {
+	return errors.New("cannot start a process that has already been started")
+}
+
+// ResolvedCommand returns the command line described by the process's
+// options, since the command that the process was actually started with is
+// not known.
+func (p *restoredProcess) ResolvedCommand() ([]string, []string, string) {
+	p.RLock()
+	defer p.RUnlock()
+
+	opts := p.info.Options
+	env := opts.ResolveEnvironment()
+	sort.Strings(env)
+
+	return append([]string{}, opts.Args...), redactEnv(env, opts.RedactedEnvironment), opts.WorkingDirectory
+}
+
+func (p *restoredProcess) ChildPIDs(ctx context.Context) ([]int, error) {
+	return processChildPIDs(p.Info(ctx))
+}
+
+func (p *restoredProcess) Signal(_ context.Context, sig syscall.Signal) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return ErrProcessComplete
+	}
+
+	skipSignal, triggerErr := p.signalTriggers.runRecovered(p.info, sig)
+	if skipSignal {
+		return errors.Wrap(triggerErr, "signal trigger panicked")
+	}
+
+	sig = makeCompatible(sig)
+	proc, err := os.FindProcess(p.info.PID)
+	if err == nil {
+		err = proc.Signal(sig)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem sending signal '%s' to '%s'", sig, p.id)
+	}
+	p.info.SignalHistory = appendSignalHistory(p.info.SignalHistory, sig)
+
+	return errors.Wrap(triggerErr, "signal trigger panicked")
+}
+
+// Wait waits for the process to exit. Since the exit code of a restored
+// process cannot be determined, it returns an exit code of -1 and an error
+// once the process exits, unless the process had already completed when
+// the snapshot was taken.
+func (p *restoredProcess) Wait(ctx context.Context) (int, error) {
+	exitCode, err := p.wait(ctx)
+	select {
+	case <-p.complete:
+		p.ctxTriggers.run(ctx, p.Info(ctx))
+	default:
+	}
+	return exitCode, err
+}
+
+func (p *restoredProcess) wait(ctx context.Context) (int, error) {
+	p.RLock()
+	waitCtx, cancel := waitContext(ctx, p.info.Options.WaitTimeout)
+	p.RUnlock()
+	defer cancel()
+
+	select {
+	case <-waitCtx.Done():
+		return -1, waitCanceledError(ctx, "operation canceled")
+	case <-p.complete:
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	if !p.info.Successful {
+		return p.info.ExitCode, errors.Errorf("restored process '%s' did not exit successfully or its exit status is unknown", p.id)
+	}
+	return p.info.ExitCode, nil
+}
+
+// Done returns a channel that is closed once the restored process is
+// detected to have exited, or immediately if it had already completed when
+// the snapshot was taken.
+func (p *restoredProcess) Done() <-chan struct{} { return p.complete }
+
+func (p *restoredProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(ProcessInfo)) error {
+	return WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *restoredProcess) WaitResult(ctx context.Context) (WaitResult, error) {
+	return WaitForResult(ctx, p)
+}
+
+func (p *restoredProcess) WaitForOutput(_ context.Context, _ *regexp.Regexp) error {
+	return errors.New("cannot watch the output of a restored process")
+}
+
+func (p *restoredProcess) Respawn(ctx context.Context) (Process, error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	return NewProcess(ctx, p.info.Options.Copy())
+}
+
+func (p *restoredProcess) RegisterTrigger(_ context.Context, trigger ProcessTrigger) error {
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.triggers = append(p.triggers, trigger)
+
+	return nil
+}
+
+func (p *restoredProcess) RegisterTriggerWithContext(_ context.Context, trigger ProcessTriggerWithContext) error {
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.ctxTriggers.add(trigger)
+
+	return nil
+}
+
+func (p *restoredProcess) RegisterTriggerWithPhase(ctx context.Context, phase TriggerPhase, trigger ProcessTrigger) error {
+	if err := phase.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if phase == TriggerPhasePre {
+		return p.RegisterTrigger(ctx, trigger)
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	p.postTriggers = append(p.postTriggers, trigger)
+
+	return nil
+}
+
+func (p *restoredProcess) RegisterNamedTrigger(_ context.Context, name string, trigger ProcessTrigger) error {
+	if name == "" {
+		return errors.New("cannot register trigger with an empty name")
+	}
+
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register trigger after process exits")
+	}
+
+	if _, ok := p.namedTriggers[name]; ok {
+		return errors.Errorf("trigger named '%s' is already registered", name)
+	}
+
+	p.namedTriggers[name] = trigger
+	p.triggers = append(p.triggers, trigger)
+
+	return nil
+}
+
+func (p *restoredProcess) RegisterSignalTrigger(_ context.Context, trigger SignalTrigger) error {
+	if trigger == nil {
+		return errors.New("cannot register nil trigger")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.info.Complete {
+		return errors.New("cannot register signal trigger after process exits")
+	}
+
+	p.signalTriggers = append(p.signalTriggers, trigger)
+
+	return nil
+}
+
+func (p *restoredProcess) RegisterSignalTriggerID(ctx context.Context, id SignalTriggerID) error {
+	makeTrigger, ok := GetSignalTriggerFactory(id)
+	if !ok {
+		return errors.Errorf("could not find signal trigger with id '%s'", id)
+	}
+	return errors.Wrap(p.RegisterSignalTrigger(ctx, makeTrigger()), "failed to register signal trigger")
+}
+
+func (p *restoredProcess) Tag(t string) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.tags.add(t) {
+		p.info.Options.Tags = p.tags.list()
+	}
+}
+
+func (p *restoredProcess) ResetTags() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.tags.reset()
+	p.info.Options.Tags = p.tags.list()
+}
+
+func (p *restoredProcess) GetTags() []string {
+	return p.tags.list()
+}
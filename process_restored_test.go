@@ -0,0 +1,27 @@
+package jasper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextPollInterval(t *testing.T) {
+	interval := restoredProcessPollInterval
+	var intervals []time.Duration
+	for i := 0; i < 7; i++ {
+		interval = nextPollInterval(interval)
+		intervals = append(intervals, interval)
+	}
+
+	assert.Equal(t, []time.Duration{
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1600 * time.Millisecond,
+		restoredProcessMaxPollInterval,
+		restoredProcessMaxPollInterval,
+		restoredProcessMaxPollInterval,
+	}, intervals)
+}
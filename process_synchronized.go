@@ -2,8 +2,10 @@ package jasper
 
 import (
 	"context"
+	"regexp"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -27,6 +29,20 @@ func (p *synchronizedProcess) Info(ctx context.Context) ProcessInfo {
 	return p.proc.Info(ctx)
 }
 
+func (p *synchronizedProcess) InfoWith(ctx context.Context, opts InfoOptions) ProcessInfo {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.proc.InfoWith(ctx, opts)
+}
+
+func (p *synchronizedProcess) setHealth(healthy bool, failures int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	recordHealth(p.proc, healthy, failures)
+}
+
 func (p *synchronizedProcess) Running(ctx context.Context) bool {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
@@ -41,6 +57,27 @@ func (p *synchronizedProcess) Complete(ctx context.Context) bool {
 	return p.proc.Complete(ctx)
 }
 
+func (p *synchronizedProcess) Start(ctx context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.proc.Start(ctx)
+}
+
+func (p *synchronizedProcess) ResolvedCommand() ([]string, []string, string) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.proc.ResolvedCommand()
+}
+
+func (p *synchronizedProcess) ChildPIDs(ctx context.Context) ([]int, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.proc.ChildPIDs(ctx)
+}
+
 func (p *synchronizedProcess) Signal(ctx context.Context, sig syscall.Signal) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -76,6 +113,27 @@ func (p *synchronizedProcess) RegisterTrigger(ctx context.Context, trigger Proce
 	return errors.WithStack(p.proc.RegisterTrigger(ctx, trigger))
 }
 
+func (p *synchronizedProcess) RegisterTriggerWithContext(ctx context.Context, trigger ProcessTriggerWithContext) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return errors.WithStack(p.proc.RegisterTriggerWithContext(ctx, trigger))
+}
+
+func (p *synchronizedProcess) RegisterTriggerWithPhase(ctx context.Context, phase TriggerPhase, trigger ProcessTrigger) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return errors.WithStack(p.proc.RegisterTriggerWithPhase(ctx, phase, trigger))
+}
+
+func (p *synchronizedProcess) RegisterNamedTrigger(ctx context.Context, name string, trigger ProcessTrigger) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return errors.WithStack(p.proc.RegisterNamedTrigger(ctx, name, trigger))
+}
+
 func (p *synchronizedProcess) RegisterSignalTrigger(ctx context.Context, trigger SignalTrigger) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -98,6 +156,29 @@ func (p *synchronizedProcess) Wait(ctx context.Context) (int, error) {
 	return exitCode, errors.WithStack(err)
 }
 
+// Done does not hold the lock since the channel never changes.
+func (p *synchronizedProcess) Done() <-chan struct{} { return p.proc.Done() }
+
+// WaitWithProgress does not hold the lock while waiting so that the callback
+// can use the process.
+func (p *synchronizedProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(ProcessInfo)) error {
+	return errors.WithStack(p.proc.WaitWithProgress(ctx, interval, cb))
+}
+
+func (p *synchronizedProcess) WaitResult(ctx context.Context) (WaitResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	res, err := p.proc.WaitResult(ctx)
+	return res, errors.WithStack(err)
+}
+
+// WaitForOutput does not hold the lock while waiting so that the process can
+// be used while its output is watched.
+func (p *synchronizedProcess) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	return errors.WithStack(p.proc.WaitForOutput(ctx, pattern))
+}
+
 func (p *synchronizedProcess) Respawn(ctx context.Context) (Process, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -3,13 +3,21 @@ package jasper
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
@@ -19,6 +27,7 @@ import (
 	"github.com/tychoish/grip"
 	"github.com/tychoish/jasper/options"
 	"github.com/tychoish/jasper/testutil"
+	"github.com/tychoish/jasper/util"
 )
 
 func TestProcessImplementations(t *testing.T) {
@@ -147,6 +156,27 @@ func TestProcessImplementations(t *testing.T) {
 							proc.Tag("bar")
 							assert.Len(t, proc.GetTags(), 2)
 						},
+						"TagsHaveStableInsertionOrder": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Tags = []string{"zulu", "alpha"}
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+
+							for _, tag := range []string{"mike", "bravo", "alpha", "yankee"} {
+								proc.Tag(tag)
+							}
+							expected := []string{"zulu", "alpha", "mike", "bravo", "yankee"}
+							for i := 0; i < 100; i++ {
+								require.Equal(t, expected, proc.GetTags())
+							}
+							assert.Equal(t, expected, proc.Info(ctx).Options.Tags)
+
+							proc.ResetTags()
+							proc.Tag("yankee")
+							proc.Tag("zulu")
+							for i := 0; i < 100; i++ {
+								require.Equal(t, []string{"yankee", "zulu"}, proc.GetTags())
+							}
+						},
 						"CompleteIsTrueAfterWait": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
 							proc, err := makep(ctx, opts)
 							require.NoError(t, err)
@@ -171,6 +201,321 @@ func TestProcessImplementations(t *testing.T) {
 							require.NoError(t, err)
 							assert.Error(t, proc.RegisterTrigger(ctx, nil))
 						},
+						"RegisterTriggerWithContextErrorsForNil": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Error(t, proc.RegisterTriggerWithContext(ctx, nil))
+						},
+						"TriggerWithContextReceivesContextValues": func(ctx context.Context, t *testing.T, _ *options.Create, makep ProcessConstructor) {
+							type traceKey struct{}
+							proc, err := makep(ctx, testutil.SleepCreateOpts(1))
+							require.NoError(t, err)
+
+							seen := make(chan interface{}, 2)
+							require.NoError(t, proc.RegisterTriggerWithContext(ctx, func(tctx context.Context, info ProcessInfo) {
+								assert.True(t, info.Complete)
+								seen <- tctx.Value(traceKey{})
+							}))
+
+							_, err = proc.Wait(context.WithValue(ctx, traceKey{}, "trace-id"))
+							require.NoError(t, err)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+
+							require.Len(t, seen, 1, "trigger should run once before Wait returns")
+							assert.Equal(t, "trace-id", <-seen)
+						},
+						"RegisterTriggerWithPhaseErrorsForInvalidPhase": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Error(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhase("during"), func(ProcessInfo) {}))
+						},
+						"RegisterTriggerWithPhaseErrorsForNil": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Error(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePre, nil))
+							assert.Error(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePost, nil))
+						},
+						"PreTriggerCompletesBeforeWaitReturns": func(ctx context.Context, t *testing.T, _ *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(1))
+							require.NoError(t, err)
+
+							var finished int32
+							require.NoError(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePre, func(info ProcessInfo) {
+								assert.True(t, info.Complete)
+								time.Sleep(100 * time.Millisecond)
+								atomic.StoreInt32(&finished, 1)
+							}))
+
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+							assert.EqualValues(t, 1, atomic.LoadInt32(&finished))
+						},
+						"PostTriggerRunsAfterWaitReturns": func(ctx context.Context, t *testing.T, _ *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(1))
+							require.NoError(t, err)
+
+							release := make(chan struct{})
+							done := make(chan ProcessInfo, 1)
+							require.NoError(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePost, func(info ProcessInfo) {
+								<-release
+								done <- info
+							}))
+
+							// The post trigger cannot finish until Wait
+							// returns, so Wait must not block on it.
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+							close(release)
+
+							select {
+							case <-ctx.Done():
+								assert.Fail(t, "post trigger did not run")
+							case info := <-done:
+								assert.True(t, info.Complete)
+							}
+						},
+						"PostTriggerStartsAfterWaitIsReleased": func(ctx context.Context, t *testing.T, _ *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.TrueCreateOpts())
+							require.NoError(t, err)
+
+							released := make(chan bool, 1)
+							require.NoError(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePost, func(ProcessInfo) {
+								select {
+								case <-proc.Done():
+									released <- true
+								default:
+									released <- false
+								}
+							}))
+
+							select {
+							case <-ctx.Done():
+								assert.Fail(t, "post trigger did not run")
+							case ok := <-released:
+								assert.True(t, ok)
+							}
+						},
+						"RegisterNamedTriggerErrorsForNil": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Error(t, proc.RegisterNamedTrigger(ctx, "foo", nil))
+						},
+						"RegisterNamedTriggerErrorsForEmptyName": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Error(t, proc.RegisterNamedTrigger(ctx, "", func(ProcessInfo) {}))
+						},
+						"RegisterNamedTriggerRejectsDuplicateName": func(ctx context.Context, t *testing.T, _ *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(1))
+							require.NoError(t, err)
+
+							count := 0
+							trigger := func(ProcessInfo) { count++ }
+							require.NoError(t, proc.RegisterNamedTrigger(ctx, "cleanup", trigger))
+							assert.Error(t, proc.RegisterNamedTrigger(ctx, "cleanup", trigger))
+							assert.NoError(t, proc.RegisterNamedTrigger(ctx, "other", trigger))
+
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+							assert.Equal(t, 2, count)
+						},
+						"OutputIsTranscodedFromConfiguredEncoding": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS == "windows" {
+								t.Skip("printf is not available on windows")
+							}
+							output := &bytes.Buffer{}
+							opts.Args = []string{"printf", `caf\351`}
+							opts.Output.Output = output
+							opts.Output.Encoding = "windows-1252"
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+
+							assert.True(t, utf8.Valid(output.Bytes()))
+							assert.Equal(t, "café", output.String())
+						},
+						"UnknownOutputEncodingFailsCreation": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Output.Encoding = "not-an-encoding"
+							proc, err := makep(ctx, opts)
+							assert.Error(t, err)
+							assert.Nil(t, proc)
+						},
+						"ShellCommandSupportsPipesAndSubstitution": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS == "windows" {
+								t.Skip("tr is not available on windows")
+							}
+							output := &bytes.Buffer{}
+							opts.Args = nil
+							opts.ShellCommand = `echo "$JASPER_SHELL_TEST" | tr a-z A-Z`
+							opts.AddEnvVar("JASPER_SHELL_TEST", "hello world")
+							opts.Output.Output = output
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							exitCode, err := proc.Wait(ctx)
+							require.NoError(t, err)
+							assert.Zero(t, exitCode)
+							assert.Equal(t, "HELLO WORLD\n", output.String())
+						},
+						"PreExecAndPostStartHooksRunInOrder": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if opts.Docker != nil {
+								t.Skip("hooks are only supported for local processes")
+							}
+							var events []string
+							var hookPID int
+							opts.PreExec = []func() error{
+								func() error {
+									events = append(events, "pre-exec-1")
+									return nil
+								},
+								func() error {
+									events = append(events, "pre-exec-2")
+									return nil
+								},
+							}
+							opts.PostStart = []func(int){
+								func(pid int) {
+									events = append(events, "post-start")
+									hookPID = pid
+								},
+							}
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							assert.Equal(t, []string{"pre-exec-1", "pre-exec-2", "post-start"}, events)
+							assert.NotZero(t, hookPID)
+							assert.Equal(t, proc.Info(ctx).PID, hookPID)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+						},
+						"FailingPreExecHookPreventsStart": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS == "windows" {
+								t.Skip("touch is not available on windows")
+							}
+							if opts.Docker != nil {
+								t.Skip("hooks are only supported for local processes")
+							}
+							dir, err := ioutil.TempDir(testutil.BuildDirectory(), "pre-exec")
+							require.NoError(t, err)
+							defer func() {
+								assert.NoError(t, os.RemoveAll(dir))
+							}()
+							fileName := filepath.Join(dir, "touched")
+
+							var postStartRan bool
+							var laterPreExecRan bool
+							opts.Args = []string{"touch", fileName}
+							opts.PreExec = []func() error{
+								func() error { return errors.New("pre-exec failed") },
+								func() error {
+									laterPreExecRan = true
+									return nil
+								},
+							}
+							opts.PostStart = []func(int){func(int) { postStartRan = true }}
+
+							proc, err := makep(ctx, opts)
+							require.Error(t, err)
+							assert.Contains(t, err.Error(), "pre-exec failed")
+							assert.Nil(t, proc)
+							assert.False(t, laterPreExecRan)
+							assert.False(t, postStartRan)
+							_, err = os.Stat(fileName)
+							assert.True(t, os.IsNotExist(err))
+						},
+						"StderrWithZeroExitSucceedsByDefault": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Args = nil
+							opts.ShellCommand = "echo warning >&2"
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							exitCode, err := proc.Wait(ctx)
+							require.NoError(t, err)
+							assert.Zero(t, exitCode)
+							assert.True(t, proc.Info(ctx).Successful)
+						},
+						"StderrWithZeroExitFailsWhenTreatedAsError": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							errOutput := &bytes.Buffer{}
+							opts.Args = nil
+							opts.ShellCommand = "echo warning >&2"
+							opts.Output.Error = errOutput
+							opts.Output.TreatStderrAsError = true
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							exitCode, err := proc.Wait(ctx)
+							require.Error(t, err)
+							procErr, ok := errors.Cause(err).(*ProcessError)
+							require.True(t, ok)
+							assert.Equal(t, proc.ID(), procErr.ID)
+							assert.Zero(t, procErr.ExitCode)
+							assert.Zero(t, exitCode)
+							assert.False(t, proc.Info(ctx).Successful)
+							assert.Contains(t, errOutput.String(), "warning")
+						},
+						"NoStderrSucceedsWhenTreatedAsError": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Output.TreatStderrAsError = true
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+							assert.True(t, proc.Info(ctx).Successful)
+						},
+						"ResourceLimitsApplyToChild": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS != "linux" {
+								t.Skip("resource limit test only runs on linux")
+							}
+							opts.ResourceLimits = map[string]options.ResourceLimit{
+								options.ResourceLimitNumFiles: {Soft: 10, Hard: 10},
+							}
+							// paste opens all of its input files at once.
+							opts.Args = []string{"paste"}
+							for i := 0; i < 20; i++ {
+								opts.Args = append(opts.Args, os.DevNull)
+							}
+							output := &bytes.Buffer{}
+							opts.Output.Error = output
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							exitCode, err := proc.Wait(ctx)
+							assert.Error(t, err)
+							assert.NotZero(t, exitCode)
+							assert.Contains(t, output.String(), "Too many open files")
+						},
+						"InfoWithIncludesOutputTail": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS == "windows" {
+								t.Skip("seq is not available on windows")
+							}
+							logger, err := NewInMemoryLogger(100)
+							require.NoError(t, err)
+							opts.Output.Loggers = []*options.LoggerConfig{logger}
+							opts.Args = []string{"seq", "1", "20"}
+
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+
+							assert.Empty(t, proc.Info(ctx).OutputTail)
+							assert.Empty(t, proc.InfoWith(ctx, InfoOptions{}).OutputTail)
+
+							info := proc.InfoWith(ctx, InfoOptions{IncludeOutputTail: 5})
+							assert.Equal(t, []string{"16", "17", "18", "19", "20"}, info.OutputTail)
+							assert.Equal(t, proc.ID(), info.ID)
+						},
+						"InfoWithWithoutInMemoryLoggerHasNoOutputTail": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+							_, err = proc.Wait(ctx)
+							require.NoError(t, err)
+
+							assert.Empty(t, proc.InfoWith(ctx, InfoOptions{IncludeOutputTail: 5}).OutputTail)
+						},
 						"RegisterSignalTriggerErrorsForNil": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
 							proc, err := makep(ctx, opts)
 							require.NoError(t, err)
@@ -528,6 +873,97 @@ func TestProcessImplementations(t *testing.T) {
 							assert.Error(t, err)
 							assert.Equal(t, 1, exitCode)
 						},
+						"WaitResultMatchesInfoOnSuccess": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.TrueCreateOpts())
+							require.NoError(t, err)
+
+							res, err := proc.WaitResult(ctx)
+							require.NoError(t, err)
+
+							info := proc.Info(ctx)
+							assert.Equal(t, 0, res.ExitCode)
+							assert.Equal(t, info.ExitCode, res.ExitCode)
+							assert.Zero(t, res.Signal)
+							assert.Equal(t, EndReasonExited, res.EndReason)
+							assert.Equal(t, info.EndReason, res.EndReason)
+							assert.Equal(t, info.EndAt.Sub(info.StartAt), res.Duration)
+							assert.True(t, res.Duration > 0)
+						},
+						"WaitResultMatchesInfoOnFailure": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.FalseCreateOpts())
+							require.NoError(t, err)
+
+							res, err := proc.WaitResult(ctx)
+							assert.Error(t, err)
+
+							info := proc.Info(ctx)
+							assert.Equal(t, 1, res.ExitCode)
+							assert.Equal(t, info.ExitCode, res.ExitCode)
+							assert.Zero(t, res.Signal)
+							assert.Equal(t, info.EndReason, res.EndReason)
+							assert.Equal(t, info.EndAt.Sub(info.StartAt), res.Duration)
+						},
+						"WaitResultMatchesInfoOnSignalDeath": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							if runtime.GOOS == "windows" {
+								t.Skip("windows processes do not report terminating signals")
+							}
+							proc, err := makep(ctx, testutil.SleepCreateOpts(100))
+							require.NoError(t, err)
+							require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+
+							res, err := proc.WaitResult(ctx)
+							assert.Error(t, err)
+
+							info := proc.Info(ctx)
+							assert.Equal(t, syscall.SIGTERM, res.Signal)
+							assert.Equal(t, info.Signal, res.Signal)
+							assert.Equal(t, int(syscall.SIGTERM), res.ExitCode)
+							assert.Equal(t, info.ExitCode, res.ExitCode)
+							assert.Equal(t, EndReasonSignaled, res.EndReason)
+							assert.Equal(t, info.EndReason, res.EndReason)
+							assert.Equal(t, info.EndAt.Sub(info.StartAt), res.Duration)
+						},
+						"WaitResultIsEmptyWhenContextIsCanceled": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(100))
+							require.NoError(t, err)
+							defer func() {
+								assert.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+							}()
+
+							waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+							defer waitCancel()
+							res, err := proc.WaitResult(waitCtx)
+							assert.Error(t, err)
+							assert.Zero(t, res)
+						},
+						"WaitWithProgressCallsBackUntilCompletion": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Args = []string{"sleep", "1"}
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+
+							var calls int32
+							require.NoError(t, proc.WaitWithProgress(ctx, 200*time.Millisecond, func(info ProcessInfo) {
+								atomic.AddInt32(&calls, 1)
+								assert.Equal(t, proc.ID(), info.ID)
+								assert.False(t, info.Complete)
+							}))
+							assert.True(t, proc.Complete(ctx))
+
+							count := atomic.LoadInt32(&calls)
+							assert.True(t, count >= 3 && count <= 5, "expected about five progress callbacks, got %d", count)
+
+							time.Sleep(300 * time.Millisecond)
+							assert.Equal(t, count, atomic.LoadInt32(&calls), "progress callback should not run after completion")
+						},
+						"WaitWithProgressReturnsWaitError": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							opts.Args = testutil.FalseCreateOpts().Args
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+
+							assert.Error(t, proc.WaitWithProgress(ctx, time.Millisecond, func(ProcessInfo) {}))
+							assert.Error(t, proc.WaitWithProgress(ctx, 0, func(ProcessInfo) {}))
+							assert.Error(t, proc.WaitWithProgress(ctx, time.Millisecond, nil))
+						},
 						"WaitGivesProperExitCodeOnSignalDeath": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
 							proc, err := makep(ctx, testutil.SleepCreateOpts(100))
 							require.NoError(t, err)
@@ -602,7 +1038,57 @@ func TestProcessImplementations(t *testing.T) {
 
 							err = proc.Signal(ctx, syscall.SIGTERM)
 							require.Error(t, err)
-							assert.True(t, strings.Contains(err.Error(), "cannot signal a process that has terminated"))
+							assert.Equal(t, ErrProcessComplete, errors.Cause(err))
+							assert.Equal(t, ErrProcessComplete, errors.Cause(proc.Signal(ctx, syscall.SIGTERM)), "signaling again should return the same error")
+						},
+						"CallingSignalOnRunningProcessSucceeds": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(20))
+							require.NoError(t, err)
+
+							require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+							_, err = proc.Wait(ctx)
+							assert.Error(t, err)
+							assert.True(t, proc.Complete(ctx))
+							assert.Equal(t, ErrProcessComplete, errors.Cause(proc.Signal(ctx, syscall.SIGTERM)))
+						},
+						"DoneIsClosedAfterCompletion": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, opts)
+							require.NoError(t, err)
+
+							select {
+							case <-proc.Done():
+							case <-ctx.Done():
+								require.FailNow(t, "process did not complete")
+							}
+							assert.True(t, proc.Complete(ctx))
+							_, err = proc.Wait(ctx)
+							assert.NoError(t, err)
+
+							select {
+							case <-proc.Done():
+							default:
+								assert.Fail(t, "done channel should remain closed after completion")
+							}
+						},
+						"DoneIsOpenWhileRunning": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
+							proc, err := makep(ctx, testutil.SleepCreateOpts(20))
+							require.NoError(t, err)
+
+							select {
+							case <-proc.Done():
+								assert.Fail(t, "done channel should not be closed while the process is running")
+							case <-time.After(100 * time.Millisecond):
+							}
+
+							procCtx, cancel := ProcessContext(ctx, proc)
+							defer cancel()
+							require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+							select {
+							case <-procCtx.Done():
+							case <-ctx.Done():
+								require.FailNow(t, "process context was not canceled")
+							}
+							assert.True(t, proc.Complete(ctx))
 						},
 						"StandardInput": func(ctx context.Context, t *testing.T, opts *options.Create, makep ProcessConstructor) {
 							for subTestName, subTestCase := range map[string]func(ctx context.Context, t *testing.T, opts *options.Create, expectedOutput string, stdin []byte, output *bytes.Buffer){
@@ -703,3 +1189,912 @@ func TestProcessImplementations(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessEndReason(t *testing.T) {
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			for testName, testCase := range map[string]func(ctx context.Context, t *testing.T){
+				"Exited": func(ctx context.Context, t *testing.T) {
+					opts := testutil.FalseCreateOpts()
+					opts.Implementation = impl
+					proc, err := NewProcess(ctx, opts)
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, EndReasonExited, proc.Info(ctx).EndReason)
+				},
+				"RunningProcessHasNoReason": func(ctx context.Context, t *testing.T) {
+					opts := testutil.SleepCreateOpts(10)
+					opts.Implementation = impl
+					proc, err := NewProcess(ctx, opts)
+					require.NoError(t, err)
+					assert.Empty(t, proc.Info(ctx).EndReason)
+					require.NoError(t, Kill(ctx, proc))
+				},
+				"Signaled": func(ctx context.Context, t *testing.T) {
+					if runtime.GOOS == "windows" {
+						t.Skip("processes are not terminated by signals on Windows")
+					}
+					opts := testutil.SleepCreateOpts(10)
+					opts.Implementation = impl
+					proc, err := NewProcess(ctx, opts)
+					require.NoError(t, err)
+					require.NoError(t, proc.Signal(ctx, syscall.SIGTERM))
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, EndReasonSignaled, proc.Info(ctx).EndReason)
+				},
+				"TimedOut": func(ctx context.Context, t *testing.T) {
+					opts := testutil.SleepCreateOpts(10)
+					opts.Implementation = impl
+					opts.Timeout = time.Second
+					proc, err := NewProcess(ctx, opts)
+					require.NoError(t, err)
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, EndReasonTimedOut, proc.Info(ctx).EndReason)
+				},
+				"ContextCanceled": func(ctx context.Context, t *testing.T) {
+					procCtx, procCancel := context.WithCancel(ctx)
+					opts := testutil.SleepCreateOpts(10)
+					opts.Implementation = impl
+					proc, err := NewProcess(procCtx, opts)
+					require.NoError(t, err)
+					procCancel()
+					_, _ = proc.Wait(ctx)
+					assert.Equal(t, EndReasonContextCanceled, proc.Info(ctx).EndReason)
+				},
+				"Aborted": func(ctx context.Context, t *testing.T) {
+					manager, err := NewSynchronizedManager(false)
+					require.NoError(t, err)
+					opts := testutil.SleepCreateOpts(10)
+					opts.Implementation = impl
+					proc, err := manager.CreateProcess(ctx, opts)
+					require.NoError(t, err)
+					require.NoError(t, manager.Close(ctx))
+					_, err = proc.Wait(ctx)
+					assert.Error(t, err)
+					assert.Equal(t, EndReasonAborted, proc.Info(ctx).EndReason)
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+					defer cancel()
+					testCase(ctx, t)
+				})
+			}
+		})
+	}
+}
+
+func TestProcessOutputChecksum(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			run := func(t *testing.T, opts *options.Create) ProcessInfo {
+				opts.Implementation = impl
+				proc, err := NewProcess(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+				return proc.Info(ctx)
+			}
+			makeOpts := func() *options.Create {
+				return &options.Create{
+					Args:   []string{"sh", "-c", "echo foo; echo bar >&2"},
+					Output: options.Output{ChecksumAlgorithm: options.ChecksumSHA256},
+				}
+			}
+
+			t.Run("IsIdenticalForDeterministicCommand", func(t *testing.T) {
+				first := run(t, makeOpts())
+				second := run(t, makeOpts())
+				require.NotNil(t, first.OutputChecksum)
+				require.NotNil(t, second.OutputChecksum)
+				assert.Equal(t, *first.OutputChecksum, *second.OutputChecksum)
+			})
+			t.Run("CoversOutputAndErrorSeparately", func(t *testing.T) {
+				info := run(t, makeOpts())
+				require.NotNil(t, info.OutputChecksum)
+				assert.Equal(t, options.ChecksumSHA256, info.OutputChecksum.Algorithm)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("foo\n"))), info.OutputChecksum.Output)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("bar\n"))), info.OutputChecksum.Error)
+			})
+			t.Run("CoversRedirectedOutputSeparately", func(t *testing.T) {
+				opts := makeOpts()
+				opts.Output.Output = &bytes.Buffer{}
+				opts.Output.SendErrorToOutput = true
+				info := run(t, opts)
+				require.NotNil(t, info.OutputChecksum)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("foo\n"))), info.OutputChecksum.Output)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("bar\n"))), info.OutputChecksum.Error)
+			})
+			t.Run("IsOmittedWithoutAlgorithm", func(t *testing.T) {
+				info := run(t, &options.Create{Args: []string{"echo", "foo"}})
+				assert.Nil(t, info.OutputChecksum)
+			})
+		})
+	}
+}
+
+func TestProcessInfoDuringWait(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			proc, err := NewProcess(ctx, &options.Create{
+				Args:           []string{"sleep", "0.5"},
+				Implementation: impl,
+			})
+			require.NoError(t, err)
+
+			waitErr := make(chan error, 1)
+			go func() {
+				_, err := proc.Wait(ctx)
+				waitErr <- err
+			}()
+
+			wg := &sync.WaitGroup{}
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for proc.Running(ctx) {
+						info := proc.Info(ctx)
+						assert.Equal(t, proc.ID(), info.ID)
+						proc.Tag("polled")
+						_ = proc.GetTags()
+						_ = proc.Complete(ctx)
+					}
+				}()
+			}
+
+			require.NoError(t, <-waitErr)
+			wg.Wait()
+
+			info := proc.Info(ctx)
+			assert.True(t, info.Complete)
+			assert.False(t, info.IsRunning)
+			assert.True(t, info.Successful)
+			assert.Zero(t, info.ExitCode)
+			assert.Contains(t, proc.GetTags(), "polled")
+		})
+	}
+}
+
+func TestProcessTagsConcurrently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			proc, err := NewProcess(ctx, &options.Create{
+				Args:           []string{"sleep", "0.5"},
+				Implementation: impl,
+				Tags:           []string{"initial", "initial"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"initial"}, proc.GetTags())
+			assert.Equal(t, []string{"initial"}, proc.Info(ctx).Options.Tags)
+
+			wg := &sync.WaitGroup{}
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						proc.Tag(fmt.Sprintf("tag%d", j%10))
+						if i == 0 && j%25 == 0 {
+							proc.ResetTags()
+						}
+						_ = proc.GetTags()
+						_ = proc.Info(ctx)
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+
+			proc.Tag("final")
+			tags := proc.GetTags()
+			assert.Contains(t, tags, "final")
+			assert.Equal(t, tags, proc.Info(ctx).Options.Tags)
+
+			proc.ResetTags()
+			assert.Empty(t, proc.GetTags())
+			assert.Empty(t, proc.Info(ctx).Options.Tags)
+		})
+	}
+}
+
+func TestProcessDeferStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, synchronized := range map[string]bool{
+		"Basic":        false,
+		"Synchronized": true,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			makeDeferred := func(t *testing.T) (Process, *util.LocalBuffer) {
+				buf := util.NewLocalBuffer(bytes.Buffer{})
+				opts := &options.Create{
+					Args:         []string{"echo", "started"},
+					DeferStart:   true,
+					Synchronized: synchronized,
+				}
+				opts.Output.Output = buf
+				proc, err := NewProcess(ctx, opts)
+				require.NoError(t, err)
+				return proc, buf
+			}
+
+			t.Run("DoesNotRunUntilStarted", func(t *testing.T) {
+				proc, buf := makeDeferred(t)
+
+				info := proc.Info(ctx)
+				assert.True(t, info.NotStarted)
+				assert.False(t, info.IsRunning)
+				assert.False(t, info.Complete)
+				assert.Zero(t, info.PID)
+				assert.True(t, info.StartAt.IsZero())
+				assert.False(t, proc.Running(ctx))
+				assert.False(t, proc.Complete(ctx))
+
+				time.Sleep(100 * time.Millisecond)
+				assert.Empty(t, buf.String())
+
+				require.NoError(t, proc.Start(ctx))
+				info = proc.Info(ctx)
+				assert.False(t, info.NotStarted)
+				assert.NotZero(t, info.PID)
+				assert.False(t, info.StartAt.IsZero())
+
+				exitCode, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Zero(t, exitCode)
+				assert.True(t, proc.Complete(ctx))
+				assert.Equal(t, "started\n", buf.String())
+			})
+			t.Run("WaitBlocksUntilStarted", func(t *testing.T) {
+				proc, _ := makeDeferred(t)
+
+				tctx, tcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+				defer tcancel()
+				_, err := proc.Wait(tctx)
+				assert.Error(t, err)
+
+				require.NoError(t, proc.Start(ctx))
+				_, err = proc.Wait(ctx)
+				assert.NoError(t, err)
+			})
+			t.Run("StartErrorsIfAlreadyStarted", func(t *testing.T) {
+				proc, _ := makeDeferred(t)
+				require.NoError(t, proc.Start(ctx))
+				assert.Error(t, proc.Start(ctx))
+				_, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Error(t, proc.Start(ctx))
+			})
+			t.Run("StartErrorsWithoutDeferStart", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:         []string{"echo", "started"},
+					Synchronized: synchronized,
+				})
+				require.NoError(t, err)
+				assert.Error(t, proc.Start(ctx))
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+			})
+			t.Run("SignalErrorsBeforeStart", func(t *testing.T) {
+				proc, _ := makeDeferred(t)
+				assert.Equal(t, ErrProcessNotStarted, errors.Cause(proc.Signal(ctx, syscall.SIGTERM)))
+				require.NoError(t, proc.Start(ctx))
+				_, err := proc.Wait(ctx)
+				require.NoError(t, err)
+			})
+			t.Run("TimeoutBeginsWhenStarted", func(t *testing.T) {
+				opts := &options.Create{
+					Args:         []string{"echo", "started"},
+					DeferStart:   true,
+					Synchronized: synchronized,
+					Timeout:      time.Second,
+				}
+				proc, err := NewProcess(ctx, opts)
+				require.NoError(t, err)
+
+				time.Sleep(opts.Timeout + 200*time.Millisecond)
+
+				require.NoError(t, proc.Start(ctx))
+				exitCode, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Zero(t, exitCode)
+				assert.False(t, proc.Info(ctx).Timeout)
+			})
+			t.Run("RespawnStartsImmediately", func(t *testing.T) {
+				proc, _ := makeDeferred(t)
+				respawned, err := proc.Respawn(ctx)
+				require.NoError(t, err)
+				assert.False(t, respawned.Info(ctx).NotStarted)
+				_, err = respawned.Wait(ctx)
+				require.NoError(t, err)
+				require.NoError(t, proc.Start(ctx))
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+			})
+		})
+	}
+	t.Run("BlockingProcessIsNotSupported", func(t *testing.T) {
+		_, err := NewProcess(ctx, &options.Create{
+			Args:           []string{"echo", "started"},
+			DeferStart:     true,
+			Implementation: options.ProcessImplementationBlocking,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestProcessWaitTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("BackgroundWaitTimesOut", func(t *testing.T) {
+				waitTimeout := 200 * time.Millisecond
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sleep", "5"},
+					Implementation: impl,
+					WaitTimeout:    waitTimeout,
+				})
+				require.NoError(t, err)
+
+				start := time.Now()
+				exitCode, err := proc.Wait(context.Background())
+				elapsed := time.Since(start)
+				assert.Equal(t, context.DeadlineExceeded, err)
+				assert.Equal(t, -1, exitCode)
+				assert.True(t, elapsed >= waitTimeout, "wait returned after %s", elapsed)
+				assert.True(t, elapsed < 2*time.Second, "wait returned after %s", elapsed)
+
+				// The wait timeout does not affect the process itself.
+				assert.True(t, proc.Running(ctx))
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, err = proc.Wait(ctx)
+				assert.Error(t, err)
+				assert.NotEqual(t, context.DeadlineExceeded, err)
+				assert.True(t, proc.Complete(ctx))
+			})
+			t.Run("WaitSucceedsBeforeTimeout", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"true"},
+					Implementation: impl,
+					WaitTimeout:    5 * time.Second,
+				})
+				require.NoError(t, err)
+
+				exitCode, err := proc.Wait(context.Background())
+				assert.NoError(t, err)
+				assert.Zero(t, exitCode)
+			})
+			t.Run("CallerCancellationIsNotTimeout", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sleep", "5"},
+					Implementation: impl,
+					WaitTimeout:    5 * time.Second,
+				})
+				require.NoError(t, err)
+
+				cctx, ccancel := context.WithCancel(ctx)
+				ccancel()
+				_, err = proc.Wait(cctx)
+				assert.Error(t, err)
+				assert.NotEqual(t, context.DeadlineExceeded, err)
+
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+		})
+	}
+}
+
+func TestProcessWaitForOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			makeProc := func(t *testing.T, script string) Process {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sh", "-c", script},
+					Implementation: impl,
+					Output:         options.Output{WatchLines: true},
+				})
+				require.NoError(t, err)
+				return proc
+			}
+
+			t.Run("ReturnsOnMatch", func(t *testing.T) {
+				proc := makeProc(t, "echo starting; sleep 0.5; echo 'server started on port 8080'; exec sleep 5")
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`server started`)))
+				assert.True(t, proc.Running(ctx))
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+			t.Run("MatchesError", func(t *testing.T) {
+				proc := makeProc(t, "sleep 0.5; echo ready >&2; exec sleep 5")
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`^ready$`)))
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+			t.Run("MatchesOutputWrittenBeforeWaiting", func(t *testing.T) {
+				proc := makeProc(t, "echo banner; exec sleep 5")
+				time.Sleep(500 * time.Millisecond)
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`banner`)))
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+			t.Run("ErrorsIfProcessExitsFirst", func(t *testing.T) {
+				proc := makeProc(t, "echo starting; sleep 0.2")
+				err := proc.WaitForOutput(ctx, regexp.MustCompile(`server started`))
+				assert.Equal(t, ErrOutputNotMatched, err)
+				assert.True(t, proc.Complete(ctx))
+			})
+			t.Run("ErrorsIfContextCanceled", func(t *testing.T) {
+				proc := makeProc(t, "exec sleep 5")
+				tctx, tcancel := context.WithTimeout(ctx, 200*time.Millisecond)
+				defer tcancel()
+				assert.Error(t, proc.WaitForOutput(tctx, regexp.MustCompile(`never`)))
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+			t.Run("ErrorsWithoutWatchLines", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"echo", "foo"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+				err = proc.WaitForOutput(ctx, regexp.MustCompile(`foo`))
+				assert.Error(t, err)
+				assert.NotEqual(t, ErrOutputNotMatched, err)
+			})
+		})
+	}
+}
+
+func TestProcessOutputCounts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("MatchesOutputAfterCompletion", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sh", "-c", `printf 'one\ntwo\nthree\n'; printf 'error\nwithout newline' >&2`},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				info := proc.Info(ctx)
+				assert.EqualValues(t, len("one\ntwo\nthree\n"), info.OutputBytes)
+				assert.Equal(t, 3, info.OutputLines)
+				assert.EqualValues(t, len("error\nwithout newline"), info.ErrorBytes)
+				assert.Equal(t, 2, info.ErrorLines)
+			})
+			t.Run("IsZeroWithoutOutput", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"true"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				info := proc.Info(ctx)
+				assert.Zero(t, info.OutputBytes)
+				assert.Zero(t, info.OutputLines)
+				assert.Zero(t, info.ErrorBytes)
+				assert.Zero(t, info.ErrorLines)
+			})
+			t.Run("UpdatesWhileRunning", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"sh", "-c", "echo first; echo second; exec sleep 5"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+
+				assert.Eventually(t, func() bool {
+					info := proc.Info(ctx)
+					return info.OutputLines == 2 && info.OutputBytes == int64(len("first\nsecond\n"))
+				}, 2*time.Second, 10*time.Millisecond)
+				assert.True(t, proc.Running(ctx))
+
+				require.NoError(t, proc.Signal(ctx, syscall.SIGKILL))
+				_, _ = proc.Wait(ctx)
+			})
+		})
+	}
+}
+
+func TestProcessOutputFlushedOnKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			for testName, kill := range map[string]func(context.CancelFunc, Process) error{
+				"Signal": func(_ context.CancelFunc, proc Process) error {
+					return proc.Signal(ctx, syscall.SIGKILL)
+				},
+				"ContextCancel": func(procCancel context.CancelFunc, _ Process) error {
+					procCancel()
+					return nil
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					logger, err := NewInMemoryLogger(100)
+					require.NoError(t, err)
+
+					procCtx, procCancel := context.WithCancel(ctx)
+					defer procCancel()
+					proc, err := NewProcess(procCtx, &options.Create{
+						Args:           []string{"sh", "-c", "echo first; printf last; exec sleep 10"},
+						Implementation: impl,
+						Output: options.Output{
+							Loggers: []*options.LoggerConfig{logger},
+						},
+					})
+					require.NoError(t, err)
+
+					triggerOutput := make(chan []string, 1)
+					require.NoError(t, proc.RegisterTrigger(ctx, func(info ProcessInfo) {
+						lines, err := getInMemoryLogTail(info, 10)
+						assert.NoError(t, err)
+						triggerOutput <- lines
+					}))
+
+					require.Eventually(t, func() bool {
+						return proc.Info(ctx).OutputBytes == int64(len("first\nlast"))
+					}, 2*time.Second, 10*time.Millisecond)
+
+					require.NoError(t, kill(procCancel, proc))
+					_, _ = proc.Wait(ctx)
+
+					select {
+					case lines := <-triggerOutput:
+						assert.Equal(t, "first\nlast", strings.Join(lines, "\n"))
+					case <-ctx.Done():
+						require.FailNow(t, "trigger did not run")
+					}
+
+					lines, err := GetInMemoryLogStream(ctx, proc, 10)
+					require.NoError(t, err)
+					assert.Equal(t, "first\nlast", strings.Join(lines, "\n"))
+				})
+			}
+		})
+	}
+}
+
+func TestProcessOutputThrottled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("LimitsRateWithoutDroppingOutput", func(t *testing.T) {
+				output := &bytes.Buffer{}
+				start := time.Now()
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"seq", "1", "30"},
+					Implementation: impl,
+					Output: options.Output{
+						Output:            output,
+						MaxLinesPerSecond: 10,
+					},
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				elapsed := time.Since(start)
+				assert.True(t, elapsed >= 1500*time.Millisecond, "twenty lines beyond the burst should take about two seconds, took %s", elapsed)
+				lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+				require.Len(t, lines, 30)
+				assert.Equal(t, "1", lines[0])
+				assert.Equal(t, "30", lines[29])
+				assert.Equal(t, 30, proc.Info(ctx).OutputLines)
+			})
+			t.Run("CanceledContextStopsThrottledProcess", func(t *testing.T) {
+				procCtx, procCancel := context.WithCancel(ctx)
+				defer procCancel()
+				proc, err := NewProcess(procCtx, &options.Create{
+					Args:           []string{"yes"},
+					Implementation: impl,
+					Output: options.Output{
+						Output:            ioutil.Discard,
+						MaxLinesPerSecond: 1,
+					},
+				})
+				require.NoError(t, err)
+
+				time.Sleep(100 * time.Millisecond)
+				procCancel()
+				_, _ = proc.Wait(ctx)
+				assert.True(t, proc.Complete(ctx))
+				assert.NoError(t, ctx.Err(), "process should exit without waiting for throttled output")
+			})
+		})
+	}
+}
+
+func TestProcessOutputTruncated(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("CapturesPartialOutputOnTimeout", func(t *testing.T) {
+				buf := util.NewLocalBuffer(bytes.Buffer{})
+				opts := &options.Create{
+					Args:           []string{"sh", "-c", "echo line1; echo line2; exec sleep 5"},
+					Implementation: impl,
+					Timeout:        time.Second,
+				}
+				opts.Output.Output = buf
+				proc, err := NewProcess(ctx, opts)
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				assert.Error(t, err)
+
+				info := proc.Info(ctx)
+				assert.True(t, info.Timeout)
+				assert.True(t, info.OutputTruncated)
+				assert.Contains(t, buf.String(), "line1\nline2\n")
+			})
+			t.Run("IsNotSetWithoutTimeout", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           []string{"echo", "line1"},
+					Implementation: impl,
+					Timeout:        time.Minute,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.False(t, proc.Info(ctx).OutputTruncated)
+			})
+		})
+	}
+}
+
+func TestProcessResolvedCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	dir, err := ioutil.TempDir(testutil.BuildDirectory(), "resolved-command")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("MatchesOptions", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:             []string{"echo", "foo", "bar"},
+					Environment:      map[string]string{"FOO": "bar"},
+					WorkingDirectory: dir,
+					OverrideEnviron:  true,
+					Implementation:   impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				args, env, resolvedDir := proc.ResolvedCommand()
+				assert.Equal(t, []string{"echo", "foo", "bar"}, args)
+				assert.ElementsMatch(t, []string{"FOO=bar", EnvironID + "=" + proc.ID()}, env)
+				assert.Equal(t, dir, resolvedDir)
+			})
+			t.Run("IncludesShellAndInheritedEnvironment", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					ShellCommand:   "echo foo",
+					Environment:    map[string]string{"FOO": "bar"},
+					Implementation: impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				args, env, _ := proc.ResolvedCommand()
+				assert.Equal(t, []string{"/bin/sh", "-c", "echo foo"}, args)
+				assert.Subset(t, env, append(os.Environ(), "FOO=bar"))
+			})
+			t.Run("RedactsEnvironment", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:                []string{"true"},
+					Environment:         map[string]string{"SECRET": "hunter2", "PUBLIC": "value"},
+					RedactedEnvironment: []string{"SECRET", "UNSET"},
+					OverrideEnviron:     true,
+					Implementation:      impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				_, env, _ := proc.ResolvedCommand()
+				assert.Contains(t, env, "SECRET="+RedactedEnvValue)
+				assert.Contains(t, env, "PUBLIC=value")
+				for _, kv := range env {
+					assert.NotContains(t, kv, "hunter2")
+				}
+			})
+			t.Run("ReturnsCopies", func(t *testing.T) {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:            []string{"true"},
+					OverrideEnviron: true,
+					Implementation:  impl,
+				})
+				require.NoError(t, err)
+				_, err = proc.Wait(ctx)
+				require.NoError(t, err)
+
+				args, env, _ := proc.ResolvedCommand()
+				args[0] = "false"
+				env[0] = "FOO=baz"
+				args, env, _ = proc.ResolvedCommand()
+				assert.Equal(t, []string{"true"}, args)
+				assert.Equal(t, []string{EnvironID + "=" + proc.ID()}, env)
+			})
+		})
+	}
+}
+
+func TestProcessArgsFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	// The arguments are long enough to exceed the limit on the length
+	// of the command line.
+	const numArgs = 200000
+	args := make([]string, 0, numArgs)
+	for i := 0; i < numArgs; i++ {
+		args = append(args, fmt.Sprintf("argument-%06d", i))
+	}
+
+	dir, err := ioutil.TempDir(testutil.BuildDirectory(), "args-file")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "count-args")
+	require.NoError(t, ioutil.WriteFile(script, []byte("#!/bin/sh\nfile=\"${1#@}\"\necho \"$file\"\nwc -l < \"$file\"\n"), 0755))
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			t.Run("ResponseFileIsReadAndRemoved", func(t *testing.T) {
+				output := &bytes.Buffer{}
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           append([]string{script}, args...),
+					ArgsFile:       options.ArgsFileResponse,
+					Implementation: impl,
+					Output:         options.Output{Output: output},
+				})
+				require.NoError(t, err)
+				exitCode, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Zero(t, exitCode)
+
+				lines := strings.Fields(output.String())
+				require.Len(t, lines, 2)
+				assert.Equal(t, strconv.Itoa(numArgs), lines[1])
+				_, err = os.Stat(lines[0])
+				assert.True(t, os.IsNotExist(err), "arguments file should be removed on completion")
+			})
+			t.Run("SplitArgsAreAllPassedAndFileIsRemoved", func(t *testing.T) {
+				before, err := filepath.Glob(filepath.Join(os.TempDir(), "jasper-args-*"))
+				require.NoError(t, err)
+
+				output := &bytes.Buffer{}
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           append([]string{"echo"}, args...),
+					ArgsFile:       options.ArgsFileSplit,
+					Implementation: impl,
+					Output:         options.Output{Output: output},
+				})
+				require.NoError(t, err)
+				exitCode, err := proc.Wait(ctx)
+				require.NoError(t, err)
+				assert.Zero(t, exitCode)
+
+				assert.Equal(t, args, strings.Fields(output.String()))
+				after, err := filepath.Glob(filepath.Join(os.TempDir(), "jasper-args-*"))
+				require.NoError(t, err)
+				assert.ElementsMatch(t, before, after, "arguments file should be removed on completion")
+			})
+		})
+	}
+}
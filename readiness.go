@@ -0,0 +1,27 @@
+package jasper
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/jasper/options"
+)
+
+// awaitReadiness waits for the process's readiness probe to succeed, returning
+// an error if it does not succeed within the probe's timeout. It also returns
+// an error if the context is done or done is closed because the process has
+// completed before it became ready.
+func awaitReadiness(ctx context.Context, opts *options.Create, done <-chan struct{}) error {
+	probe := opts.ReadinessProbe
+	probeCtx, cancel := context.WithTimeout(ctx, probe.GetTimeout())
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-probeCtx.Done():
+		}
+	}()
+
+	return errors.Wrapf(probe.Wait(probeCtx, &opts.Output), "process did not become ready within %s", probe.GetTimeout())
+}
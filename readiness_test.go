@@ -0,0 +1,108 @@
+package jasper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestProcessReadinessProbe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	const interval = 20 * time.Millisecond
+
+	for procType, makeProc := range map[string]ProcessConstructor{
+		"Basic":    newBasicProcess,
+		"Blocking": newBlockingProcess,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, url string, ready *int32){
+				"RunningOnlyAfterSlowServerIsReady": func(ctx context.Context, t *testing.T, url string, ready *int32) {
+					opts := testutil.SleepCreateOpts(10)
+					opts.ReadinessProbe = &options.ReadinessProbe{URL: url, Interval: interval}
+					proc, err := makeProc(ctx, opts)
+					require.NoError(t, err)
+					defer func() { assert.NoError(t, proc.Signal(ctx, syscall.SIGKILL)) }()
+
+					time.Sleep(10 * interval)
+					info := proc.Info(ctx)
+					assert.False(t, info.IsRunning)
+					assert.False(t, info.Complete)
+					assert.True(t, proc.Running(ctx), "process that is not ready should still be signalable")
+
+					atomic.StoreInt32(ready, 1)
+					assert.Eventually(t, func() bool {
+						return proc.Info(ctx).IsRunning
+					}, 5*time.Second, interval)
+				},
+				"FailsIfNotReadyBeforeTimeout": func(ctx context.Context, t *testing.T, url string, ready *int32) {
+					opts := testutil.SleepCreateOpts(10)
+					opts.ReadinessProbe = &options.ReadinessProbe{URL: url, Interval: interval, Timeout: 10 * interval}
+					proc, err := makeProc(ctx, opts)
+					require.NoError(t, err)
+
+					_, err = proc.Wait(ctx)
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), "did not become ready")
+
+					info := proc.Info(ctx)
+					assert.True(t, info.Complete)
+					assert.False(t, info.IsRunning)
+					assert.False(t, info.Successful)
+					assert.Equal(t, EndReasonNotReady, info.EndReason)
+				},
+				"RunningAfterOutputMatches": func(ctx context.Context, t *testing.T, _ string, _ *int32) {
+					opts := &options.Create{Args: []string{"sh", "-c", "sleep 0.2; echo listening; sleep 10"}}
+					opts.ReadinessProbe = &options.ReadinessProbe{OutputPattern: "^listening$"}
+					proc, err := makeProc(ctx, opts)
+					require.NoError(t, err)
+					defer func() { assert.NoError(t, proc.Signal(ctx, syscall.SIGKILL)) }()
+
+					assert.False(t, proc.Info(ctx).IsRunning)
+					assert.Eventually(t, func() bool {
+						return proc.Info(ctx).IsRunning
+					}, 5*time.Second, interval)
+				},
+				"SucceedsIfExitingBeforeReady": func(ctx context.Context, t *testing.T, url string, _ *int32) {
+					opts := testutil.TrueCreateOpts()
+					opts.ReadinessProbe = &options.ReadinessProbe{URL: url, Interval: interval}
+					proc, err := makeProc(ctx, opts)
+					require.NoError(t, err)
+
+					_, err = proc.Wait(ctx)
+					require.NoError(t, err)
+					info := proc.Info(ctx)
+					assert.True(t, info.Successful)
+					assert.Equal(t, EndReasonExited, info.EndReason)
+				},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+					defer cancel()
+
+					var ready int32
+					srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if atomic.LoadInt32(&ready) == 0 {
+							w.WriteHeader(http.StatusServiceUnavailable)
+						}
+					}))
+					defer srv.Close()
+
+					testCase(ctx, t, srv.URL, &ready)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/tychoish/gimlet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthFunc validates the credentials of a request to a remote service before
+// the request is handled. If it returns an error, the request is denied.
+// BearerToken can be used to get the token sent with the request from the
+// context.
+type AuthFunc func(context.Context) error
+
+// authorizationKey is the REST header and RPC metadata key that holds the
+// request's credentials.
+const authorizationKey = "authorization"
+
+type restHeaderKey struct{}
+
+// BearerToken returns the bearer token sent with a request to a remote
+// service, given the context of the request. REST requests send the token in
+// the Authorization header and RPC requests send it in the "authorization"
+// metadata, both in the form "Bearer <token>".
+func BearerToken(ctx context.Context) (string, bool) {
+	var auth string
+	if header, ok := ctx.Value(restHeaderKey{}).(http.Header); ok {
+		auth = header.Get(authorizationKey)
+	} else if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(authorizationKey); len(vals) != 0 {
+			auth = vals[0]
+		}
+	}
+
+	const prefix = "bearer "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	return auth[len(prefix):], true
+}
+
+// makeRESTAuthMiddleware returns middleware that denies requests with a
+// forbidden status if they fail authentication.
+func makeRESTAuthMiddleware(auth AuthFunc) gimlet.HandlerFuncWrapper {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), restHeaderKey{}, r.Header)
+			if err := auth(ctx); err != nil {
+				writeError(rw, gimlet.ErrorResponse{
+					StatusCode: http.StatusForbidden,
+					Message:    err.Error(),
+				})
+				return
+			}
+
+			next(rw, r.WithContext(ctx))
+		}
+	}
+}
+
+// RPCAuthServerOptions returns the server options that authenticate every
+// request to an RPC service using the given function. Requests that fail
+// authentication are denied with a PermissionDenied status. These options
+// can be passed to StartRPCService.
+func RPCAuthServerOptions(auth AuthFunc) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authenticateRPC(ctx, auth); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authenticateRPC(stream.Context(), auth); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+}
+
+func authenticateRPC(ctx context.Context, auth AuthFunc) error {
+	err := auth(ctx)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(codes.PermissionDenied, err.Error())
+}
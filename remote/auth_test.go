@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testAuthToken = "secret-token"
+
+func checkTestAuthToken(ctx context.Context) error {
+	token, ok := BearerToken(ctx)
+	if !ok {
+		return errors.New("missing bearer token")
+	}
+	if token != testAuthToken {
+		return errors.New("invalid bearer token")
+	}
+	return nil
+}
+
+type bearerTokenTransport struct {
+	token string
+}
+
+func (t bearerTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if t.token != "" {
+		r.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+type bearerTokenCredentials struct {
+	token string
+}
+
+func (c bearerTokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	if c.token == "" {
+		return nil, nil
+	}
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (bearerTokenCredentials) RequireTransportSecurity() bool { return false }
+
+func TestBearerToken(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		header   string
+		token    string
+		hasToken bool
+	}{
+		"Valid":            {header: "Bearer foo", token: "foo", hasToken: true},
+		"CaseInsensitive":  {header: "bearer foo", token: "foo", hasToken: true},
+		"Missing":          {},
+		"WrongScheme":      {header: "Basic foo"},
+		"EmptyBearerToken": {header: "Bearer "},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			header := http.Header{}
+			if testCase.header != "" {
+				header.Set("Authorization", testCase.header)
+			}
+			token, ok := BearerToken(context.WithValue(context.Background(), restHeaderKey{}, header))
+			assert.Equal(t, testCase.hasToken, ok)
+			assert.Equal(t, testCase.token, token)
+		})
+	}
+}
+
+func TestRESTServiceAuth(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		token   string
+		allowed bool
+	}{
+		"ValidTokenIsAllowed":  {token: testAuthToken, allowed: true},
+		"InvalidTokenIsDenied": {token: "wrong-token"},
+		"MissingTokenIsDenied": {},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := jasper.NewSynchronizedManager(false)
+			require.NoError(t, err)
+			srv := NewRestService(manager)
+			srv.SetAuthFunc(checkTestAuthToken)
+			app := srv.App(ctx)
+			app.SetPrefix("jasper")
+			handler, err := app.Handler()
+			require.NoError(t, err)
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			client := &restClient{
+				prefix: server.URL + "/jasper/v1",
+				client: &http.Client{Transport: bearerTokenTransport{token: testCase.token}},
+			}
+
+			proc, err := client.CreateProcess(ctx, testutil.TrueCreateOpts())
+			procs, listErr := manager.List(ctx, options.All)
+			require.NoError(t, listErr)
+			if testCase.allowed {
+				require.NoError(t, err)
+				assert.NotNil(t, proc)
+				assert.Len(t, procs, 1)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "bearer token")
+			assert.Nil(t, proc)
+			assert.Empty(t, procs)
+		})
+	}
+}
+
+func TestRPCServiceAuth(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		token   string
+		allowed bool
+	}{
+		"ValidTokenIsAllowed":  {token: testAuthToken, allowed: true},
+		"InvalidTokenIsDenied": {token: "wrong-token"},
+		"MissingTokenIsDenied": {},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := jasper.NewSynchronizedManager(false)
+			require.NoError(t, err)
+			addr, err := tryStartRPCService(ctx, func(ctx context.Context, addr net.Addr) error {
+				closeService, err := StartRPCService(ctx, manager, addr, nil, RPCAuthServerOptions(checkTestAuthToken)...)
+				if err != nil {
+					return err
+				}
+				go func() {
+					<-ctx.Done()
+					assert.NoError(t, closeService())
+				}()
+				return nil
+			})
+			require.NoError(t, err)
+
+			conn, err := grpc.DialContext(ctx, addr.String(),
+				grpc.WithInsecure(),
+				grpc.WithBlock(),
+				grpc.WithPerRPCCredentials(bearerTokenCredentials{token: testCase.token}),
+			)
+			require.NoError(t, err)
+			client := newRPCClient(conn)
+			defer func() {
+				assert.NoError(t, client.CloseConnection())
+			}()
+
+			proc, err := client.CreateProcess(ctx, testutil.TrueCreateOpts())
+			procs, listErr := manager.List(ctx, options.All)
+			require.NoError(t, listErr)
+			if testCase.allowed {
+				require.NoError(t, err)
+				assert.NotNil(t, proc)
+				assert.Len(t, procs, 1)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, codes.PermissionDenied, status.Code(errors.Cause(err)))
+			assert.Nil(t, proc)
+			assert.Empty(t, procs)
+		})
+	}
+}
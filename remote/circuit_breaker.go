@@ -0,0 +1,371 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/gimlet"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/jasper"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/scripting"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultCircuitBreakerFailureThreshold is the default number of
+	// consecutive failed calls that trip a circuit breaker.
+	DefaultCircuitBreakerFailureThreshold = 5
+	// DefaultCircuitBreakerCooldown is the default amount of time that a
+	// tripped circuit breaker fails calls before allowing a trial call.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by a circuit breaker client when it fails a call
+// without making it because the circuit breaker has tripped.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerOptions configures a client created by
+// NewCircuitBreakerClient.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed calls that
+	// trip the circuit breaker. If it is zero,
+	// DefaultCircuitBreakerFailureThreshold is used.
+	FailureThreshold int
+	// Cooldown is how long the tripped circuit breaker fails calls
+	// before it allows a trial call to check if the manager has
+	// recovered. If it is zero, DefaultCircuitBreakerCooldown is used.
+	Cooldown time.Duration
+}
+
+// Validate checks that the options are valid and sets the defaults for any
+// options that are unset.
+func (opts *CircuitBreakerOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(opts.FailureThreshold < 0, "failure threshold cannot be negative")
+	catcher.NewWhen(opts.Cooldown < 0, "cooldown cannot be negative")
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if opts.FailureThreshold == 0 {
+		opts.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if opts.Cooldown == 0 {
+		opts.Cooldown = DefaultCircuitBreakerCooldown
+	}
+
+	return nil
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker tracks the outcome of calls to decide whether to allow new
+// calls.
+type circuitBreaker struct {
+	opts     CircuitBreakerOptions
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns ErrCircuitOpen if the call should fail fast. Once the cooldown
+// has elapsed, it allows a single trial call, whose outcome must be passed to
+// record.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitClosed:
+		return nil
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.opts.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		return nil
+	default:
+		// Another call is already checking if the manager has recovered.
+		return ErrCircuitOpen
+	}
+}
+
+// isTransportError returns whether the error indicates that the manager
+// could not be reached, as opposed to the manager rejecting the call.
+func isTransportError(err error) bool {
+	cause := errors.Cause(err)
+	if cause == io.EOF || cause == io.ErrUnexpectedEOF || cause == context.DeadlineExceeded {
+		return true
+	}
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+	if resp, ok := cause.(gimlet.ErrorResponse); ok {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if s, ok := status.FromError(cause); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return true
+		}
+	}
+	return false
+}
+
+// record updates the state of the circuit breaker with the outcome of an
+// allowed call. Errors that are not caused by failing to reach the manager
+// show that it is reachable, so they are recorded like successful calls.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isTransportError(err) {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.opts.FailureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// isClosed returns whether the circuit breaker is allowing calls normally.
+// It is used for calls that do not report errors, which are skipped rather
+// than used as trial calls.
+func (b *circuitBreaker) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state == circuitClosed
+}
+
+func (b *circuitBreaker) do(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.record(err)
+	return err
+}
+
+type circuitBreakerClient struct {
+	Manager
+	breaker *circuitBreaker
+}
+
+// NewCircuitBreakerClient returns a client that wraps the given client with a
+// circuit breaker. Once the configured number of consecutive calls to the
+// manager fail, the circuit breaker trips and calls fail with ErrCircuitOpen
+// without being made for the cooldown. After the cooldown, a single trial call
+// is allowed: if it succeeds, calls are made normally again, and otherwise the
+// circuit breaker trips again.
+//
+// Only errors caused by failing to reach the manager, such as network errors
+// and unavailable services, count as failures. Errors returned by the manager
+// itself, such as a process not being found or invalid options, show that it
+// is reachable, so they reset the count like successful calls. Calls that do
+// not return errors, such as Stats and Subscribe, are not counted and return
+// empty values while the circuit breaker is tripped. ID, LoggingCache and
+// CloseConnection are always passed through to the wrapped client.
+func NewCircuitBreakerClient(m Manager, opts CircuitBreakerOptions) (Manager, error) {
+	if m == nil {
+		return nil, errors.New("must specify a client to wrap")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid circuit breaker options")
+	}
+
+	return &circuitBreakerClient{
+		Manager: m,
+		breaker: &circuitBreaker{opts: opts},
+	}, nil
+}
+
+func (c *circuitBreakerClient) CreateProcess(ctx context.Context, opts *options.Create) (jasper.Process, error) {
+	var proc jasper.Process
+	err := c.breaker.do(func() (err error) {
+		proc, err = c.Manager.CreateProcess(ctx, opts)
+		return err
+	})
+	return proc, err
+}
+
+func (c *circuitBreakerClient) CreateCommand(ctx context.Context) *jasper.Command {
+	return c.Manager.CreateCommand(ctx).ProcConstructor(c.CreateProcess)
+}
+
+func (c *circuitBreakerClient) Register(ctx context.Context, proc jasper.Process) error {
+	return c.breaker.do(func() error { return c.Manager.Register(ctx, proc) })
+}
+
+func (c *circuitBreakerClient) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	var proc jasper.Process
+	err := c.breaker.do(func() (err error) {
+		proc, err = c.Manager.RegisterExternal(ctx, pid, opts)
+		return err
+	})
+	return proc, err
+}
+
+func (c *circuitBreakerClient) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
+	var procs []jasper.Process
+	err := c.breaker.do(func() (err error) {
+		procs, err = c.Manager.List(ctx, f)
+		return err
+	})
+	return procs, err
+}
+
+func (c *circuitBreakerClient) Group(ctx context.Context, tag string) ([]jasper.Process, error) {
+	var procs []jasper.Process
+	err := c.breaker.do(func() (err error) {
+		procs, err = c.Manager.Group(ctx, tag)
+		return err
+	})
+	return procs, err
+}
+
+func (c *circuitBreakerClient) Get(ctx context.Context, id string) (jasper.Process, error) {
+	var proc jasper.Process
+	err := c.breaker.do(func() (err error) {
+		proc, err = c.Manager.Get(ctx, id)
+		return err
+	})
+	return proc, err
+}
+
+func (c *circuitBreakerClient) Clear(ctx context.Context) {
+	if c.breaker.isClosed() {
+		c.Manager.Clear(ctx)
+	}
+}
+
+func (c *circuitBreakerClient) Close(ctx context.Context) error {
+	return c.breaker.do(func() error { return c.Manager.Close(ctx) })
+}
+
+func (c *circuitBreakerClient) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return c.breaker.do(func() error { return c.Manager.SignalGroup(ctx, tag, sig) })
+}
+
+func (c *circuitBreakerClient) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	var results map[string]error
+	err := c.breaker.do(func() (err error) {
+		results, err = c.Manager.WaitForTag(ctx, tag)
+		return err
+	})
+	return results, err
+}
+
+func (c *circuitBreakerClient) SetTagLimit(ctx context.Context, tag string, max int) error {
+	return c.breaker.do(func() error { return c.Manager.SetTagLimit(ctx, tag, max) })
+}
+
+func (c *circuitBreakerClient) WriteFile(ctx context.Context, opts options.WriteFile) error {
+	return c.breaker.do(func() error { return c.Manager.WriteFile(ctx, opts) })
+}
+
+func (c *circuitBreakerClient) Stats(ctx context.Context) jasper.ManagerStats {
+	if !c.breaker.isClosed() {
+		return jasper.ManagerStats{}
+	}
+	return c.Manager.Stats(ctx)
+}
+
+func (c *circuitBreakerClient) Subscribe(ctx context.Context) <-chan jasper.ProcessEvent {
+	if !c.breaker.isClosed() {
+		events := make(chan jasper.ProcessEvent)
+		close(events)
+		return events
+	}
+	return c.Manager.Subscribe(ctx)
+}
+
+func (c *circuitBreakerClient) Snapshot(ctx context.Context) ([]byte, error) {
+	var data []byte
+	err := c.breaker.do(func() (err error) {
+		data, err = c.Manager.Snapshot(ctx)
+		return err
+	})
+	return data, err
+}
+
+func (c *circuitBreakerClient) PruneLoggers(ctx context.Context, olderThan time.Duration) (int, error) {
+	var pruned int
+	err := c.breaker.do(func() (err error) {
+		pruned, err = c.Manager.PruneLoggers(ctx, olderThan)
+		return err
+	})
+	return pruned, err
+}
+
+func (c *circuitBreakerClient) DownloadFile(ctx context.Context, opts options.Download) error {
+	return c.breaker.do(func() error { return c.Manager.DownloadFile(ctx, opts) })
+}
+
+func (c *circuitBreakerClient) GetLogStream(ctx context.Context, id string, count int) (jasper.LogStream, error) {
+	var stream jasper.LogStream
+	err := c.breaker.do(func() (err error) {
+		stream, err = c.Manager.GetLogStream(ctx, id, count)
+		return err
+	})
+	return stream, err
+}
+
+func (c *circuitBreakerClient) StreamLogs(ctx context.Context, id string) (<-chan []byte, error) {
+	var logs <-chan []byte
+	err := c.breaker.do(func() (err error) {
+		logs, err = c.Manager.StreamLogs(ctx, id)
+		return err
+	})
+	return logs, err
+}
+
+func (c *circuitBreakerClient) SignalEvent(ctx context.Context, name string) error {
+	return c.breaker.do(func() error { return c.Manager.SignalEvent(ctx, name) })
+}
+
+func (c *circuitBreakerClient) CreateScripting(ctx context.Context, opts options.ScriptingHarness) (scripting.Harness, error) {
+	var sh scripting.Harness
+	err := c.breaker.do(func() (err error) {
+		sh, err = c.Manager.CreateScripting(ctx, opts)
+		return err
+	})
+	return sh, err
+}
+
+func (c *circuitBreakerClient) GetScripting(ctx context.Context, id string) (scripting.Harness, error) {
+	var sh scripting.Harness
+	err := c.breaker.do(func() (err error) {
+		sh, err = c.Manager.GetScripting(ctx, id)
+		return err
+	})
+	return sh, err
+}
+
+func (c *circuitBreakerClient) SendMessages(ctx context.Context, opts options.LoggingPayload) error {
+	return c.breaker.do(func() error { return c.Manager.SendMessages(ctx, opts) })
+}
@@ -0,0 +1,229 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/gimlet"
+	"github.com/tychoish/jasper"
+	"github.com/tychoish/jasper/mock"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreakerOptions(t *testing.T) {
+	t.Run("ZeroValueSetsDefaults", func(t *testing.T) {
+		opts := CircuitBreakerOptions{}
+		require.NoError(t, opts.Validate())
+		assert.Equal(t, DefaultCircuitBreakerFailureThreshold, opts.FailureThreshold)
+		assert.Equal(t, DefaultCircuitBreakerCooldown, opts.Cooldown)
+	})
+	t.Run("NegativeFailureThresholdFails", func(t *testing.T) {
+		opts := CircuitBreakerOptions{FailureThreshold: -1}
+		assert.Error(t, opts.Validate())
+	})
+	t.Run("NegativeCooldownFails", func(t *testing.T) {
+		opts := CircuitBreakerOptions{Cooldown: -time.Second}
+		assert.Error(t, opts.Validate())
+	})
+}
+
+func TestIsTransportError(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		err       error
+		transport bool
+	}{
+		"Nil":                   {err: nil},
+		"ManagerError":          {err: errors.New("process not found")},
+		"NetworkError":          {err: errors.Wrap(&net.OpError{Op: "dial", Err: errors.New("connection refused")}, "problem making request"), transport: true},
+		"UnexpectedEOF":         {err: errors.WithStack(io.ErrUnexpectedEOF), transport: true},
+		"DeadlineExceeded":      {err: errors.WithStack(context.DeadlineExceeded), transport: true},
+		"Canceled":              {err: errors.WithStack(context.Canceled)},
+		"ServiceUnavailable":    {err: errors.WithStack(gimlet.ErrorResponse{StatusCode: http.StatusServiceUnavailable}), transport: true},
+		"NotFoundResponse":      {err: errors.WithStack(gimlet.ErrorResponse{StatusCode: http.StatusNotFound})},
+		"BadRequestResponse":    {err: errors.WithStack(gimlet.ErrorResponse{StatusCode: http.StatusBadRequest})},
+		"UnavailableStatus":     {err: errors.WithStack(status.Error(codes.Unavailable, "connection refused")), transport: true},
+		"NotFoundStatus":        {err: errors.WithStack(status.Error(codes.NotFound, "process not found"))},
+		"InvalidArgumentStatus": {err: errors.WithStack(status.Error(codes.InvalidArgument, "invalid options"))},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.transport, isTransportError(testCase.err))
+		})
+	}
+}
+
+// unreachableClient wraps a mock client so that some calls can fail as if the
+// manager could not be reached.
+type unreachableClient struct {
+	*mock.RemoteClient
+	unreachable bool
+}
+
+func (c *unreachableClient) unreachableError() error {
+	return errors.Wrap(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, "problem making request")
+}
+
+func (c *unreachableClient) CreateProcess(ctx context.Context, opts *options.Create) (jasper.Process, error) {
+	if c.unreachable {
+		return nil, c.unreachableError()
+	}
+	return c.RemoteClient.CreateProcess(ctx, opts)
+}
+
+func (c *unreachableClient) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
+	if c.unreachable {
+		return nil, c.unreachableError()
+	}
+	return c.RemoteClient.List(ctx, f)
+}
+
+func (c *unreachableClient) Get(ctx context.Context, id string) (jasper.Process, error) {
+	if c.unreachable {
+		return nil, c.unreachableError()
+	}
+	return c.RemoteClient.Get(ctx, id)
+}
+
+func TestCircuitBreakerClient(t *testing.T) {
+	const cooldown = 100 * time.Millisecond
+
+	for testName, testCase := range map[string]func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager){
+		"SuccessfulCallsDoNotTrip": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			for i := 0; i < 5; i++ {
+				_, err := client.List(ctx, options.All)
+				require.NoError(t, err)
+			}
+		},
+		"TripsAfterConsecutiveFailures": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.unreachable = true
+			for i := 0; i < 3; i++ {
+				_, err := client.List(ctx, options.All)
+				require.Error(t, err)
+				assert.NotEqual(t, ErrCircuitOpen, err)
+			}
+
+			mc.unreachable = false
+			_, err := client.List(ctx, options.All)
+			assert.Equal(t, ErrCircuitOpen, err)
+			assert.Equal(t, ErrCircuitOpen, client.SignalEvent(ctx, "event"))
+			assert.Empty(t, mc.EventName, "call should fail without being made")
+		},
+		"SuccessResetsFailureCount": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			for i := 0; i < 3; i++ {
+				mc.unreachable = true
+				for j := 0; j < 2; j++ {
+					_, err := client.List(ctx, options.All)
+					require.Error(t, err)
+				}
+				mc.unreachable = false
+				_, err := client.List(ctx, options.All)
+				require.NoError(t, err)
+			}
+		},
+		"RecoversAfterCooldown": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.unreachable = true
+			for i := 0; i < 3; i++ {
+				_, _ = client.List(ctx, options.All)
+			}
+			_, err := client.List(ctx, options.All)
+			require.Equal(t, ErrCircuitOpen, err)
+
+			mc.unreachable = false
+			time.Sleep(cooldown)
+			_, err = client.List(ctx, options.All)
+			require.NoError(t, err)
+			assert.NoError(t, client.SignalEvent(ctx, "event"))
+			assert.Equal(t, "event", mc.EventName)
+		},
+		"FailedTrialCallTripsAgain": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.unreachable = true
+			for i := 0; i < 3; i++ {
+				_, _ = client.List(ctx, options.All)
+			}
+
+			time.Sleep(cooldown)
+			_, err := client.List(ctx, options.All)
+			require.Error(t, err)
+			assert.NotEqual(t, ErrCircuitOpen, err)
+
+			mc.unreachable = false
+			_, err = client.List(ctx, options.All)
+			assert.Equal(t, ErrCircuitOpen, err)
+		},
+		"CallsWithoutErrorsAreSkippedWhileTripped": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			proc, err := client.CreateProcess(ctx, testutil.TrueCreateOpts())
+			require.NoError(t, err)
+			require.NotNil(t, proc)
+
+			mc.unreachable = true
+			for i := 0; i < 3; i++ {
+				_, _ = client.Get(ctx, proc.ID())
+			}
+
+			client.Clear(ctx)
+			assert.Len(t, mc.Procs, 1)
+			assert.Zero(t, client.Stats(ctx))
+			_, ok := <-client.Subscribe(ctx)
+			assert.False(t, ok)
+		},
+		"ManagerErrorsDoNotTrip": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.FailList = true
+			mc.FailGet = true
+			for i := 0; i < 5; i++ {
+				_, err := client.List(ctx, options.All)
+				require.Error(t, err)
+				assert.NotEqual(t, ErrCircuitOpen, err)
+				_, err = client.Get(ctx, "foo")
+				require.Error(t, err)
+				assert.NotEqual(t, ErrCircuitOpen, err)
+			}
+		},
+		"ManagerErrorsResetFailureCount": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.FailList = true
+			for i := 0; i < 3; i++ {
+				mc.unreachable = true
+				for j := 0; j < 2; j++ {
+					_, err := client.List(ctx, options.All)
+					require.Error(t, err)
+				}
+				mc.unreachable = false
+				_, err := client.List(ctx, options.All)
+				require.Error(t, err)
+				assert.NotEqual(t, ErrCircuitOpen, err)
+			}
+		},
+		"CommandsUseCircuitBreaker": func(ctx context.Context, t *testing.T, mc *unreachableClient, client Manager) {
+			mc.unreachable = true
+			for i := 0; i < 3; i++ {
+				_, _ = client.CreateProcess(ctx, testutil.TrueCreateOpts())
+			}
+
+			err := client.CreateCommand(ctx).Append("true").Run(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), ErrCircuitOpen.Error())
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			mc := &unreachableClient{RemoteClient: &mock.RemoteClient{}}
+			client, err := NewCircuitBreakerClient(mc, CircuitBreakerOptions{
+				FailureThreshold: 3,
+				Cooldown:         cooldown,
+			})
+			require.NoError(t, err)
+
+			testCase(ctx, t, mc, client)
+		})
+	}
+}
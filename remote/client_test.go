@@ -15,8 +15,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tychoish/gimlet"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
 	"github.com/tychoish/grip/send"
@@ -25,6 +27,8 @@ import (
 	"github.com/tychoish/jasper/scripting"
 	"github.com/tychoish/jasper/testutil"
 	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -1272,3 +1276,112 @@ func createTestScriptingHarness(ctx context.Context, t *testing.T, client Manage
 
 	return harness
 }
+
+func TestCreateProcessStartErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not enforced on windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.RPCTestTimeout)
+	defer cancel()
+
+	httpClient := testutil.GetHTTPClient()
+	defer testutil.PutHTTPClient(httpClient)
+
+	tmpDir, err := ioutil.TempDir(testutil.BuildDirectory(), "start_errors")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+	notExecutable := filepath.Join(tmpDir, "not_executable")
+	require.NoError(t, ioutil.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0600))
+
+	type startErrorCase struct {
+		args     []string
+		expected jasper.StartErrorCode
+		grpcCode codes.Code
+		httpCode int
+	}
+	startErrorCases := map[string]startErrorCase{
+		"BinaryNotFound": {
+			args:     []string{"this-binary-does-not-exist"},
+			expected: jasper.StartErrorNotFound,
+			grpcCode: codes.NotFound,
+			httpCode: http.StatusNotFound,
+		},
+		"PermissionDenied": {
+			args:     []string{notExecutable},
+			expected: jasper.StartErrorPermissionDenied,
+			grpcCode: codes.PermissionDenied,
+			httpCode: http.StatusForbidden,
+		},
+	}
+
+	for clientName, test := range map[string]struct {
+		makeClient     func(ctx context.Context, t *testing.T) Manager
+		checkTransport func(t *testing.T, err error, testCase startErrorCase)
+	}{
+		"RPC": {
+			makeClient: func(ctx context.Context, t *testing.T) Manager {
+				manager, err := jasper.NewSynchronizedManager(false)
+				require.NoError(t, err)
+				client, err := makeInsecureRPCServiceAndClient(ctx, manager)
+				require.NoError(t, err)
+				return client
+			},
+			checkTransport: func(t *testing.T, err error, testCase startErrorCase) {
+				var startErr *jasper.StartError
+				require.True(t, errors.As(err, &startErr))
+				assert.Equal(t, testCase.grpcCode, status.Code(startErr.Err))
+			},
+		},
+		"REST": {
+			makeClient: func(ctx context.Context, t *testing.T) Manager {
+				_, port, err := startRESTService(ctx, httpClient)
+				require.NoError(t, err)
+				return &restClient{
+					prefix: fmt.Sprintf("http://localhost:%d/jasper/v1", port),
+					client: httpClient,
+				}
+			},
+			checkTransport: func(t *testing.T, err error, testCase startErrorCase) {
+				var resp gimlet.ErrorResponse
+				require.True(t, errors.As(err, &resp))
+				assert.Equal(t, testCase.httpCode, resp.StatusCode)
+			},
+		},
+	} {
+		t.Run(clientName, func(t *testing.T) {
+			client := test.makeClient(ctx, t)
+			defer func() {
+				assert.NoError(t, client.CloseConnection())
+			}()
+
+			for testName, testCase := range startErrorCases {
+				t.Run(testName, func(t *testing.T) {
+					proc, err := client.CreateProcess(ctx, &options.Create{Args: testCase.args})
+					require.Error(t, err)
+					assert.Nil(t, proc)
+					assert.Equal(t, testCase.expected, jasper.GetStartErrorCode(err))
+					test.checkTransport(t, err, testCase)
+				})
+			}
+			t.Run("OtherFailuresAreUnclassified", func(t *testing.T) {
+				_, err := client.CreateProcess(ctx, &options.Create{})
+				require.Error(t, err)
+				assert.Equal(t, jasper.StartErrorUnknown, jasper.GetStartErrorCode(err))
+			})
+		})
+	}
+}
+
+func TestStartErrorHTTPCode(t *testing.T) {
+	for code, expected := range map[jasper.StartErrorCode]int{
+		jasper.StartErrorNotFound:          http.StatusNotFound,
+		jasper.StartErrorPermissionDenied:  http.StatusForbidden,
+		jasper.StartErrorResourceExhausted: http.StatusServiceUnavailable,
+		jasper.StartErrorUnknown:           http.StatusBadRequest,
+	} {
+		assert.Equal(t, expected, startErrorHTTPCode(code), string(code))
+	}
+}
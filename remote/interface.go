@@ -17,6 +17,13 @@ type Manager interface {
 	CloseConnection() error
 	DownloadFile(ctx context.Context, opts options.Download) error
 	GetLogStream(ctx context.Context, id string, count int) (jasper.LogStream, error)
+	// StreamLogs returns a channel that receives the output of the
+	// process from its in-memory logger as it is written. The channel
+	// is closed once the process completes and all of its output has
+	// been received, or if the context is done or the stream fails.
+	// Like GetLogStream, it advances the read position of the log
+	// stream.
+	StreamLogs(ctx context.Context, id string) (<-chan []byte, error)
 	SignalEvent(ctx context.Context, name string) error
 
 	CreateScripting(context.Context, options.ScriptingHarness) (scripting.Harness, error)
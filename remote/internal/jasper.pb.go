@@ -3166,210 +3166,210 @@ func init() {
 func init() { proto.RegisterFile("jasper.proto", fileDescriptor_d30110796082ce8e) }
 
 var fileDescriptor_d30110796082ce8e = []byte{
-	// 3239 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x3a, 0xdb, 0x76, 0xdb, 0x46,
-	0x92, 0xe2, 0x45, 0xbc, 0x94, 0x6e, 0x70, 0x5b, 0x96, 0x69, 0xfa, 0x1a, 0x64, 0x1d, 0x3b, 0xca,
-	0x89, 0xec, 0xc8, 0xf1, 0xc6, 0x71, 0x12, 0xef, 0x52, 0x12, 0x25, 0x31, 0xa6, 0x28, 0x9d, 0x26,
-	0x15, 0xe7, 0x24, 0x9b, 0xf0, 0xb4, 0x89, 0x16, 0x85, 0x18, 0x04, 0xe0, 0x46, 0x43, 0xb2, 0xf2,
-	0xb6, 0x67, 0x1f, 0xf6, 0x71, 0xf7, 0x07, 0xe6, 0x6d, 0x9e, 0x66, 0xe6, 0x03, 0xe6, 0x07, 0xe6,
-	0x5b, 0xe6, 0x7d, 0xe6, 0x07, 0xe6, 0xf4, 0x05, 0x20, 0x40, 0x91, 0x94, 0x47, 0x99, 0x27, 0xa1,
-	0xaa, 0xab, 0xaa, 0xab, 0xab, 0xeb, 0xd6, 0x45, 0xc1, 0xfc, 0x2f, 0x24, 0xf0, 0x29, 0x5b, 0xf3,
-	0x99, 0xc7, 0x3d, 0x54, 0x50, 0x50, 0xf5, 0x66, 0xdf, 0xf3, 0xfa, 0x0e, 0x7d, 0x24, 0xb1, 0xaf,
-	0xc3, 0xa3, 0x47, 0x74, 0xe0, 0xf3, 0x33, 0x45, 0x54, 0xbd, 0x3b, 0xba, 0xc8, 0xed, 0x01, 0x0d,
-	0x38, 0x19, 0xf8, 0x9a, 0xe0, 0xce, 0x28, 0x81, 0x15, 0x32, 0xc2, 0x6d, 0xcf, 0x55, 0xeb, 0xe6,
-	0x5f, 0xb3, 0x30, 0xdf, 0xf4, 0xfa, 0x7d, 0xca, 0x36, 0x3d, 0xf7, 0xc8, 0xee, 0xa3, 0x67, 0x50,
-	0xb4, 0xe8, 0x11, 0x09, 0x1d, 0x5e, 0xc9, 0xdc, 0xcb, 0x3c, 0x9c, 0x5b, 0xbf, 0xb5, 0xa6, 0xd5,
-	0xda, 0x52, 0x68, 0x45, 0xbd, 0xef, 0x0b, 0x21, 0xc1, 0xee, 0x0c, 0x8e, 0xc8, 0xd1, 0x23, 0xc8,
-	0x1f, 0xd9, 0x0e, 0xad, 0x64, 0x25, 0xdb, 0x8d, 0x88, 0x6d, 0xdb, 0x76, 0xe8, 0x28, 0x8f, 0x24,
-	0x44, 0x2f, 0xa0, 0x6c, 0xbb, 0xc7, 0x94, 0xd9, 0x9c, 0x5a, 0x95, 0x9c, 0xe4, 0xba, 0x13, 0x71,
-	0x35, 0xa2, 0x85, 0x51, 0xd6, 0x21, 0x0b, 0xfa, 0x5a, 0xf0, 0x77, 0x07, 0x74, 0xe0, 0xb1, 0xb3,
-	0x4a, 0x5e, 0xf2, 0xdf, 0x1e, 0xf2, 0xef, 0x49, 0xfc, 0x28, 0x7b, 0xc9, 0xd6, 0x0b, 0xe8, 0x13,
-	0xc8, 0x31, 0x72, 0x5a, 0x99, 0x95, 0x7c, 0xd7, 0x23, 0x3e, 0x4c, 0x4e, 0x93, 0xe6, 0xd8, 0x9d,
-	0xc1, 0x82, 0x0a, 0x3d, 0x85, 0x42, 0xe0, 0x3b, 0xa1, 0xfb, 0xa6, 0x52, 0x90, 0xf4, 0x37, 0x23,
-	0xfa, 0xb6, 0xc4, 0x8e, 0xee, 0xa2, 0x89, 0x37, 0x00, 0x4a, 0x3e, 0xf3, 0xac, 0xb0, 0x47, 0x99,
-	0xb9, 0x01, 0xa5, 0xa6, 0xd7, 0x6f, 0xd2, 0x13, 0xea, 0xa0, 0x5b, 0x50, 0xe6, 0xc7, 0x8c, 0x06,
-	0xc7, 0x9e, 0x63, 0x49, 0x33, 0xcf, 0xe2, 0x21, 0x02, 0x55, 0x86, 0x57, 0x90, 0x95, 0x6b, 0x11,
-	0x68, 0xbe, 0x86, 0x85, 0x8d, 0xf0, 0xe8, 0x28, 0xde, 0x0a, 0x55, 0xa1, 0xf4, 0x5a, 0x22, 0xa8,
-	0x92, 0x53, 0xc2, 0x31, 0x2c, 0xd6, 0xa2, 0xcb, 0x96, 0x72, 0x72, 0x38, 0x86, 0xd1, 0x0d, 0x28,
-	0x0d, 0xc8, 0xbb, 0x6e, 0x60, 0xff, 0x4a, 0xa5, 0xe5, 0x73, 0xb8, 0x38, 0x20, 0xef, 0xda, 0xf6,
-	0xaf, 0xd4, 0xfc, 0xbf, 0x0c, 0xcc, 0x6d, 0x90, 0x80, 0x46, 0x5b, 0x7c, 0x04, 0xb3, 0x8e, 0x50,
-	0x5a, 0xbb, 0x83, 0x11, 0x9d, 0x3c, 0x3a, 0x0c, 0x56, 0xcb, 0xe8, 0x53, 0x28, 0xa8, 0xad, 0xb5,
-	0x03, 0x5c, 0x8b, 0x08, 0x53, 0x1a, 0x63, 0x4d, 0x84, 0x3e, 0x86, 0xc2, 0x91, 0xc7, 0x06, 0x84,
-	0xcb, 0xfd, 0x17, 0xd7, 0xaf, 0x24, 0xe4, 0x6e, 0xcb, 0x05, 0xac, 0x09, 0xcc, 0x57, 0xb0, 0x3c,
-	0xce, 0xf7, 0xd0, 0x0a, 0x14, 0x7c, 0x46, 0x8f, 0xec, 0x77, 0x52, 0xb5, 0x32, 0xd6, 0x10, 0x7a,
-	0x00, 0xf9, 0xd7, 0x24, 0x88, 0x1c, 0xf1, 0x6a, 0xac, 0xc7, 0xf0, 0x50, 0x58, 0x12, 0x98, 0xdf,
-	0xc3, 0x95, 0x73, 0xde, 0x29, 0xcc, 0x26, 0xbc, 0xd3, 0x25, 0x03, 0xaa, 0xe5, 0xc6, 0xf0, 0xfb,
-	0x4b, 0xae, 0xc1, 0xca, 0x78, 0x0f, 0x8e, 0x45, 0x64, 0x2e, 0x12, 0x61, 0xc1, 0xb5, 0xb1, 0x4e,
-	0x8c, 0x4c, 0x58, 0x88, 0xdd, 0xbe, 0xdb, 0x23, 0xbe, 0x14, 0x95, 0xc3, 0x73, 0x91, 0x67, 0x6f,
-	0x12, 0xff, 0xfd, 0x15, 0x6d, 0x01, 0x28, 0x17, 0x6e, 0xb8, 0x47, 0x1e, 0x32, 0x20, 0x17, 0x32,
-	0x47, 0x1f, 0x5b, 0x7c, 0xa2, 0x65, 0x98, 0xe5, 0xde, 0x1b, 0xaa, 0x3c, 0xa8, 0x8c, 0x15, 0x20,
-	0x3c, 0xb4, 0x77, 0x4c, 0x5c, 0x97, 0x3a, 0xf2, 0xf6, 0xca, 0x38, 0x02, 0xcd, 0x5f, 0xe0, 0xea,
-	0x98, 0x90, 0x40, 0xab, 0x71, 0xfc, 0xa8, 0x73, 0xa3, 0x74, 0xfc, 0x88, 0xcd, 0xa3, 0xa0, 0x79,
-	0x7f, 0xdd, 0x6d, 0x58, 0x1a, 0x09, 0x57, 0x11, 0xa7, 0xda, 0xab, 0x32, 0xd2, 0xab, 0x6e, 0x4f,
-	0x88, 0xeb, 0xb4, 0x87, 0xa1, 0xbb, 0x30, 0xd7, 0x93, 0xf8, 0xae, 0x45, 0x38, 0x91, 0x3b, 0xcf,
-	0x63, 0x50, 0xa8, 0x2d, 0xc2, 0x89, 0xf9, 0xdf, 0x59, 0x58, 0xd8, 0x0f, 0xb9, 0x1f, 0xf2, 0xe8,
-	0x44, 0x6b, 0x50, 0x74, 0xa4, 0xc0, 0xa0, 0x92, 0xb9, 0x97, 0x7b, 0x38, 0xb7, 0xbe, 0x9c, 0x70,
-	0xe0, 0x78, 0x1f, 0x1c, 0x11, 0xa1, 0x07, 0xb0, 0x14, 0x84, 0xbe, 0xcf, 0x68, 0x10, 0x74, 0x3d,
-	0x29, 0x49, 0x6e, 0x53, 0xc2, 0x8b, 0x11, 0x5a, 0xc9, 0x47, 0xf7, 0x21, 0xc6, 0x74, 0x29, 0x63,
-	0x1e, 0x93, 0x26, 0x2e, 0xe1, 0x85, 0x08, 0x5b, 0x17, 0x48, 0xf4, 0x05, 0x54, 0x18, 0xb5, 0x6c,
-	0x46, 0x7b, 0x5c, 0xcb, 0xeb, 0x72, 0x4f, 0x33, 0xe4, 0x25, 0xc3, 0xb5, 0x68, 0x5d, 0x09, 0xee,
-	0x78, 0xe7, 0x19, 0x25, 0xb9, 0xe0, 0xd3, 0x1a, 0xcd, 0xa6, 0x19, 0x25, 0x43, 0xc7, 0x53, 0xfc,
-	0xe6, 0x5f, 0xf2, 0xb0, 0xb0, 0xc9, 0x28, 0xe1, 0x71, 0x6a, 0x40, 0x90, 0x27, 0xac, 0xaf, 0x0c,
-	0x50, 0xc6, 0xf2, 0x1b, 0x7d, 0x02, 0x57, 0x4e, 0x3d, 0xf6, 0xc6, 0x76, 0xfb, 0x5d, 0x25, 0x44,
-	0x24, 0x67, 0xe5, 0x3c, 0x86, 0x5e, 0xd8, 0x8a, 0xf0, 0x68, 0x17, 0xe6, 0xa8, 0x7b, 0x62, 0x33,
-	0xcf, 0x1d, 0x50, 0x57, 0x64, 0x02, 0x61, 0xc8, 0x8f, 0x22, 0x43, 0xa6, 0x36, 0x5b, 0xab, 0x0f,
-	0x09, 0xeb, 0x2e, 0x67, 0x67, 0x38, 0xc9, 0x8a, 0x3e, 0x06, 0xc3, 0x3b, 0xa1, 0x8c, 0xd9, 0x16,
-	0xed, 0x6a, 0xbc, 0x36, 0xc3, 0x52, 0x84, 0xd7, 0x02, 0xc4, 0x4d, 0x88, 0x2a, 0xe9, 0x85, 0xbc,
-	0x1b, 0xd0, 0x9e, 0xe7, 0x5a, 0x81, 0x3c, 0x77, 0x0e, 0x2f, 0x6a, 0x74, 0x5b, 0x61, 0xc5, 0xf1,
-	0x38, 0xe9, 0x07, 0x95, 0x82, 0x3a, 0x9e, 0xf8, 0x46, 0x9f, 0x03, 0x78, 0x6e, 0x37, 0x08, 0x7b,
-	0x3d, 0x1a, 0x04, 0x95, 0xa2, 0x54, 0xf8, 0xda, 0x58, 0x85, 0x71, 0xd9, 0x73, 0xdb, 0x8a, 0x4e,
-	0x73, 0x1d, 0x11, 0xdb, 0x09, 0x19, 0xad, 0x94, 0x2e, 0xe0, 0xda, 0x56, 0x74, 0x9a, 0x4b, 0x2b,
-	0x55, 0x29, 0x5f, 0xc0, 0xd5, 0x51, 0x74, 0x22, 0x0f, 0xeb, 0xdb, 0x84, 0x74, 0x1e, 0x4e, 0xf9,
-	0x2f, 0xd6, 0x44, 0xe8, 0x31, 0x2c, 0x07, 0x9c, 0xb8, 0x16, 0x61, 0x56, 0xd7, 0x76, 0x85, 0x1b,
-	0xbd, 0x3e, 0xe3, 0x34, 0xa8, 0xcc, 0xc9, 0x18, 0x40, 0xd1, 0x5a, 0x43, 0x2c, 0x6d, 0x88, 0x95,
-	0xea, 0x0b, 0x30, 0x46, 0xef, 0x42, 0x24, 0x8e, 0x37, 0xf4, 0x2c, 0x4a, 0x1c, 0x6f, 0xe8, 0x99,
-	0x48, 0x1c, 0x27, 0xc4, 0x09, 0x69, 0x94, 0x38, 0x24, 0xf0, 0x3c, 0xfb, 0x2c, 0x63, 0x9a, 0x00,
-	0x8d, 0x2d, 0x4c, 0x03, 0xdf, 0x73, 0x03, 0x3a, 0xa4, 0xcb, 0x24, 0xe8, 0xcc, 0xbf, 0x65, 0x61,
-	0xee, 0x80, 0x79, 0xc2, 0x78, 0x32, 0x31, 0x2d, 0x42, 0xd6, 0xb6, 0x34, 0x49, 0xd6, 0xb6, 0xc4,
-	0x7e, 0xbe, 0x6d, 0xe9, 0xb2, 0x26, 0x3e, 0xd1, 0x75, 0x28, 0x1e, 0x7b, 0x01, 0xef, 0xda, 0x96,
-	0x4e, 0x49, 0x05, 0x01, 0x36, 0x64, 0x35, 0x65, 0xa1, 0xeb, 0xda, 0x6e, 0x5f, 0x3b, 0x44, 0x04,
-	0xa2, 0x3b, 0x00, 0xfa, 0x22, 0x8f, 0x42, 0x47, 0xfb, 0x7e, 0x02, 0x23, 0x2a, 0x41, 0xcf, 0x1b,
-	0xf8, 0x0e, 0xe5, 0x54, 0x96, 0xfd, 0x12, 0x8e, 0x61, 0xb1, 0x26, 0x2e, 0xc6, 0x12, 0x37, 0x53,
-	0x54, 0x6b, 0x11, 0x8c, 0x1e, 0x41, 0xd1, 0x53, 0x56, 0xae, 0x94, 0xd2, 0x57, 0x90, 0xbe, 0xb4,
-	0x88, 0x0a, 0xdd, 0x84, 0x32, 0x7d, 0x67, 0xf3, 0x6e, 0xcf, 0xb3, 0x68, 0xa5, 0x2c, 0x4b, 0x7e,
-	0x49, 0x20, 0x36, 0x3d, 0x8b, 0xa2, 0xa7, 0x50, 0x0a, 0x38, 0x61, 0xbc, 0x4b, 0xa2, 0x1b, 0xad,
-	0xae, 0xa9, 0xa6, 0x6e, 0x2d, 0x6a, 0xea, 0xd6, 0x3a, 0x51, 0xd7, 0x87, 0x8b, 0x92, 0xb6, 0xc6,
-	0xd1, 0x67, 0x50, 0xa0, 0xae, 0x25, 0x98, 0xe6, 0x2e, 0x64, 0x9a, 0xa5, 0xae, 0x55, 0xe3, 0x66,
-	0x0d, 0x16, 0xdb, 0x9c, 0xf0, 0x30, 0x88, 0x2f, 0x27, 0x61, 0xd4, 0x4c, 0xca, 0xa8, 0x2b, 0x50,
-	0x20, 0x3d, 0x6e, 0x9f, 0x50, 0x9d, 0xc4, 0x34, 0x64, 0x3e, 0x87, 0xc2, 0xb6, 0xed, 0x70, 0xca,
-	0xd0, 0x63, 0xc8, 0xc7, 0x25, 0x74, 0x71, 0xd8, 0x44, 0xaa, 0xd5, 0xb6, 0x4f, 0x7b, 0xf6, 0x91,
-	0xdd, 0x23, 0x3a, 0x9d, 0x0b, 0x4a, 0xd3, 0x83, 0x85, 0xb6, 0xdd, 0x77, 0x89, 0xa3, 0x2f, 0x1e,
-	0x3d, 0x85, 0x72, 0xe4, 0x03, 0x5b, 0xba, 0x6e, 0xc4, 0x7d, 0xda, 0xb7, 0xf2, 0x4f, 0xbc, 0x8c,
-	0x87, 0x94, 0xe8, 0x01, 0x14, 0x02, 0x29, 0x47, 0xea, 0xb6, 0xb8, 0xbe, 0x14, 0xd7, 0x1a, 0x89,
-	0x0d, 0xb0, 0x5e, 0x36, 0xef, 0x42, 0xb1, 0x43, 0xfa, 0x2d, 0x51, 0xd8, 0xc7, 0x7b, 0xe1, 0x7f,
-	0xc4, 0x4e, 0xd8, 0x11, 0xb1, 0x7f, 0x0b, 0xca, 0x7e, 0x4a, 0x9f, 0x32, 0x1e, 0x22, 0xe2, 0x6c,
-	0x91, 0x1d, 0x66, 0x0b, 0xf3, 0x01, 0x2c, 0x8d, 0x28, 0x3a, 0x61, 0xa7, 0x9f, 0xc0, 0xd8, 0xf7,
-	0xa9, 0x6a, 0xce, 0xf6, 0x43, 0xde, 0xf3, 0x06, 0x54, 0x38, 0x6e, 0x94, 0x67, 0x54, 0x6b, 0x17,
-	0x81, 0x72, 0x2b, 0xfa, 0x8e, 0xeb, 0xd0, 0x92, 0xdf, 0x69, 0x1f, 0xca, 0xa5, 0x7d, 0xc8, 0xfc,
-	0xdf, 0x0c, 0x2c, 0xd6, 0x58, 0xef, 0xd8, 0x3e, 0x89, 0x73, 0xb7, 0x28, 0x33, 0xc7, 0x5e, 0xe8,
-	0x58, 0x5d, 0xfa, 0x8e, 0x33, 0xd2, 0xe3, 0x7a, 0x93, 0x05, 0x85, 0xad, 0x2b, 0xa4, 0xc8, 0x26,
-	0xba, 0xa0, 0x2a, 0x63, 0xc6, 0xae, 0xac, 0xc5, 0x9d, 0x2f, 0xa4, 0x9c, 0xb0, 0x3e, 0xe5, 0x5d,
-	0x9f, 0xf0, 0x63, 0x1d, 0x89, 0xa0, 0x50, 0x07, 0x84, 0x1f, 0x9b, 0x1e, 0xcc, 0x6f, 0x79, 0xa7,
-	0xae, 0xe3, 0x11, 0x6b, 0x42, 0xc7, 0x81, 0x20, 0x2f, 0x79, 0xf5, 0xe1, 0xc4, 0x37, 0xfa, 0x12,
-	0xe6, 0x89, 0xda, 0xaf, 0xeb, 0xf9, 0x3c, 0xd0, 0x8f, 0x85, 0x95, 0x11, 0x5d, 0xa2, 0xb8, 0x9a,
-	0x23, 0x31, 0x1c, 0x98, 0x36, 0x2c, 0xbc, 0x12, 0x6d, 0x98, 0x68, 0xf4, 0xe4, 0x8e, 0x91, 0xfc,
-	0x4c, 0x42, 0xbe, 0xe8, 0x67, 0x3c, 0x97, 0x8b, 0x1a, 0xa4, 0x6a, 0x7f, 0x04, 0x4a, 0x47, 0xf7,
-	0x7d, 0xea, 0x5a, 0x3a, 0x79, 0x68, 0x48, 0x4a, 0xa1, 0x6c, 0x20, 0x35, 0x59, 0xc0, 0xf2, 0xdb,
-	0xbc, 0x0f, 0x4b, 0x1b, 0xa1, 0xed, 0x58, 0xaa, 0xe4, 0x1f, 0xe2, 0xa6, 0xbc, 0xa9, 0x90, 0x39,
-	0x71, 0x85, 0x14, 0xdf, 0xe6, 0x4b, 0x80, 0xa6, 0xd7, 0xc7, 0xf4, 0x6d, 0x48, 0x03, 0x8e, 0x1e,
-	0xc4, 0x99, 0x6d, 0x8a, 0x77, 0x8b, 0x94, 0xb7, 0x0c, 0xb3, 0x3d, 0x2f, 0xd4, 0x1a, 0xe6, 0xb0,
-	0x02, 0xcc, 0x27, 0x50, 0x6e, 0x7a, 0xfd, 0x36, 0x67, 0x94, 0x0c, 0xc4, 0x6e, 0x8e, 0x37, 0xac,
-	0xc7, 0xe2, 0x5b, 0xe0, 0x2c, 0xcf, 0x8d, 0xe2, 0x54, 0x7e, 0x8b, 0x16, 0xff, 0xaa, 0x0a, 0x86,
-	0x0e, 0xb3, 0x85, 0xae, 0x07, 0x84, 0x91, 0x81, 0x0c, 0x38, 0xff, 0xbd, 0x03, 0x6e, 0xe8, 0xf9,
-	0x35, 0x58, 0x0a, 0x92, 0xd2, 0x1a, 0x5b, 0xda, 0x59, 0xae, 0xa7, 0x23, 0x2f, 0x5e, 0xc6, 0xa3,
-	0xf4, 0xe6, 0x07, 0x50, 0xae, 0x9f, 0x50, 0x97, 0x4f, 0x09, 0xc6, 0xe7, 0x80, 0xda, 0x3d, 0x66,
-	0xfb, 0xdc, 0x76, 0xfb, 0xbb, 0x84, 0xb9, 0x6a, 0xef, 0xd1, 0xc2, 0xb0, 0x0c, 0xb3, 0x01, 0xe5,
-	0xa1, 0xaf, 0xcf, 0xab, 0x00, 0xf3, 0x8f, 0x19, 0x58, 0x89, 0x99, 0xb5, 0x9b, 0xec, 0x78, 0x0e,
-	0x71, 0xfb, 0xe2, 0x82, 0xfb, 0x5e, 0xc2, 0x21, 0x34, 0xa4, 0xf0, 0xcc, 0xf3, 0xa2, 0x28, 0xd3,
-	0x90, 0x48, 0xfc, 0x3e, 0xe9, 0xbd, 0x21, 0x7d, 0x1a, 0xc8, 0x7e, 0xa5, 0x8c, 0x63, 0x58, 0x24,
-	0x88, 0x61, 0xcf, 0x93, 0x57, 0x09, 0x22, 0x46, 0x88, 0xbe, 0x23, 0xf4, 0x2d, 0xc2, 0x69, 0x37,
-	0x16, 0xa0, 0x6a, 0xce, 0xa2, 0x42, 0x1f, 0x68, 0xac, 0xf9, 0x3f, 0xd9, 0xf3, 0xda, 0x1e, 0x9c,
-	0xf1, 0x63, 0xcf, 0x45, 0x0f, 0xc1, 0x38, 0xb1, 0x19, 0x0f, 0x89, 0x23, 0xba, 0x9c, 0x6e, 0x42,
-	0xef, 0x45, 0x8d, 0xaf, 0xbb, 0x27, 0x22, 0xd0, 0x44, 0x1f, 0xc6, 0xe8, 0xdb, 0xd0, 0x66, 0x54,
-	0x54, 0xe9, 0xa0, 0x9b, 0x88, 0x29, 0x23, 0xb9, 0x20, 0x89, 0x3f, 0x05, 0x64, 0xbb, 0x9c, 0x32,
-	0x9f, 0x51, 0x4e, 0x59, 0xf7, 0xb5, 0xed, 0x12, 0x76, 0xa6, 0xa3, 0xf7, 0x4a, 0x62, 0x65, 0x43,
-	0x2e, 0xa4, 0x6c, 0x90, 0x1f, 0xb1, 0xc1, 0x87, 0xb0, 0xe0, 0xd0, 0x3e, 0xe9, 0x9d, 0x75, 0x7d,
-	0xa9, 0xb2, 0x3e, 0xe3, 0xbc, 0x42, 0xea, 0x63, 0x98, 0xb0, 0x40, 0x2c, 0xab, 0xcb, 0x69, 0xc0,
-	0xbb, 0x8c, 0xbe, 0x0d, 0x74, 0x79, 0x9d, 0x23, 0x96, 0xd5, 0xa1, 0x01, 0xc7, 0xf4, 0x6d, 0x60,
-	0xfe, 0x08, 0xd7, 0x47, 0x8d, 0x80, 0xbd, 0xe0, 0x94, 0x3a, 0xce, 0xa4, 0x10, 0x0e, 0xce, 0x02,
-	0x4e, 0x07, 0x51, 0x06, 0x8e, 0x40, 0x19, 0x15, 0x76, 0xe0, 0xeb, 0xe3, 0xc8, 0x6f, 0xf3, 0xf7,
-	0x39, 0x30, 0x46, 0xa5, 0xa3, 0x67, 0xe2, 0xca, 0x85, 0x53, 0x68, 0xdf, 0x8f, 0x87, 0x11, 0xe3,
-	0x5d, 0x47, 0xbc, 0xf3, 0x15, 0xbd, 0xe0, 0xd4, 0xa7, 0xcd, 0x4e, 0xe7, 0x54, 0xe7, 0x17, 0x9c,
-	0x8a, 0x1e, 0x7d, 0x05, 0x45, 0xa6, 0x4e, 0xa5, 0x93, 0xda, 0xdd, 0x49, 0xac, 0xfa, 0xf0, 0xbb,
-	0x33, 0x38, 0xe2, 0x40, 0x2f, 0xd3, 0xed, 0x73, 0x5e, 0x76, 0x88, 0x1f, 0x4f, 0x12, 0x70, 0x41,
-	0x07, 0x3d, 0xec, 0x1b, 0x67, 0xdf, 0xa7, 0x6f, 0x4c, 0x4e, 0x17, 0x0a, 0xe9, 0xe9, 0xc2, 0x6f,
-	0xed, 0x10, 0x37, 0x8a, 0x7a, 0xc5, 0xfc, 0x26, 0xe1, 0x03, 0x3a, 0xe6, 0x71, 0xe8, 0xd6, 0xc4,
-	0x3b, 0x63, 0x34, 0xf0, 0xa3, 0xb7, 0x48, 0x76, 0xf8, 0x16, 0x31, 0x7f, 0x82, 0x1b, 0xa3, 0xec,
-	0x32, 0x41, 0x8f, 0x15, 0x90, 0x0a, 0xde, 0xec, 0x68, 0xf0, 0x46, 0xe2, 0x73, 0x09, 0xf1, 0x7d,
-	0xb8, 0x3d, 0x56, 0x7c, 0xdc, 0x3e, 0xad, 0x43, 0xd1, 0x53, 0xc5, 0x5c, 0x7b, 0x54, 0x25, 0xb6,
-	0xe9, 0x48, 0xb1, 0xc7, 0x11, 0xe1, 0xb8, 0xf2, 0x67, 0xee, 0x9c, 0xdf, 0x08, 0x87, 0xae, 0x42,
-	0x8d, 0x3d, 0xcb, 0x0a, 0x14, 0x02, 0xb9, 0x1a, 0x25, 0x2f, 0x05, 0x89, 0x3e, 0xa0, 0x32, 0x2a,
-	0x49, 0x04, 0xdc, 0x25, 0x0c, 0xf2, 0xcd, 0xb0, 0xc9, 0x55, 0xcf, 0xb6, 0x0f, 0xcf, 0xf9, 0x5d,
-	0x62, 0x83, 0xd1, 0x96, 0xd7, 0xfc, 0x43, 0x06, 0x6e, 0x4e, 0x21, 0x14, 0x66, 0x48, 0x4c, 0x60,
-	0xe4, 0xf7, 0xb8, 0x2b, 0x16, 0x61, 0xef, 0x13, 0xce, 0x29, 0x73, 0xa3, 0x49, 0x84, 0x06, 0xd1,
-	0x13, 0x28, 0x46, 0x4f, 0xa7, 0xbc, 0x9e, 0x48, 0x8e, 0x76, 0xc0, 0x5b, 0xda, 0x61, 0x71, 0x44,
-	0x39, 0x2c, 0xb2, 0xb3, 0xb2, 0x83, 0xd2, 0x45, 0xf6, 0xcf, 0x19, 0xa8, 0x8e, 0x53, 0x16, 0xd3,
-	0x20, 0x74, 0xf8, 0x58, 0x5d, 0x93, 0x5d, 0x7b, 0xf6, 0xfd, 0xbb, 0xf6, 0xa7, 0x89, 0xa8, 0xca,
-	0x5d, 0xa4, 0xf5, 0x70, 0x9c, 0x57, 0x19, 0x3a, 0x9a, 0x2a, 0x3b, 0x11, 0x68, 0xfe, 0x7f, 0x06,
-	0x6e, 0x4d, 0x50, 0xfd, 0xf2, 0x3e, 0xfa, 0x35, 0x14, 0x99, 0x3c, 0xba, 0xba, 0x8b, 0xb9, 0x75,
-	0x73, 0xda, 0xdd, 0x2b, 0x2b, 0xe1, 0x88, 0xc5, 0xfc, 0x09, 0x56, 0x9a, 0x5e, 0xbf, 0x6f, 0xbb,
-	0xfd, 0x4d, 0xd2, 0x3b, 0xa6, 0xea, 0x4d, 0x24, 0x3d, 0x70, 0x9c, 0x21, 0x13, 0x8f, 0xa9, 0xec,
-	0xb4, 0xbc, 0x14, 0x7b, 0xd6, 0x47, 0x60, 0x24, 0xc5, 0x4f, 0x12, 0x6c, 0xfe, 0x29, 0x03, 0xcb,
-	0x49, 0xc2, 0x86, 0x2b, 0xde, 0xba, 0xbd, 0xcb, 0x59, 0x44, 0xc5, 0x4e, 0x36, 0x8e, 0x9d, 0x0a,
-	0x14, 0x07, 0xc4, 0x25, 0x7d, 0xca, 0x22, 0xb7, 0xd4, 0x20, 0xfa, 0x77, 0x28, 0x11, 0xd9, 0xc5,
-	0x53, 0x4b, 0xfb, 0xe5, 0x34, 0xc7, 0x88, 0x69, 0xcd, 0x5f, 0xd2, 0xc7, 0x6a, 0xdb, 0xbf, 0xfe,
-	0x6b, 0x34, 0x45, 0x90, 0x4f, 0x4c, 0x81, 0xe5, 0xb7, 0xb9, 0x05, 0x48, 0xef, 0x75, 0x40, 0xce,
-	0x44, 0xab, 0xbe, 0x45, 0x38, 0x41, 0x08, 0x72, 0x83, 0x40, 0xd5, 0xc6, 0xf2, 0xee, 0x0c, 0x16,
-	0x80, 0xc0, 0x31, 0x72, 0xaa, 0x9a, 0x66, 0x3d, 0x2b, 0xdf, 0x28, 0x40, 0xde, 0x22, 0x9c, 0x98,
-	0xbf, 0xcb, 0xc2, 0x62, 0x5a, 0x8c, 0x28, 0x1a, 0x6a, 0x3a, 0x16, 0x3f, 0xa0, 0x62, 0x58, 0x36,
-	0x15, 0xcc, 0xf6, 0x98, 0xcd, 0xcf, 0xf4, 0xd8, 0x3b, 0x86, 0xd1, 0xe7, 0x23, 0xc3, 0xe2, 0x5b,
-	0xc9, 0x59, 0xdb, 0x50, 0xfe, 0xc8, 0x63, 0xe4, 0x06, 0x94, 0xec, 0xa0, 0x3b, 0x08, 0x1d, 0x6e,
-	0x47, 0x4f, 0x7f, 0x3b, 0xd8, 0x13, 0x20, 0x7a, 0x04, 0xcb, 0x3e, 0xa3, 0x47, 0x94, 0x75, 0x03,
-	0xf1, 0x4a, 0x8e, 0x27, 0x67, 0xaa, 0x59, 0xb9, 0xa2, 0xd6, 0xda, 0xd4, 0xb5, 0xa2, 0xa9, 0xd9,
-	0x07, 0x30, 0x2f, 0x3a, 0x96, 0x01, 0xe5, 0x44, 0x8e, 0x08, 0x87, 0x0d, 0xcb, 0x9e, 0x46, 0xa1,
-	0x35, 0x75, 0x6e, 0x3d, 0x14, 0xaa, 0x8e, 0x57, 0x51, 0x58, 0x12, 0x4b, 0xba, 0xd5, 0x9f, 0x65,
-	0xeb, 0xae, 0x74, 0x46, 0xcb, 0x60, 0x34, 0xf7, 0x77, 0xb6, 0xf7, 0xf1, 0x5e, 0xad, 0x73, 0xd8,
-	0x7a, 0xd9, 0xda, 0x7f, 0xd5, 0x32, 0x66, 0x52, 0xd8, 0xad, 0xfa, 0x76, 0xed, 0xb0, 0xd9, 0x31,
-	0x32, 0xe8, 0x0a, 0x2c, 0xc4, 0xd8, 0x6f, 0xdb, 0xfb, 0x2d, 0x23, 0x8b, 0x10, 0x2c, 0xc6, 0xa8,
-	0x83, 0x66, 0xad, 0xd1, 0x32, 0x72, 0xab, 0xa7, 0x70, 0x6d, 0xec, 0xd4, 0x13, 0xdd, 0x86, 0x1b,
-	0xb8, 0xf6, 0xaa, 0xb9, 0xbf, 0xb3, 0x53, 0xc7, 0x9b, 0xfb, 0xad, 0xed, 0x46, 0x52, 0xd6, 0xcc,
-	0xc4, 0xe5, 0x0d, 0xb1, 0x9c, 0x41, 0xf7, 0xe0, 0xd6, 0xd8, 0xe5, 0x48, 0xeb, 0xec, 0xea, 0xf7,
-	0xb0, 0x3c, 0xee, 0x99, 0x8f, 0x8a, 0x90, 0xab, 0x35, 0x9b, 0xc6, 0x0c, 0x9a, 0x83, 0x22, 0x3e,
-	0x6c, 0xb5, 0x1a, 0xad, 0x1d, 0x23, 0x83, 0x16, 0x01, 0x3a, 0x75, 0xbc, 0xd7, 0x68, 0xd5, 0x3a,
-	0xf5, 0x2d, 0x23, 0x8b, 0x00, 0x0a, 0xdb, 0xb5, 0x46, 0xb3, 0xbe, 0x65, 0xe4, 0xc4, 0x5a, 0xfb,
-	0x70, 0x73, 0xb3, 0xde, 0x6e, 0x6f, 0x1f, 0x36, 0x8d, 0xfc, 0x2a, 0x85, 0xa2, 0x7e, 0xc4, 0x0b,
-	0x19, 0x43, 0x3b, 0x2d, 0x40, 0x39, 0x96, 0x61, 0x64, 0x50, 0x09, 0xf2, 0x2f, 0x1b, 0xcd, 0xa6,
-	0x12, 0xb6, 0x5b, 0x6b, 0xed, 0x1c, 0x1e, 0x18, 0x39, 0x81, 0x6d, 0xb4, 0x1a, 0x1d, 0x23, 0x8f,
-	0xca, 0x30, 0x7b, 0xd8, 0xae, 0xe3, 0xcf, 0x8c, 0xd9, 0xe8, 0x73, 0xdd, 0x28, 0x88, 0xf5, 0xda,
-	0x06, 0xee, 0x18, 0xc5, 0xd5, 0xef, 0x60, 0x21, 0xf5, 0xbc, 0x15, 0xe6, 0xad, 0xe1, 0xcd, 0xdd,
-	0xc6, 0x77, 0xf5, 0xe1, 0x9e, 0x4b, 0x30, 0xa7, 0x71, 0xb5, 0xc3, 0xce, 0xbe, 0x91, 0x41, 0x06,
-	0xcc, 0x6b, 0x44, 0xa7, 0x86, 0x77, 0x7e, 0x30, 0xb2, 0x42, 0x7d, 0x8d, 0xf9, 0xa1, 0x71, 0x60,
-	0xe4, 0x56, 0x3f, 0x83, 0xa5, 0x91, 0x97, 0x90, 0xd8, 0xb4, 0xb5, 0xdf, 0xaa, 0xab, 0xbb, 0xde,
-	0x6c, 0xd6, 0x6b, 0xad, 0xe8, 0x20, 0x0d, 0x61, 0xed, 0xd5, 0x1f, 0xe3, 0x24, 0x95, 0xf2, 0x71,
-	0xe1, 0x03, 0x09, 0xb3, 0xb7, 0x5e, 0x19, 0x33, 0x62, 0xb7, 0xd4, 0x45, 0xc5, 0xb0, 0xf6, 0x11,
-	0x03, 0xe6, 0x15, 0xdc, 0xee, 0x60, 0x61, 0xfa, 0xdc, 0xfa, 0xdf, 0x11, 0x2c, 0xa7, 0xde, 0x75,
-	0x7b, 0x3a, 0x49, 0x3d, 0x86, 0x6c, 0x63, 0x0b, 0xad, 0x9c, 0x4b, 0x4c, 0xf5, 0x81, 0xcf, 0xcf,
-	0xaa, 0xf1, 0xf0, 0x3e, 0x31, 0xc6, 0xfb, 0x1c, 0x0a, 0x2a, 0x91, 0xa3, 0xf1, 0xc3, 0xae, 0x6a,
-	0x3c, 0xc7, 0x4f, 0x8e, 0xf5, 0x3e, 0x85, 0x7c, 0xd3, 0x0e, 0x38, 0x5a, 0x4c, 0x8f, 0x87, 0xc6,
-	0x12, 0x3f, 0xce, 0xa0, 0x47, 0x30, 0xbb, 0xc3, 0xbc, 0xd0, 0x47, 0xf1, 0x48, 0x47, 0xcf, 0x6f,
-	0x26, 0x31, 0x3c, 0x81, 0xdc, 0x0e, 0xe5, 0x68, 0xd2, 0x23, 0x76, 0xbc, 0x52, 0x5f, 0x42, 0x41,
-	0xdd, 0xd2, 0xf0, 0x28, 0xa9, 0xb9, 0x54, 0x75, 0x62, 0x96, 0x45, 0x5f, 0xc2, 0xec, 0xa6, 0x43,
-	0x09, 0x9b, 0x68, 0xba, 0x0b, 0x58, 0xbd, 0x80, 0x5e, 0x82, 0xf5, 0x2b, 0x80, 0x0e, 0xe9, 0x47,
-	0x53, 0xb3, 0xd1, 0x33, 0x75, 0x48, 0x7f, 0x9a, 0xca, 0x2f, 0xa0, 0x8c, 0x69, 0x40, 0xb9, 0x9c,
-	0x70, 0x4d, 0x34, 0xd4, 0x64, 0xfe, 0x2f, 0xa0, 0xb8, 0x73, 0x11, 0xf7, 0x38, 0x95, 0xd0, 0x01,
-	0x5c, 0xc7, 0xb4, 0x6f, 0x07, 0x22, 0x4f, 0x8c, 0x04, 0xc5, 0xcd, 0xb1, 0x73, 0x03, 0x35, 0xa4,
-	0x98, 0x6a, 0xc2, 0xfc, 0x2b, 0x62, 0xf3, 0x4b, 0x9e, 0x42, 0xb8, 0x32, 0x39, 0x75, 0xff, 0x49,
-	0x67, 0x69, 0x25, 0x9e, 0xea, 0xba, 0xe7, 0xd1, 0x71, 0x50, 0x99, 0xf4, 0x0e, 0xab, 0x56, 0x27,
-	0x75, 0x4b, 0x8d, 0x2d, 0xb4, 0x07, 0xd7, 0xce, 0xc9, 0x3b, 0xa6, 0xbd, 0x37, 0x68, 0x0a, 0xd3,
-	0x94, 0x73, 0x8d, 0x11, 0xd7, 0xa6, 0x3c, 0xf4, 0x2f, 0x29, 0x6e, 0xff, 0xfc, 0x7b, 0x4c, 0xf8,
-	0xbb, 0x7b, 0x69, 0x81, 0x07, 0x70, 0x75, 0xcc, 0xcb, 0x06, 0xdd, 0x9d, 0x24, 0x4c, 0xbf, 0xfe,
-	0xa6, 0x48, 0x24, 0xe7, 0x4f, 0x2c, 0x1f, 0x65, 0xe8, 0x83, 0x49, 0x32, 0xe3, 0x27, 0x61, 0xf5,
-	0xfe, 0x54, 0x92, 0x38, 0xd7, 0xfd, 0xd7, 0xf9, 0x67, 0x65, 0xfc, 0x1c, 0x43, 0xf7, 0xa7, 0xa8,
-	0x3e, 0x7c, 0xb1, 0x4d, 0x39, 0xc0, 0xcf, 0xb0, 0x3c, 0xae, 0x8b, 0x46, 0xf7, 0xa6, 0xf5, 0xd8,
-	0x52, 0xe6, 0xbf, 0x5d, 0xd0, 0x85, 0x2b, 0xed, 0x71, 0xdc, 0xdc, 0x25, 0xda, 0x6f, 0x74, 0x67,
-	0xa4, 0x5d, 0x19, 0x69, 0xcd, 0xab, 0xb7, 0xc6, 0xad, 0xc7, 0x2d, 0x73, 0x03, 0x96, 0x92, 0x78,
-	0x91, 0x73, 0x2b, 0xe3, 0x18, 0xde, 0x43, 0xd4, 0x6e, 0x5a, 0x3d, 0x4c, 0x07, 0xde, 0x09, 0x9d,
-	0x22, 0x6d, 0x9a, 0x6f, 0x55, 0x53, 0x87, 0x11, 0xd9, 0xb5, 0xe6, 0x5a, 0xbf, 0x41, 0x62, 0x1d,
-	0xae, 0xa4, 0x25, 0x5e, 0x2e, 0xd5, 0x6f, 0xa6, 0xad, 0xd5, 0xa4, 0xee, 0xc5, 0x42, 0xce, 0xf5,
-	0xfe, 0x8d, 0xb4, 0x2e, 0x07, 0x2c, 0x74, 0x29, 0x9a, 0xf2, 0x94, 0x98, 0xa2, 0xcf, 0x33, 0x28,
-	0xa8, 0xdf, 0x7d, 0x26, 0xaa, 0x11, 0x4f, 0xda, 0x47, 0x7e, 0x1f, 0x7a, 0x31, 0x9c, 0xe6, 0x6f,
-	0xdb, 0x0e, 0x45, 0xf1, 0x6f, 0xe0, 0xc9, 0x19, 0xff, 0x94, 0x9d, 0x9f, 0xc2, 0xfc, 0x0e, 0xe5,
-	0x89, 0x01, 0x76, 0xe2, 0xb8, 0x7a, 0x40, 0x5e, 0x4d, 0xfe, 0x63, 0x88, 0x26, 0x7b, 0x0e, 0x73,
-	0xaa, 0x32, 0xc8, 0x99, 0x31, 0x8a, 0x29, 0xe2, 0x11, 0xf2, 0xf4, 0x7a, 0x17, 0xff, 0x1e, 0x30,
-	0x2c, 0xf0, 0xa9, 0x9f, 0x08, 0x26, 0x73, 0x3f, 0xcc, 0xa0, 0xff, 0x84, 0x79, 0xf1, 0x2e, 0xd8,
-	0xa3, 0x41, 0x20, 0xe7, 0x9d, 0x2b, 0xe3, 0xfb, 0xfc, 0xc9, 0x32, 0x36, 0xe0, 0x87, 0x92, 0x1c,
-	0xa9, 0xba, 0xc4, 0x79, 0x5d, 0x90, 0x86, 0x7e, 0xf2, 0x8f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1a,
-	0x6e, 0xe0, 0x6c, 0xfb, 0x25, 0x00, 0x00,
+	// 3246 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x3a, 0xcb, 0x72, 0x1b, 0x47,
+	0x92, 0xc2, 0x83, 0x78, 0x24, 0x09, 0xb2, 0x59, 0xa2, 0x28, 0x08, 0x7a, 0xba, 0xbd, 0xb2, 0x64,
+	0x3a, 0x4c, 0xc9, 0x94, 0xb5, 0x96, 0x65, 0x5b, 0xbb, 0x20, 0x09, 0x52, 0xb0, 0x40, 0x90, 0xd1,
+	0x00, 0x2d, 0x87, 0xbd, 0x36, 0xa2, 0x84, 0x2e, 0x82, 0x6d, 0x01, 0xdd, 0xad, 0xea, 0x6a, 0x4a,
+	0xf4, 0x6d, 0x63, 0x0f, 0x7b, 0xdc, 0xfd, 0x81, 0xb9, 0xcd, 0x69, 0x66, 0x22, 0xe6, 0x3a, 0x3f,
+	0x30, 0xdf, 0x32, 0xf7, 0xf9, 0x82, 0x89, 0x7a, 0x35, 0xba, 0x9b, 0x00, 0xa8, 0xa1, 0xe7, 0xc4,
+	0xce, 0xac, 0xcc, 0xac, 0xac, 0xac, 0x7c, 0x55, 0x82, 0xb0, 0xf0, 0x0b, 0x0e, 0x7c, 0x42, 0xd7,
+	0x7d, 0xea, 0x31, 0x0f, 0x15, 0x24, 0x54, 0xbb, 0x3e, 0xf0, 0xbc, 0xc1, 0x90, 0x3c, 0x10, 0xd8,
+	0x57, 0xe1, 0xd1, 0x03, 0x32, 0xf2, 0xd9, 0xa9, 0x24, 0xaa, 0xdd, 0x4e, 0x2f, 0x32, 0x67, 0x44,
+	0x02, 0x86, 0x47, 0xbe, 0x22, 0xb8, 0x95, 0x26, 0xb0, 0x43, 0x8a, 0x99, 0xe3, 0xb9, 0x72, 0xdd,
+	0xfc, 0x5b, 0x16, 0x16, 0x5a, 0xde, 0x60, 0x40, 0xe8, 0x96, 0xe7, 0x1e, 0x39, 0x03, 0xf4, 0x04,
+	0x8a, 0x36, 0x39, 0xc2, 0xe1, 0x90, 0x55, 0x33, 0x77, 0x32, 0xf7, 0xe7, 0x37, 0x6e, 0xac, 0x2b,
+	0xb5, 0xb6, 0x25, 0x5a, 0x52, 0xef, 0xfb, 0x5c, 0x48, 0xf0, 0xfc, 0x92, 0xa5, 0xc9, 0xd1, 0x03,
+	0xc8, 0x1f, 0x39, 0x43, 0x52, 0xcd, 0x0a, 0xb6, 0x6b, 0x9a, 0x6d, 0xc7, 0x19, 0x92, 0x34, 0x8f,
+	0x20, 0x44, 0xcf, 0xa0, 0xec, 0xb8, 0xc7, 0x84, 0x3a, 0x8c, 0xd8, 0xd5, 0x9c, 0xe0, 0xba, 0xa5,
+	0xb9, 0x9a, 0x7a, 0x21, 0xcd, 0x3a, 0x66, 0x41, 0x5f, 0x73, 0xfe, 0xde, 0x88, 0x8c, 0x3c, 0x7a,
+	0x5a, 0xcd, 0x0b, 0xfe, 0x9b, 0x63, 0xfe, 0x3d, 0x81, 0x4f, 0xb3, 0x97, 0x1c, 0xb5, 0x80, 0x3e,
+	0x81, 0x1c, 0xc5, 0x6f, 0xab, 0x73, 0x82, 0xef, 0xaa, 0xe6, 0xb3, 0xf0, 0xdb, 0xb8, 0x39, 0x9e,
+	0x5f, 0xb2, 0x38, 0x15, 0x7a, 0x0c, 0x85, 0xc0, 0x1f, 0x86, 0xee, 0xeb, 0x6a, 0x41, 0xd0, 0x5f,
+	0xd7, 0xf4, 0x1d, 0x81, 0x4d, 0xef, 0xa2, 0x88, 0x37, 0x01, 0x4a, 0x3e, 0xf5, 0xec, 0xb0, 0x4f,
+	0xa8, 0xb9, 0x09, 0xa5, 0x96, 0x37, 0x68, 0x91, 0x13, 0x32, 0x44, 0x37, 0xa0, 0xcc, 0x8e, 0x29,
+	0x09, 0x8e, 0xbd, 0xa1, 0x2d, 0xcc, 0x3c, 0x67, 0x8d, 0x11, 0xa8, 0x3a, 0xbe, 0x82, 0xac, 0x58,
+	0xd3, 0xa0, 0xf9, 0x0a, 0x2a, 0x9b, 0xe1, 0xd1, 0x51, 0xb4, 0x15, 0xaa, 0x41, 0xe9, 0x95, 0x40,
+	0x10, 0x29, 0xa7, 0x64, 0x45, 0x30, 0x5f, 0xd3, 0x97, 0x2d, 0xe4, 0xe4, 0xac, 0x08, 0x46, 0xd7,
+	0xa0, 0x34, 0xc2, 0xef, 0x7a, 0x81, 0xf3, 0x2b, 0x11, 0x96, 0xcf, 0x59, 0xc5, 0x11, 0x7e, 0xd7,
+	0x71, 0x7e, 0x25, 0xe6, 0xff, 0x65, 0x60, 0x7e, 0x13, 0x07, 0x44, 0x6f, 0xf1, 0x11, 0xcc, 0x0d,
+	0xb9, 0xd2, 0xca, 0x1d, 0x0c, 0x7d, 0x72, 0x7d, 0x18, 0x4b, 0x2e, 0xa3, 0x4f, 0xa1, 0x20, 0xb7,
+	0x56, 0x0e, 0x70, 0x45, 0x13, 0x26, 0x34, 0xb6, 0x14, 0x11, 0xfa, 0x18, 0x0a, 0x47, 0x1e, 0x1d,
+	0x61, 0x26, 0xf6, 0x5f, 0xdc, 0x58, 0x8e, 0xc9, 0xdd, 0x11, 0x0b, 0x96, 0x22, 0x30, 0x5f, 0xc2,
+	0xca, 0x24, 0xdf, 0x43, 0xab, 0x50, 0xf0, 0x29, 0x39, 0x72, 0xde, 0x09, 0xd5, 0xca, 0x96, 0x82,
+	0xd0, 0x3d, 0xc8, 0xbf, 0xc2, 0x81, 0x76, 0xc4, 0xcb, 0x91, 0x1e, 0xe3, 0x43, 0x59, 0x82, 0xc0,
+	0xfc, 0x1e, 0x96, 0xcf, 0x78, 0x27, 0x37, 0x1b, 0xf7, 0x4e, 0x17, 0x8f, 0x88, 0x92, 0x1b, 0xc1,
+	0xef, 0x2f, 0xb9, 0x0e, 0xab, 0x93, 0x3d, 0x38, 0x12, 0x91, 0x39, 0x4f, 0x84, 0x0d, 0x57, 0x26,
+	0x3a, 0x31, 0x32, 0xa1, 0x12, 0xb9, 0x7d, 0xaf, 0x8f, 0x7d, 0x21, 0x2a, 0x67, 0xcd, 0x6b, 0xcf,
+	0xde, 0xc2, 0xfe, 0xfb, 0x2b, 0xda, 0x06, 0x90, 0x2e, 0xdc, 0x74, 0x8f, 0x3c, 0x64, 0x40, 0x2e,
+	0xa4, 0x43, 0x75, 0x6c, 0xfe, 0x89, 0x56, 0x60, 0x8e, 0x79, 0xaf, 0x89, 0xf4, 0xa0, 0xb2, 0x25,
+	0x01, 0xee, 0xa1, 0xfd, 0x63, 0xec, 0xba, 0x64, 0x28, 0x6e, 0xaf, 0x6c, 0x69, 0xd0, 0xfc, 0x05,
+	0x2e, 0x4f, 0x08, 0x09, 0xb4, 0x16, 0xc5, 0x8f, 0x3c, 0x37, 0x4a, 0xc6, 0x0f, 0xdf, 0x5c, 0x07,
+	0xcd, 0xfb, 0xeb, 0xee, 0xc0, 0x52, 0x2a, 0x5c, 0x79, 0x9c, 0x2a, 0xaf, 0xca, 0x08, 0xaf, 0xba,
+	0x39, 0x25, 0xae, 0x93, 0x1e, 0x86, 0x6e, 0xc3, 0x7c, 0x5f, 0xe0, 0x7b, 0x36, 0x66, 0x58, 0xec,
+	0xbc, 0x60, 0x81, 0x44, 0x6d, 0x63, 0x86, 0xcd, 0xff, 0xce, 0x42, 0x65, 0x3f, 0x64, 0x7e, 0xc8,
+	0xf4, 0x89, 0xd6, 0xa1, 0x38, 0x14, 0x02, 0x83, 0x6a, 0xe6, 0x4e, 0xee, 0xfe, 0xfc, 0xc6, 0x4a,
+	0xcc, 0x81, 0xa3, 0x7d, 0x2c, 0x4d, 0x84, 0xee, 0xc1, 0x52, 0x10, 0xfa, 0x3e, 0x25, 0x41, 0xd0,
+	0xf3, 0x84, 0x24, 0xb1, 0x4d, 0xc9, 0x5a, 0xd4, 0x68, 0x29, 0x1f, 0xdd, 0x85, 0x08, 0xd3, 0x23,
+	0x94, 0x7a, 0x54, 0x98, 0xb8, 0x64, 0x55, 0x34, 0xb6, 0xc1, 0x91, 0xe8, 0x0b, 0xa8, 0x52, 0x62,
+	0x3b, 0x94, 0xf4, 0x99, 0x92, 0xd7, 0x63, 0x9e, 0x62, 0xc8, 0x0b, 0x86, 0x2b, 0x7a, 0x5d, 0x0a,
+	0xee, 0x7a, 0x67, 0x19, 0x05, 0x39, 0xe7, 0x53, 0x1a, 0xcd, 0x25, 0x19, 0x05, 0x43, 0xd7, 0x93,
+	0xfc, 0xe6, 0x5f, 0xf3, 0x50, 0xd9, 0xa2, 0x04, 0xb3, 0x28, 0x35, 0x20, 0xc8, 0x63, 0x3a, 0x90,
+	0x06, 0x28, 0x5b, 0xe2, 0x1b, 0x7d, 0x02, 0xcb, 0x6f, 0x3d, 0xfa, 0xda, 0x71, 0x07, 0x3d, 0x29,
+	0x84, 0x27, 0x67, 0xe9, 0x3c, 0x86, 0x5a, 0xd8, 0xd6, 0x78, 0xf4, 0x1c, 0xe6, 0x89, 0x7b, 0xe2,
+	0x50, 0xcf, 0x1d, 0x11, 0x97, 0x67, 0x02, 0x6e, 0xc8, 0x8f, 0xb4, 0x21, 0x13, 0x9b, 0xad, 0x37,
+	0xc6, 0x84, 0x0d, 0x97, 0xd1, 0x53, 0x2b, 0xce, 0x8a, 0x3e, 0x06, 0xc3, 0x3b, 0x21, 0x94, 0x3a,
+	0x36, 0xe9, 0x29, 0xbc, 0x32, 0xc3, 0x92, 0xc6, 0x2b, 0x01, 0xfc, 0x26, 0x78, 0x95, 0xf4, 0x42,
+	0xd6, 0x0b, 0x48, 0xdf, 0x73, 0xed, 0x40, 0x9c, 0x3b, 0x67, 0x2d, 0x2a, 0x74, 0x47, 0x62, 0xf9,
+	0xf1, 0x18, 0x1e, 0x04, 0xd5, 0x82, 0x3c, 0x1e, 0xff, 0x46, 0x9f, 0x03, 0x78, 0x6e, 0x2f, 0x08,
+	0xfb, 0x7d, 0x12, 0x04, 0xd5, 0xe2, 0x9d, 0x5c, 0x3c, 0xd3, 0x25, 0x14, 0xb6, 0xca, 0x9e, 0xdb,
+	0x91, 0x74, 0x8a, 0xeb, 0x08, 0x3b, 0xc3, 0x90, 0x92, 0x6a, 0xe9, 0x1c, 0xae, 0x1d, 0x49, 0xa7,
+	0xb8, 0x94, 0x52, 0xd5, 0xf2, 0x39, 0x5c, 0x5d, 0x49, 0xc7, 0xf3, 0xb0, 0xba, 0x4d, 0x48, 0xe6,
+	0xe1, 0x84, 0xff, 0x5a, 0x8a, 0x08, 0x3d, 0x84, 0x95, 0x80, 0x61, 0xd7, 0xc6, 0xd4, 0xee, 0x39,
+	0x2e, 0x77, 0xa3, 0x57, 0xa7, 0x8c, 0x04, 0xd5, 0x79, 0x11, 0x03, 0x48, 0xaf, 0x35, 0xf9, 0xd2,
+	0x26, 0x5f, 0xa9, 0x3d, 0x03, 0x23, 0x7d, 0x17, 0x3c, 0x71, 0xbc, 0x26, 0xa7, 0x3a, 0x71, 0xbc,
+	0x26, 0xa7, 0x3c, 0x71, 0x9c, 0xe0, 0x61, 0x48, 0x74, 0xe2, 0x10, 0xc0, 0xd3, 0xec, 0x93, 0x8c,
+	0x69, 0x02, 0x34, 0xb7, 0x2d, 0x12, 0xf8, 0x9e, 0x1b, 0x90, 0x31, 0x5d, 0x26, 0x46, 0x67, 0xfe,
+	0x3d, 0x0b, 0xf3, 0x07, 0xd4, 0xe3, 0xc6, 0x13, 0x89, 0x69, 0x11, 0xb2, 0x8e, 0xad, 0x48, 0xb2,
+	0x8e, 0xcd, 0xf7, 0xf3, 0x1d, 0x5b, 0x95, 0x35, 0xfe, 0x89, 0xae, 0x42, 0xf1, 0xd8, 0x0b, 0x58,
+	0xcf, 0xb1, 0x55, 0x4a, 0x2a, 0x70, 0xb0, 0x29, 0xaa, 0x29, 0x0d, 0x5d, 0xd7, 0x71, 0x07, 0xca,
+	0x21, 0x34, 0x88, 0x6e, 0x01, 0xa8, 0x8b, 0x3c, 0x0a, 0x87, 0xca, 0xf7, 0x63, 0x18, 0x5e, 0x09,
+	0xfa, 0xde, 0xc8, 0x1f, 0x12, 0x46, 0x44, 0xd9, 0x2f, 0x59, 0x11, 0xcc, 0xd7, 0xf8, 0xc5, 0xd8,
+	0xfc, 0x66, 0x8a, 0x72, 0x4d, 0xc3, 0xe8, 0x01, 0x14, 0x3d, 0x69, 0xe5, 0x6a, 0xe9, 0x4e, 0x66,
+	0xfa, 0xa5, 0x69, 0x2a, 0x74, 0x1d, 0xca, 0xe4, 0x9d, 0xc3, 0x7a, 0x7d, 0xcf, 0x26, 0xd5, 0xb2,
+	0x28, 0xf9, 0x25, 0x8e, 0xd8, 0xf2, 0x6c, 0x82, 0x1e, 0x43, 0x29, 0x60, 0x98, 0xb2, 0x1e, 0xd6,
+	0x37, 0x5a, 0x5b, 0x97, 0x4d, 0xdd, 0xba, 0x6e, 0xea, 0xd6, 0xbb, 0xba, 0xeb, 0xb3, 0x8a, 0x82,
+	0xb6, 0xce, 0xd0, 0x67, 0x50, 0x20, 0xae, 0xcd, 0x99, 0xe6, 0xcf, 0x65, 0x9a, 0x23, 0xae, 0x5d,
+	0x67, 0x66, 0x1d, 0x16, 0x3b, 0x0c, 0xb3, 0x30, 0x88, 0x2e, 0x27, 0x66, 0xd4, 0x4c, 0xc2, 0xa8,
+	0xab, 0x50, 0xc0, 0x7d, 0xe6, 0x9c, 0x10, 0x95, 0xc4, 0x14, 0x64, 0x3e, 0x85, 0xc2, 0x8e, 0x33,
+	0x64, 0x84, 0xa2, 0x87, 0x90, 0x8f, 0x4a, 0xe8, 0xe2, 0xb8, 0x89, 0x94, 0xab, 0x1d, 0x9f, 0xf4,
+	0x9d, 0x23, 0xa7, 0x8f, 0x55, 0x3a, 0xe7, 0x94, 0xa6, 0x07, 0x95, 0x8e, 0x33, 0x70, 0xf1, 0x50,
+	0x5d, 0x3c, 0x7a, 0x0c, 0x65, 0xed, 0x03, 0xdb, 0xd5, 0x4c, 0xb2, 0x4f, 0xfb, 0x56, 0xfc, 0x89,
+	0x96, 0xad, 0x31, 0x25, 0xba, 0x07, 0x85, 0x40, 0xc8, 0x11, 0xba, 0x2d, 0x6e, 0x2c, 0x45, 0xb5,
+	0x46, 0x60, 0x03, 0x4b, 0x2d, 0x9b, 0xb7, 0xa1, 0xd8, 0xc5, 0x83, 0x36, 0x1e, 0x4d, 0xf3, 0xc2,
+	0xff, 0x88, 0x9c, 0xb0, 0xcb, 0x63, 0xff, 0x06, 0x94, 0xfd, 0x84, 0x3e, 0x65, 0x6b, 0x8c, 0x88,
+	0xb2, 0x45, 0x76, 0x9c, 0x2d, 0xcc, 0x7b, 0xb0, 0x94, 0x52, 0x74, 0xca, 0x4e, 0x3f, 0x81, 0xb1,
+	0xef, 0x13, 0xd9, 0x9c, 0xed, 0x87, 0xac, 0xef, 0x8d, 0x08, 0x77, 0x5c, 0x9d, 0x67, 0x64, 0x6b,
+	0xa7, 0x41, 0xb1, 0x15, 0x79, 0xc7, 0x54, 0x68, 0x89, 0xef, 0xa4, 0x0f, 0xe5, 0x92, 0x3e, 0x64,
+	0xfe, 0x6f, 0x06, 0x16, 0xeb, 0xb4, 0x7f, 0xec, 0x9c, 0x44, 0xb9, 0x9b, 0x97, 0x99, 0x63, 0x2f,
+	0x1c, 0xda, 0x3d, 0xf2, 0x8e, 0x51, 0xdc, 0x67, 0x6a, 0x93, 0x8a, 0xc4, 0x36, 0x24, 0x92, 0x67,
+	0x13, 0x55, 0x50, 0xa5, 0x31, 0x23, 0x57, 0x56, 0xe2, 0xce, 0x16, 0x52, 0x86, 0xe9, 0x80, 0xb0,
+	0x9e, 0x8f, 0xd9, 0xb1, 0x8a, 0x44, 0x90, 0xa8, 0x03, 0xcc, 0x8e, 0x4d, 0x0f, 0x16, 0xb6, 0xbd,
+	0xb7, 0xee, 0xd0, 0xc3, 0xf6, 0x94, 0x8e, 0x03, 0x41, 0x5e, 0xf0, 0xaa, 0xc3, 0xf1, 0x6f, 0xf4,
+	0x25, 0x2c, 0x60, 0xb9, 0x5f, 0xcf, 0xf3, 0x59, 0xa0, 0x1e, 0x0b, 0xab, 0x29, 0x5d, 0x74, 0x5c,
+	0xcd, 0xe3, 0x08, 0x0e, 0x4c, 0x07, 0x2a, 0x2f, 0xa9, 0xc3, 0x08, 0x6f, 0xf4, 0xc4, 0x8e, 0x5a,
+	0x7e, 0x26, 0x26, 0x9f, 0xf7, 0x33, 0x9e, 0xcb, 0x78, 0x0d, 0x92, 0xb5, 0x5f, 0x83, 0xc2, 0xd1,
+	0x7d, 0x9f, 0xb8, 0xb6, 0x4a, 0x1e, 0x0a, 0x12, 0x52, 0x08, 0x1d, 0x09, 0x4d, 0x2a, 0x96, 0xf8,
+	0x36, 0xef, 0xc2, 0xd2, 0x66, 0xe8, 0x0c, 0x6d, 0x59, 0xf2, 0x0f, 0xad, 0x96, 0xb8, 0xa9, 0x90,
+	0x0e, 0xa3, 0x0a, 0xc9, 0xbf, 0xcd, 0x17, 0x00, 0x2d, 0x6f, 0x60, 0x91, 0x37, 0x21, 0x09, 0x18,
+	0xba, 0x17, 0x65, 0xb6, 0x19, 0xde, 0xcd, 0x53, 0xde, 0x0a, 0xcc, 0xf5, 0xbd, 0x50, 0x69, 0x98,
+	0xb3, 0x24, 0x60, 0x3e, 0x82, 0x72, 0xcb, 0x1b, 0x74, 0x18, 0x25, 0x78, 0xc4, 0x77, 0x1b, 0x7a,
+	0xe3, 0x7a, 0xcc, 0xbf, 0x39, 0xce, 0xf6, 0x5c, 0x1d, 0xa7, 0xe2, 0x9b, 0xb7, 0xf8, 0x97, 0x65,
+	0x30, 0x74, 0xa9, 0xc3, 0x75, 0x3d, 0xc0, 0x14, 0x8f, 0x44, 0xc0, 0xf9, 0xef, 0x1d, 0x70, 0x63,
+	0xcf, 0xaf, 0xc3, 0x52, 0x10, 0x97, 0xd6, 0xdc, 0x56, 0xce, 0x72, 0x35, 0x19, 0x79, 0xd1, 0xb2,
+	0x95, 0xa6, 0x37, 0x3f, 0x80, 0x72, 0xe3, 0x84, 0xb8, 0x6c, 0x46, 0x30, 0x3e, 0x05, 0xd4, 0xe9,
+	0x53, 0xc7, 0x67, 0x8e, 0x3b, 0x78, 0x8e, 0xa9, 0x2b, 0xf7, 0x4e, 0x17, 0x86, 0x15, 0x98, 0x0b,
+	0x08, 0x0b, 0x7d, 0x75, 0x5e, 0x09, 0x98, 0x7f, 0xcc, 0xc0, 0x6a, 0xc4, 0xac, 0xdc, 0x64, 0xd7,
+	0x1b, 0x62, 0x77, 0xc0, 0x2f, 0x78, 0xe0, 0xc5, 0x1c, 0x42, 0x41, 0x12, 0x4f, 0x3d, 0x4f, 0x47,
+	0x99, 0x82, 0x78, 0xe2, 0xf7, 0x71, 0xff, 0x35, 0x1e, 0x90, 0x40, 0xf4, 0x2b, 0x65, 0x2b, 0x82,
+	0x79, 0x82, 0x18, 0xf7, 0x3c, 0x79, 0x99, 0x20, 0x22, 0x04, 0xef, 0x3b, 0x42, 0xdf, 0xc6, 0x8c,
+	0xf4, 0x22, 0x01, 0xb2, 0xe6, 0x2c, 0x4a, 0xf4, 0x81, 0xc2, 0x9a, 0xff, 0x93, 0x3d, 0xab, 0xed,
+	0xc1, 0x29, 0x3b, 0xf6, 0x5c, 0x74, 0x1f, 0x8c, 0x13, 0x87, 0xb2, 0x10, 0x0f, 0x79, 0x97, 0xd3,
+	0x8b, 0xe9, 0xbd, 0xa8, 0xf0, 0x0d, 0xf7, 0x84, 0x07, 0x1a, 0xef, 0xc3, 0x28, 0x79, 0x13, 0x3a,
+	0x94, 0xf0, 0x2a, 0x1d, 0xf4, 0x62, 0x31, 0x65, 0xc4, 0x17, 0x04, 0xf1, 0xa7, 0x80, 0x1c, 0x97,
+	0x11, 0xea, 0x53, 0xc2, 0x08, 0xed, 0xbd, 0x72, 0x5c, 0x4c, 0x4f, 0x55, 0xf4, 0x2e, 0xc7, 0x56,
+	0x36, 0xc5, 0x42, 0xc2, 0x06, 0xf9, 0x94, 0x0d, 0x3e, 0x84, 0xca, 0x90, 0x0c, 0x70, 0xff, 0xb4,
+	0xe7, 0x0b, 0x95, 0xd5, 0x19, 0x17, 0x24, 0x52, 0x1d, 0xc3, 0x84, 0x0a, 0xb6, 0xed, 0x1e, 0x23,
+	0x01, 0xeb, 0x51, 0xf2, 0x26, 0x50, 0xe5, 0x75, 0x1e, 0xdb, 0x76, 0x97, 0x04, 0xcc, 0x22, 0x6f,
+	0x02, 0xf3, 0x47, 0xb8, 0x9a, 0x36, 0x82, 0xe5, 0x05, 0x6f, 0xc9, 0x70, 0x38, 0x2d, 0x84, 0x83,
+	0xd3, 0x80, 0x91, 0x91, 0xce, 0xc0, 0x1a, 0x14, 0x51, 0xe1, 0x04, 0xbe, 0x3a, 0x8e, 0xf8, 0x36,
+	0x7f, 0x9f, 0x03, 0x23, 0x2d, 0x1d, 0x3d, 0xe1, 0x57, 0xce, 0x9d, 0xa2, 0x9a, 0x49, 0x0e, 0x23,
+	0x26, 0xbb, 0x0e, 0x7f, 0xe7, 0x4b, 0x7a, 0xce, 0xa9, 0x4e, 0x9b, 0x9d, 0xcd, 0x29, 0xcf, 0xcf,
+	0x39, 0x25, 0x3d, 0xfa, 0x0a, 0x8a, 0x54, 0x9e, 0x4a, 0x25, 0xb5, 0xdb, 0xd3, 0x58, 0xd5, 0xe1,
+	0xf9, 0xc4, 0x45, 0x71, 0xa0, 0x17, 0xc9, 0xf6, 0x39, 0x2f, 0x3a, 0xc4, 0x8f, 0xa7, 0x09, 0x38,
+	0xa7, 0x83, 0x1e, 0xf7, 0x8d, 0x73, 0xef, 0xd3, 0x37, 0xc6, 0xa7, 0x0b, 0x85, 0xe4, 0x74, 0xe1,
+	0xb7, 0x76, 0x88, 0x9b, 0x45, 0xb5, 0x62, 0x7e, 0x03, 0x57, 0xd3, 0x31, 0x6f, 0x85, 0x6e, 0x9d,
+	0xbf, 0x33, 0xd2, 0x81, 0xaf, 0xdf, 0x22, 0xd9, 0xf1, 0x5b, 0xc4, 0xfc, 0x09, 0xae, 0xa5, 0xd9,
+	0x45, 0x82, 0x9e, 0x28, 0x20, 0x11, 0xbc, 0xd9, 0x74, 0xf0, 0x6a, 0xf1, 0xb9, 0x98, 0xf8, 0x01,
+	0xdc, 0x9c, 0x28, 0x3e, 0x6a, 0x9f, 0x36, 0xa0, 0xe8, 0xc9, 0x62, 0xae, 0x3c, 0xaa, 0x1a, 0xd9,
+	0x34, 0x55, 0xec, 0x2d, 0x4d, 0x38, 0xa9, 0xfc, 0x99, 0xbb, 0x67, 0x37, 0xb2, 0x42, 0x57, 0xa2,
+	0x26, 0x9e, 0x65, 0x15, 0x0a, 0x81, 0x58, 0xd5, 0xc9, 0x4b, 0x42, 0xbc, 0x0f, 0xa8, 0xa6, 0x25,
+	0xf1, 0x80, 0xbb, 0x80, 0x41, 0xbe, 0x19, 0x37, 0xb9, 0xf2, 0xd9, 0xf6, 0xe1, 0x19, 0xbf, 0x8b,
+	0x6d, 0x90, 0x6e, 0x79, 0xcd, 0x3f, 0x64, 0xe0, 0xfa, 0x0c, 0x42, 0x6e, 0x86, 0xd8, 0x04, 0x46,
+	0x7c, 0x4f, 0xba, 0x62, 0x1e, 0xf6, 0x3e, 0x66, 0x8c, 0x50, 0x57, 0x4f, 0x22, 0x14, 0x88, 0x1e,
+	0x41, 0x51, 0x3f, 0x9d, 0xf2, 0x6a, 0x22, 0x99, 0xee, 0x80, 0xb7, 0x95, 0xc3, 0x5a, 0x9a, 0x72,
+	0x5c, 0x64, 0xe7, 0x44, 0x07, 0xa5, 0x8a, 0xec, 0x5f, 0x32, 0x50, 0x9b, 0xa4, 0xac, 0x45, 0x02,
+	0x3e, 0xf8, 0x9c, 0xa4, 0x6b, 0xbc, 0x6b, 0xcf, 0xbe, 0x7f, 0xd7, 0xfe, 0x38, 0x16, 0x55, 0xb9,
+	0xf3, 0xb4, 0x8e, 0x48, 0xb9, 0x15, 0xb4, 0xa3, 0xc9, 0xb2, 0xa3, 0x41, 0xf3, 0xff, 0x33, 0x70,
+	0x63, 0x8a, 0xea, 0x17, 0xf7, 0xd1, 0xaf, 0xa1, 0x48, 0xc5, 0xd1, 0xe5, 0x5d, 0xcc, 0x6f, 0x98,
+	0xb3, 0xee, 0x5e, 0x5a, 0xc9, 0xd2, 0x2c, 0xe6, 0x4f, 0xb0, 0xca, 0x47, 0x24, 0x8e, 0x3b, 0xd8,
+	0xc2, 0xfd, 0x63, 0x22, 0xdf, 0x44, 0xc2, 0x03, 0x27, 0x19, 0x32, 0xf6, 0x98, 0xca, 0xce, 0xca,
+	0x4b, 0x91, 0x67, 0x7d, 0x04, 0x46, 0x5c, 0xfc, 0x34, 0xc1, 0xe6, 0x9f, 0x32, 0xb0, 0x12, 0x27,
+	0x6c, 0xba, 0x01, 0xc3, 0x6e, 0xff, 0x62, 0x16, 0x91, 0xb1, 0x93, 0x8d, 0x62, 0xa7, 0x0a, 0xc5,
+	0x11, 0x76, 0xf1, 0x80, 0x50, 0xed, 0x96, 0x0a, 0x44, 0xff, 0x0e, 0x25, 0x2c, 0xba, 0x78, 0x62,
+	0x57, 0xf3, 0xe7, 0x3a, 0x46, 0x44, 0x6b, 0xfe, 0x92, 0x3c, 0x16, 0x1f, 0xd5, 0xfe, 0x4b, 0x34,
+	0x45, 0x90, 0x8f, 0x4d, 0x81, 0xc5, 0xb7, 0xb9, 0x0d, 0x48, 0xed, 0x75, 0x80, 0x4f, 0x79, 0xab,
+	0xce, 0x67, 0x60, 0x08, 0x41, 0x6e, 0x14, 0xc8, 0xda, 0x58, 0xe6, 0x73, 0xf1, 0x51, 0x30, 0x40,
+	0x48, 0x0e, 0xd1, 0x45, 0xd3, 0xac, 0x66, 0xe5, 0x9b, 0x05, 0xc8, 0xf3, 0x29, 0x9a, 0xf9, 0xbb,
+	0x2c, 0x2c, 0x26, 0xc5, 0xf0, 0xa2, 0xd1, 0xf2, 0x54, 0x8b, 0xa8, 0x66, 0xab, 0x1a, 0xe6, 0x6b,
+	0x3e, 0x75, 0x3c, 0xea, 0xb0, 0x53, 0x35, 0xf6, 0x8e, 0x60, 0xf4, 0x79, 0x6a, 0x58, 0x7c, 0x23,
+	0x3e, 0x6b, 0x1b, 0xcb, 0x4f, 0x3d, 0x46, 0xae, 0x41, 0xc9, 0x09, 0x7a, 0xa3, 0x70, 0xc8, 0x1c,
+	0xfd, 0xf4, 0x77, 0x82, 0x3d, 0x0e, 0xa2, 0x07, 0xb0, 0xc2, 0x87, 0xc5, 0x84, 0xf6, 0x02, 0xfe,
+	0x4a, 0x8e, 0x26, 0x67, 0xb2, 0x59, 0x59, 0x96, 0x6b, 0x1d, 0xe2, 0xda, 0x7a, 0x6a, 0xf6, 0x01,
+	0x2c, 0xf0, 0x8e, 0x65, 0x44, 0x18, 0x16, 0x23, 0xc2, 0x71, 0xc3, 0xb2, 0xa7, 0x50, 0x68, 0x5d,
+	0x9e, 0x5b, 0x0d, 0x85, 0x6a, 0x93, 0x55, 0xe4, 0x96, 0xb4, 0x04, 0xdd, 0xda, 0xcf, 0xa2, 0x75,
+	0x97, 0x3a, 0xa3, 0x15, 0x30, 0x5a, 0xfb, 0xbb, 0x3b, 0xfb, 0xd6, 0x5e, 0xbd, 0x7b, 0xd8, 0x7e,
+	0xd1, 0xde, 0x7f, 0xd9, 0x36, 0x2e, 0x25, 0xb0, 0xdb, 0x8d, 0x9d, 0xfa, 0x61, 0xab, 0x6b, 0x64,
+	0xd0, 0x32, 0x54, 0x22, 0xec, 0xb7, 0x9d, 0xfd, 0xb6, 0x91, 0x45, 0x08, 0x16, 0x23, 0xd4, 0x41,
+	0xab, 0xde, 0x6c, 0x1b, 0xb9, 0xb5, 0xb7, 0x70, 0x65, 0xe2, 0xd4, 0x13, 0xdd, 0x84, 0x6b, 0x56,
+	0xfd, 0x65, 0x6b, 0x7f, 0x77, 0xb7, 0x61, 0x6d, 0xed, 0xb7, 0x77, 0x9a, 0x71, 0x59, 0x97, 0xa6,
+	0x2e, 0x6f, 0xf2, 0xe5, 0x0c, 0xba, 0x03, 0x37, 0x26, 0x2e, 0x6b, 0xad, 0xb3, 0x6b, 0xdf, 0xc3,
+	0xca, 0xa4, 0x67, 0x3e, 0x2a, 0x42, 0xae, 0xde, 0x6a, 0x19, 0x97, 0xd0, 0x3c, 0x14, 0xad, 0xc3,
+	0x76, 0xbb, 0xd9, 0xde, 0x35, 0x32, 0x68, 0x11, 0xa0, 0xdb, 0xb0, 0xf6, 0x9a, 0xed, 0x7a, 0xb7,
+	0xb1, 0x6d, 0x64, 0x11, 0x40, 0x61, 0xa7, 0xde, 0x6c, 0x35, 0xb6, 0x8d, 0x1c, 0x5f, 0xeb, 0x1c,
+	0x6e, 0x6d, 0x35, 0x3a, 0x9d, 0x9d, 0xc3, 0x96, 0x91, 0x5f, 0x23, 0x50, 0x54, 0x8f, 0x78, 0x2e,
+	0x63, 0x6c, 0xa7, 0x0a, 0x94, 0x23, 0x19, 0x46, 0x06, 0x95, 0x20, 0xff, 0xa2, 0xd9, 0x6a, 0x49,
+	0x61, 0xcf, 0xeb, 0xed, 0xdd, 0xc3, 0x03, 0x23, 0xc7, 0xb1, 0xcd, 0x76, 0xb3, 0x6b, 0xe4, 0x51,
+	0x19, 0xe6, 0x0e, 0x3b, 0x0d, 0xeb, 0x33, 0x63, 0x4e, 0x7f, 0x6e, 0x18, 0x05, 0xbe, 0x5e, 0xdf,
+	0xb4, 0xba, 0x46, 0x71, 0xed, 0x3b, 0xa8, 0x24, 0x9e, 0xb7, 0xdc, 0xbc, 0x75, 0x6b, 0xeb, 0x79,
+	0xf3, 0xbb, 0xc6, 0x78, 0xcf, 0x25, 0x98, 0x57, 0xb8, 0xfa, 0x61, 0x77, 0xdf, 0xc8, 0x20, 0x03,
+	0x16, 0x14, 0xa2, 0x5b, 0xb7, 0x76, 0x7f, 0x30, 0xb2, 0x5c, 0x7d, 0x85, 0xf9, 0xa1, 0x79, 0x60,
+	0xe4, 0xd6, 0x3e, 0x83, 0xa5, 0xd4, 0x4b, 0x88, 0x6f, 0xda, 0xde, 0x6f, 0x37, 0xe4, 0x5d, 0x6f,
+	0xb5, 0x1a, 0xf5, 0xb6, 0x3e, 0x48, 0x93, 0x5b, 0x7b, 0xed, 0x47, 0x58, 0x49, 0x3a, 0x90, 0xd2,
+	0x68, 0x19, 0x2a, 0x31, 0xb3, 0xb7, 0x5f, 0x1a, 0x97, 0xf8, 0x6e, 0x89, 0x8b, 0x8a, 0x60, 0xe5,
+	0x23, 0x06, 0x2c, 0x48, 0xb8, 0xd3, 0xb5, 0xb8, 0xe9, 0x73, 0x1b, 0x7f, 0xbe, 0x0c, 0x2b, 0x89,
+	0x77, 0xdd, 0x9e, 0x4a, 0x52, 0x0f, 0x21, 0xdb, 0xdc, 0x46, 0xab, 0x67, 0x12, 0x53, 0x83, 0xff,
+	0xf4, 0x58, 0x8b, 0x86, 0xf7, 0xb1, 0x31, 0xde, 0xe7, 0x50, 0x90, 0x89, 0x1c, 0x4d, 0x1e, 0x76,
+	0xd5, 0xa2, 0x39, 0x7e, 0x7c, 0xac, 0xf7, 0x29, 0xe4, 0x5b, 0x4e, 0xc0, 0xd0, 0x62, 0x72, 0x3c,
+	0x34, 0x91, 0xf8, 0x61, 0x06, 0x3d, 0x80, 0xb9, 0x5d, 0xea, 0x85, 0x3e, 0x8a, 0x46, 0x3a, 0x6a,
+	0x7e, 0x33, 0x8d, 0xe1, 0x11, 0xe4, 0x76, 0x09, 0x43, 0xd3, 0x1e, 0xb1, 0x93, 0x95, 0xfa, 0x12,
+	0x0a, 0xf2, 0x96, 0xc6, 0x47, 0x49, 0xcc, 0xa5, 0x6a, 0x53, 0xb3, 0x2c, 0xfa, 0x12, 0xe6, 0xb6,
+	0x86, 0x04, 0xd3, 0xa9, 0xa6, 0x3b, 0x87, 0xd5, 0x0b, 0xc8, 0x05, 0x58, 0xbf, 0x02, 0xe8, 0xe2,
+	0x81, 0xd2, 0x0e, 0xa5, 0xcf, 0xc4, 0x47, 0x57, 0x33, 0x98, 0x9f, 0x41, 0xd9, 0x22, 0x01, 0x61,
+	0x9c, 0x6c, 0xba, 0xa1, 0xa6, 0xf3, 0x7f, 0x01, 0xc5, 0xdd, 0xf3, 0xb8, 0x27, 0xa9, 0x84, 0x0e,
+	0xe0, 0xaa, 0x45, 0x06, 0x4e, 0xc0, 0xf3, 0x44, 0x2a, 0x28, 0xae, 0x4f, 0x9c, 0x1b, 0xc8, 0x21,
+	0xc5, 0x4c, 0x13, 0xe6, 0x5f, 0x62, 0x87, 0x5d, 0xf0, 0x14, 0xdc, 0x95, 0xf1, 0x5b, 0xf7, 0x9f,
+	0x74, 0x96, 0x36, 0xac, 0xa6, 0x7b, 0x1e, 0x15, 0x07, 0xd5, 0x69, 0xef, 0xb0, 0x5a, 0x6d, 0x5a,
+	0xb7, 0xd4, 0xdc, 0x46, 0x7b, 0x70, 0xe5, 0x8c, 0xbc, 0x63, 0xd2, 0x7f, 0x8d, 0x66, 0x30, 0xcd,
+	0x38, 0xd7, 0x04, 0x71, 0x1d, 0x3e, 0x11, 0xb9, 0xa0, 0xb8, 0xfd, 0xb3, 0xef, 0x31, 0xee, 0xef,
+	0xee, 0x85, 0x05, 0x1e, 0xc0, 0xe5, 0x09, 0x2f, 0x1b, 0x74, 0x7b, 0x9a, 0x30, 0xf5, 0xfa, 0x9b,
+	0x21, 0x11, 0xc3, 0x95, 0x89, 0x8f, 0x32, 0xf4, 0xc1, 0x34, 0x99, 0xd1, 0x93, 0xb0, 0x76, 0x77,
+	0x26, 0x49, 0x94, 0xeb, 0xfe, 0x0b, 0xae, 0x4d, 0x7d, 0x8e, 0xa1, 0xbb, 0x33, 0x54, 0x1f, 0xbf,
+	0xd8, 0x66, 0x1c, 0xe0, 0x67, 0x58, 0x99, 0xd4, 0x45, 0xa3, 0x3b, 0xb3, 0x7a, 0x6c, 0x21, 0xf3,
+	0xdf, 0xce, 0xe9, 0xc2, 0xa5, 0xf6, 0x16, 0xa0, 0x78, 0x23, 0xa9, 0xbc, 0xf5, 0x56, 0xaa, 0x5d,
+	0x49, 0xb5, 0xe6, 0xb5, 0x1b, 0x93, 0xd6, 0xa3, 0x96, 0xb9, 0x09, 0x4b, 0x71, 0x3c, 0xcf, 0xb9,
+	0xd5, 0x49, 0x0c, 0xef, 0x21, 0xea, 0x79, 0x52, 0x3d, 0x8b, 0x8c, 0xbc, 0x13, 0x32, 0x43, 0xda,
+	0x2c, 0xdf, 0xaa, 0x25, 0x0e, 0xc3, 0xb3, 0x6b, 0xdd, 0xb5, 0x7f, 0x83, 0xc4, 0x06, 0x2c, 0x27,
+	0x25, 0x5e, 0x2c, 0xd5, 0x6f, 0x25, 0xad, 0xd5, 0x22, 0xee, 0xf9, 0x42, 0xce, 0xf4, 0xfe, 0xcd,
+	0xa4, 0x2e, 0x07, 0x34, 0x74, 0x09, 0x9a, 0xf1, 0x94, 0x98, 0xa1, 0xcf, 0x13, 0x28, 0xc8, 0xdf,
+	0x7d, 0xa6, 0xaa, 0x11, 0x4d, 0xda, 0x53, 0xbf, 0x0f, 0x3d, 0x1b, 0x4f, 0xf3, 0xf9, 0x7c, 0x1d,
+	0x45, 0xbf, 0x81, 0xc7, 0x67, 0xfc, 0x33, 0x76, 0x7e, 0x0c, 0x0b, 0xbb, 0x84, 0xc5, 0x06, 0xd8,
+	0xb1, 0xe3, 0xaa, 0x01, 0x79, 0x2d, 0xfe, 0x8f, 0x21, 0x8a, 0xec, 0x29, 0xcc, 0xcb, 0xca, 0x20,
+	0x66, 0xc6, 0x28, 0xa2, 0x88, 0x46, 0xc8, 0xb3, 0xeb, 0x5d, 0xf4, 0x7b, 0xc0, 0xb8, 0xc0, 0x27,
+	0x7e, 0x22, 0x98, 0xce, 0x7d, 0x3f, 0x83, 0xfe, 0x13, 0x16, 0xf8, 0xbb, 0x60, 0x8f, 0x04, 0x81,
+	0x98, 0x77, 0xae, 0x4e, 0xee, 0xf3, 0x67, 0x68, 0xf0, 0x14, 0x40, 0x9e, 0xa3, 0xe5, 0xcd, 0x2a,
+	0x9a, 0x67, 0xcf, 0xfd, 0x30, 0xb3, 0x09, 0x3f, 0x94, 0xc4, 0x38, 0xd6, 0xc5, 0xc3, 0x57, 0x05,
+	0x71, 0x49, 0x8f, 0xfe, 0x31, 0x00, 0x82, 0xde, 0x7a, 0x4b, 0x37, 0x26, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3424,6 +3424,7 @@ type JasperProcessManagerClient interface {
 	SignalEvent(ctx context.Context, in *EventName, opts ...grpc.CallOption) (*OperationOutcome, error)
 	WriteFile(ctx context.Context, opts ...grpc.CallOption) (JasperProcessManager_WriteFileClient, error)
 	SendMessages(ctx context.Context, in *LoggingPayload, opts ...grpc.CallOption) (*OperationOutcome, error)
+	StreamLogs(ctx context.Context, in *JasperProcessID, opts ...grpc.CallOption) (JasperProcessManager_StreamLogsClient, error)
 }
 
 type jasperProcessManagerClient struct {
@@ -3820,6 +3821,38 @@ func (c *jasperProcessManagerClient) SendMessages(ctx context.Context, in *Loggi
 	return out, nil
 }
 
+func (c *jasperProcessManagerClient) StreamLogs(ctx context.Context, in *JasperProcessID, opts ...grpc.CallOption) (JasperProcessManager_StreamLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_JasperProcessManager_serviceDesc.Streams[3], "/jasper.JasperProcessManager/StreamLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jasperProcessManagerStreamLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type JasperProcessManager_StreamLogsClient interface {
+	Recv() (*LogStream, error)
+	grpc.ClientStream
+}
+
+type jasperProcessManagerStreamLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *jasperProcessManagerStreamLogsClient) Recv() (*LogStream, error) {
+	m := new(LogStream)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // JasperProcessManagerServer is the server API for JasperProcessManager service.
 type JasperProcessManagerServer interface {
 	// Manager functions
@@ -3862,6 +3895,7 @@ type JasperProcessManagerServer interface {
 	SignalEvent(context.Context, *EventName) (*OperationOutcome, error)
 	WriteFile(JasperProcessManager_WriteFileServer) error
 	SendMessages(context.Context, *LoggingPayload) (*OperationOutcome, error)
+	StreamLogs(*JasperProcessID, JasperProcessManager_StreamLogsServer) error
 }
 
 func RegisterJasperProcessManagerServer(s *grpc.Server, srv JasperProcessManagerServer) {
@@ -4512,6 +4546,27 @@ func _JasperProcessManager_SendMessages_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JasperProcessManager_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JasperProcessID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JasperProcessManagerServer).StreamLogs(m, &jasperProcessManagerStreamLogsServer{stream})
+}
+
+type JasperProcessManager_StreamLogsServer interface {
+	Send(*LogStream) error
+	grpc.ServerStream
+}
+
+type jasperProcessManagerStreamLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *jasperProcessManagerStreamLogsServer) Send(m *LogStream) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _JasperProcessManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "jasper.JasperProcessManager",
 	HandlerType: (*JasperProcessManagerServer)(nil),
@@ -4661,6 +4716,11 @@ var _JasperProcessManager_serviceDesc = grpc.ServiceDesc{
 			Handler:       _JasperProcessManager_WriteFile_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _JasperProcessManager_StreamLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "jasper.proto",
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/tychoish/jasper/scripting"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -27,6 +28,25 @@ func newGRPCError(code codes.Code, err error) error {
 	return status.Errorf(code, "%v", err)
 }
 
+// StartErrorMetadataKey is the key of the trailer metadata that contains the
+// jasper.StartErrorCode when the service fails to start a process.
+const StartErrorMetadataKey = "jasper-start-error"
+
+// startErrorGRPCCode returns the gRPC status code that corresponds to the
+// reason that a process could not be started.
+func startErrorGRPCCode(code jasper.StartErrorCode) codes.Code {
+	switch code {
+	case jasper.StartErrorNotFound:
+		return codes.NotFound
+	case jasper.StartErrorPermissionDenied:
+		return codes.PermissionDenied
+	case jasper.StartErrorResourceExhausted:
+		return codes.ResourceExhausted
+	default:
+		return codes.Unknown
+	}
+}
+
 // AttachService attaches the given manager to the jasper GRPC server. This
 // function eventually calls generated Protobuf code for registering the
 // GRPC Jasper server with the given Manager.
@@ -83,6 +103,13 @@ func (s *jasperService) Create(ctx context.Context, opts *CreateOptions) (*Proce
 
 	proc, err := s.manager.CreateProcess(pctx, jopts)
 	if err != nil {
+		cancel()
+		if code := jasper.GetStartErrorCode(err); code != jasper.StartErrorUnknown {
+			// The status code still describes the failure if the
+			// trailer cannot be sent.
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(StartErrorMetadataKey, string(code)))
+			return nil, newGRPCError(startErrorGRPCCode(code), err)
+		}
 		return nil, errors.WithStack(err)
 	}
 
@@ -359,6 +386,54 @@ func (s *jasperService) GetLogStream(ctx context.Context, request *LogRequest) (
 	return stream, nil
 }
 
+const (
+	// logStreamPollInterval is how often StreamLogs checks for new output
+	// once it has sent all of the output that the process has written.
+	logStreamPollInterval = 100 * time.Millisecond
+	// logStreamBatchSize is the maximum number of messages of output that
+	// StreamLogs sends at once.
+	logStreamBatchSize = 100
+)
+
+// StreamLogs sends the output of the process from its in-memory logger as it
+// is written until the process completes and all of its output has been sent.
+// An empty message is sent first to confirm that the process exists.
+func (s *jasperService) StreamLogs(id *JasperProcessID, stream JasperProcessManager_StreamLogsServer) error {
+	ctx := stream.Context()
+	proc, err := s.manager.Get(ctx, id.Value)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding process '%s'", id.Value)
+	}
+	if err = stream.Send(&LogStream{}); err != nil {
+		return errors.Wrap(err, "problem sending log stream")
+	}
+
+	for {
+		// Check if the process is complete before reading its output
+		// so that output written before it completed is not missed.
+		complete := proc.Complete(ctx)
+		logs, err := jasper.GetInMemoryLogStream(ctx, proc, logStreamBatchSize)
+		if err == nil {
+			if err = stream.Send(&LogStream{Logs: logs}); err != nil {
+				return errors.Wrap(err, "problem sending logs")
+			}
+			continue
+		}
+		if err != io.EOF {
+			return errors.Wrapf(err, "could not get logs for process '%s'", id.Value)
+		}
+		if complete {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(logStreamPollInterval):
+		}
+	}
+}
+
 func (s *jasperService) RegisterSignalTriggerID(ctx context.Context, params *SignalTriggerParams) (*OperationOutcome, error) {
 	jasperProcessID, signalTriggerID := params.Export()
 
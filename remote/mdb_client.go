@@ -3,6 +3,7 @@ package remote
 import (
 	"context"
 	"net"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
@@ -392,6 +393,42 @@ func (s *mdbScriptingClient) Test(ctx context.Context, dir string, opts ...scrip
 	return resp.Results, errors.Wrap(resp.SuccessOrError(), "error in response")
 }
 
+func (c *mdbClient) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return jasper.SignalGroup(ctx, c, tag, sig)
+}
+
+func (c *mdbClient) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return jasper.WaitForTag(ctx, c, tag)
+}
+
+func (c *mdbClient) SetTagLimit(ctx context.Context, tag string, max int) error {
+	return errors.New("cannot set tag limits on remote process managers")
+}
+
+func (c *mdbClient) Stats(ctx context.Context) jasper.ManagerStats {
+	stats, err := jasper.CollectManagerStats(ctx, c)
+	grip.Debug(message.WrapError(err, "problem collecting manager stats"))
+	return stats
+}
+
+// Subscribe is not supported by the MongoDB wire protocol client, so the returned channel is
+// closed.
+func (c *mdbClient) Subscribe(_ context.Context) <-chan jasper.ProcessEvent {
+	events := make(chan jasper.ProcessEvent)
+	close(events)
+	return events
+}
+
+// Snapshot is not supported by the MongoDB wire protocol client.
+func (c *mdbClient) Snapshot(_ context.Context) ([]byte, error) {
+	return nil, errors.New("snapshots are not supported by the MongoDB wire protocol client")
+}
+
+// PruneLoggers is not supported by the MongoDB wire protocol client.
+func (c *mdbClient) PruneLoggers(_ context.Context, _ time.Duration) (int, error) {
+	return 0, errors.New("pruning loggers is not supported by the MongoDB wire protocol client")
+}
+
 func (c *mdbClient) LoggingCache(ctx context.Context) jasper.LoggingCache {
 	return &mdbLoggingCache{
 		client: c,
@@ -427,6 +464,10 @@ func (c *mdbClient) Register(ctx context.Context, proc jasper.Process) error {
 	return errors.New("cannot register local processes on remote process managers")
 }
 
+func (c *mdbClient) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	return nil, errors.New("cannot register external processes on remote process managers")
+}
+
 func (c *mdbClient) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
 	payload, err := c.makeRequest(listRequest{Filter: f})
 	if err != nil {
@@ -661,6 +702,11 @@ func (c *mdbClient) GetLogStream(ctx context.Context, id string, count int) (jas
 	return resp.LogStream, nil
 }
 
+// StreamLogs is not supported by the MongoDB wire protocol client.
+func (c *mdbClient) StreamLogs(_ context.Context, _ string) (<-chan []byte, error) {
+	return nil, errors.New("streaming logs is not supported by the MongoDB wire protocol client")
+}
+
 func (c *mdbClient) SignalEvent(ctx context.Context, name string) error {
 	payload, err := c.makeRequest(signalEventRequest{Name: name})
 	if err != nil {
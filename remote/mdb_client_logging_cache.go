@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tychoish/birch/mrpc/mongowire"
 	"github.com/tychoish/birch/mrpc/shell"
+	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -78,6 +79,12 @@ func (lc *mdbLoggingCache) Get(id string) *options.CachedLogger {
 	return resp.CachedLogger
 }
 
+func (lc *mdbLoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return jasper.SendToMany(lc, ids, lp, func(_ *options.CachedLogger, lp *options.LoggingPayload) error {
+		return lc.client.SendMessages(lc.ctx, *lp)
+	})
+}
+
 func (lc *mdbLoggingCache) Remove(id string) {
 	payload, err := lc.client.makeRequest(&loggingCacheDeleteRequest{ID: id})
 	if err != nil {
@@ -140,6 +147,11 @@ func (lc *mdbLoggingCache) Clear(ctx context.Context) error {
 	return resp.SuccessOrError()
 }
 
+// Drain is not supported by the MongoDB wire protocol client.
+func (lc *mdbLoggingCache) Drain(_ context.Context) error {
+	return errors.New("draining the logging cache is not supported by the MongoDB wire protocol client")
+}
+
 func (lc *mdbLoggingCache) Prune(lastAccessed time.Time) {
 	payload, err := lc.client.makeRequest(&loggingCachePruneRequest{LastAccessed: lastAccessed})
 	if err != nil {
@@ -2,7 +2,10 @@ package remote
 
 import (
 	"context"
+	"regexp"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/birch"
@@ -19,6 +22,8 @@ type mdbProcess struct {
 	doRequest   func(context.Context, mongowire.Message) (mongowire.Message, error)
 	marshaler   options.Marshaler
 	unmarshaler options.Unmarshaler
+	done        <-chan struct{}
+	doneOnce    sync.Once
 }
 
 func (p *mdbProcess) readRequest(msg mongowire.Message, in interface{}) error {
@@ -91,6 +96,12 @@ func (p *mdbProcess) Info(ctx context.Context) jasper.ProcessInfo {
 	return p.info
 }
 
+// InfoWith returns the same information as Info, since the optional fields
+// are not supported for remote processes.
+func (p *mdbProcess) InfoWith(ctx context.Context, _ jasper.InfoOptions) jasper.ProcessInfo {
+	return p.Info(ctx)
+}
+
 func (p *mdbProcess) Running(ctx context.Context) bool {
 	if p.info.Complete {
 		return false
@@ -205,6 +216,36 @@ func (p *mdbProcess) Wait(ctx context.Context) (int, error) {
 	return resp.ExitCode, errors.Wrap(resp.SuccessOrError(), "error in response")
 }
 
+// Done waits for the process in the background to detect when it completes.
+func (p *mdbProcess) Done() <-chan struct{} {
+	p.doneOnce.Do(func() { p.done = jasper.WaitDone(p) })
+	return p.done
+}
+
+func (p *mdbProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(jasper.ProcessInfo)) error {
+	return jasper.WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *mdbProcess) WaitResult(ctx context.Context) (jasper.WaitResult, error) {
+	return jasper.WaitForResult(ctx, p)
+}
+
+func (p *mdbProcess) Start(_ context.Context) error {
+	return errors.New("cannot start remote processes")
+}
+
+func (p *mdbProcess) ResolvedCommand() ([]string, []string, string) {
+	return nil, nil, ""
+}
+
+func (p *mdbProcess) ChildPIDs(_ context.Context) ([]int, error) {
+	return nil, errors.New("cannot get child PIDs of remote processes")
+}
+
+func (p *mdbProcess) WaitForOutput(ctx context.Context, pattern *regexp.Regexp) error {
+	return errors.New("cannot watch the output of remote processes")
+}
+
 func (p *mdbProcess) Respawn(ctx context.Context) (jasper.Process, error) {
 	payload, err := p.makeRequest(respawnRequest{ID: p.ID()})
 	if err != nil {
@@ -231,6 +272,18 @@ func (p *mdbProcess) RegisterTrigger(ctx context.Context, t jasper.ProcessTrigge
 	return errors.New("cannot register triggers on remote processes")
 }
 
+func (p *mdbProcess) RegisterTriggerWithContext(ctx context.Context, t jasper.ProcessTriggerWithContext) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *mdbProcess) RegisterTriggerWithPhase(ctx context.Context, phase jasper.TriggerPhase, t jasper.ProcessTrigger) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *mdbProcess) RegisterNamedTrigger(ctx context.Context, name string, t jasper.ProcessTrigger) error {
+	return errors.New("cannot register named triggers on remote processes")
+}
+
 func (p *mdbProcess) RegisterSignalTrigger(ctx context.Context, t jasper.SignalTrigger) error {
 	return errors.New("cannot register signal triggers on remote processes")
 }
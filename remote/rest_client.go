@@ -8,8 +8,11 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"regexp"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/gimlet"
@@ -71,12 +74,15 @@ func handleError(resp *http.Response) error {
 		return nil
 	}
 
-	gimerr := gimlet.ErrorResponse{}
-	if err := gimlet.GetJSON(resp.Body, &gimerr); err != nil {
+	resperr := restErrorResponse{}
+	if err := gimlet.GetJSON(resp.Body, &resperr); err != nil {
 		return errors.WithStack(err)
 	}
+	if resperr.StartError != "" {
+		return &jasper.StartError{Code: resperr.StartError, Err: resperr.ErrorResponse}
+	}
 
-	return errors.WithStack(gimerr)
+	return errors.WithStack(resperr.ErrorResponse)
 }
 
 func (c *restClient) doRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Response, error) {
@@ -196,6 +202,10 @@ func (c *restClient) Register(ctx context.Context, proc jasper.Process) error {
 	return errors.New("cannot register a local process on a remote service")
 }
 
+func (c *restClient) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	return nil, errors.New("cannot register external processes on remote process managers")
+}
+
 func (c *restClient) getListOfProcesses(resp *http.Response) ([]jasper.Process, error) {
 	payload := []jasper.ProcessInfo{}
 	if err := gimlet.GetJSON(resp.Body, &payload); err != nil {
@@ -323,6 +333,11 @@ func (c *restClient) GetLogStream(ctx context.Context, id string, count int) (ja
 	return stream, nil
 }
 
+// StreamLogs is not supported by the REST client.
+func (c *restClient) StreamLogs(_ context.Context, _ string) (<-chan []byte, error) {
+	return nil, errors.New("streaming logs is not supported by the REST client")
+}
+
 func (c *restClient) DownloadFile(ctx context.Context, opts options.Download) error {
 	body, err := makeBody(opts)
 	if err != nil {
@@ -383,6 +398,42 @@ func (c *restClient) SendMessages(ctx context.Context, lp options.LoggingPayload
 	return nil
 }
 
+func (c *restClient) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return jasper.SignalGroup(ctx, c, tag, sig)
+}
+
+func (c *restClient) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return jasper.WaitForTag(ctx, c, tag)
+}
+
+func (c *restClient) SetTagLimit(ctx context.Context, tag string, max int) error {
+	return errors.New("cannot set tag limits on remote process managers")
+}
+
+func (c *restClient) Stats(ctx context.Context) jasper.ManagerStats {
+	stats, err := jasper.CollectManagerStats(ctx, c)
+	grip.Debug(message.WrapError(err, "problem collecting manager stats"))
+	return stats
+}
+
+// Subscribe is not supported by the REST client, so the returned channel is
+// closed.
+func (c *restClient) Subscribe(_ context.Context) <-chan jasper.ProcessEvent {
+	events := make(chan jasper.ProcessEvent)
+	close(events)
+	return events
+}
+
+// Snapshot is not supported by the REST client.
+func (c *restClient) Snapshot(_ context.Context) ([]byte, error) {
+	return nil, errors.New("snapshots are not supported by the REST client")
+}
+
+// PruneLoggers is not supported by the REST client.
+func (c *restClient) PruneLoggers(_ context.Context, _ time.Duration) (int, error) {
+	return 0, errors.New("pruning loggers is not supported by the REST client")
+}
+
 func (c *restClient) LoggingCache(ctx context.Context) jasper.LoggingCache {
 	return &restLoggingCache{
 		client: c,
@@ -391,8 +442,10 @@ func (c *restClient) LoggingCache(ctx context.Context) jasper.LoggingCache {
 }
 
 type restProcess struct {
-	id     string
-	client *restClient
+	id       string
+	client   *restClient
+	done     <-chan struct{}
+	doneOnce sync.Once
 }
 
 func (p *restProcess) ID() string { return p.id }
@@ -403,6 +456,12 @@ func (p *restProcess) Info(ctx context.Context) jasper.ProcessInfo {
 	return info
 }
 
+// InfoWith returns the same information as Info, since the optional fields
+// are not supported for remote processes.
+func (p *restProcess) InfoWith(ctx context.Context, _ jasper.InfoOptions) jasper.ProcessInfo {
+	return p.Info(ctx)
+}
+
 func (p *restProcess) Running(ctx context.Context) bool {
 	info, err := p.client.getProcessInfo(ctx, p.id)
 	grip.Debug(message.WrapError(err, message.Fields{"process": p.id}))
@@ -442,6 +501,36 @@ func (p *restProcess) Wait(ctx context.Context) (int, error) {
 	return exitCode, nil
 }
 
+// Done waits for the process in the background to detect when it completes.
+func (p *restProcess) Done() <-chan struct{} {
+	p.doneOnce.Do(func() { p.done = jasper.WaitDone(p) })
+	return p.done
+}
+
+func (p *restProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(jasper.ProcessInfo)) error {
+	return jasper.WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *restProcess) WaitResult(ctx context.Context) (jasper.WaitResult, error) {
+	return jasper.WaitForResult(ctx, p)
+}
+
+func (p *restProcess) Start(_ context.Context) error {
+	return errors.New("cannot start remote processes")
+}
+
+func (p *restProcess) ResolvedCommand() ([]string, []string, string) {
+	return nil, nil, ""
+}
+
+func (p *restProcess) ChildPIDs(_ context.Context) ([]int, error) {
+	return nil, errors.New("cannot get child PIDs of remote processes")
+}
+
+func (p *restProcess) WaitForOutput(_ context.Context, _ *regexp.Regexp) error {
+	return errors.New("cannot watch the output of remote processes")
+}
+
 func (p *restProcess) Respawn(ctx context.Context) (jasper.Process, error) {
 	resp, err := p.client.doRequest(ctx, http.MethodGet, p.client.getURL("/process/%s/respawn", p.id), nil)
 	if err != nil {
@@ -464,6 +553,18 @@ func (p *restProcess) RegisterTrigger(_ context.Context, _ jasper.ProcessTrigger
 	return errors.New("cannot register triggers on remote processes")
 }
 
+func (p *restProcess) RegisterTriggerWithContext(_ context.Context, _ jasper.ProcessTriggerWithContext) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *restProcess) RegisterTriggerWithPhase(_ context.Context, _ jasper.TriggerPhase, _ jasper.ProcessTrigger) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *restProcess) RegisterNamedTrigger(_ context.Context, _ string, _ jasper.ProcessTrigger) error {
+	return errors.New("cannot register named triggers on remote processes")
+}
+
 func (p *restProcess) RegisterSignalTrigger(_ context.Context, _ jasper.SignalTrigger) error {
 	return errors.New("cannot register signal trigger on remote processes")
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/tychoish/gimlet"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/message"
+	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -70,6 +71,12 @@ func (lc *restLoggingCache) Get(id string) *options.CachedLogger {
 	return out
 }
 
+func (lc *restLoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return jasper.SendToMany(lc, ids, lp, func(_ *options.CachedLogger, lp *options.LoggingPayload) error {
+		return lc.client.SendMessages(lc.ctx, *lp)
+	})
+}
+
 func (lc *restLoggingCache) Remove(id string) {
 	resp, err := lc.client.doRequest(lc.ctx, http.MethodDelete, lc.client.getURL("/logging/id/%s", id), nil)
 	grip.Info(message.Fields{
@@ -82,6 +89,11 @@ func (lc *restLoggingCache) Remove(id string) {
 	})
 }
 
+// Drain is not supported by the REST client.
+func (lc *restLoggingCache) Drain(_ context.Context) error {
+	return errors.New("draining the logging cache is not supported by the REST client")
+}
+
 func (lc *restLoggingCache) Prune(ts time.Time) {
 	resp, err := lc.client.doRequest(lc.ctx, http.MethodDelete, lc.client.getURL("/logging/prune/%s", ts.Format(time.RFC3339)), nil)
 	grip.Info(message.Fields{
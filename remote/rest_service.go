@@ -25,6 +25,7 @@ type Service struct {
 	hostID    string
 	manager   jasper.Manager
 	harnesses scripting.HarnessCache
+	auth      AuthFunc
 }
 
 // NewManagerService creates a service object around an existing
@@ -38,12 +39,23 @@ func NewRestService(m jasper.Manager) *Service {
 	}
 }
 
+// SetAuthFunc sets the function used to authenticate every request to the
+// service. Requests that fail authentication are denied with a forbidden
+// status. This must be called before App.
+func (s *Service) SetAuthFunc(auth AuthFunc) {
+	s.auth = auth
+}
+
 // App constructs and returns a gimlet application for this
-// service. It attaches no middleware and does not start the service.
+// service. It does not start the service, and it attaches no
+// middleware other than authentication, if an AuthFunc is set.
 func (s *Service) App(ctx context.Context) *gimlet.APIApp {
 	s.hostID, _ = os.Hostname()
 
 	app := gimlet.NewApp()
+	if s.auth != nil {
+		app.AddMiddlewareFunc(makeRESTAuthMiddleware(s.auth))
+	}
 
 	app.AddRoute("/").Version(1).Get().Handler(s.rootRoute)
 	app.AddRoute("/id").Version(1).Get().Handler(s.id)
@@ -95,6 +107,28 @@ func writeError(rw http.ResponseWriter, err gimlet.ErrorResponse) {
 	gimlet.WriteJSONResponse(rw, err.StatusCode, err)
 }
 
+// restErrorResponse is an error response that describes why a process could
+// not be started.
+type restErrorResponse struct {
+	gimlet.ErrorResponse
+	StartError jasper.StartErrorCode `json:"start_error,omitempty"`
+}
+
+// startErrorHTTPCode returns the HTTP status code that corresponds to the
+// reason that a process could not be started.
+func startErrorHTTPCode(code jasper.StartErrorCode) int {
+	switch code {
+	case jasper.StartErrorNotFound:
+		return http.StatusNotFound
+	case jasper.StartErrorPermissionDenied:
+		return http.StatusForbidden
+	case jasper.StartErrorResourceExhausted:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func (s *Service) rootRoute(rw http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(rw, struct {
 		HostID string `json:"host_id"`
@@ -133,10 +167,16 @@ func (s *Service) createProcess(rw http.ResponseWriter, r *http.Request) {
 	proc, err := s.manager.CreateProcess(pctx, opts)
 	if err != nil {
 		cancel()
-		writeError(rw, gimlet.ErrorResponse{
+		resp := gimlet.ErrorResponse{
 			StatusCode: http.StatusBadRequest,
 			Message:    errors.Wrap(err, "problem submitting request").Error(),
-		})
+		}
+		if code := jasper.GetStartErrorCode(err); code != jasper.StartErrorUnknown {
+			resp.StatusCode = startErrorHTTPCode(code)
+			gimlet.WriteJSONResponse(rw, resp.StatusCode, restErrorResponse{ErrorResponse: resp, StartError: code})
+			return
+		}
+		writeError(rw, resp)
 		return
 	}
 
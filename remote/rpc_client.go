@@ -4,7 +4,10 @@ import (
 	"context"
 	"io"
 	"net"
+	"regexp"
+	"sync"
 	"syscall"
+	"time"
 
 	empty "github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
@@ -17,6 +20,7 @@ import (
 	"github.com/tychoish/jasper/util"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 type rpcClient struct {
@@ -90,8 +94,12 @@ func (c *rpcClient) CreateProcess(ctx context.Context, opts *options.Create) (ja
 	if err != nil {
 		return nil, errors.Wrap(err, "problem converting create options")
 	}
-	proc, err := c.client.Create(ctx, convertedOpts)
+	var trailer metadata.MD
+	proc, err := c.client.Create(ctx, convertedOpts, grpc.Trailer(&trailer))
 	if err != nil {
+		if code := trailer.Get(internal.StartErrorMetadataKey); len(code) != 0 {
+			return nil, &jasper.StartError{Code: jasper.StartErrorCode(code[0]), Err: err}
+		}
 		return nil, errors.WithStack(err)
 	}
 
@@ -131,6 +139,10 @@ func (c *rpcClient) Register(ctx context.Context, proc jasper.Process) error {
 	return errors.New("cannot register local processes on remote process managers")
 }
 
+func (c *rpcClient) RegisterExternal(ctx context.Context, pid int, opts *options.Create) (jasper.Process, error) {
+	return nil, errors.New("cannot register external processes on remote process managers")
+}
+
 func (c *rpcClient) List(ctx context.Context, f options.Filter) ([]jasper.Process, error) {
 	procs, err := c.client.List(ctx, internal.ConvertFilter(f))
 	if err != nil {
@@ -240,6 +252,41 @@ func (c *rpcClient) GetLogStream(ctx context.Context, id string, count int) (jas
 	return stream.Export(), nil
 }
 
+func (c *rpcClient) StreamLogs(ctx context.Context, id string) (<-chan []byte, error) {
+	stream, err := c.client.StreamLogs(ctx, &internal.JasperProcessID{Value: id})
+	if err != nil {
+		return nil, errors.Wrap(err, "problem getting log stream")
+	}
+	// The first message confirms that the process exists.
+	if _, err = stream.Recv(); err != nil {
+		return nil, errors.Wrapf(err, "problem streaming logs for process '%s'", id)
+	}
+
+	logs := make(chan []byte)
+	go func() {
+		defer close(logs)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				grip.WarningWhen(err != io.EOF && ctx.Err() == nil, message.WrapError(err, message.Fields{
+					"message": "log stream ended unexpectedly",
+					"process": id,
+				}))
+				return
+			}
+			for _, log := range resp.Logs {
+				select {
+				case <-ctx.Done():
+					return
+				case logs <- []byte(log):
+				}
+			}
+		}
+	}()
+
+	return logs, nil
+}
+
 func (c *rpcClient) SignalEvent(ctx context.Context, name string) error {
 	resp, err := c.client.SignalEvent(ctx, &internal.EventName{Value: name})
 	if err != nil {
@@ -295,13 +342,51 @@ func (c *rpcClient) SendMessages(ctx context.Context, lp options.LoggingPayload)
 	return nil
 }
 
+func (c *rpcClient) SignalGroup(ctx context.Context, tag string, sig syscall.Signal) error {
+	return jasper.SignalGroup(ctx, c, tag, sig)
+}
+
+func (c *rpcClient) WaitForTag(ctx context.Context, tag string) (map[string]error, error) {
+	return jasper.WaitForTag(ctx, c, tag)
+}
+
+func (c *rpcClient) SetTagLimit(ctx context.Context, tag string, max int) error {
+	return errors.New("cannot set tag limits on remote process managers")
+}
+
+func (c *rpcClient) Stats(ctx context.Context) jasper.ManagerStats {
+	stats, err := jasper.CollectManagerStats(ctx, c)
+	grip.Debug(message.WrapError(err, "problem collecting manager stats"))
+	return stats
+}
+
+// Subscribe is not supported by the RPC client, so the returned channel is
+// closed.
+func (c *rpcClient) Subscribe(_ context.Context) <-chan jasper.ProcessEvent {
+	events := make(chan jasper.ProcessEvent)
+	close(events)
+	return events
+}
+
+// Snapshot is not supported by the RPC client.
+func (c *rpcClient) Snapshot(_ context.Context) ([]byte, error) {
+	return nil, errors.New("snapshots are not supported by the RPC client")
+}
+
+// PruneLoggers is not supported by the RPC client.
+func (c *rpcClient) PruneLoggers(_ context.Context, _ time.Duration) (int, error) {
+	return 0, errors.New("pruning loggers is not supported by the RPC client")
+}
+
 func (c *rpcClient) LoggingCache(ctx context.Context) jasper.LoggingCache {
 	return &rpcLoggingCache{ctx: ctx, client: c.client}
 }
 
 type rpcProcess struct {
-	client internal.JasperProcessManagerClient
-	info   *internal.ProcessInfo
+	client   internal.JasperProcessManagerClient
+	info     *internal.ProcessInfo
+	done     <-chan struct{}
+	doneOnce sync.Once
 }
 
 func (p *rpcProcess) ID() string { return p.info.Id }
@@ -330,6 +415,13 @@ func (p *rpcProcess) Info(ctx context.Context) jasper.ProcessInfo {
 
 	return exportedInfo
 }
+
+// InfoWith returns the same information as Info, since the optional fields
+// are not supported for remote processes.
+func (p *rpcProcess) InfoWith(ctx context.Context, _ jasper.InfoOptions) jasper.ProcessInfo {
+	return p.Info(ctx)
+}
+
 func (p *rpcProcess) Running(ctx context.Context) bool {
 	if p.info.Complete {
 		return false
@@ -388,6 +480,36 @@ func (p *rpcProcess) Wait(ctx context.Context) (int, error) {
 	return int(resp.ExitCode), nil
 }
 
+// Done waits for the process in the background to detect when it completes.
+func (p *rpcProcess) Done() <-chan struct{} {
+	p.doneOnce.Do(func() { p.done = jasper.WaitDone(p) })
+	return p.done
+}
+
+func (p *rpcProcess) WaitWithProgress(ctx context.Context, interval time.Duration, cb func(jasper.ProcessInfo)) error {
+	return jasper.WaitWithProgress(ctx, p, interval, cb)
+}
+
+func (p *rpcProcess) WaitResult(ctx context.Context) (jasper.WaitResult, error) {
+	return jasper.WaitForResult(ctx, p)
+}
+
+func (p *rpcProcess) Start(_ context.Context) error {
+	return errors.New("cannot start remote processes")
+}
+
+func (p *rpcProcess) ResolvedCommand() ([]string, []string, string) {
+	return nil, nil, ""
+}
+
+func (p *rpcProcess) ChildPIDs(_ context.Context) ([]int, error) {
+	return nil, errors.New("cannot get child PIDs of remote processes")
+}
+
+func (p *rpcProcess) WaitForOutput(ctx context.Context, _ *regexp.Regexp) error {
+	return errors.New("cannot watch the output of remote processes")
+}
+
 func (p *rpcProcess) Respawn(ctx context.Context) (jasper.Process, error) {
 	newProc, err := p.client.Respawn(ctx, &internal.JasperProcessID{Value: p.info.Id})
 	if err != nil {
@@ -401,6 +523,18 @@ func (p *rpcProcess) RegisterTrigger(ctx context.Context, _ jasper.ProcessTrigge
 	return errors.New("cannot register triggers on remote processes")
 }
 
+func (p *rpcProcess) RegisterTriggerWithContext(ctx context.Context, _ jasper.ProcessTriggerWithContext) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *rpcProcess) RegisterTriggerWithPhase(ctx context.Context, _ jasper.TriggerPhase, _ jasper.ProcessTrigger) error {
+	return errors.New("cannot register triggers on remote processes")
+}
+
+func (p *rpcProcess) RegisterNamedTrigger(ctx context.Context, _ string, _ jasper.ProcessTrigger) error {
+	return errors.New("cannot register named triggers on remote processes")
+}
+
 func (p *rpcProcess) RegisterSignalTrigger(ctx context.Context, _ jasper.SignalTrigger) error {
 	return errors.New("cannot register signal triggers on remote processes")
 }
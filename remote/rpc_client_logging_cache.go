@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/message"
+	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
 	internal "github.com/tychoish/jasper/remote/internal"
 )
@@ -59,6 +60,19 @@ func (lc *rpcLoggingCache) Get(id string) *options.CachedLogger {
 	return out
 }
 
+func (lc *rpcLoggingCache) SendToMany(ids []string, lp *options.LoggingPayload) error {
+	return jasper.SendToMany(lc, ids, lp, func(_ *options.CachedLogger, lp *options.LoggingPayload) error {
+		resp, err := lc.client.SendMessages(lc.ctx, internal.ConvertLoggingPayload(*lp))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !resp.Success {
+			return errors.New(resp.Text)
+		}
+		return nil
+	})
+}
+
 func (lc *rpcLoggingCache) Remove(id string) {
 	_, _ = lc.client.LoggingCacheRemove(lc.ctx, &internal.LoggingCacheArgs{Name: id})
 }
@@ -87,6 +101,11 @@ func (lc *rpcLoggingCache) Clear(ctx context.Context) error {
 	return nil
 }
 
+// Drain is not supported by the RPC client.
+func (lc *rpcLoggingCache) Drain(_ context.Context) error {
+	return errors.New("draining the logging cache is not supported by the RPC client")
+}
+
 func (lc *rpcLoggingCache) Prune(ts time.Time) {
 	pbts, err := ptypes.TimestampProto(ts)
 	if err != nil {
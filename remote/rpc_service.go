@@ -32,6 +32,8 @@ func AttachService(ctx context.Context, manager jasper.Manager, s *grpc.Server)
 // logging configured, and panics are not handled. Passing
 // interceptors from the aviation package or grpc-middleware as
 // gprc.ServerOptions to this function can handle that.
+// Authentication can be required by passing the options from
+// RPCAuthServerOptions.
 func StartRPCService(ctx context.Context, manager jasper.Manager, addr net.Addr, creds *options.CertificateCredentials, opts ...grpc.ServerOption) (util.CloseFunc, error) {
 	lis, err := net.Listen(addr.Network(), addr.String())
 	if err != nil {
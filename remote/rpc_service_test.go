@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tychoish/jasper"
+	"github.com/tychoish/jasper/options"
 	"github.com/tychoish/jasper/remote/internal"
 	"github.com/tychoish/jasper/testutil"
 	"google.golang.org/grpc"
@@ -52,3 +55,54 @@ func TestRPCService(t *testing.T) {
 		})
 	}
 }
+
+func TestRPCClientStreamLogs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	for testName, testCase := range map[string]func(context.Context, *testing.T, Manager){
+		"StreamsOutputUntilProcessCompletes": func(ctx context.Context, t *testing.T, client Manager) {
+			logger, err := jasper.NewInMemoryLogger(100)
+			require.NoError(t, err)
+			proc, err := client.CreateProcess(ctx, &options.Create{
+				Args: []string{"sh", "-c", "for i in 1 2 3 4 5; do echo line$i; sleep 0.1; done"},
+				Output: options.Output{
+					Loggers: []*options.LoggerConfig{logger},
+				},
+			})
+			require.NoError(t, err)
+
+			logs, err := client.StreamLogs(ctx, proc.ID())
+			require.NoError(t, err)
+
+			var output []string
+			for log := range logs {
+				output = append(output, strings.TrimSpace(string(log)))
+			}
+			require.NoError(t, ctx.Err(), "stream should end once the process completes")
+			assert.True(t, proc.Complete(ctx))
+			assert.Equal(t, "line1\nline2\nline3\nline4\nline5", strings.Join(output, "\n"))
+		},
+		"FailsForNonexistentProcess": func(ctx context.Context, t *testing.T, client Manager) {
+			logs, err := client.StreamLogs(ctx, "foo")
+			assert.Error(t, err)
+			assert.Nil(t, logs)
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			manager, err := jasper.NewSynchronizedManager(false)
+			require.NoError(t, err)
+			client, err := makeInsecureRPCServiceAndClient(ctx, manager)
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, client.CloseConnection())
+			}()
+
+			testCase(ctx, t, client)
+		})
+	}
+}
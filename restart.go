@@ -0,0 +1,147 @@
+package jasper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/jasper/options"
+)
+
+// restartHistory tracks the restarts of a process that should always be
+// restarted in order to prevent it from restarting too frequently.
+type restartHistory struct {
+	limit    int
+	interval time.Duration
+	restarts []time.Time
+
+	// backoff, if set, determines the delay before each restart, which
+	// increases with the number of consecutive restarts of processes that
+	// ran for less than resetAfter.
+	backoff    options.BackoffStrategy
+	resetAfter time.Duration
+	attempt    int
+	backoffMu  sync.Mutex
+}
+
+func newRestartHistory(opts *options.Create) *restartHistory {
+	h := &restartHistory{
+		limit:    opts.RestartLimit,
+		interval: opts.RestartLimitInterval,
+	}
+	if h.limit == 0 {
+		h.limit = options.DefaultRestartLimit
+	}
+	if h.interval == 0 {
+		h.interval = options.DefaultRestartLimitInterval
+	}
+	if opts.RestartBackoff != nil {
+		h.backoff = opts.RestartBackoff.Resolve()
+		h.resetAfter = opts.RestartBackoff.GetResetAfter()
+	}
+	return h
+}
+
+// nextDelay returns the backoff delay before restarting a process that ran
+// for the given duration, resetting the backoff if the process ran for long
+// enough. It returns zero if there is no backoff.
+func (h *restartHistory) nextDelay(ran time.Duration) time.Duration {
+	if h.backoff == nil {
+		return 0
+	}
+
+	h.backoffMu.Lock()
+	defer h.backoffMu.Unlock()
+
+	if ran >= h.resetAfter {
+		h.attempt = 0
+	}
+	delay := h.backoff.Delay(h.attempt)
+	h.attempt++
+	return delay
+}
+
+// withBackoff returns a restart function that waits for the backoff delay
+// before restarting the process using the restart function.
+func (h *restartHistory) withBackoff(proc Process, restart func(context.Context, *options.Create) (Process, error)) func(context.Context, *options.Create) (Process, error) {
+	return func(ctx context.Context, opts *options.Create) (Process, error) {
+		info := proc.Info(ctx)
+		if err := sleepContext(ctx, h.nextDelay(info.EndAt.Sub(info.StartAt))); err != nil {
+			return nil, errors.Wrap(err, "stopped waiting to restart process")
+		}
+		return restart(ctx, opts)
+	}
+}
+
+// sleepContext waits for the given duration, returning early with an error
+// if the context is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// record records a restart at the given time if it would not exceed the
+// restart limit. It returns whether or not the restart is allowed.
+func (h *restartHistory) record(now time.Time) bool {
+	recent := h.restarts[:0]
+	for _, restart := range h.restarts {
+		if now.Sub(restart) < h.interval {
+			recent = append(recent, restart)
+		}
+	}
+	h.restarts = recent
+
+	if len(h.restarts) >= h.limit {
+		return false
+	}
+
+	h.restarts = append(h.restarts, now)
+	return true
+}
+
+// makeRestartTrigger returns a trigger that restarts the process after the
+// restart delay or backoff, regardless of how the process exited, using the
+// restart function.
+func makeRestartTrigger(ctx context.Context, opts *options.Create, parentID string, restarts *restartHistory, restart func(context.Context, *options.Create) (Process, error)) ProcessTrigger {
+	return func(info ProcessInfo) {
+		delay := opts.RestartDelay
+		if opts.RestartBackoff != nil {
+			delay = restarts.nextDelay(info.EndAt.Sub(info.StartAt))
+		}
+
+		// Triggers run while the process is finishing, so the restart
+		// must not block the trigger.
+		go func() {
+			if sleepContext(ctx, delay) != nil {
+				return
+			}
+
+			// The restarted process replaces one that has already
+			// run, so it should start immediately.
+			restartOpts := opts.Copy()
+			restartOpts.DeferStart = false
+			proc, err := restart(ctx, restartOpts)
+			if err != nil {
+				grip.Warning(message.WrapError(err, message.Fields{
+					"trigger": "restart-always",
+					"parent":  parentID,
+				}))
+				return
+			}
+			grip.Info(message.Fields{
+				"message": "restarted process",
+				"parent":  parentID,
+				"process": proc.ID(),
+			})
+		}()
+	}
+}
@@ -0,0 +1,151 @@
+package jasper
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/tychoish/grip"
+	"github.com/tychoish/grip/message"
+	"github.com/tychoish/jasper/options"
+)
+
+const (
+	// watchPollInterval is how often the paths watched by a process are
+	// checked for changes. Paths are polled rather than watched with
+	// filesystem notifications, which would require a platform-specific
+	// dependency, and polling also detects paths that are created after
+	// the watch begins without watching their parent directories.
+	watchPollInterval = 100 * time.Millisecond
+	// watchTerminateTimeout is how long to wait for a process to exit after
+	// terminating it for a restart before killing it.
+	watchTerminateTimeout = 5 * time.Second
+)
+
+// pathState is the state of a watched path that is compared to detect
+// changes.
+type pathState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statPaths(paths []string) map[string]pathState {
+	states := make(map[string]pathState, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			states[path] = pathState{}
+			continue
+		}
+		states[path] = pathState{
+			exists:  true,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+	return states
+}
+
+func pathStatesEqual(a, b map[string]pathState) bool {
+	for path, state := range a {
+		other := b[path]
+		if state.exists != other.exists || state.size != other.size || !state.modTime.Equal(other.modTime) {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// watchForRestart polls the paths that the options watch and, once they have
+// changed from the given states and then not changed again for the debounce
+// interval, terminates the process and replaces it using the restart
+// function. It returns once the process has been restarted, the process
+// completes without being restarted, or the context is done.
+func watchForRestart(ctx context.Context, proc Process, opts *options.Create, states map[string]pathState, restart func(*options.Create) (Process, error)) {
+	debounce := opts.WatchDebounce
+	if debounce == 0 {
+		debounce = options.DefaultWatchDebounce
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-proc.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := statPaths(opts.WatchPaths)
+		if !pathStatesEqual(states, current) {
+			states = current
+			changedAt = time.Now()
+			continue
+		}
+		if changedAt.IsZero() || time.Since(changedAt) < debounce {
+			continue
+		}
+		if proc.Info(ctx).NotStarted {
+			// A process that has not been started yet will use the
+			// changed files once it starts.
+			changedAt = time.Time{}
+			continue
+		}
+
+		replaceProcess(ctx, proc, opts, restart, "watched paths changed")
+		return
+	}
+}
+
+// replaceProcess stops the process and replaces it with a new process created
+// by the restart function, logging the reason for the restart.
+func replaceProcess(ctx context.Context, proc Process, opts *options.Create, restart func(*options.Create) (Process, error), reason string) {
+	stopForRestart(ctx, proc)
+
+	// The restarted process replaces one that has already run, so it
+	// should start immediately.
+	restartOpts := opts.Copy()
+	restartOpts.DeferStart = false
+	newProc, err := restart(restartOpts)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "could not restart process",
+			"reason":  reason,
+			"parent":  proc.ID(),
+		}))
+		return
+	}
+	grip.Info(message.Fields{
+		"message": "restarted process",
+		"reason":  reason,
+		"parent":  proc.ID(),
+		"process": newProc.ID(),
+	})
+}
+
+// stopForRestart terminates the process and waits for it to exit, killing it
+// if it does not exit in time.
+func stopForRestart(ctx context.Context, proc Process) {
+	if !proc.Running(ctx) {
+		return
+	}
+
+	termCtx, cancel := context.WithTimeout(ctx, watchTerminateTimeout)
+	defer cancel()
+	if err := Terminate(termCtx, proc); err == nil {
+		_, _ = proc.Wait(termCtx)
+	}
+
+	if proc.Running(ctx) {
+		grip.Warning(message.WrapError(Kill(ctx, proc), message.Fields{
+			"message": "could not kill process for restart",
+			"process": proc.ID(),
+		}))
+		_, _ = proc.Wait(ctx)
+	}
+}
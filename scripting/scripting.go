@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tychoish/jasper"
 	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/util"
 )
 
 // Harness provides an interface to execute code in a
@@ -64,6 +65,39 @@ func NewHarness(m jasper.Manager, env options.ScriptingHarness) (Harness, error)
 	}
 }
 
+// RunScriptOutput is the same as the harness's RunScript, but also returns
+// the combined output and error of the script, which is returned even if the
+// script fails. Output that the harness's output options send to their own
+// writers is not captured. Only harnesses that run scripts with a local
+// manager (i.e. those created by NewHarness) are supported; harnesses that
+// proxy to a remote service return an error.
+func RunScriptOutput(ctx context.Context, h Harness, script string) ([]byte, error) {
+	runner, ok := h.(scriptOutputRunner)
+	if !ok {
+		return nil, errors.Errorf("capturing script output is not supported by harness %T", h)
+	}
+
+	return runner.runScriptOutput(ctx, script)
+}
+
+// scriptOutputRunner is implemented by harnesses that can capture the output
+// of the scripts that they run.
+type scriptOutputRunner interface {
+	runScriptOutput(ctx context.Context, script string) ([]byte, error)
+}
+
+// runScriptWithOutput runs the script with the harness that withManager
+// returns for a manager that captures the combined output and error of every
+// process that it creates, and returns the captured output.
+func runScriptWithOutput(ctx context.Context, m jasper.Manager, withManager func(jasper.Manager) Harness, script string) ([]byte, error) {
+	output := &util.LocalBuffer{}
+	capturing := jasper.NewCapturingManager(m, options.Output{Output: output, Error: output})
+
+	err := withManager(capturing).RunScript(ctx, script)
+
+	return []byte(output.String()), err
+}
+
 // HarnessCache provides an internal local cache for scripting
 // environments.
 type HarnessCache interface {
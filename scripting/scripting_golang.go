@@ -118,6 +118,14 @@ func (e *golangEnvironment) RunScript(ctx context.Context, script string) error
 		SetOutputOptions(e.opts.Output).AppendArgs(e.opts.Interpreter(), "run", wo.Path).Run(ctx)
 }
 
+func (e *golangEnvironment) runScriptOutput(ctx context.Context, script string) ([]byte, error) {
+	return runScriptWithOutput(ctx, e.manager, func(m jasper.Manager) Harness {
+		env := *e
+		env.manager = m
+		return &env
+	}, script)
+}
+
 func (e *golangEnvironment) Cleanup(ctx context.Context) error {
 	return errors.Wrapf(e.manager.CreateCommand(ctx).SetOutputOptions(e.opts.Output).Sudo(true).AppendArgs("rm", "-rf", e.opts.Gopath).Run(ctx),
 		"problem removing golang environment '%s'", e.opts.Gopath)
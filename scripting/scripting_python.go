@@ -89,6 +89,14 @@ func (e *pythonEnvironment) RunScript(ctx context.Context, script string) error
 	return e.manager.CreateCommand(ctx).Environment(e.opts.Environment).SetOutputOptions(e.opts.Output).AppendArgs(e.opts.Interpreter(), wo.Path).Run(ctx)
 }
 
+func (e *pythonEnvironment) runScriptOutput(ctx context.Context, script string) ([]byte, error) {
+	return runScriptWithOutput(ctx, e.manager, func(m jasper.Manager) Harness {
+		env := *e
+		env.manager = m
+		return &env
+	}, script)
+}
+
 func (e *pythonEnvironment) Build(ctx context.Context, dir string, args []string) (string, error) {
 	output := &util.LocalBuffer{}
 
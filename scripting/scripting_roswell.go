@@ -73,6 +73,14 @@ func (e *roswellEnvironment) RunScript(ctx context.Context, script string) error
 		SetOutputOptions(e.opts.Output).AppendArgs(e.opts.Interpreter(), wo.Path).Run(ctx)
 }
 
+func (e *roswellEnvironment) runScriptOutput(ctx context.Context, script string) ([]byte, error) {
+	return runScriptWithOutput(ctx, e.manager, func(m jasper.Manager) Harness {
+		env := *e
+		env.manager = m
+		return &env
+	}, script)
+}
+
 func (e *roswellEnvironment) Build(ctx context.Context, dir string, args []string) (string, error) {
 	err := e.manager.CreateCommand(ctx).Directory(dir).Environment(e.opts.Environment).AddEnv("ROSWELL_HOME", e.opts.Path).
 		SetOutputOptions(e.opts.Output).Add(append([]string{e.opts.Interpreter(), "dump", "executable"}, args...)).Run(ctx)
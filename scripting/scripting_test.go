@@ -95,6 +95,16 @@ func TestScriptingHarness(t *testing.T) {
 						require.Error(t, se.RunScript(ctx, `(sb-ext:exit :code 42)`))
 					},
 				},
+				{
+					Name: "ScriptOutputWithExitError",
+					Case: func(t *testing.T, opts options.ScriptingHarness) {
+						se := makeScriptingEnv(ctx, t, manager, opts)
+						output, err := RunScriptOutput(ctx, se, `(progn (format t "hello~%") (format *error-output* "oops~%") (sb-ext:exit :code 42))`)
+						require.Error(t, err)
+						assert.Contains(t, string(output), "hello")
+						assert.Contains(t, string(output), "oops")
+					},
+				},
 			},
 		},
 		{
@@ -135,6 +145,16 @@ func TestScriptingHarness(t *testing.T) {
 						require.Error(t, se.RunScript(ctx, `exit(42)`))
 					},
 				},
+				{
+					Name: "ScriptOutputWithExitError",
+					Case: func(t *testing.T, opts options.ScriptingHarness) {
+						se := makeScriptingEnv(ctx, t, manager, opts)
+						output, err := RunScriptOutput(ctx, se, `import sys; print("hello"); sys.stderr.write("oops\n"); sys.exit(42)`)
+						require.Error(t, err)
+						assert.Contains(t, string(output), "hello")
+						assert.Contains(t, string(output), "oops")
+					},
+				},
 			},
 		},
 		{
@@ -176,6 +196,16 @@ func TestScriptingHarness(t *testing.T) {
 						require.Error(t, se.RunScript(ctx, `exit(42)`))
 					},
 				},
+				{
+					Name: "ScriptOutputWithExitError",
+					Case: func(t *testing.T, opts options.ScriptingHarness) {
+						se := makeScriptingEnv(ctx, t, manager, opts)
+						output, err := RunScriptOutput(ctx, se, `import sys; print("hello"); sys.stderr.write("oops\n"); sys.exit(42)`)
+						require.Error(t, err)
+						assert.Contains(t, string(output), "hello")
+						assert.Contains(t, string(output), "oops")
+					},
+				},
 			},
 		},
 		{
@@ -211,6 +241,16 @@ func TestScriptingHarness(t *testing.T) {
 						require.Error(t, se.RunScript(ctx, `package main; import "os"; func main() { os.Exit(42) }`))
 					},
 				},
+				{
+					Name: "ScriptOutputWithExitError",
+					Case: func(t *testing.T, opts options.ScriptingHarness) {
+						se := makeScriptingEnv(ctx, t, manager, opts)
+						output, err := RunScriptOutput(ctx, se, `package main; import ("fmt"; "os"); func main() { fmt.Println("hello"); fmt.Fprintln(os.Stderr, "oops"); os.Exit(42) }`)
+						require.Error(t, err)
+						assert.Contains(t, string(output), "hello")
+						assert.Contains(t, string(output), "oops")
+					},
+				},
 				{
 					Name: "Dependencies",
 					Case: func(t *testing.T, opts options.ScriptingHarness) {
@@ -308,3 +348,11 @@ func TestScriptingHarness(t *testing.T) {
 		})
 	}
 }
+
+type remoteHarness struct{ Harness }
+
+func TestRunScriptOutputRequiresLocalHarness(t *testing.T) {
+	output, err := RunScriptOutput(context.Background(), remoteHarness{}, "true")
+	assert.Error(t, err)
+	assert.Nil(t, output)
+}
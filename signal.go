@@ -2,6 +2,7 @@ package jasper
 
 import (
 	"context"
+	"regexp"
 	"syscall"
 
 	"github.com/pkg/errors"
@@ -22,6 +23,52 @@ func Kill(ctx context.Context, p Process) error {
 	return errors.WithStack(p.Signal(ctx, syscall.SIGKILL))
 }
 
+// Reload sends a SIGHUP signal to the given process under the given context,
+// which conventionally asks it to reload its configuration. If ack is
+// non-nil, Reload then blocks until the process acknowledges the reload by
+// writing a line that matches ack to standard output or error. Only output
+// written after the signal is sent is matched, so the process must be
+// created with (options.Output).WatchLines set. It returns
+// ErrOutputNotMatched if the process exits before acknowledging the reload.
+func Reload(ctx context.Context, p Process, ack *regexp.Regexp) error {
+	if ack == nil {
+		return errors.WithStack(p.Signal(ctx, syscall.SIGHUP))
+	}
+
+	output := p.Info(ctx).Options.Output
+	acked, stop, err := output.WatchNewOutput(ack)
+	if err != nil {
+		return errors.Wrap(err, "problem watching process output for reload acknowledgement")
+	}
+	defer stop()
+
+	if err = p.Signal(ctx, syscall.SIGHUP); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return awaitOutputMatch(ctx, acked)
+}
+
+// SignalGroup sends the signal to each running process in the manager that
+// has the given tag, using the manager's Group method. It attempts to signal
+// every process and returns an aggregate of the errors from signaling each
+// process. This function does not Wait() on the processes.
+func SignalGroup(ctx context.Context, m Manager, tag string, sig syscall.Signal) error {
+	procs, err := m.Group(ctx, tag)
+	if err != nil {
+		return errors.Wrapf(err, "problem getting processes with tag '%s'", tag)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, proc := range procs {
+		if proc.Running(ctx) {
+			catcher.Wrapf(proc.Signal(ctx, sig), "problem signaling process '%s'", proc.ID())
+		}
+	}
+
+	return catcher.Resolve()
+}
+
 // TerminateAll sends a SIGTERM signal to each of the given processes under the
 // given context. This does not guarantee that each process will actually die.
 // This function calls Wait() on each process after sending them SIGTERM
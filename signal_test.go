@@ -0,0 +1,137 @@
+package jasper
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestReload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	const script = `n=0; trap 'n=$((n+1)); echo "reloaded $n"' HUP; echo ready; while true; do sleep 0.1; done`
+	reloaded := regexp.MustCompile(`^reloaded \d+$`)
+
+	for procType, impl := range map[string]string{
+		"Basic":    options.ProcessImplementationBasic,
+		"Blocking": options.ProcessImplementationBlocking,
+	} {
+		t.Run(procType, func(t *testing.T) {
+			makeProc := func(t *testing.T, args []string, watch bool) Process {
+				proc, err := NewProcess(ctx, &options.Create{
+					Args:           args,
+					Implementation: impl,
+					Output:         options.Output{WatchLines: watch},
+				})
+				require.NoError(t, err)
+				return proc
+			}
+			stop := func(t *testing.T, proc Process) {
+				if proc.Running(ctx) {
+					require.NoError(t, Kill(ctx, proc))
+				}
+				_, _ = proc.Wait(ctx)
+			}
+
+			t.Run("WaitsForAcknowledgement", func(t *testing.T) {
+				proc := makeProc(t, []string{"sh", "-c", script}, true)
+				defer stop(t, proc)
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`^ready$`)))
+
+				require.NoError(t, Reload(ctx, proc, reloaded))
+				assert.Equal(t, 2, proc.Info(ctx).OutputLines)
+				assert.True(t, proc.Running(ctx))
+			})
+			t.Run("IgnoresPreviousAcknowledgements", func(t *testing.T) {
+				proc := makeProc(t, []string{"sh", "-c", script}, true)
+				defer stop(t, proc)
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`^ready$`)))
+
+				require.NoError(t, Reload(ctx, proc, reloaded))
+				require.NoError(t, Reload(ctx, proc, reloaded))
+				assert.Equal(t, 3, proc.Info(ctx).OutputLines)
+			})
+			t.Run("WithoutAcknowledgementOnlySignals", func(t *testing.T) {
+				proc := makeProc(t, []string{"sh", "-c", script}, true)
+				defer stop(t, proc)
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`^ready$`)))
+
+				require.NoError(t, Reload(ctx, proc, nil))
+				require.NoError(t, proc.WaitForOutput(ctx, regexp.MustCompile(`^reloaded 1$`)))
+			})
+			t.Run("ErrorsIfProcessExitsWithoutAcknowledging", func(t *testing.T) {
+				// Without a handler, SIGHUP terminates the process.
+				proc := makeProc(t, []string{"sleep", "5"}, true)
+				defer stop(t, proc)
+
+				assert.Equal(t, ErrOutputNotMatched, Reload(ctx, proc, reloaded))
+				_, err := proc.Wait(ctx)
+				assert.Error(t, err)
+				assert.Equal(t, int(syscall.SIGHUP), proc.Info(ctx).ExitCode)
+			})
+			t.Run("ErrorsWithoutWatchLines", func(t *testing.T) {
+				proc := makeProc(t, []string{"sleep", "5"}, false)
+				defer stop(t, proc)
+
+				assert.Error(t, Reload(ctx, proc, reloaded))
+				assert.True(t, proc.Running(ctx))
+			})
+		})
+	}
+}
+
+func TestSignalGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on Windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.ProcessTestTimeout)
+	defer cancel()
+
+	manager, err := newBasicProcessManager(map[string]Process{}, false, false)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, manager.Close(ctx))
+	}()
+
+	completedOpts := testutil.TrueCreateOpts()
+	completedOpts.Tags = []string{"group"}
+	completed, err := manager.CreateProcess(ctx, completedOpts)
+	require.NoError(t, err)
+	_, err = completed.Wait(ctx)
+	require.NoError(t, err)
+
+	sleepOpts := testutil.SleepCreateOpts(100)
+	sleepOpts.Tags = []string{"group"}
+	procs, err := createProcs(ctx, sleepOpts, manager, 3)
+	require.NoError(t, err)
+
+	t.Run("SkipsCompletedProcesses", func(t *testing.T) {
+		require.NoError(t, SignalGroup(ctx, manager, "group", syscall.SIGKILL))
+		for _, proc := range procs {
+			_, err = proc.Wait(ctx)
+			assert.Error(t, err)
+			sigs := proc.Info(ctx).SignalHistory
+			require.Len(t, sigs, 1)
+			assert.Equal(t, syscall.SIGKILL, sigs[0].Signal)
+		}
+		assert.Empty(t, completed.Info(ctx).SignalHistory)
+	})
+	t.Run("FailsWithCanceledContext", func(t *testing.T) {
+		cctx, ccancel := context.WithCancel(ctx)
+		ccancel()
+		assert.Error(t, SignalGroup(cctx, manager, "group", syscall.SIGKILL))
+	})
+}
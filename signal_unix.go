@@ -2,8 +2,21 @@
 
 package jasper
 
-import "syscall"
+import (
+	"os"
+	"syscall"
+)
 
 func makeCompatible(sig syscall.Signal) syscall.Signal {
 	return sig
 }
+
+// processIsAlive reports whether a process with the given PID exists, which
+// is determined by sending it signal 0.
+func processIsAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -1,6 +1,9 @@
 package jasper
 
-import "syscall"
+import (
+	"os"
+	"syscall"
+)
 
 func makeCompatible(sig syscall.Signal) syscall.Signal {
 	switch sig {
@@ -12,3 +15,15 @@ func makeCompatible(sig syscall.Signal) syscall.Signal {
 		return sig
 	}
 }
+
+// processIsAlive reports whether a process with the given PID exists. Windows
+// does not support signal 0, so this is determined by whether the process can
+// be opened.
+func processIsAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = proc.Release()
+	return true
+}
@@ -0,0 +1,87 @@
+package jasper
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// managerSnapshot is the serialized state of a manager, which is sufficient
+// to reattach to its processes after a restart.
+type managerSnapshot struct {
+	ID        string            `json:"id"`
+	Processes []processSnapshot `json:"processes"`
+}
+
+type processSnapshot struct {
+	Info ProcessInfo `json:"info"`
+	Tags []string    `json:"tags,omitempty"`
+}
+
+// snapshotProcesses serializes the state of the manager's processes. Remote
+// and Docker processes are omitted, since they cannot be reattached to by
+// PID.
+func snapshotProcesses(ctx context.Context, id string, procs []Process) ([]byte, error) {
+	snapshot := managerSnapshot{ID: id, Processes: []processSnapshot{}}
+	for _, proc := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		info := proc.Info(ctx)
+		if info.Options.Remote != nil || info.Options.Docker != nil {
+			continue
+		}
+
+		snapshot.Processes = append(snapshot.Processes, processSnapshot{
+			Info: info,
+			Tags: proc.GetTags(),
+		})
+	}
+	sort.Slice(snapshot.Processes, func(i, j int) bool {
+		return snapshot.Processes[i].Info.StartAt.Before(snapshot.Processes[j].Info.StartAt)
+	})
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem serializing manager snapshot")
+	}
+
+	return data, nil
+}
+
+// RestoreManager returns a new thread-safe manager with the ID and processes
+// of the manager that produced the snapshot data using Manager.Snapshot.
+// Processes that are still running are reattached to and watched until they
+// exit, while processes that are no longer running are marked complete.
+// Since restored processes are not children of the current process, their
+// exit codes cannot be determined, so they report an exit code of -1 and
+// Wait returns an error once they exit. The manager stops watching the
+// restored processes when the context is done.
+func RestoreManager(ctx context.Context, data []byte) (Manager, error) {
+	snapshot := managerSnapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, errors.Wrap(err, "problem reading manager snapshot")
+	}
+	if snapshot.ID == "" {
+		return nil, errors.New("manager snapshot must have an ID")
+	}
+
+	procs := make(map[string]Process, len(snapshot.Processes))
+	for _, procSnapshot := range snapshot.Processes {
+		if procSnapshot.Info.ID == "" {
+			return nil, errors.New("process in manager snapshot must have an ID")
+		}
+		procs[procSnapshot.Info.ID] = newRestoredProcess(ctx, procSnapshot.Info, procSnapshot.Tags)
+	}
+
+	basicManager, err := newBasicProcessManager(procs, false, false)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	basicManager.(*basicProcessManager).id = snapshot.ID
+
+	return newSynchronizedProcessManager(basicManager), nil
+}
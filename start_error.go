@@ -0,0 +1,72 @@
+package jasper
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// StartErrorCode classifies the reason that a process could not be
+// started.
+type StartErrorCode string
+
+const (
+	// StartErrorNotFound indicates that the executable does not exist.
+	StartErrorNotFound StartErrorCode = "not_found"
+	// StartErrorPermissionDenied indicates that the executable could not
+	// be run due to insufficient permissions.
+	StartErrorPermissionDenied StartErrorCode = "permission_denied"
+	// StartErrorResourceExhausted indicates that the system did not have
+	// the resources, such as memory, processes, or file descriptors, to
+	// start the process.
+	StartErrorResourceExhausted StartErrorCode = "resource_exhausted"
+	// StartErrorUnknown indicates that the reason that the process could
+	// not be started is not known.
+	StartErrorUnknown StartErrorCode = "unknown"
+)
+
+// StartError is returned when a process could not be started, so that
+// callers can react to the reason it failed. Use GetStartErrorCode to get
+// the reason from an error that may wrap a StartError.
+type StartError struct {
+	Code StartErrorCode
+	Err  error
+}
+
+func (e *StartError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *StartError) Unwrap() error { return e.Err }
+
+// GetStartErrorCode returns the code of the StartError that the error wraps.
+// It returns StartErrorUnknown if the error is nil or does not wrap a
+// StartError.
+func GetStartErrorCode(err error) StartErrorCode {
+	var startErr *StartError
+	if errors.As(err, &startErr) {
+		return startErr.Code
+	}
+	return StartErrorUnknown
+}
+
+// newStartError returns a StartError for err, which describes the failure
+// to start a process, classified by its underlying cause.
+func newStartError(cause, err error) *StartError {
+	return &StartError{Code: classifyStartError(cause), Err: err}
+}
+
+func classifyStartError(err error) StartErrorCode {
+	switch {
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, os.ErrNotExist):
+		return StartErrorNotFound
+	case errors.Is(err, os.ErrPermission):
+		return StartErrorPermissionDenied
+	case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.ENOMEM),
+		errors.Is(err, syscall.EMFILE), errors.Is(err, syscall.ENFILE):
+		return StartErrorResourceExhausted
+	default:
+		return StartErrorUnknown
+	}
+}
@@ -0,0 +1,112 @@
+package jasper
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tychoish/jasper/options"
+	"github.com/tychoish/jasper/testutil"
+)
+
+func TestClassifyStartError(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		err      error
+		expected StartErrorCode
+	}{
+		"ExecutableNotInPath": {
+			err:      &exec.Error{Name: "foo", Err: exec.ErrNotFound},
+			expected: StartErrorNotFound,
+		},
+		"NoSuchFile": {
+			err:      &os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.ENOENT},
+			expected: StartErrorNotFound,
+		},
+		"AccessDenied": {
+			err:      &os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.EACCES},
+			expected: StartErrorPermissionDenied,
+		},
+		"OperationNotPermitted": {
+			err:      &os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.EPERM},
+			expected: StartErrorPermissionDenied,
+		},
+		"TooManyProcesses": {
+			err:      &os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.EAGAIN},
+			expected: StartErrorResourceExhausted,
+		},
+		"OutOfMemory": {
+			err:      &os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.ENOMEM},
+			expected: StartErrorResourceExhausted,
+		},
+		"TooManyOpenFiles": {
+			err:      errors.Wrap(&os.PathError{Op: "fork/exec", Path: "/foo", Err: syscall.EMFILE}, "wrapped"),
+			expected: StartErrorResourceExhausted,
+		},
+		"Other": {
+			err:      errors.New("foo"),
+			expected: StartErrorUnknown,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, classifyStartError(testCase.err))
+		})
+	}
+}
+
+func TestGetStartErrorCode(t *testing.T) {
+	assert.Equal(t, StartErrorUnknown, GetStartErrorCode(nil))
+	assert.Equal(t, StartErrorUnknown, GetStartErrorCode(errors.New("foo")))
+
+	err := errors.Wrap(&StartError{Code: StartErrorNotFound, Err: errors.New("foo")}, "wrapped")
+	assert.Equal(t, StartErrorNotFound, GetStartErrorCode(err))
+	assert.Contains(t, err.Error(), "foo")
+}
+
+func TestProcessStartError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not enforced on windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(testutil.BuildDirectory(), "start_error")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+	notExecutable := filepath.Join(tmpDir, "not_executable")
+	require.NoError(t, ioutil.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0600))
+
+	for _, impl := range []string{options.ProcessImplementationBasic, options.ProcessImplementationBlocking} {
+		t.Run(impl, func(t *testing.T) {
+			for testName, testCase := range map[string]struct {
+				args     []string
+				expected StartErrorCode
+			}{
+				"BinaryNotFound":         {args: []string{"this-binary-does-not-exist"}, expected: StartErrorNotFound},
+				"NonexistentPath":        {args: []string{filepath.Join(tmpDir, "nonexistent")}, expected: StartErrorNotFound},
+				"FileIsNotExecutable":    {args: []string{notExecutable}, expected: StartErrorPermissionDenied},
+				"DirectoryIsNotRunnable": {args: []string{tmpDir}, expected: StartErrorPermissionDenied},
+			} {
+				t.Run(testName, func(t *testing.T) {
+					proc, err := NewProcess(ctx, &options.Create{
+						Args:           testCase.args,
+						Implementation: impl,
+					})
+					require.Error(t, err)
+					assert.Nil(t, proc)
+					assert.Equal(t, testCase.expected, GetStartErrorCode(err))
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package jasper
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// tagLimiter limits the number of processes with each tag that can run
+// concurrently. It is safe for concurrent use.
+type tagLimiter struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newTagLimiter() *tagLimiter {
+	return &tagLimiter{slots: map[string]chan struct{}{}}
+}
+
+// setLimit sets the maximum number of processes with the tag that can run
+// concurrently. A limit of zero removes the limit. Processes that hold a slot
+// under the previous limit are not counted against the new one.
+func (l *tagLimiter) setLimit(tag string, max int) error {
+	if tag == "" {
+		return errors.New("cannot limit an empty tag")
+	}
+	if max < 0 {
+		return errors.Errorf("limit for tag '%s' cannot be negative", tag)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if max == 0 {
+		delete(l.slots, tag)
+		return nil
+	}
+	if slots, ok := l.slots[tag]; ok && cap(slots) == max {
+		return nil
+	}
+	l.slots[tag] = make(chan struct{}, max)
+
+	return nil
+}
+
+// acquire blocks until there is a slot for each of the limited tags or the
+// context is done. The returned function releases the slots and is safe to
+// call more than once. A nil limiter does not limit any tags.
+func (l *tagLimiter) acquire(ctx context.Context, tags []string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	held := []chan struct{}{}
+	once := &sync.Once{}
+	release := func() {
+		once.Do(func() {
+			for _, slots := range held {
+				<-slots
+			}
+		})
+	}
+
+	// Acquire the slots in a consistent order so that processes with
+	// several limited tags cannot deadlock.
+	for _, tag := range uniqueSortedTags(tags) {
+		l.mu.Lock()
+		slots, ok := l.slots[tag]
+		l.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+			held = append(held, slots)
+		case <-ctx.Done():
+			release()
+			return nil, errors.Wrapf(ctx.Err(), "context done while waiting for a slot for tag '%s'", tag)
+		}
+	}
+
+	return release, nil
+}
+
+func uniqueSortedTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// releaseOnExit calls release once the process completes.
+func releaseOnExit(ctx context.Context, proc Process, release func()) {
+	// The trigger cannot be registered if the process has already
+	// completed or the process does not support triggers, in which case
+	// release is called once waiting on the process returns.
+	if err := proc.RegisterTrigger(ctx, func(ProcessInfo) { release() }); err != nil {
+		go func() {
+			defer release()
+			_, _ = proc.Wait(context.Background())
+		}()
+	}
+}
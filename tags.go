@@ -0,0 +1,72 @@
+package jasper
+
+import "sync"
+
+// tagSet is the set of tags for a process. The tags are kept in the order in
+// which they were first added so that they can be reported consistently in
+// the process's options. It is safe for concurrent use.
+type tagSet struct {
+	mu    sync.RWMutex
+	tags  map[string]struct{}
+	order []string
+}
+
+// newTagSet returns a tag set containing the given tags. Duplicate tags are
+// only added once.
+func newTagSet(tags []string) *tagSet {
+	s := &tagSet{tags: make(map[string]struct{}, len(tags))}
+	for _, t := range tags {
+		s.add(t)
+	}
+	return s
+}
+
+// add adds the tag to the set and returns true if it was not already present.
+func (s *tagSet) add(t string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tags[t]; ok {
+		return false
+	}
+
+	s.tags[t] = struct{}{}
+	s.order = append(s.order, t)
+	return true
+}
+
+// reset removes all tags from the set.
+func (s *tagSet) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tags = map[string]struct{}{}
+	s.order = nil
+}
+
+// list returns a copy of the tags in the order in which they were added. It is
+// never nil, even if the tag set is nil.
+func (s *tagSet) list() []string {
+	if s == nil {
+		return []string{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]string, len(s.order))
+	copy(out, s.order)
+	return out
+}
+
+// len returns the number of tags in the set.
+func (s *tagSet) len() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.order)
+}
@@ -0,0 +1,63 @@
+package jasper
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagSet(t *testing.T) {
+	t.Run("DeduplicatesInitialTags", func(t *testing.T) {
+		s := newTagSet([]string{"a", "b", "a"})
+		assert.Equal(t, []string{"a", "b"}, s.list())
+		assert.Equal(t, 2, s.len())
+	})
+	t.Run("AddPreservesOrder", func(t *testing.T) {
+		s := newTagSet(nil)
+		assert.True(t, s.add("b"))
+		assert.True(t, s.add("a"))
+		assert.False(t, s.add("b"))
+		assert.Equal(t, []string{"b", "a"}, s.list())
+		assert.Equal(t, 2, s.len())
+	})
+	t.Run("ResetRemovesAllTags", func(t *testing.T) {
+		s := newTagSet([]string{"a", "b"})
+		s.reset()
+		assert.Zero(t, s.len())
+		assert.NotNil(t, s.list())
+		assert.Empty(t, s.list())
+		assert.True(t, s.add("a"))
+	})
+	t.Run("NilSetIsEmpty", func(t *testing.T) {
+		var s *tagSet
+		assert.NotNil(t, s.list())
+		assert.Empty(t, s.list())
+		assert.Zero(t, s.len())
+	})
+	t.Run("ListReturnsCopy", func(t *testing.T) {
+		s := newTagSet([]string{"a"})
+		tags := s.list()
+		tags[0] = "b"
+		assert.Equal(t, []string{"a"}, s.list())
+	})
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		s := newTagSet(nil)
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					s.add(string(rune('a' + j%26)))
+					if i == 0 && j%10 == 0 {
+						s.reset()
+					}
+					_ = s.list()
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Len(t, s.list(), s.len())
+	})
+}
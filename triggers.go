@@ -2,12 +2,14 @@ package jasper
 
 import (
 	"context"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/message"
+	"github.com/tychoish/grip/recovery"
 	"github.com/tychoish/jasper/options"
 )
 
@@ -28,6 +30,93 @@ func (s ProcessTriggerSequence) Run(info ProcessInfo) {
 	}
 }
 
+// runRecovered runs the triggers like Run. If a trigger panics, the panic is
+// logged rather than crashing the program, and the remaining triggers still
+// run. The panics are returned as an error.
+func (s ProcessTriggerSequence) runRecovered(info ProcessInfo) error {
+	catcher := grip.NewBasicCatcher()
+	for _, trigger := range s {
+		catcher.Add(runTriggerRecovered(info, trigger))
+	}
+	return catcher.Resolve()
+}
+
+func runTriggerRecovered(info ProcessInfo, trigger ProcessTrigger) (err error) {
+	defer func() { err = recovery.HandlePanicWithError(recover(), nil, "running process trigger", info.ID) }()
+	trigger(info)
+	return nil
+}
+
+// TriggerPhase describes when a ProcessTrigger runs relative to the
+// process's Wait returning.
+type TriggerPhase string
+
+const (
+	// TriggerPhasePre triggers run, in order, before Wait returns to the
+	// caller. Triggers registered with RegisterTrigger run in this phase.
+	// Pre triggers are suitable for cleanup that must finish before the
+	// caller observes that the process has completed.
+	TriggerPhasePre TriggerPhase = "pre"
+	// TriggerPhasePost triggers run, in order, asynchronously after Wait
+	// returns to the caller. Post triggers are suitable for
+	// notifications that should not delay the caller.
+	TriggerPhasePost TriggerPhase = "post"
+)
+
+// Validate checks that the trigger phase is recognized.
+func (p TriggerPhase) Validate() error {
+	switch p {
+	case TriggerPhasePre, TriggerPhasePost:
+		return nil
+	default:
+		return errors.Errorf("unrecognized trigger phase '%s'", p)
+	}
+}
+
+// ProcessTriggerWithContext is a ProcessTrigger that also receives a
+// context, which allows triggers to access request-scoped values
+// (e.g. trace IDs). The trigger runs with the context passed to the first
+// call to Wait that returns after the process completes, before that call
+// returns. If the process is never waited on, the trigger does not run.
+type ProcessTriggerWithContext func(context.Context, ProcessInfo)
+
+// Bind returns a ProcessTrigger that calls the trigger with the given
+// context.
+func (t ProcessTriggerWithContext) Bind(ctx context.Context) ProcessTrigger {
+	return func(info ProcessInfo) { t(ctx, info) }
+}
+
+// contextTriggers holds the triggers registered with a context until the
+// process is waited on.
+type contextTriggers struct {
+	mu       sync.Mutex
+	triggers []ProcessTriggerWithContext
+}
+
+func (t *contextTriggers) add(trigger ProcessTriggerWithContext) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.triggers = append(t.triggers, trigger)
+}
+
+// run runs the triggers with the context passed to Wait and removes them,
+// so that each trigger runs once even if the process is waited on more than
+// once. Panics are logged and do not prevent the remaining triggers from
+// running.
+func (t *contextTriggers) run(ctx context.Context, info ProcessInfo) {
+	t.mu.Lock()
+	triggers := t.triggers
+	t.triggers = nil
+	t.mu.Unlock()
+
+	seq := make(ProcessTriggerSequence, 0, len(triggers))
+	for _, trigger := range triggers {
+		seq = append(seq, trigger.Bind(ctx))
+	}
+	_ = seq.runRecovered(info)
+}
+
 // SignalTrigger describes the way to write hooks that will execute
 // before a process is about to be signaled. It returns a bool
 // indicating if the signal should be skipped after execution of the
@@ -50,6 +139,31 @@ func (s SignalTriggerSequence) Run(info ProcessInfo, sig syscall.Signal) (skipSi
 	return
 }
 
+// runRecovered runs the signal triggers like Run. If a trigger panics, the
+// panic is logged rather than crashing the program, the trigger does not skip
+// the signal, and the remaining triggers still run. The panics are returned
+// as an error.
+func (s SignalTriggerSequence) runRecovered(info ProcessInfo, sig syscall.Signal) (bool, error) {
+	var skipSignal bool
+	catcher := grip.NewBasicCatcher()
+	for _, trigger := range s {
+		skip, err := runSignalTriggerRecovered(info, sig, trigger)
+		catcher.Add(err)
+		skipSignal = skip || skipSignal
+	}
+	return skipSignal, catcher.Resolve()
+}
+
+func runSignalTriggerRecovered(info ProcessInfo, sig syscall.Signal, trigger SignalTrigger) (skipSignal bool, err error) {
+	defer func() {
+		if perr := recovery.HandlePanicWithError(recover(), nil, "running signal trigger", info.ID); perr != nil {
+			skipSignal = false
+			err = perr
+		}
+	}()
+	return trigger(info, sig), nil
+}
+
 // SignalTriggerID is the unique representation of a signal trigger.
 type SignalTriggerID string
 
@@ -124,3 +238,22 @@ func makeDefaultTrigger(ctx context.Context, m Manager, opts *options.Create, pa
 		}
 	}
 }
+
+// MakeChannelTrigger returns a trigger that sends the final ProcessInfo of
+// the process to the channel, which allows callers to be notified when the
+// process completes instead of polling it. The send does not block: if the
+// channel is full, the info is dropped and a warning is logged, so the
+// channel should be buffered.
+func MakeChannelTrigger(ch chan<- ProcessInfo) ProcessTrigger {
+	return func(info ProcessInfo) {
+		select {
+		case ch <- info:
+		default:
+			grip.Warning(message.Fields{
+				"message": "could not send process info to full channel",
+				"trigger": "channel",
+				"process": info.ID,
+			})
+		}
+	}
+}
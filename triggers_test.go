@@ -2,6 +2,9 @@ package jasper
 
 import (
 	"context"
+	"fmt"
+	"runtime"
+	"syscall"
 	"testing"
 	"time"
 
@@ -166,3 +169,160 @@ func TestDefaultTrigger(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelTrigger(t *testing.T) {
+	for name, testcase := range map[string]func(context.Context, *testing.T){
+		"SendsInfoOnCompletion": func(ctx context.Context, t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("test commands are not available on windows")
+			}
+			for _, impl := range []string{options.ProcessImplementationBasic, options.ProcessImplementationBlocking} {
+				for _, exitCode := range []int{0, 3} {
+					opts := &options.Create{
+						ShellCommand:   fmt.Sprintf("sleep 0.5; exit %d", exitCode),
+						Implementation: impl,
+					}
+					proc, err := NewProcess(ctx, opts)
+					require.NoError(t, err)
+
+					ch := make(chan ProcessInfo, 1)
+					require.NoError(t, proc.RegisterTrigger(ctx, MakeChannelTrigger(ch)))
+
+					select {
+					case info := <-ch:
+						assert.Equal(t, proc.ID(), info.ID)
+						assert.True(t, info.Complete)
+						assert.Equal(t, exitCode, info.ExitCode)
+						assert.Equal(t, exitCode == 0, info.Successful)
+					case <-ctx.Done():
+						assert.FailNow(t, "timed out waiting for process info")
+					}
+				}
+			}
+		},
+		"DoesNotBlockOnFullChannel": func(ctx context.Context, t *testing.T) {
+			ch := make(chan ProcessInfo, 1)
+			trigger := MakeChannelTrigger(ch)
+
+			trigger(ProcessInfo{ID: "first"})
+			trigger(ProcessInfo{ID: "second"})
+
+			require.Len(t, ch, 1)
+			assert.Equal(t, "first", (<-ch).ID)
+		},
+		"DoesNotBlockOnUnbufferedChannel": func(ctx context.Context, t *testing.T) {
+			ch := make(chan ProcessInfo)
+			MakeChannelTrigger(ch)(ProcessInfo{ID: "id"})
+			assert.Len(t, ch, 0)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+			defer cancel()
+
+			testcase(ctx, t)
+		})
+	}
+}
+
+func TestTriggerPanic(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands are not available on windows")
+	}
+
+	for name, testcase := range map[string]func(context.Context, *testing.T, string){
+		"FailsWaitInsteadOfCrashing": func(ctx context.Context, t *testing.T, impl string) {
+			proc, err := NewProcess(ctx, &options.Create{
+				ShellCommand:   "sleep 0.5",
+				Implementation: impl,
+			})
+			require.NoError(t, err)
+			require.NoError(t, proc.RegisterTrigger(ctx, func(ProcessInfo) {
+				panic("trigger panic")
+			}))
+
+			_, err = proc.Wait(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "trigger panic")
+
+			info := proc.Info(ctx)
+			assert.True(t, info.Complete)
+			assert.False(t, info.Successful)
+		},
+		"RemainingTriggersRunAfterPanic": func(ctx context.Context, t *testing.T, impl string) {
+			proc, err := NewProcess(ctx, &options.Create{
+				ShellCommand:   "sleep 0.5",
+				Implementation: impl,
+			})
+			require.NoError(t, err)
+			var ran bool
+			require.NoError(t, proc.RegisterTrigger(ctx, func(ProcessInfo) {
+				panic("first panic")
+			}))
+			require.NoError(t, proc.RegisterTrigger(ctx, func(ProcessInfo) {
+				ran = true
+			}))
+			require.NoError(t, proc.RegisterTrigger(ctx, func(ProcessInfo) {
+				panic("second panic")
+			}))
+
+			_, err = proc.Wait(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "first panic")
+			assert.Contains(t, err.Error(), "second panic")
+			assert.True(t, ran)
+			assert.False(t, proc.Info(ctx).Successful)
+		},
+		"SignalTriggerPanicDoesNotPreventSignal": func(ctx context.Context, t *testing.T, impl string) {
+			proc, err := NewProcess(ctx, &options.Create{
+				Args:           []string{"sleep", "10"},
+				Implementation: impl,
+			})
+			require.NoError(t, err)
+			require.NoError(t, proc.RegisterSignalTrigger(ctx, func(ProcessInfo, syscall.Signal) bool {
+				panic("signal trigger panic")
+			}))
+
+			err = proc.Signal(ctx, syscall.SIGKILL)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "signal trigger panic")
+
+			_, err = proc.Wait(ctx)
+			assert.Error(t, err)
+			assert.Equal(t, syscall.SIGKILL, proc.Info(ctx).Signal)
+		},
+		"PostTriggerPanicDoesNotFailWait": func(ctx context.Context, t *testing.T, impl string) {
+			proc, err := NewProcess(ctx, &options.Create{
+				ShellCommand:   "sleep 0.5",
+				Implementation: impl,
+			})
+			require.NoError(t, err)
+			ran := make(chan struct{})
+			require.NoError(t, proc.RegisterTriggerWithPhase(ctx, TriggerPhasePost, func(ProcessInfo) {
+				defer close(ran)
+				panic("trigger panic")
+			}))
+
+			_, err = proc.Wait(ctx)
+			require.NoError(t, err)
+			assert.True(t, proc.Info(ctx).Successful)
+
+			select {
+			case <-ran:
+			case <-ctx.Done():
+				assert.FailNow(t, "post trigger did not run")
+			}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, impl := range []string{options.ProcessImplementationBasic, options.ProcessImplementationBlocking} {
+				t.Run(impl, func(t *testing.T) {
+					ctx, cancel := context.WithTimeout(context.Background(), testutil.TestTimeout)
+					defer cancel()
+
+					testcase(ctx, t, impl)
+				})
+			}
+		})
+	}
+}
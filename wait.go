@@ -0,0 +1,234 @@
+package jasper
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/jasper/options"
+)
+
+// WaitAny blocks until any of the given processes completes and returns the
+// first process to complete. Processes that have already completed are
+// returned immediately. The outcome of the returned process is not reflected
+// in the error, so callers should check the process's Info to determine if it
+// was successful. If the context is canceled before any process completes,
+// WaitAny returns the context's error.
+func WaitAny(ctx context.Context, procs []Process) (Process, error) {
+	if len(procs) == 0 {
+		return nil, errors.New("must specify at least one process to wait on")
+	}
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan Process, len(procs))
+	for _, proc := range procs {
+		go func(proc Process) {
+			_, _ = proc.Wait(waitCtx)
+			if waitCtx.Err() != nil {
+				return
+			}
+			done <- proc
+		}(proc)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case proc := <-done:
+		return proc, nil
+	}
+}
+
+// WaitWithProgress waits for the process to complete in the same manner as
+// Process.Wait, calling the callback with the process's current Info every
+// interval while it is running. The callback is called synchronously and
+// is never called once the process has completed or after WaitWithProgress
+// returns. It returns the error from waiting on the process. Process
+// implementations can use this to implement Process.WaitWithProgress.
+func WaitWithProgress(ctx context.Context, proc Process, interval time.Duration, cb func(ProcessInfo)) error {
+	if interval <= 0 {
+		return errors.New("progress interval must be positive")
+	}
+	if cb == nil {
+		return errors.New("must specify a progress callback")
+	}
+
+	var waitErr error
+	waitDone := make(chan struct{})
+	go func() {
+		defer close(waitDone)
+		_, waitErr = proc.Wait(ctx)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-waitDone:
+			return waitErr
+		case <-ticker.C:
+			info := proc.Info(ctx)
+			// Check for completion again, since the process may have
+			// completed while the tick was pending.
+			select {
+			case <-waitDone:
+				return waitErr
+			default:
+			}
+			if info.Complete {
+				continue
+			}
+			cb(info)
+		}
+	}
+}
+
+// WaitForResult waits for the process to complete in the same manner as
+// Process.Wait and returns the outcome reported by its Info. If the process
+// does not complete, the result is empty. The error is the error from waiting
+// on the process, so it is also returned for processes that completed
+// unsuccessfully. Process implementations can use this to implement
+// Process.WaitResult.
+func WaitForResult(ctx context.Context, proc Process) (WaitResult, error) {
+	_, err := proc.Wait(ctx)
+	info := proc.Info(ctx)
+	if !info.Complete {
+		return WaitResult{}, err
+	}
+
+	res := WaitResult{
+		ExitCode:  info.ExitCode,
+		Signal:    info.Signal,
+		EndReason: info.EndReason,
+	}
+	if !info.StartAt.IsZero() && !info.EndAt.IsZero() {
+		res.Duration = info.EndAt.Sub(info.StartAt)
+	}
+
+	return res, err
+}
+
+// WaitForTag waits for each process in the manager that has the given tag,
+// using the manager's Group method, and returns the error from waiting on each
+// process keyed by its ID. Processes that completed successfully map to a nil
+// error. If no process has the tag, it returns an empty map immediately. If
+// the context is done before every process completes, it returns the results
+// collected so far along with the context's error. Manager implementations can
+// use this to implement Manager.WaitForTag.
+func WaitForTag(ctx context.Context, m Manager, tag string) (map[string]error, error) {
+	procs, err := m.Group(ctx, tag)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting processes with tag '%s'", tag)
+	}
+
+	results := make(map[string]error, len(procs))
+	for _, proc := range procs {
+		_, err := proc.Wait(ctx)
+		if ctx.Err() != nil {
+			return results, errors.Wrapf(ctx.Err(), "waiting for process '%s'", proc.ID())
+		}
+		results[proc.ID()] = err
+	}
+
+	return results, nil
+}
+
+// waitDoneRetryInterval is how long WaitDone waits before waiting on the
+// process again if waiting failed before the process completed.
+const waitDoneRetryInterval = time.Second
+
+// WaitDone returns a channel that is closed once the process completes, which
+// it detects by waiting on the process in the background. If waiting fails
+// with a transient error before the process completes (e.g. because a remote
+// service is temporarily unavailable), it waits on the process again. If
+// waiting fails with any other error, such as because the process does not
+// exist, the channel is closed, since the process cannot be waited on.
+// Process implementations that do not track their completion locally can use
+// this to implement Process.Done, but should only call it once for each
+// process.
+func WaitDone(proc Process) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctx := context.Background()
+		for {
+			_, err := proc.Wait(ctx)
+			if proc.Complete(ctx) || !isTransientError(err) {
+				return
+			}
+			time.Sleep(waitDoneRetryInterval)
+		}
+	}()
+	return done
+}
+
+// isTransientError returns whether the error may not occur again if the
+// operation is retried, such as a network error or a timeout.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	cause := errors.Cause(err)
+	if cause == context.DeadlineExceeded {
+		return true
+	}
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+	if temp, ok := cause.(interface{ Temporary() bool }); ok {
+		return temp.Temporary()
+	}
+	return false
+}
+
+// ProcessContext returns a context derived from the given context that is
+// canceled once the process completes, which is useful to stop work that
+// depends on the process. Callers should call the returned function once the
+// context is no longer needed to release its resources.
+func ProcessContext(ctx context.Context, proc Process) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-proc.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// ErrOutputNotMatched is returned by Process.WaitForOutput when the process
+// exits without writing a line that matches the pattern.
+var ErrOutputNotMatched = errors.New("process exited before its output matched the pattern")
+
+// waitForOutput blocks until the process's output matches the pattern, the
+// output is closed because the process exited, or the context is canceled.
+func waitForOutput(ctx context.Context, output *options.Output, pattern *regexp.Regexp) error {
+	matched, stop, err := output.WatchOutput(pattern)
+	if err != nil {
+		return errors.Wrap(err, "problem watching process output")
+	}
+	defer stop()
+
+	return awaitOutputMatch(ctx, matched)
+}
+
+// awaitOutputMatch blocks until the result of watching a process's output is
+// received or the context is canceled.
+func awaitOutputMatch(ctx context.Context, matched <-chan bool) error {
+	select {
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	case ok := <-matched:
+		if !ok {
+			return ErrOutputNotMatched
+		}
+		return nil
+	}
+}